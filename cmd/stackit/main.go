@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"stackit.dev/stackit/internal/cli"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 )
 
 var (
@@ -21,6 +22,6 @@ func main() {
 
 	rootCmd := cli.NewRootCmd(version, commit, date)
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(stackiterrors.ExitCode(err))
 	}
 }
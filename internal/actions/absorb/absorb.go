@@ -7,6 +7,7 @@ import (
 
 	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/git"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui"
@@ -16,10 +17,14 @@ import (
 
 // Options contains options for the absorb command
 type Options struct {
-	All    bool
-	DryRun bool
-	Force  bool
-	Patch  bool
+	All         bool
+	DryRun      bool
+	Force       bool
+	Patch       bool
+	Interactive bool
+	// UnmatchedIntoFirst absorbs hunks that match no commit, such as new files, into the
+	// newest commit instead of leaving them staged (absorb.newFileMode=first)
+	UnmatchedIntoFirst bool
 }
 
 // Action performs the absorb operation
@@ -30,7 +35,7 @@ func Action(ctx *runtime.Context, opts Options) error {
 	// Get current branch
 	currentBranch := eng.CurrentBranch()
 	if currentBranch == nil {
-		return fmt.Errorf("not on a branch")
+		return stackiterrors.ErrNotOnBranch
 	}
 
 	// Take snapshot before modifying the repository
@@ -39,6 +44,7 @@ func Action(ctx *runtime.Context, opts Options) error {
 		actions.WithFlag(opts.DryRun, "--dry-run"),
 		actions.WithFlag(opts.Force, "--force"),
 		actions.WithFlag(opts.Patch, "--patch"),
+		actions.WithFlag(opts.Interactive, "--interactive"),
 	)
 	if err := eng.TakeSnapshot(snapshotOpts); err != nil {
 		// Log but don't fail - snapshot is best effort
@@ -129,9 +135,12 @@ func Action(ctx *runtime.Context, opts Options) error {
 		}
 
 		if commitSHA == "" {
-			// Hunk commutes with all commits - can't be absorbed
-			unabsorbedHunks = append(unabsorbedHunks, hunk)
-			continue
+			if !opts.UnmatchedIntoFirst || len(commitSHAs) == 0 {
+				// No commit matches the hunk - leave it staged
+				unabsorbedHunks = append(unabsorbedHunks, hunk)
+				continue
+			}
+			commitSHA, commitIndex = commitSHAs[0], 0
 		}
 
 		hunkTargets = append(hunkTargets, git.HunkTarget{
@@ -141,6 +150,16 @@ func Action(ctx *runtime.Context, opts Options) error {
 		})
 	}
 
+	// Let the user accept, skip, or retarget each hunk. Skipped hunks are re-staged
+	// once the absorb is done.
+	var skippedHunks []git.Hunk
+	if opts.Interactive && len(hunkTargets) > 0 {
+		hunkTargets, skippedHunks, err = reviewHunkTargets(ctx.Context, eng, hunkTargets, downstackBranches, commitSHAs, splog)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Group hunks by branch, then by commit
 	hunksByBranch := make(map[string]map[string][]git.Hunk)
 	for _, target := range hunkTargets {
@@ -156,10 +175,7 @@ func Action(ctx *runtime.Context, opts Options) error {
 
 	if len(hunksByBranch) == 0 {
 		if len(unabsorbedHunks) > 0 {
-			splog.Warn("The following hunks could not be absorbed (they commute with all commits):")
-			for _, hunk := range unabsorbedHunks {
-				splog.Info("  %s (lines %d-%d)", hunk.File, hunk.NewStart, hunk.NewStart+hunk.NewCount-1)
-			}
+			printUnabsorbedHunks(unabsorbedHunks, splog)
 		} else {
 			splog.Info("Nothing to absorb.")
 		}
@@ -188,8 +204,8 @@ func Action(ctx *runtime.Context, opts Options) error {
 	}
 	printAbsorbPlan(flatHunksByCommit, unabsorbedHunks, eng, splog)
 
-	// Prompt for confirmation if not --force
-	if !opts.Force {
+	// Prompt for confirmation if not --force; interactive mode has already confirmed each hunk
+	if !opts.Force && !opts.Interactive {
 		confirmed, err := tui.PromptConfirm("Apply these changes to the commits?", false)
 		if err != nil {
 			return fmt.Errorf("confirmation canceled: %w", err)
@@ -207,6 +223,16 @@ func Action(ctx *runtime.Context, opts Options) error {
 		defer func() {
 			// Restore stash after we're done
 			_ = eng.StashPop(ctx.Context)
+
+			// Popping the stash leaves everything unstaged; put back what the user skipped
+			if opts.Interactive {
+				restage := make([]git.Hunk, 0, len(skippedHunks)+len(unabsorbedHunks))
+				restage = append(restage, skippedHunks...)
+				restage = append(restage, unabsorbedHunks...)
+				if err := eng.StageHunks(ctx.Context, restage); err != nil {
+					splog.Warn("Failed to re-stage skipped hunks: %v", err)
+				}
+			}
 		}()
 	}
 
@@ -239,10 +265,7 @@ func Action(ctx *runtime.Context, opts Options) error {
 
 	// Warn about unabsorbed hunks
 	if len(unabsorbedHunks) > 0 {
-		splog.Warn("The following hunks could not be absorbed (they commute with all commits):")
-		for _, hunk := range unabsorbedHunks {
-			splog.Info("  %s (lines %d-%d)", hunk.File, hunk.NewStart, hunk.NewStart+hunk.NewCount-1)
-		}
+		printUnabsorbedHunks(unabsorbedHunks, splog)
 	}
 
 	// Refresh engine state after modifying branch references directly via git
@@ -256,7 +279,7 @@ func Action(ctx *runtime.Context, opts Options) error {
 		upstackBranches := eng.GetRelativeStackUpstack(branch)
 
 		if len(upstackBranches) > 0 {
-			if err := actions.RestackBranches(ctx.Context, upstackBranches, eng, splog, ctx.RepoRoot); err != nil {
+			if err := actions.RestackBranchesWithSummary(ctx.Context, upstackBranches, eng, splog, ctx.RepoRoot); err != nil {
 				return fmt.Errorf("failed to restack upstack branches: %w", err)
 			}
 		}
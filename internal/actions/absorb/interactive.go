@@ -0,0 +1,116 @@
+package absorb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"stackit.dev/stackit/internal/engine"
+	"stackit.dev/stackit/internal/git"
+	"stackit.dev/stackit/internal/tui"
+)
+
+// hunkAction is the user's decision for a single hunk in interactive mode
+type hunkAction string
+
+const (
+	hunkAccept   hunkAction = "accept"
+	hunkSkip     hunkAction = "skip"
+	hunkRetarget hunkAction = "retarget"
+)
+
+// hunkDecision is the answer to a hunk prompt. Branch is set when retargeting.
+type hunkDecision struct {
+	Action hunkAction
+	Branch string
+}
+
+// promptForHunk asks what to do with a hunk and its computed target. It is a variable
+// so tests can script the answers.
+var promptForHunk = func(target git.HunkTarget, targetBranch string, branches []string, splog *tui.Splog) (hunkDecision, error) {
+	splog.Newline()
+	splog.Info("%s (lines %d-%d) → %s in %s", target.Hunk.File, target.Hunk.NewStart,
+		target.Hunk.NewStart+target.Hunk.NewCount-1, target.CommitSHA[:8], targetBranch)
+	splog.Info("%s", target.Hunk.Content)
+
+	options := []tui.SelectOption{
+		{Label: fmt.Sprintf("Absorb into %s (%s)", target.CommitSHA[:8], targetBranch), Value: string(hunkAccept)},
+		{Label: "Skip (leave staged)", Value: string(hunkSkip)},
+	}
+	if len(branches) > 1 {
+		options = append(options, tui.SelectOption{Label: "Absorb into another branch", Value: string(hunkRetarget)})
+	}
+	selected, err := tui.PromptSelect("What should be done with this hunk?", options, 0)
+	if err != nil {
+		return hunkDecision{}, err
+	}
+	if hunkAction(selected) != hunkRetarget {
+		return hunkDecision{Action: hunkAction(selected)}, nil
+	}
+
+	branchOptions := make([]tui.SelectOption, 0, len(branches))
+	for _, branch := range branches {
+		if branch != targetBranch {
+			branchOptions = append(branchOptions, tui.SelectOption{Label: branch, Value: branch})
+		}
+	}
+	branch, err := tui.PromptSelect("Absorb into which branch?", branchOptions, 0)
+	if err != nil {
+		return hunkDecision{}, err
+	}
+	return hunkDecision{Action: hunkRetarget, Branch: branch}, nil
+}
+
+// reviewHunkTargets prompts for each hunk target, returning the targets to absorb and
+// the hunks the user chose to leave staged. Retargeted hunks are absorbed into the
+// newest commit of the chosen branch, or left staged if they can't be. commitSHAs are
+// the downstack commits, newest first.
+func reviewHunkTargets(ctx context.Context, eng engine.Engine, targets []git.HunkTarget, downstackBranches []engine.Branch, commitSHAs []string, splog *tui.Splog) ([]git.HunkTarget, []git.Hunk, error) {
+	branchNames := make([]string, 0, len(downstackBranches))
+	for _, branch := range downstackBranches {
+		branchNames = append(branchNames, branch.GetName())
+	}
+
+	accepted := []git.HunkTarget{}
+	skipped := []git.Hunk{}
+	for _, target := range targets {
+		targetBranch, err := eng.FindBranchForCommit(target.CommitSHA)
+		if err != nil {
+			targetBranch = unknown
+		}
+
+		decision, err := promptForHunk(target, targetBranch, branchNames, splog)
+		if err != nil {
+			return nil, nil, fmt.Errorf("hunk selection canceled: %w", err)
+		}
+
+		switch decision.Action {
+		case hunkAccept:
+			accepted = append(accepted, target)
+		case hunkSkip:
+			skipped = append(skipped, target.Hunk)
+		case hunkRetarget:
+			revision, err := eng.GetBranch(decision.Branch).GetRevision()
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get revision for branch %s: %w", decision.Branch, err)
+			}
+			index := slices.Index(commitSHAs, revision)
+			if index < 0 {
+				return nil, nil, fmt.Errorf("branch %s has no commits to absorb into", decision.Branch)
+			}
+			if err := eng.ValidateHunkTarget(ctx, target.Hunk, revision, commitSHAs[:index]); err != nil {
+				splog.Warn("Can't absorb %s into %s: %v. Leaving it staged.", target.Hunk.File, decision.Branch, err)
+				skipped = append(skipped, target.Hunk)
+				continue
+			}
+			accepted = append(accepted, git.HunkTarget{
+				Hunk:        target.Hunk,
+				CommitSHA:   revision,
+				CommitIndex: index,
+			})
+		default:
+			return nil, nil, fmt.Errorf("unknown hunk action %q", decision.Action)
+		}
+	}
+	return accepted, skipped, nil
+}
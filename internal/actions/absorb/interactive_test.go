@@ -0,0 +1,121 @@
+package absorb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/internal/git"
+	"stackit.dev/stackit/internal/tui"
+	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
+)
+
+func TestAbsorbInteractive(t *testing.T) {
+	t.Run("absorbs accepted hunks and leaves skipped hunks staged", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch-a": "main",
+				"branch-b": "branch-a",
+			})
+		s.Checkout("branch-b")
+
+		// Edit the file each branch introduced, so each hunk targets a different commit
+		require.NoError(t, s.Scene.Repo.CreateChange("absorbed into a", "branch-a", false))
+		require.NoError(t, s.Scene.Repo.CreateChange("left staged on b", "branch-b", false))
+
+		// Script the answers: accept the hunk targeting branch-a, skip the one targeting branch-b
+		prompted := map[string]string{}
+		original := promptForHunk
+		t.Cleanup(func() { promptForHunk = original })
+		promptForHunk = func(target git.HunkTarget, targetBranch string, _ []string, _ *tui.Splog) (hunkDecision, error) {
+			prompted[target.Hunk.File] = targetBranch
+			if strings.HasPrefix(target.Hunk.File, "branch-a") {
+				return hunkDecision{Action: hunkAccept}, nil
+			}
+			return hunkDecision{Action: hunkSkip}, nil
+		}
+
+		err := Action(s.Context, Options{Interactive: true})
+		require.NoError(t, err)
+
+		require.Equal(t, map[string]string{
+			"branch-a_test.txt": "branch-a",
+			"branch-b_test.txt": "branch-b",
+		}, prompted)
+
+		// The accepted hunk was absorbed into branch-a's commit
+		content, err := s.Scene.Repo.RunGitCommandAndGetOutput("show", "branch-a:branch-a_test.txt")
+		require.NoError(t, err)
+		require.Equal(t, "absorbed into a", strings.TrimSpace(content))
+
+		// The skipped hunk was not absorbed and is still staged
+		content, err = s.Scene.Repo.RunGitCommandAndGetOutput("show", "branch-b:branch-b_test.txt")
+		require.NoError(t, err)
+		require.Equal(t, "change on branch-b", strings.TrimSpace(content))
+
+		staged, err := s.Scene.Repo.RunGitCommandAndGetOutput("diff", "--cached", "--name-only")
+		require.NoError(t, err)
+		require.Equal(t, "branch-b_test.txt", strings.TrimSpace(staged))
+	})
+	t.Run("absorbs a retargeted hunk into the chosen branch", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch-a": "main",
+				"branch-b": "branch-a",
+			})
+		s.Checkout("branch-b")
+
+		// The hunk belongs to branch-a, but nothing newer touches it, so branch-b can take it
+		require.NoError(t, s.Scene.Repo.CreateChange("moved to b", "branch-a", false))
+		scriptRetarget(t, "branch-b")
+
+		require.NoError(t, Action(s.Context, Options{Interactive: true}))
+
+		content, err := s.Scene.Repo.RunGitCommandAndGetOutput("show", "branch-b:branch-a_test.txt")
+		require.NoError(t, err)
+		require.Equal(t, "moved to b", strings.TrimSpace(content))
+		content, err = s.Scene.Repo.RunGitCommandAndGetOutput("show", "branch-a:branch-a_test.txt")
+		require.NoError(t, err)
+		require.Equal(t, "change on branch-a", strings.TrimSpace(content))
+	})
+
+	t.Run("leaves a hunk staged when it can't be retargeted", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch-a": "main",
+				"branch-b": "branch-a",
+			})
+		s.Checkout("branch-b")
+		require.NoError(t, s.Scene.Repo.CreateChange("b edits a", "branch-a", false))
+		s.Commit("b edits a")
+
+		// branch-b's newer commit changed the same lines, so the hunk can't move below it
+		require.NoError(t, s.Scene.Repo.CreateChange("retargeted", "branch-a", false))
+		scriptRetarget(t, "branch-a")
+
+		require.NoError(t, Action(s.Context, Options{Interactive: true}))
+
+		content, err := s.Scene.Repo.RunGitCommandAndGetOutput("show", "branch-a:branch-a_test.txt")
+		require.NoError(t, err)
+		require.Equal(t, "change on branch-a", strings.TrimSpace(content))
+		content, err = s.Scene.Repo.RunGitCommandAndGetOutput("show", "branch-b:branch-a_test.txt")
+		require.NoError(t, err)
+		require.Equal(t, "b edits a", strings.TrimSpace(content))
+
+		staged, err := s.Scene.Repo.RunGitCommandAndGetOutput("diff", "--cached", "--name-only")
+		require.NoError(t, err)
+		require.Equal(t, "branch-a_test.txt", strings.TrimSpace(staged))
+	})
+}
+
+// scriptRetarget answers every hunk prompt by retargeting the hunk into branch
+func scriptRetarget(t *testing.T, branch string) {
+	t.Helper()
+	original := promptForHunk
+	t.Cleanup(func() { promptForHunk = original })
+	promptForHunk = func(git.HunkTarget, string, []string, *tui.Splog) (hunkDecision, error) {
+		return hunkDecision{Action: hunkRetarget, Branch: branch}, nil
+	}
+}
@@ -70,3 +70,11 @@ func printAbsorbPlan(hunksByCommit map[string][]git.Hunk, unabsorbedHunks []git.
 		}
 	}
 }
+
+// printUnabsorbedHunks lists the hunks no commit matched, which were left staged
+func printUnabsorbedHunks(unabsorbedHunks []git.Hunk, splog *tui.Splog) {
+	splog.Warn("The following hunks could not be absorbed (no commit in the stack matches them):")
+	for _, hunk := range unabsorbedHunks {
+		splog.Info("  %s (lines %d-%d)", hunk.File, hunk.NewStart, hunk.NewStart+hunk.NewCount-1)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui"
 	"stackit.dev/stackit/internal/tui/style"
@@ -17,13 +18,13 @@ type CheckoutOptions struct {
 	All           bool   // Show all branches across trunks
 	StackOnly     bool   // Only show current stack (ancestors + descendants)
 	CheckoutTrunk bool   // Checkout trunk directly
+	Autostash     bool   // Stash local changes that block the checkout and restore them afterwards
 }
 
 // CheckoutAction performs the checkout operation
 func CheckoutAction(ctx *runtime.Context, opts CheckoutOptions) error {
 	eng := ctx.Engine
 	splog := ctx.Splog
-	context := ctx.Context
 
 	if err := eng.PopulateRemoteShas(); err != nil {
 		return fmt.Errorf("failed to populate remote SHAs: %w", err)
@@ -57,8 +58,8 @@ func CheckoutAction(ctx *runtime.Context, opts CheckoutOptions) error {
 	}
 
 	branch := eng.GetBranch(branchName)
-	if err := eng.CheckoutBranch(context, branch); err != nil {
-		return fmt.Errorf("failed to checkout branch %s: %w", branchName, err)
+	if err := CheckoutWithAutostash(ctx, branch, opts.Autostash); err != nil {
+		return err
 	}
 
 	splog.Info("Checked out %s.", style.ColorBranchName(branchName, false))
@@ -67,6 +68,24 @@ func CheckoutAction(ctx *runtime.Context, opts CheckoutOptions) error {
 	return nil
 }
 
+// CheckoutWithAutostash checks out a branch for a navigation command. With autostash, local
+// changes that block the checkout are carried over to the branch through the stash.
+func CheckoutWithAutostash(ctx *runtime.Context, branch engine.Branch, autostash bool) error {
+	branchName := branch.GetName()
+	result, err := ctx.Engine.SafeCheckout(ctx.Context, branch, autostash)
+	if err != nil {
+		if !autostash && utils.HasUncommittedChanges(ctx.Context) {
+			return fmt.Errorf("failed to checkout branch %s: %w\nCommit or stash your changes, or pass --autostash", branchName, err)
+		}
+		return fmt.Errorf("failed to checkout branch %s: %w", branchName, err)
+	}
+	if result.StashConflict {
+		ctx.Splog.Warn("Your local changes conflict with %s. Resolve the conflicts, then run `git stash drop`; the changes are still in the stash.",
+			style.ColorBranchName(branchName, false))
+	}
+	return nil
+}
+
 // getUntrackedBranchesForCheckout returns all untracked branches (excluding trunk)
 func getUntrackedBranchesForCheckout(eng engine.BranchReader) []engine.Branch {
 	var untracked []engine.Branch
@@ -88,7 +107,7 @@ func buildBranchChoices(ctx *runtime.Context, opts CheckoutOptions) ([]engine.Br
 
 	if opts.StackOnly {
 		if currentBranch == nil {
-			return nil, fmt.Errorf("not on a branch; cannot use --stack flag")
+			return nil, fmt.Errorf("%w; cannot use --stack flag", stackiterrors.ErrNotOnBranch)
 		}
 
 		rng := engine.StackRange{
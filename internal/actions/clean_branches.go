@@ -3,6 +3,7 @@ package actions
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sync"
 
 	"stackit.dev/stackit/internal/engine"
@@ -14,6 +15,7 @@ import (
 // CleanBranchesOptions contains options for cleaning branches
 type CleanBranchesOptions struct {
 	Force bool
+	Keep  []string // Branches that must not be deleted, even if they are safe to delete
 }
 
 // CleanBranchesResult contains the result of cleaning branches
@@ -40,7 +42,7 @@ func CleanBranches(ctx *runtime.Context, opts CleanBranchesOptions) (*CleanBranc
 
 	for _, branch := range allTrackedBranches {
 		branchName := branch.GetName()
-		if branch.IsTrunk() {
+		if branch.IsTrunk() || slices.Contains(opts.Keep, branchName) {
 			continue
 		}
 		wg.Add(1)
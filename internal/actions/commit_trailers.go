@@ -0,0 +1,54 @@
+package actions
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	stackiterrors "stackit.dev/stackit/internal/errors"
+	"stackit.dev/stackit/internal/git"
+)
+
+// ParseTrailerFlags turns --trailer key=value flags into "Key: value" trailers
+func ParseTrailerFlags(values []string) ([]string, error) {
+	trailers := make([]string, 0, len(values))
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, stackiterrors.NewValidationError("invalid --trailer %q (must be key=value)", value)
+		}
+		trailers = append(trailers, strings.TrimSpace(key)+": "+strings.TrimSpace(val))
+	}
+	return trailers, nil
+}
+
+// ExpandCommitTrailers fills in the placeholders of commit.trailers templates: {name} and
+// {email} are the git user, {branch} the branch being committed to and {changeId} a new
+// Gerrit Change-Id
+func ExpandCommitTrailers(ctx context.Context, templates []string, branchName string) []string {
+	if len(templates) == 0 {
+		return nil
+	}
+	name, _ := git.GetUserName(ctx)
+	email, _ := git.GetUserEmail(ctx)
+
+	replacer := strings.NewReplacer("{name}", name, "{email}", email, "{branch}", branchName)
+
+	trailers := make([]string, 0, len(templates))
+	for _, template := range templates {
+		trailer := replacer.Replace(template)
+		if strings.Contains(trailer, "{changeId}") {
+			trailer = strings.ReplaceAll(trailer, "{changeId}", newChangeID())
+		}
+		trailers = append(trailers, trailer)
+	}
+	return trailers
+}
+
+// newChangeID returns a random Change-Id in Gerrit's format: "I" followed by 40 hex digits
+func newChangeID() string {
+	b := make([]byte, 20)
+	_, _ = rand.Read(b)
+	return "I" + hex.EncodeToString(b)
+}
@@ -3,9 +3,11 @@ package actions
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"stackit.dev/stackit/internal/config"
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/tui"
 	"stackit.dev/stackit/internal/tui/style"
 )
@@ -18,8 +20,41 @@ type Restacker interface {
 
 // RestackBranches restacks a list of branches using the engine's batch restack method
 func RestackBranches(ctx context.Context, branches []engine.Branch, eng Restacker, splog *tui.Splog, repoRoot string) error {
+	return restackBranches(ctx, branches, eng, splog, repoRoot, false)
+}
+
+// persistPostRestackHookFailure saves the continuation state after the restack.postHook failed,
+// so `stackit continue` can restack the remaining branches once the problem is fixed
+func persistPostRestackHookFailure(repoRoot string, continuation *config.ContinuationState, hookErr error, splog *tui.Splog) error {
+	continuation.PostRestackHookFailed = true
+	if err := config.PersistContinuationState(repoRoot, continuation); err != nil {
+		return fmt.Errorf("failed to persist continuation: %w", err)
+	}
+	splog.Info("Fix the problem on %s, then run %s to restack the remaining branches (or %s).",
+		style.ColorBranchName(continuation.CurrentBranchOverride, false),
+		style.ColorCyan("stackit continue"), style.ColorCyan("stackit abort"))
+	return fmt.Errorf("restack stopped: %w", hookErr)
+}
+
+// RestackBranchesWithSummary is like RestackBranches, but reports every branch's outcome
+// in a single summary table instead of a line per branch. The table is also printed when
+// a conflict stops the restack, before the conflict status.
+func RestackBranchesWithSummary(ctx context.Context, branches []engine.Branch, eng Restacker, splog *tui.Splog, repoRoot string) error {
+	return restackBranches(ctx, branches, eng, splog, repoRoot, true)
+}
+
+func restackBranches(ctx context.Context, branches []engine.Branch, eng Restacker, splog *tui.Splog, repoRoot string, summary bool) error {
 	batchResult, err := eng.RestackBranches(ctx, branches)
+	if summary {
+		PrintRestackSummary(splog, RestackOutcomes(branches, batchResult))
+	}
 	if err != nil {
+		if batchResult.HookFailedBranch != "" {
+			return persistPostRestackHookFailure(repoRoot, &config.ContinuationState{
+				BranchesToRestack:     batchResult.RemainingBranches,
+				CurrentBranchOverride: batchResult.HookFailedBranch,
+			}, err, splog)
+		}
 		if batchResult.ConflictBranch != "" {
 			continuation := &config.ContinuationState{
 				BranchesToRestack:     batchResult.RemainingBranches,
@@ -54,7 +89,18 @@ func RestackBranches(ctx context.Context, branches []engine.Branch, eng Restacke
 			return fmt.Errorf("failed to print conflict status: %w", err)
 		}
 
-		return fmt.Errorf("restack stopped due to conflict on %s", batchResult.ConflictBranch)
+		return stackiterrors.WithCategory(stackiterrors.ErrRebaseConflict, fmt.Errorf("restack stopped due to conflict on %s", batchResult.ConflictBranch))
+	}
+
+	for _, outcome := range RestackOutcomes(branches, batchResult) {
+		if outcome.RemoteOnlyParent != "" {
+			splog.Warn("Didn't restack %s: its parent %s was deleted locally but is still on the remote. Restore it with 'git checkout %s', or move %s elsewhere with 'stackit move'.",
+				outcome.BranchName, outcome.RemoteOnlyParent, outcome.RemoteOnlyParent, outcome.BranchName)
+		}
+	}
+
+	if summary {
+		return nil
 	}
 
 	currentBranch := eng.CurrentBranch()
@@ -66,8 +112,8 @@ func RestackBranches(ctx context.Context, branches []engine.Branch, eng Restacke
 	for _, branch := range branches {
 		branchName := branch.GetName()
 		result, exists := batchResult.Results[branchName]
-		if !exists {
-			continue // Skip branches not processed (e.g., trunk)
+		if !exists || result.RemoteOnlyParent != "" {
+			continue // Skip branches not processed (e.g., trunk) and those warned about above
 		}
 
 		if result.Reparented {
@@ -91,6 +137,10 @@ func RestackBranches(ctx context.Context, branches []engine.Branch, eng Restacke
 			splog.Info("Restacked %s on %s.",
 				style.ColorBranchName(branchName, isCurrent),
 				style.ColorBranchName(parentName, false))
+		case engine.RestackPruned:
+			splog.Info("Deleted %s, which has no changes on top of %s.",
+				style.ColorBranchName(branchName, false),
+				style.ColorBranchName(result.NewParent, false))
 		case engine.RestackConflict:
 			// This should not happen since conflicts are handled at the batch level
 			return fmt.Errorf("unexpected conflict in batch result for branch %s", branchName)
@@ -124,12 +174,21 @@ func PluralSuffix(plural bool) string {
 	return ""
 }
 
-// Pluralize returns the word with "ren" suffix if count != 1 (specific to "child" -> "children")
+// Pluralize returns the plural of a noun if count != 1, e.g. "child" -> "children",
+// "branch" -> "branches", "commit" -> "commits"
 func Pluralize(word string, count int) string {
 	if count == 1 {
 		return word
 	}
-	return word + "ren" // "child" -> "children"
+	switch {
+	case word == "child":
+		return "children"
+	case strings.HasSuffix(word, "s"), strings.HasSuffix(word, "x"),
+		strings.HasSuffix(word, "ch"), strings.HasSuffix(word, "sh"):
+		return word + "es"
+	default:
+		return word + "s"
+	}
 }
 
 // ShouldDeleteBranch checks if a branch should be deleted
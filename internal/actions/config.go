@@ -27,9 +27,70 @@ func ConfigListAction(repoRoot string) error {
 	// Get branch name pattern
 	branchPattern := cfg.BranchNamePattern()
 
+	// Get branch.onCollision
+	branchOnCollision := cfg.BranchOnCollision()
+
 	// Get submit.footer
 	submitFooter := cfg.SubmitFooter()
 
+	// Get submit.footerMode
+	submitFooterMode := cfg.SubmitFooterMode()
+
+	// Get submit.skipHooks
+	submitSkipHooks := cfg.SubmitSkipHooks()
+
+	// Get submit.maxPrs
+	submitMaxPRs := cfg.SubmitMaxPRs()
+
+	// Get submit.concurrency
+	submitConcurrency := cfg.SubmitConcurrency()
+
+	// Get submit.draftDefault
+	submitDraftDefault := cfg.SubmitDraftDefault()
+
+	// Get submit.wipPattern
+	submitWIPPattern := cfg.SubmitWIPPattern()
+
+	// Get submit.stackLabel
+	submitStackLabel := cfg.SubmitStackLabel()
+
+	// Get push.setUpstream
+	pushSetUpstream := cfg.PushSetUpstream()
+
+	// Get restack.strategy
+	restackStrategy := cfg.RestackStrategy()
+
+	// Get restack.preserveDates
+	restackPreserveDates := cfg.RestackPreserveDates()
+
+	// Get restack.pruneEmpty
+	restackPruneEmpty := cfg.RestackPruneEmpty()
+
+	// Get restack.postHook
+	restackPostHook := cfg.RestackPostHook()
+
+	// Get checkout.autostash
+	checkoutAutostash := cfg.CheckoutAutostash()
+
+	// Get commit.trailers
+	commitTrailers := cfg.CommitTrailers()
+
+	// Get absorb.newFileMode
+	absorbNewFileMode := cfg.AbsorbNewFileMode()
+
+	// Get sync.trunkStrategy
+	syncTrunkStrategy := cfg.SyncTrunkStrategy()
+
+	// Get merge.strategy
+	mergeStrategy := cfg.MergeStrategy()
+
+	// Get merge.requireCleanStatus
+	mergeRequireCleanStatus := cfg.MergeRequireCleanStatus()
+
+	// Get git.timeout.local and git.timeout.network
+	gitTimeoutLocal := cfg.GitTimeoutLocal()
+	gitTimeoutNetwork := cfg.GitTimeoutNetwork()
+
 	// Format and print
 	var lines []string
 	lines = append(lines, fmt.Sprintf("%s: %s", style.ColorCyan("trunk"), trunk))
@@ -47,7 +108,28 @@ func ConfigListAction(repoRoot string) error {
 	}
 
 	lines = append(lines, fmt.Sprintf("%s: %s", style.ColorCyan("branch.pattern"), branchPattern))
+	lines = append(lines, fmt.Sprintf("%s: %s", style.ColorCyan("branch.onCollision"), branchOnCollision))
 	lines = append(lines, fmt.Sprintf("%s: %v", style.ColorCyan("submit.footer"), submitFooter))
+	lines = append(lines, fmt.Sprintf("%s: %s", style.ColorCyan("submit.footerMode"), submitFooterMode))
+	lines = append(lines, fmt.Sprintf("%s: %v", style.ColorCyan("submit.skipHooks"), submitSkipHooks))
+	lines = append(lines, fmt.Sprintf("%s: %d", style.ColorCyan("submit.maxPrs"), submitMaxPRs))
+	lines = append(lines, fmt.Sprintf("%s: %d", style.ColorCyan("submit.concurrency"), submitConcurrency))
+	lines = append(lines, fmt.Sprintf("%s: %v", style.ColorCyan("submit.draftDefault"), submitDraftDefault))
+	lines = append(lines, fmt.Sprintf("%s: %s", style.ColorCyan("submit.wipPattern"), submitWIPPattern))
+	lines = append(lines, fmt.Sprintf("%s: %s", style.ColorCyan("submit.stackLabel"), submitStackLabel))
+	lines = append(lines, fmt.Sprintf("%s: %v", style.ColorCyan("push.setUpstream"), pushSetUpstream))
+	lines = append(lines, fmt.Sprintf("%s: %s", style.ColorCyan("restack.strategy"), restackStrategy))
+	lines = append(lines, fmt.Sprintf("%s: %v", style.ColorCyan("restack.preserveDates"), restackPreserveDates))
+	lines = append(lines, fmt.Sprintf("%s: %s", style.ColorCyan("restack.pruneEmpty"), restackPruneEmpty))
+	lines = append(lines, fmt.Sprintf("%s: %s", style.ColorCyan("restack.postHook"), restackPostHook))
+	lines = append(lines, fmt.Sprintf("%s: %v", style.ColorCyan("checkout.autostash"), checkoutAutostash))
+	lines = append(lines, fmt.Sprintf("%s: %s", style.ColorCyan("commit.trailers"), strings.Join(commitTrailers, ", ")))
+	lines = append(lines, fmt.Sprintf("%s: %s", style.ColorCyan("absorb.newFileMode"), absorbNewFileMode))
+	lines = append(lines, fmt.Sprintf("%s: %s", style.ColorCyan("sync.trunkStrategy"), syncTrunkStrategy))
+	lines = append(lines, fmt.Sprintf("%s: %s", style.ColorCyan("merge.strategy"), mergeStrategy))
+	lines = append(lines, fmt.Sprintf("%s: %v", style.ColorCyan("merge.requireCleanStatus"), mergeRequireCleanStatus))
+	lines = append(lines, fmt.Sprintf("%s: %s", style.ColorCyan("git.timeout.local"), gitTimeoutLocal))
+	lines = append(lines, fmt.Sprintf("%s: %s", style.ColorCyan("git.timeout.network"), gitTimeoutNetwork))
 
 	splog.Page(strings.Join(lines, "\n"))
 	splog.Newline()
@@ -1,10 +1,12 @@
 package actions
 
 import (
+	"errors"
 	"fmt"
 
 	"stackit.dev/stackit/internal/config"
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/git"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui/style"
@@ -20,8 +22,12 @@ func ContinueAction(ctx *runtime.Context, opts ContinueOptions) error {
 	eng := ctx.Engine
 	splog := ctx.Splog
 
-	// Check if rebase is in progress
-	if !git.IsRebaseInProgress(ctx.Context) {
+	// Check if rebase (or a merge from the merge restack strategy) is in progress
+	if !git.IsRebaseInProgress(ctx.Context) && !git.IsMergeInProgress(ctx.Context) {
+		// A failed post-restack hook stops between branches, so no rebase is left in progress
+		if continuation, err := config.GetContinuationState(ctx.RepoRoot); err == nil && continuation.PostRestackHookFailed {
+			return resumeAfterPostRestackHook(ctx, continuation)
+		}
 		// Clear any stale continuation state
 		_ = config.ClearContinuationState(ctx.RepoRoot)
 		return fmt.Errorf("no rebase in progress. Nothing to continue")
@@ -37,7 +43,7 @@ func ContinueAction(ctx *runtime.Context, opts ContinueOptions) error {
 		// But we need a rebasedBranchBase - try to get it from current branch's parent
 		currentBranch := eng.CurrentBranch()
 		if currentBranch == nil {
-			return fmt.Errorf("not on a branch")
+			return stackiterrors.ErrNotOnBranch
 		}
 		parent := eng.GetParent(*currentBranch)
 		parentName := ""
@@ -67,6 +73,10 @@ func ContinueAction(ctx *runtime.Context, opts ContinueOptions) error {
 
 	// Continue the rebase
 	result, err := eng.ContinueRebase(ctx.Context, continuation.CurrentBranchOverride, continuation.RebasedBranchBase)
+	var hookErr *stackiterrors.PostRestackHookError
+	if errors.As(err, &hookErr) {
+		return persistPostRestackHookFailure(ctx.RepoRoot, continuation, err, splog)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to continue rebase: %w", err)
 	}
@@ -82,19 +92,35 @@ func ContinueAction(ctx *runtime.Context, opts ContinueOptions) error {
 		if branchName == "" {
 			currentBranch := eng.CurrentBranch()
 			if currentBranch == nil {
-				return fmt.Errorf("not on a branch")
+				return stackiterrors.ErrNotOnBranch
 			}
 			branchName = currentBranch.GetName()
 		}
 		if err := PrintConflictStatus(ctx.Context, branchName, splog); err != nil {
 			return fmt.Errorf("failed to print conflict status: %w", err)
 		}
-		return fmt.Errorf("rebase conflict is not yet resolved")
+		return stackiterrors.WithCategory(stackiterrors.ErrRebaseConflict, fmt.Errorf("rebase conflict is not yet resolved"))
 	}
 
 	// Success - inform user
 	splog.Info("Resolved rebase conflict for %s.", style.ColorBranchName(result.BranchName, true))
 
+	return restackRemainingBranches(ctx, continuation)
+}
+
+// resumeAfterPostRestackHook continues a restack that stopped because the post-restack hook
+// failed. The branch the hook failed on was already restacked, so only the rest are left.
+func resumeAfterPostRestackHook(ctx *runtime.Context, continuation *config.ContinuationState) error {
+	ctx.Splog.Info("Resuming restack after the post-restack hook failed on %s.",
+		style.ColorBranchName(continuation.CurrentBranchOverride, false))
+	return restackRemainingBranches(ctx, continuation)
+}
+
+// restackRemainingBranches restacks the branches a continuation left to do, then clears it
+func restackRemainingBranches(ctx *runtime.Context, continuation *config.ContinuationState) error {
+	eng := ctx.Engine
+	splog := ctx.Splog
+
 	// Continue with remaining branches to restack
 	if len(continuation.BranchesToRestack) > 0 {
 		// Convert []string to []Branch for RestackBranches
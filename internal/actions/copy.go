@@ -0,0 +1,73 @@
+package actions
+
+import (
+	"fmt"
+
+	"stackit.dev/stackit/internal/engine"
+	"stackit.dev/stackit/internal/runtime"
+	"stackit.dev/stackit/internal/tui/style"
+	"stackit.dev/stackit/internal/utils"
+)
+
+// CopyOptions contains options for the branch copy command
+type CopyOptions struct {
+	NewName string
+}
+
+// CopyAction creates a branch at the current branch's tip and checks it out, tracking it
+// on the same parent with the same scope. The PR info isn't copied, so the copy gets a PR
+// of its own when it's submitted.
+func CopyAction(ctx *runtime.Context, opts CopyOptions) error {
+	eng := ctx.Engine
+	splog := ctx.Splog
+
+	currentBranch, err := utils.ValidateOnBranch(eng)
+	if err != nil {
+		return err
+	}
+	source := eng.GetBranch(currentBranch)
+	if source.IsTrunk() {
+		return fmt.Errorf("cannot copy trunk branch %s", currentBranch)
+	}
+	if !source.IsTracked() {
+		return fmt.Errorf("branch %s is not tracked; track it with stackit track first", currentBranch)
+	}
+
+	newName := utils.SanitizeBranchName(opts.NewName)
+	if newName == "" {
+		return fmt.Errorf("invalid branch name")
+	}
+	if eng.BranchExistsLocally(newName) {
+		return fmt.Errorf("branch %s already exists", newName)
+	}
+
+	sourceMeta, err := eng.ReadMetadataRef(currentBranch)
+	if err != nil {
+		return fmt.Errorf("failed to read metadata for %s: %w", currentBranch, err)
+	}
+
+	snapshotOpts := NewSnapshot("branch-copy", WithArg(newName))
+	if err := eng.TakeSnapshot(snapshotOpts); err != nil {
+		splog.Debug("Failed to take snapshot: %v", err)
+	}
+
+	newBranch := eng.GetBranch(newName)
+	if err := eng.CreateAndCheckoutBranch(ctx.Context, newBranch); err != nil {
+		return fmt.Errorf("failed to create branch: %w", err)
+	}
+
+	meta := &engine.Meta{
+		ParentBranchName:     sourceMeta.ParentBranchName,
+		ParentBranchRevision: sourceMeta.ParentBranchRevision,
+		Scope:                sourceMeta.Scope,
+	}
+	if err := eng.WriteMetadataRef(newBranch, meta); err != nil {
+		return fmt.Errorf("failed to write metadata for %s: %w", newName, err)
+	}
+	if err := eng.RebuildBranches(newName); err != nil {
+		return err
+	}
+
+	splog.Info("Copied %s to %s.", style.ColorBranchName(currentBranch, false), style.ColorBranchName(newName, true))
+	return nil
+}
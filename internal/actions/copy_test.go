@@ -0,0 +1,68 @@
+package actions_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/engine"
+	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
+)
+
+func TestCopyAction(t *testing.T) {
+	t.Run("copies the parent and scope but not the PR info", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "branch1",
+			})
+		branch2 := s.Engine.GetBranch("branch2")
+		require.NoError(t, s.Engine.SetScope(branch2, engine.NewScope("PROJ-1")))
+		require.NoError(t, s.Engine.UpsertPrInfo(branch2, testhelpers.NewTestPrInfoWithTitle(7, "branch2")))
+		s.Checkout("branch2")
+
+		require.NoError(t, actions.CopyAction(s.Context, actions.CopyOptions{NewName: "branch2-alt"}))
+
+		s.ExpectBranch("branch2-alt").
+			ExpectStackStructure(map[string]string{"branch1": "main", "branch2": "branch1", "branch2-alt": "branch1"})
+
+		sourceMeta, err := s.Engine.ReadMetadataRef("branch2")
+		require.NoError(t, err)
+		copyMeta, err := s.Engine.ReadMetadataRef("branch2-alt")
+		require.NoError(t, err)
+		require.Equal(t, sourceMeta.ParentBranchRevision, copyMeta.ParentBranchRevision)
+		require.Equal(t, "PROJ-1", s.Engine.GetExplicitScopeInternal("branch2-alt").String())
+		require.Nil(t, copyMeta.PrInfo)
+
+		prInfo, err := s.Engine.GetPrInfo(s.Engine.GetBranch("branch2-alt"))
+		require.NoError(t, err)
+		require.Nil(t, prInfo)
+
+		sourceRev, err := s.Engine.GetBranch("branch2").GetRevision()
+		require.NoError(t, err)
+		copyRev, err := s.Engine.GetBranch("branch2-alt").GetRevision()
+		require.NoError(t, err)
+		require.Equal(t, sourceRev, copyRev)
+	})
+
+	t.Run("refuses an existing branch name", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "main",
+			})
+		s.Checkout("branch1")
+
+		err := actions.CopyAction(s.Context, actions.CopyOptions{NewName: "branch2"})
+		require.ErrorContains(t, err, "branch branch2 already exists")
+	})
+
+	t.Run("refuses to copy trunk", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+
+		err := actions.CopyAction(s.Context, actions.CopyOptions{NewName: "main-copy"})
+		require.ErrorContains(t, err, "cannot copy trunk branch main")
+	})
+}
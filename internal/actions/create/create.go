@@ -2,10 +2,13 @@ package create
 
 import (
 	"fmt"
+	"slices"
 
 	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/config"
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
+	"stackit.dev/stackit/internal/git"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui"
 	"stackit.dev/stackit/internal/utils"
@@ -18,13 +21,21 @@ type Options struct {
 	Scope         string
 	All           bool
 	Insert        bool
+	Before        bool // Create the branch below the current branch, between it and its parent
 	Patch         bool
 	Update        bool
 	Verbose       int
 	BranchPattern config.BranchPattern
+	// SuffixOnCollision appends -2, -3, ... to a generated branch name that is already
+	// taken instead of failing. Names given explicitly always fail.
+	SuffixOnCollision bool
 	// SelectedChildren is used to specify which children to move during insert
 	// in non-interactive mode (mostly for tests)
 	SelectedChildren []string
+	// TrailerTemplates are commit.trailers templates, whose placeholders are expanded
+	TrailerTemplates []string
+	// Trailers are --trailer flags, as "Key: value", added as given
+	Trailers []string
 }
 
 // Action creates a new branch stacked on top of the current branch
@@ -32,12 +43,30 @@ func Action(ctx *runtime.Context, opts Options) error {
 	eng := ctx.Engine
 	splog := ctx.Splog
 
+	if opts.Insert && opts.Before {
+		return stackiterrors.NewValidationError("can't use both --insert and --before")
+	}
+
 	// Get current branch
 	currentBranch, err := utils.ValidateOnBranch(ctx.Engine)
 	if err != nil {
 		return err
 	}
 
+	// The new branch is stacked on the current branch, or with --before on its parent
+	parentBranch := currentBranch
+	if opts.Before {
+		current := eng.GetBranch(currentBranch)
+		if current.IsTrunk() {
+			return stackiterrors.NewValidationError("can't create a branch before trunk")
+		}
+		parent := eng.GetParent(current)
+		if parent == nil {
+			return stackiterrors.NewValidationError("%s is not tracked; track it before creating a branch before it", currentBranch)
+		}
+		parentBranch = parent.GetName()
+	}
+
 	// Take snapshot before modifying the repository
 	snapshotOpts := actions.NewSnapshot("create",
 		actions.WithArg(opts.BranchName),
@@ -45,6 +74,7 @@ func Action(ctx *runtime.Context, opts Options) error {
 		actions.WithFlagValue("--scope", opts.Scope),
 		actions.WithFlag(opts.All, "--all"),
 		actions.WithFlag(opts.Insert, "--insert"),
+		actions.WithFlag(opts.Before, "--before"),
 		actions.WithFlag(opts.Patch, "--patch"),
 		actions.WithFlag(opts.Update, "--update"),
 	)
@@ -101,7 +131,7 @@ func Action(ctx *runtime.Context, opts Options) error {
 	if opts.Scope != "" {
 		scopeToUse = opts.Scope
 	} else {
-		parentScope := eng.GetScopeInternal(currentBranch)
+		parentScope := eng.GetScopeInternal(parentBranch)
 		scopeToUse = parentScope.String()
 	}
 	branch, err := determineBranch(ctx, &opts, commitMessage, scopeToUse)
@@ -118,6 +148,14 @@ func Action(ctx *runtime.Context, opts Options) error {
 		}
 	}
 
+	// Start from the parent when creating the branch below the current one; staged
+	// changes come along
+	if parentBranch != currentBranch {
+		if err := eng.CheckoutBranch(ctx.Context, eng.GetBranch(parentBranch)); err != nil {
+			return fmt.Errorf("failed to check out %s to create the branch on: %w", parentBranch, err)
+		}
+	}
+
 	// Create and checkout new branch
 	if err := eng.CreateAndCheckoutBranch(ctx.Context, branch); err != nil {
 		return fmt.Errorf("failed to create branch: %w", err)
@@ -125,7 +163,11 @@ func Action(ctx *runtime.Context, opts Options) error {
 
 	// Commit if there are staged changes
 	if hasStaged {
-		if err := eng.Commit(ctx.Context, commitMessage, opts.Verbose); err != nil {
+		if err := eng.CommitWithOptions(ctx.Context, git.CommitOptions{
+			Message:  commitMessage,
+			Verbose:  opts.Verbose,
+			Trailers: slices.Concat(actions.ExpandCommitTrailers(ctx.Context, opts.TrailerTemplates, branchName), opts.Trailers),
+		}); err != nil {
 			// Clean up branch on commit failure
 			_ = eng.DeleteBranch(ctx.Context, branch)
 			return fmt.Errorf("failed to commit: %w", err)
@@ -134,8 +176,8 @@ func Action(ctx *runtime.Context, opts Options) error {
 		splog.Info("No staged changes; created a branch with no commit.")
 	}
 
-	// Track the branch with current branch as parent
-	if err := eng.TrackBranch(ctx.Context, branchName, currentBranch); err != nil {
+	// Track the branch with current branch (or its parent, for --before) as parent
+	if err := eng.TrackBranch(ctx.Context, branchName, parentBranch); err != nil {
 		// Log error but don't fail - branch is created, just not tracked
 		splog.Info("Warning: failed to track branch: %v", err)
 	}
@@ -151,11 +193,16 @@ func Action(ctx *runtime.Context, opts Options) error {
 	// If no scope provided, don't set anything - it will inherit from parent automatically
 
 	// Handle insert logic
-	if opts.Insert {
+	switch {
+	case opts.Insert:
 		if err := handleInsert(ctx.Context, branchName, currentBranch, ctx, &opts); err != nil {
 			splog.Info("Warning: failed to insert branch: %v", err)
 		}
-	} else {
+	case opts.Before:
+		if err := handleBefore(ctx.Context, branchName, currentBranch, ctx); err != nil {
+			splog.Info("Warning: failed to insert branch: %v", err)
+		}
+	default:
 		// Check if current branch has children and show tip
 		currentBranchObj := eng.GetBranch(currentBranch)
 		children := currentBranchObj.GetChildren()
@@ -185,6 +232,9 @@ func determineBranch(ctx *runtime.Context, opts *Options, commitMessage string,
 		if err != nil {
 			return engine.Branch{}, err
 		}
+		if opts.SuffixOnCollision {
+			branchName = nextFreeBranchName(ctx.Engine, branchName)
+		}
 	} else {
 		// Sanitize provided branch name
 		branchName = utils.SanitizeBranchName(branchName)
@@ -192,3 +242,18 @@ func determineBranch(ctx *runtime.Context, opts *Options, commitMessage string,
 
 	return ctx.Engine.GetBranch(branchName), nil
 }
+
+// nextFreeBranchName returns branchName, or the first of branchName-2, branchName-3, ...
+// that no existing branch uses
+func nextFreeBranchName(eng engine.Engine, branchName string) string {
+	taken := make(map[string]bool)
+	for _, branch := range eng.AllBranches() {
+		taken[branch.GetName()] = true
+	}
+
+	candidate := branchName
+	for n := 2; taken[candidate]; n++ {
+		candidate = fmt.Sprintf("%s-%d", branchName, n)
+	}
+	return candidate
+}
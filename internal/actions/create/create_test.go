@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"stackit.dev/stackit/internal/config"
 	"stackit.dev/stackit/testhelpers"
 	"stackit.dev/stackit/testhelpers/scenario"
 )
@@ -262,3 +263,94 @@ func TestCreateAction_Insert(t *testing.T) {
 		require.False(t, isAncestor, "inserted should NOT be an ancestor of child2")
 	})
 }
+
+func TestCreateAction_Before(t *testing.T) {
+	t.Run("inserts branch below a mid-stack branch", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"bottom": "main",
+				"middle": "bottom",
+				"top":    "middle",
+			})
+		s.Checkout("middle")
+
+		// The prerequisite's change is staged while on middle
+		require.NoError(t, s.Scene.Repo.CreateChange("prerequisite content", "prereq", false))
+		require.NoError(t, s.Scene.Repo.RunGitCommand("add", "."))
+		require.NoError(t, Action(s.Context, Options{
+			BranchName: "prereq",
+			Message:    "Add prerequisite",
+			Before:     true,
+		}))
+
+		// main -> bottom -> prereq -> middle -> top
+		s.ExpectStackStructure(map[string]string{
+			"bottom": "main",
+			"prereq": "bottom",
+			"middle": "prereq",
+			"top":    "middle",
+		})
+		currentBranch, err := s.Scene.Repo.CurrentBranchName()
+		require.NoError(t, err)
+		require.Equal(t, "prereq", currentBranch)
+
+		for _, pair := range [][2]string{{"bottom", "prereq"}, {"prereq", "middle"}, {"middle", "top"}} {
+			isAncestor, err := s.Scene.Repo.IsAncestor(pair[0], pair[1])
+			require.NoError(t, err)
+			require.True(t, isAncestor, "%s should be an ancestor of %s", pair[0], pair[1])
+		}
+		commits, err := s.Scene.Repo.RunGitCommandAndGetOutput("log", "--format=%s", "bottom..prereq")
+		require.NoError(t, err)
+		require.Equal(t, "Add prerequisite", commits)
+		s.ExpectBranchFixed("middle").ExpectBranchFixed("top")
+	})
+
+	t.Run("rejects trunk and --insert", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+
+		err := Action(s.Context, Options{BranchName: "before-main", Before: true})
+		require.ErrorContains(t, err, "can't create a branch before trunk")
+
+		err = Action(s.Context, Options{BranchName: "both", Before: true, Insert: true})
+		require.ErrorContains(t, err, "can't use both --insert and --before")
+	})
+}
+
+func TestCreateAction_NameCollision(t *testing.T) {
+	pattern, err := config.NewBranchPattern("{message}")
+	require.NoError(t, err)
+
+	t.Run("suffixes a generated name that is taken", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		opts := Options{Message: "add feature", BranchPattern: pattern, SuffixOnCollision: true}
+
+		require.NoError(t, Action(s.Context, opts))
+		s.Checkout("main")
+		require.NoError(t, Action(s.Context, opts))
+		s.ExpectBranch("add-feature-2")
+		s.Checkout("main")
+		require.NoError(t, Action(s.Context, opts))
+		s.ExpectBranch("add-feature-3")
+		require.Equal(t, "main", s.Engine.GetParent(s.Engine.GetBranch("add-feature-3")).GetName())
+	})
+
+	t.Run("errors on a taken generated name by default", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		opts := Options{Message: "add feature", BranchPattern: pattern}
+
+		require.NoError(t, Action(s.Context, opts))
+		s.Checkout("main")
+		require.EqualError(t, Action(s.Context, opts), "branch add-feature already exists")
+	})
+
+	t.Run("errors on a taken explicit name even when suffixing", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"add-feature": "main",
+			})
+		s.Checkout("main")
+
+		err := Action(s.Context, Options{BranchName: "add-feature", Message: "add feature", BranchPattern: pattern, SuffixOnCollision: true})
+		require.EqualError(t, err, "branch add-feature already exists")
+	})
+}
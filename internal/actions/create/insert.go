@@ -61,13 +61,27 @@ func handleInsert(ctx context.Context, newBranch, currentBranch string, runtimeC
 		toMove = siblings
 	}
 
+	return moveOnto(ctx, newBranch, toMove, runtimeCtx)
+}
+
+// handleBefore moves the branch the new branch was created below onto it, so the new
+// branch sits between that branch and its old parent
+func handleBefore(ctx context.Context, newBranch, currentBranch string, runtimeCtx *runtime.Context) error {
+	return moveOnto(ctx, newBranch, []string{currentBranch}, runtimeCtx)
+}
+
+// moveOnto reparents branches onto the new branch and restacks them, and the branches
+// above them, onto it
+func moveOnto(ctx context.Context, newBranch string, toMove []string, runtimeCtx *runtime.Context) error {
 	// Update parent for each child to move
 	branchesToRestack := make([]engine.Branch, 0, len(toMove))
 	for _, child := range toMove {
-		if err := runtimeCtx.Engine.TrackBranch(ctx, child, newBranch); err != nil {
+		if err := runtimeCtx.Engine.ForceTrackBranch(ctx, child, newBranch); err != nil {
 			return fmt.Errorf("failed to update parent for %s: %w", child, err)
 		}
-		branchesToRestack = append(branchesToRestack, runtimeCtx.Engine.GetBranch(child))
+		branch := runtimeCtx.Engine.GetBranch(child)
+		branchesToRestack = append(branchesToRestack, branch)
+		branchesToRestack = append(branchesToRestack, runtimeCtx.Engine.GetRelativeStackUpstack(branch)...)
 	}
 
 	// Restack children onto the new branch to physically insert it
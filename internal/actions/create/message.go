@@ -40,7 +40,7 @@ func getCommitMessageForBranch(ctx *runtime.Context, opts *Options, commitMessag
 			return stdinMsg, nil
 		}
 
-		if !utils.IsInteractive() {
+		if !utils.IsInteractive() || tui.EditorDisabled() {
 			return "", fmt.Errorf("must specify either a branch name or commit message")
 		}
 
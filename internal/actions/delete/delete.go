@@ -3,10 +3,14 @@ package delete
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/runtime"
+	"stackit.dev/stackit/internal/tui"
 	"stackit.dev/stackit/internal/tui/style"
 )
 
@@ -16,6 +20,7 @@ type Options struct {
 	Downstack  bool
 	Force      bool
 	Upstack    bool
+	Stack      bool // Delete the branch and every descendant, instead of rehoming the children
 }
 
 // Action deletes a branch and its metadata
@@ -23,6 +28,11 @@ func Action(ctx *runtime.Context, opts Options) error {
 	eng := ctx.Engine
 	splog := ctx.Splog
 
+	// --stack already takes every descendant; it has no downstack form
+	if opts.Stack && (opts.Upstack || opts.Downstack) {
+		return stackiterrors.NewValidationError("--stack can't be combined with --upstack or --downstack")
+	}
+
 	branchName := opts.BranchName
 	if branchName == "" {
 		currentBranch := eng.CurrentBranch()
@@ -45,6 +55,10 @@ func Action(ctx *runtime.Context, opts Options) error {
 		return fmt.Errorf("branch %s is not tracked by stackit", branchName)
 	}
 
+	if opts.Stack {
+		return deleteStack(ctx, branch, opts.Force)
+	}
+
 	// Determine branches to delete
 	toDelete := []engine.Branch{branch}
 
@@ -108,3 +122,55 @@ func Action(ctx *runtime.Context, opts Options) error {
 
 	return nil
 }
+
+// deleteStack deletes branch and its whole subtree. If the current branch is inside the
+// subtree, the branch's parent (or trunk) is checked out first.
+func deleteStack(ctx *runtime.Context, branch engine.Branch, force bool) error {
+	eng := ctx.Engine
+	splog := ctx.Splog
+
+	subtree := []engine.Branch{}
+	inSubtree := make(map[string]bool)
+	for b := range eng.BranchesDepthFirst(branch) {
+		subtree = append(subtree, b)
+		inSubtree[b.GetName()] = true
+	}
+
+	if !force {
+		names := make([]string, len(subtree))
+		for i, b := range subtree {
+			names[i] = style.ColorBranchName(b.GetName(), false)
+		}
+		msg := fmt.Sprintf("Delete %d branch%s (%s)?", len(names), actions.PluralSuffix(len(names) != 1), strings.Join(names, ", "))
+		confirmed, err := tui.PromptConfirm(msg, false)
+		if err != nil {
+			return fmt.Errorf("failed to get confirmation: %w", err)
+		}
+		if !confirmed {
+			splog.Info("Delete canceled.")
+			return nil
+		}
+	}
+
+	if current := eng.CurrentBranch(); current != nil && inSubtree[current.GetName()] {
+		safeBranch := eng.Trunk()
+		if parent := eng.GetParent(branch); parent != nil {
+			safeBranch = *parent
+		}
+		if err := eng.CheckoutBranch(ctx.Context, safeBranch); err != nil {
+			return fmt.Errorf("failed to check out %s: %w", safeBranch.GetName(), err)
+		}
+		splog.Info("Checked out %s.", style.ColorBranchName(safeBranch.GetName(), true))
+	}
+
+	// Delete children before their parents so nothing is rehomed along the way
+	slices.Reverse(subtree)
+	if _, err := eng.DeleteBranches(ctx.Context, subtree); err != nil {
+		return err
+	}
+	for _, b := range subtree {
+		splog.Info("Deleted branch %s", style.ColorBranchName(b.GetName(), false))
+	}
+
+	return nil
+}
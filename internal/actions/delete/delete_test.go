@@ -1,6 +1,7 @@
 package delete
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -142,4 +143,73 @@ func TestDelete(t *testing.T) {
 		require.NotNil(t, parent2)
 		require.Equal(t, "main", parent2.GetName())
 	})
+
+	t.Run("deletes a subtree with --stack and checks out its parent", func(t *testing.T) {
+		s := scenario.NewScenario(t, nil).
+			WithStack(map[string]string{
+				"base":        "main",
+				"top":         "base",
+				"mid":         "top",
+				"leaf":        "mid",
+				"mid-sibling": "top",
+			})
+		s.Checkout("leaf")
+
+		err := Action(s.Context, Options{
+			BranchName: "top",
+			Stack:      true,
+			Force:      true,
+		})
+		require.NoError(t, err)
+
+		for _, name := range []string{"top", "mid", "leaf", "mid-sibling"} {
+			require.False(t, s.Engine.GetBranch(name).IsTracked(), name)
+			_, err := s.Scene.Repo.RunGitCommandAndGetOutput("rev-parse", "--verify", "--quiet", "refs/stackit/metadata/"+name)
+			require.Error(t, err, "metadata ref for %s should be gone", name)
+			_, err = s.Scene.Repo.RunGitCommandAndGetOutput("rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+			require.Error(t, err, "branch %s should be gone", name)
+		}
+
+		// The rest of the stack is untouched and HEAD moved to the subtree's parent
+		require.True(t, s.Engine.GetBranch("base").IsTracked())
+		currentBranch := s.Engine.CurrentBranch()
+		require.NotNil(t, currentBranch)
+		require.Equal(t, "base", currentBranch.GetName())
+		head, err := s.Scene.Repo.RunGitCommandAndGetOutput("rev-parse", "--abbrev-ref", "HEAD")
+		require.NoError(t, err)
+		require.Equal(t, "base", strings.TrimSpace(head))
+	})
+
+	t.Run("refuses to combine --stack with --upstack or --downstack", func(t *testing.T) {
+		s := scenario.NewScenario(t, nil).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "branch1",
+			})
+
+		for _, opts := range []Options{
+			{BranchName: "branch1", Stack: true, Upstack: true, Force: true},
+			{BranchName: "branch2", Stack: true, Downstack: true, Force: true},
+		} {
+			err := Action(s.Context, opts)
+			require.ErrorContains(t, err, "--stack can't be combined with --upstack or --downstack")
+		}
+		require.True(t, s.Engine.GetBranch("branch1").IsTracked())
+		require.True(t, s.Engine.GetBranch("branch2").IsTracked())
+	})
+
+	t.Run("refuses to delete trunk with --stack", func(t *testing.T) {
+		s := scenario.NewScenario(t, nil).
+			WithStack(map[string]string{
+				"branch1": "main",
+			})
+
+		err := Action(s.Context, Options{
+			BranchName: "main",
+			Stack:      true,
+			Force:      true,
+		})
+		require.ErrorContains(t, err, "cannot delete trunk branch main")
+		require.True(t, s.Engine.GetBranch("branch1").IsTracked())
+	})
 }
@@ -0,0 +1,64 @@
+package actions
+
+import (
+	"fmt"
+
+	stackiterrors "stackit.dev/stackit/internal/errors"
+	"stackit.dev/stackit/internal/git"
+	"stackit.dev/stackit/internal/runtime"
+)
+
+// DiffOptions contains options for the diff command
+type DiffOptions struct {
+	BranchName string
+	Stat       bool
+	NameOnly   bool
+}
+
+// DiffAction prints the changes a single branch introduces on top of its parent.
+// The diff is taken from the branch's stored parent revision rather than the parent's
+// current tip, so it shows only the branch's own changes even when it needs restacking.
+func DiffAction(ctx *runtime.Context, opts DiffOptions) error {
+	eng := ctx.Engine
+	splog := ctx.Splog
+
+	branchName := opts.BranchName
+	if branchName == "" {
+		currentBranch := eng.CurrentBranch()
+		if currentBranch == nil {
+			return fmt.Errorf("%w and no branch specified", stackiterrors.ErrNotOnBranch)
+		}
+		branchName = currentBranch.GetName()
+	}
+
+	branch := eng.GetBranch(branchName)
+	head, err := branch.GetRevision()
+	if err != nil {
+		return fmt.Errorf("branch %s does not exist", branchName)
+	}
+
+	var base string
+	if branch.IsTrunk() {
+		// Trunk has no parent; show its most recent commit
+		base, err = eng.GetCommitSHA(branchName, 1)
+	} else {
+		base, err = branch.GetDiffBase()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to determine diff base for %s: %w", branchName, err)
+	}
+
+	output, err := eng.ShowDiff(ctx.Context, base, head, git.DiffOptions{
+		Stat:     opts.Stat,
+		NameOnly: opts.NameOnly,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to diff %s: %w", branchName, err)
+	}
+
+	if output != "" {
+		splog.Page(output)
+		splog.Newline()
+	}
+	return nil
+}
@@ -5,18 +5,76 @@ import (
 	"fmt"
 
 	"stackit.dev/stackit/internal/runtime"
+	"stackit.dev/stackit/internal/tui"
 )
 
 // Options contains options for the doctor command
 type Options struct {
 	Fix   bool
 	Trunk string // Trunk branch name from config
+	// Env probes the tools stackit depends on; nil uses the real environment
+	Env *Environment
+}
+
+// Status is the outcome of a single doctor check
+type Status int
+
+const (
+	// StatusPass means the check found nothing wrong
+	StatusPass Status = iota
+	// StatusWarn means stackit works, but something may cause trouble
+	StatusWarn
+	// StatusFail means stackit won't work until the problem is fixed
+	StatusFail
+)
+
+// Result is the outcome of one check, as printed on its own line
+type Result struct {
+	Status  Status
+	Message string
+}
+
+// report prints each check's result as it is recorded and keeps them for the summary
+type report struct {
+	splog   *tui.Splog
+	results []Result
+}
+
+func (r *report) pass(format string, args ...any) {
+	msg := r.record(StatusPass, format, args...)
+	r.splog.Info("  ✅ %s", msg)
+}
+
+func (r *report) warn(format string, args ...any) {
+	msg := r.record(StatusWarn, format, args...)
+	r.splog.Warn("  %s", msg)
+}
+
+func (r *report) fail(format string, args ...any) {
+	msg := r.record(StatusFail, format, args...)
+	r.splog.Error("  %s", msg)
+}
+
+func (r *report) record(status Status, format string, args ...any) string {
+	msg := fmt.Sprintf(format, args...)
+	r.results = append(r.results, Result{Status: status, Message: msg})
+	return msg
+}
+
+// messages returns the messages of the results with the given status
+func (r *report) messages(status Status) []string {
+	var messages []string
+	for _, result := range r.results {
+		if result.Status == status {
+			messages = append(messages, result.Message)
+		}
+	}
+	return messages
 }
 
 // Action runs diagnostic checks on the stackit environment and repository
 func Action(ctx *runtime.Context, opts Options) error {
 	splog := ctx.Splog
-	eng := ctx.Engine
 
 	if opts.Fix {
 		splog.Info("Running stackit doctor with --fix...")
@@ -25,24 +83,9 @@ func Action(ctx *runtime.Context, opts Options) error {
 	}
 	splog.Newline()
 
-	var warnings []string
-	var errors []string
-
-	// Environment checks
-	splog.Info("Environment:")
-	warnings, errors = checkEnvironment(splog, warnings, errors)
-
-	splog.Newline()
-
-	// Repository checks
-	splog.Info("Repository:")
-	warnings, errors = checkRepository(ctx, splog, warnings, errors, opts.Trunk)
-
-	splog.Newline()
-
-	// Stack state checks
-	splog.Info("Stack State:")
-	warnings, errors = checkStackState(eng, splog, warnings, errors, opts.Fix)
+	r := diagnose(ctx, opts)
+	warnings := r.messages(StatusWarn)
+	errors := r.messages(StatusFail)
 
 	// Summary
 	splog.Newline()
@@ -71,3 +114,48 @@ func Action(ctx *runtime.Context, opts Options) error {
 
 	return nil
 }
+
+// diagnose runs every check, printing each result under its section. Checks don't depend
+// on each other, so one failing never hides the results of the rest.
+func diagnose(ctx *runtime.Context, opts Options) *report {
+	env := opts.Env
+	if env == nil {
+		env = NewEnvironment()
+	}
+	r := &report{splog: ctx.Splog}
+
+	sections := []struct {
+		title  string
+		checks []func()
+	}{
+		{"Environment:", []func(){
+			func() { checkGitVersion(r, env) },
+			func() { checkGHInstalled(r, env) },
+			func() { checkGitHubAuth(ctx.Context, r, env) },
+		}},
+		{"Repository:", []func(){
+			func() { checkGitRepository(ctx, r) },
+			func() { checkRemote(ctx, r) },
+			func() { checkTrunk(ctx, r, opts.Trunk) },
+		}},
+		{"Stack State:", []func(){
+			func() { checkOrphanedMetadata(ctx.Engine, r, opts.Fix) },
+			func() { checkMetadataIntegrity(ctx.Engine, r) },
+			func() { checkCycles(ctx.Engine, r) },
+			func() { checkMissingParentBranches(ctx.Engine, r) },
+			func() { checkParentRevisions(ctx.Engine, r, opts.Fix) },
+		}},
+	}
+
+	for i, section := range sections {
+		if i > 0 {
+			ctx.Splog.Newline()
+		}
+		ctx.Splog.Info("%s", section.title)
+		for _, check := range section.checks {
+			check()
+		}
+	}
+
+	return r
+}
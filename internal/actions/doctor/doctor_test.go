@@ -0,0 +1,163 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
+)
+
+// healthyEnvironment stands in for a machine with recent git and gh, authenticated to GitHub
+func healthyEnvironment() *Environment {
+	return &Environment{
+		GitVersion:  func() (string, error) { return "git version 2.43.0\n", nil },
+		GHVersion:   func() (string, error) { return "gh version 2.40.0 (2023-12-07)\n", nil },
+		GitHubToken: func() (string, error) { return "token", nil },
+		GitHubRepo: func(context.Context) (string, string, error) {
+			return "owner", "repo", nil
+		},
+	}
+}
+
+func TestDoctor(t *testing.T) {
+	t.Run("reports every check even when some fail", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "main",
+			})
+		// Deleting the branch with git leaves its metadata behind
+		s.Checkout("main").
+			RunGit("branch", "-D", "branch2")
+
+		env := healthyEnvironment()
+		env.GitVersion = func() (string, error) { return "git version 2.20.1\n", nil }
+		env.GHVersion = func() (string, error) { return "", errors.New("executable file not found in $PATH") }
+
+		r := diagnose(s.Context, Options{Trunk: "main", Env: env})
+		require.Equal(t, []string{"git version 2.20.1 is too old; stackit needs git 2.25 or newer"}, r.messages(StatusFail))
+		require.Equal(t, []string{
+			"GitHub CLI (gh) is not installed or not in PATH",
+			"remote 'origin' is not configured",
+			"orphaned metadata found for deleted branch 'branch2' (run 'stackit doctor --fix' to prune)",
+		}, r.messages(StatusWarn))
+		require.Contains(t, r.messages(StatusPass), "GitHub authentication successful (owner/repo)")
+		require.Contains(t, r.messages(StatusPass), "Trunk branch 'main' exists")
+		require.Contains(t, r.messages(StatusPass), "Metadata integrity check passed")
+
+		err := Action(s.Context, Options{Trunk: "main", Env: env})
+		require.EqualError(t, err, "doctor found 1 error(s)")
+	})
+
+	t.Run("passes with only warnings", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+
+		env := healthyEnvironment()
+		env.GitHubToken = func() (string, error) { return "", errors.New("not logged in") }
+
+		r := diagnose(s.Context, Options{Trunk: "main", Env: env})
+		require.Empty(t, r.messages(StatusFail))
+		require.Equal(t, []string{
+			"GitHub authentication not configured (GITHUB_TOKEN env var or gh auth token)",
+			"remote 'origin' is not configured",
+		}, r.messages(StatusWarn))
+		require.Contains(t, r.messages(StatusPass), "gh 2.40.0")
+
+		require.NoError(t, Action(s.Context, Options{Trunk: "main", Env: env}))
+	})
+
+	t.Run("fails when the trunk is missing", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+
+		r := diagnose(s.Context, Options{Trunk: "develop", Env: healthyEnvironment()})
+		require.Equal(t, []string{"trunk branch 'develop' does not exist"}, r.messages(StatusFail))
+	})
+
+	t.Run("warns when trunk isn't the remote's default branch", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "main"))
+		s.CreateBranch("develop").
+			RunGit("push", "origin", "develop").
+			RunGit("symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/main")
+
+		r := diagnose(s.Context, Options{Trunk: "develop", Env: healthyEnvironment()})
+		require.Contains(t, r.messages(StatusWarn),
+			"trunk branch 'develop' is not the default branch of 'origin' ('main'); run 'stackit init --trunk main' if that's unintended")
+
+		r = diagnose(s.Context, Options{Trunk: "main", Env: healthyEnvironment()})
+		for _, warning := range r.messages(StatusWarn) {
+			require.NotContains(t, warning, "default branch")
+		}
+	})
+
+	t.Run("--fix prunes orphaned metadata", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+			})
+		s.Checkout("main").
+			RunGit("branch", "-D", "branch1")
+
+		r := diagnose(s.Context, Options{Trunk: "main", Fix: true, Env: healthyEnvironment()})
+		require.NotContains(t, r.messages(StatusWarn),
+			"orphaned metadata found for deleted branch 'branch1' (run 'stackit doctor --fix' to prune)")
+
+		refs, err := s.Engine.ListMetadataRefs()
+		require.NoError(t, err)
+		require.NotContains(t, refs, "branch1")
+	})
+
+	t.Run("--fix repairs a parent revision that isn't in the branch's history", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+			})
+		mergeBase, err := s.Engine.Trunk().GetRevision()
+		require.NoError(t, err)
+		s.Checkout("main").
+			CommitChange("main.txt", "feat: main")
+
+		// Point the stored parent revision at a commit branch1 doesn't contain
+		mainTip, err := s.Engine.Trunk().GetRevision()
+		require.NoError(t, err)
+		require.NoError(t, s.Engine.UpdateParentRevision("branch1", mainTip))
+
+		r := diagnose(s.Context, Options{Trunk: "main", Env: healthyEnvironment()})
+		require.Contains(t, r.messages(StatusWarn),
+			"branch 'branch1' has a parent revision that isn't in its history (run 'stackit doctor --fix' to repair)")
+
+		r = diagnose(s.Context, Options{Trunk: "main", Fix: true, Env: healthyEnvironment()})
+		for _, warning := range r.messages(StatusWarn) {
+			require.NotContains(t, warning, "parent revision")
+		}
+		meta, err := s.Engine.ReadMetadataRef("branch1")
+		require.NoError(t, err)
+		require.Equal(t, mergeBase, *meta.ParentBranchRevision)
+	})
+}
+
+func TestParseGitVersion(t *testing.T) {
+	tests := []struct {
+		output       string
+		major, minor int
+		ok           bool
+	}{
+		{"git version 2.43.0", 2, 43, true},
+		{"git version 2.39.3 (Apple Git-146)", 2, 39, true},
+		{"git version 2.45.1.windows.1", 2, 45, true},
+		{"git version", 0, 0, false},
+		{"not git", 0, 0, false},
+	}
+	for _, tt := range tests {
+		major, minor, ok := parseGitVersion(tt.output)
+		require.Equal(t, tt.ok, ok, tt.output)
+		require.Equal(t, tt.major, major, tt.output)
+		require.Equal(t, tt.minor, minor, tt.output)
+	}
+}
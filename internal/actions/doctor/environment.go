@@ -2,68 +2,130 @@ package doctor
 
 import (
 	"context"
-	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
 
 	"stackit.dev/stackit/internal/github"
-	"stackit.dev/stackit/internal/tui"
 )
 
-// checkEnvironment performs environment-related checks
-func checkEnvironment(splog *tui.Splog, warnings []string, errors []string) ([]string, []string) {
-	// Check git version
-	gitVersion, err := exec.Command("git", "version").Output()
+// minGitVersion is the oldest git stackit supports
+var minGitVersion = [2]int{2, 25}
+
+// Environment is how doctor probes the tools stackit depends on, so tests can stand in
+// for a machine with missing or outdated tools
+type Environment struct {
+	// GitVersion returns the output of `git version`
+	GitVersion func() (string, error)
+	// GHVersion returns the output of `gh version`
+	GHVersion func() (string, error)
+	// GitHubToken returns the token stackit would authenticate to GitHub with
+	GitHubToken func() (string, error)
+	// GitHubRepo connects to GitHub and returns the repository it resolved
+	GitHubRepo func(ctx context.Context) (owner string, repo string, err error)
+}
+
+// NewEnvironment returns the Environment of the machine stackit is running on
+func NewEnvironment() *Environment {
+	return &Environment{
+		GitVersion: func() (string, error) {
+			output, err := exec.Command("git", "version").Output()
+			return string(output), err
+		},
+		GHVersion: func() (string, error) {
+			output, err := exec.Command("gh", "version").Output()
+			return string(output), err
+		},
+		GitHubToken: getGitHubToken,
+		GitHubRepo: func(ctx context.Context) (string, string, error) {
+			client, err := github.NewRealGitHubClient(ctx)
+			if err != nil {
+				return "", "", err
+			}
+			owner, repo := client.GetOwnerRepo()
+			return owner, repo, nil
+		},
+	}
+}
+
+// checkGitVersion checks git is installed and new enough
+func checkGitVersion(r *report, env *Environment) {
+	output, err := env.GitVersion()
 	if err != nil {
-		errors = append(errors, "git is not installed or not in PATH")
-		splog.Error("  git is not installed or not in PATH")
-	} else {
-		version := strings.TrimSpace(string(gitVersion))
-		splog.Info("  ✅ %s", version)
+		r.fail("git is not installed or not in PATH")
+		return
+	}
+
+	version := strings.TrimSpace(output)
+	major, minor, ok := parseGitVersion(version)
+	switch {
+	case !ok:
+		r.warn("could not determine the git version from %q", version)
+	case major < minGitVersion[0] || (major == minGitVersion[0] && minor < minGitVersion[1]):
+		r.fail("%s is too old; stackit needs git %d.%d or newer", version, minGitVersion[0], minGitVersion[1])
+	default:
+		r.pass("%s", version)
+	}
+}
+
+// parseGitVersion extracts the major and minor version from `git version` output such
+// as "git version 2.39.3 (Apple Git-146)"
+func parseGitVersion(output string) (int, int, bool) {
+	fields := strings.Fields(output)
+	if len(fields) < 3 || fields[0] != "git" || fields[1] != "version" {
+		return 0, 0, false
 	}
+	parts := strings.Split(fields[2], ".")
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
 
-	// Check gh CLI
-	ghVersion, err := exec.Command("gh", "version").Output()
+// checkGHInstalled checks the GitHub CLI is available
+func checkGHInstalled(r *report, env *Environment) {
+	output, err := env.GHVersion()
 	if err != nil {
-		warnings = append(warnings, "GitHub CLI (gh) is not installed or not in PATH")
-		splog.Warn("  GitHub CLI (gh) is not installed or not in PATH")
+		r.warn("GitHub CLI (gh) is not installed or not in PATH")
+		return
+	}
+
+	// `gh version` prints "gh version 2.40.0 (2023-12-07)"; show just the version number
+	fields := strings.Fields(output)
+	if len(fields) >= 3 && fields[0] == "gh" && fields[1] == "version" {
+		r.pass("gh %s", fields[2])
 	} else {
-		version := strings.TrimSpace(string(ghVersion))
-		// Extract just the version number
-		parts := strings.Fields(version)
-		if len(parts) > 0 {
-			splog.Info("  ✅ gh %s", parts[0])
-		} else {
-			splog.Info("  ✅ %s", version)
-		}
+		r.pass("%s", strings.TrimSpace(output))
 	}
+}
 
-	// Check GitHub authentication
-	token, err := getGitHubToken()
+// checkGitHubAuth checks stackit can authenticate to GitHub
+func checkGitHubAuth(ctx context.Context, r *report, env *Environment) {
+	token, err := env.GitHubToken()
 	if err != nil {
-		warnings = append(warnings, "GitHub authentication not configured (GITHUB_TOKEN env var or gh auth token)")
-		splog.Warn("  GitHub authentication not configured")
-	} else {
-		if token == "" {
-			warnings = append(warnings, "GitHub token is empty")
-			splog.Warn("  GitHub token is empty")
-		} else {
-			// Try to create a GitHub client to verify connectivity
-			ghCtx := context.Background()
-			client, err := github.NewRealGitHubClient(ghCtx)
-			if err != nil {
-				warnings = append(warnings, fmt.Sprintf("GitHub authentication failed: %v", err))
-				splog.Warn("  GitHub authentication failed: %v", err)
-			} else {
-				owner, repo := client.GetOwnerRepo()
-				if owner != "" && repo != "" {
-					splog.Info("  ✅ GitHub authentication successful (%s/%s)", owner, repo)
-				} else {
-					splog.Info("  ✅ GitHub authentication successful")
-				}
-			}
-		}
+		r.warn("GitHub authentication not configured (GITHUB_TOKEN env var or gh auth token)")
+		return
+	}
+	if token == "" {
+		r.warn("GitHub token is empty")
+		return
 	}
 
-	return warnings, errors
+	owner, repo, err := env.GitHubRepo(ctx)
+	switch {
+	case err != nil:
+		r.warn("GitHub authentication failed: %v", err)
+	case owner != "" && repo != "":
+		r.pass("GitHub authentication successful (%s/%s)", owner, repo)
+	default:
+		r.pass("GitHub authentication successful")
+	}
 }
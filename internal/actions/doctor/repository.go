@@ -1,69 +1,63 @@
 package doctor
 
 import (
-	"fmt"
-
 	"stackit.dev/stackit/internal/git"
 	"stackit.dev/stackit/internal/github"
 	"stackit.dev/stackit/internal/runtime"
-	"stackit.dev/stackit/internal/tui"
 )
 
-// checkRepository performs repository-related checks
-func checkRepository(ctx *runtime.Context, splog *tui.Splog, warnings []string, errors []string, trunk string) ([]string, []string) {
-	// Check if we're in a git repository
+// checkGitRepository checks the current directory is inside a git repository
+func checkGitRepository(ctx *runtime.Context, r *report) {
 	if ctx.RepoRoot == "" {
 		if err := git.InitDefaultRepo(); err != nil {
-			errors = append(errors, "not in a git repository")
-			splog.Error("  not in a git repository")
-			return warnings, errors
+			r.fail("not in a git repository")
+			return
 		}
 		if _, err := git.GetRepoRoot(); err != nil {
-			errors = append(errors, fmt.Sprintf("failed to get repo root: %v", err))
-			splog.Error("  failed to get repo root: %v", err)
-			return warnings, errors
+			r.fail("failed to get repo root: %v", err)
+			return
 		}
 	}
-	splog.Info("  ✅ Current directory is a git repository")
+	r.pass("Current directory is a git repository")
+}
 
-	// Check remote configuration
-	remoteURL, err := git.RunGitCommandWithContext(ctx.Context, "config", "--get", "remote.origin.url")
+// checkRemote checks a GitHub remote is configured
+func checkRemote(ctx *runtime.Context, r *report) {
+	remote := git.GetRemote()
+	remoteURL, err := git.RunGitCommandWithContext(ctx.Context, "config", "--get", "remote."+remote+".url")
 	if err != nil {
-		warnings = append(warnings, "remote 'origin' is not configured")
-		splog.Warn("  remote 'origin' is not configured")
-	} else {
-		// Check if it's a GitHub remote
-		repoInfo, err := github.ParseGitHubRemoteURL(remoteURL)
-		if err != nil {
-			warnings = append(warnings, fmt.Sprintf("remote 'origin' is not a GitHub repository: %s", remoteURL))
-			splog.Warn("  remote 'origin' is not a GitHub repository")
-		} else {
-			splog.Info("  ✅ Remote 'origin' is configured to GitHub (%s/%s)", repoInfo.Owner, repoInfo.Repo)
-		}
+		r.warn("remote '%s' is not configured", remote)
+		return
 	}
 
-	// Check trunk branch
-	if trunk == "" {
-		errors = append(errors, "trunk branch not configured")
-		splog.Error("  trunk branch not configured")
-	} else {
-		// Check if trunk branch exists
-		_, err := git.GetRevision(trunk)
-		if err != nil {
-			errors = append(errors, fmt.Sprintf("trunk branch '%s' does not exist", trunk))
-			splog.Error("  trunk branch '%s' does not exist", trunk)
-		} else {
-			splog.Info("  ✅ Trunk branch '%s' exists", trunk)
-		}
+	repoInfo, err := github.ParseGitHubRemoteURL(remoteURL)
+	if err != nil {
+		r.warn("remote '%s' is not a GitHub repository: %s", remote, remoteURL)
+		return
 	}
+	r.pass("Remote '%s' is configured to GitHub (%s/%s)", remote, repoInfo.Owner, repoInfo.Repo)
+}
 
-	// Check if stackit is initialized (if trunk is set, it's initialized)
+// checkTrunk checks stackit is initialized with a trunk that exists locally and matches
+// the remote's default branch
+func checkTrunk(ctx *runtime.Context, r *report, trunk string) {
 	if trunk == "" {
-		errors = append(errors, "stackit is not initialized (run 'stackit init')")
-		splog.Error("  stackit is not initialized")
-	} else {
-		splog.Info("  ✅ stackit is initialized")
+		r.fail("stackit is not initialized; no trunk branch is configured (run 'stackit init')")
+		return
 	}
 
-	return warnings, errors
+	if _, err := git.GetRevision(trunk); err != nil {
+		r.fail("trunk branch '%s' does not exist", trunk)
+		return
+	}
+	r.pass("Trunk branch '%s' exists", trunk)
+
+	// The remote's HEAD is only known once it has been fetched or set with
+	// `git remote set-head`, so a missing one isn't a problem
+	remote := git.GetRemote()
+	defaultBranch := git.GetRemoteDefaultBranch(ctx.Context, remote)
+	if defaultBranch != "" && defaultBranch != trunk {
+		r.warn("trunk branch '%s' is not the default branch of '%s' ('%s'); run 'stackit init --trunk %s' if that's unintended",
+			trunk, remote, defaultBranch, defaultBranch)
+	}
 }
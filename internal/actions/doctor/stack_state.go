@@ -1,128 +1,153 @@
 package doctor
 
 import (
-	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"stackit.dev/stackit/internal/engine"
 	"stackit.dev/stackit/internal/git"
-	"stackit.dev/stackit/internal/tui"
 	"stackit.dev/stackit/internal/tui/style"
 )
 
-// checkStackState performs stack state and metadata integrity checks
-func checkStackState(eng engine.Engine, splog *tui.Splog, warnings []string, errors []string, fix bool) ([]string, []string) {
-	// Get all branches
+// checkOrphanedMetadata looks for metadata left behind by deleted branches, pruning it
+// when fix is set
+func checkOrphanedMetadata(eng engine.Engine, r *report, fix bool) {
 	allBranches, err := git.GetAllBranchNames()
 	if err != nil {
-		errors = append(errors, fmt.Sprintf("failed to get branch names: %v", err))
-		splog.Error("  failed to get branch names: %v", err)
-		return warnings, errors
+		r.fail("failed to get branch names: %v", err)
+		return
 	}
-
-	// Get all metadata refs
 	metadataRefs, err := eng.ListMetadataRefs()
 	if err != nil {
-		errors = append(errors, fmt.Sprintf("failed to get metadata refs: %v", err))
-		splog.Error("  failed to get metadata refs: %v", err)
-		return warnings, errors
+		r.fail("failed to get metadata refs: %v", err)
+		return
 	}
 
-	// Check for orphaned metadata (metadata for branches that don't exist)
 	branchSet := make(map[string]bool)
 	for _, branch := range allBranches {
 		branchSet[branch] = true
 	}
 
-	orphanedCount := 0
-	prunedCount := 0
-	for branchName := range metadataRefs {
-		if !branchSet[branchName] {
-			orphanedCount++
-			if fix {
-				if err := eng.DeleteMetadataRef(eng.GetBranch(branchName)); err != nil {
-					splog.Error("  Failed to prune orphaned metadata for %s: %v", branchName, err)
-					warnings = append(warnings, fmt.Sprintf("orphaned metadata found for deleted branch '%s' (fix failed)", branchName))
-				} else {
-					splog.Info("  ✅ Pruned orphaned metadata for deleted branch %s", style.ColorBranchName(branchName, false))
-					prunedCount++
-				}
-			} else {
-				warnings = append(warnings, fmt.Sprintf("orphaned metadata found for deleted branch '%s'", branchName))
-			}
+	orphaned := 0
+	for _, branchName := range slices.Sorted(maps.Keys(metadataRefs)) {
+		if branchSet[branchName] {
+			continue
+		}
+		orphaned++
+		switch {
+		case !fix:
+			r.warn("orphaned metadata found for deleted branch '%s' (run 'stackit doctor --fix' to prune)", branchName)
+		case eng.DeleteMetadataRef(eng.GetBranch(branchName)) != nil:
+			r.warn("orphaned metadata found for deleted branch '%s' (fix failed)", branchName)
+		default:
+			r.pass("Pruned orphaned metadata for deleted branch %s", style.ColorBranchName(branchName, false))
 		}
 	}
 
-	if orphanedCount > 0 {
-		if fix {
-			if prunedCount == orphanedCount {
-				splog.Info("  ✅ All %d orphaned metadata ref(s) pruned", prunedCount)
-			} else {
-				splog.Warn("  Found %d orphaned metadata ref(s), pruned %d", orphanedCount, prunedCount)
-			}
-		} else {
-			splog.Warn("  Found %d orphaned metadata ref(s) (run 'stackit doctor --fix' to prune)", orphanedCount)
-		}
-	} else {
-		splog.Info("  ✅ No orphaned metadata found")
+	if orphaned == 0 {
+		r.pass("No orphaned metadata found")
 	}
+}
 
-	// Check for corrupted metadata
-	metadataRefNames := make([]string, 0, len(metadataRefs))
-	for branchName := range metadataRefs {
-		metadataRefNames = append(metadataRefNames, branchName)
+// checkMetadataIntegrity checks every metadata ref can be read and is well formed
+func checkMetadataIntegrity(eng engine.Engine, r *report) {
+	metadataRefs, err := eng.ListMetadataRefs()
+	if err != nil {
+		r.fail("failed to get metadata refs: %v", err)
+		return
 	}
+
+	metadataRefNames := slices.Sorted(maps.Keys(metadataRefs))
 	allMeta, allMetaErrs := eng.BatchReadMetadataRefs(metadataRefNames)
 
-	corruptedCount := 0
+	corrupted := 0
 	for _, branchName := range metadataRefNames {
 		if err := allMetaErrs[branchName]; err != nil {
-			corruptedCount++
-			errors = append(errors, fmt.Sprintf("corrupted metadata for branch '%s': %v", branchName, err))
-		} else if meta := allMeta[branchName]; meta != nil {
-			// Validate that if parent is set, it's not empty
-			if meta.ParentBranchName != nil && *meta.ParentBranchName == "" {
-				corruptedCount++
-				errors = append(errors, fmt.Sprintf("invalid metadata for branch '%s': parent branch name is empty", branchName))
-			}
+			corrupted++
+			r.fail("corrupted metadata for branch '%s': %v", branchName, err)
+		} else if meta := allMeta[branchName]; meta != nil && meta.ParentBranchName != nil && *meta.ParentBranchName == "" {
+			corrupted++
+			r.fail("invalid metadata for branch '%s': parent branch name is empty", branchName)
 		}
 	}
 
-	if corruptedCount > 0 {
-		splog.Error("  Found %d corrupted metadata ref(s)", corruptedCount)
-	} else {
-		splog.Info("  ✅ Metadata integrity check passed")
+	if corrupted == 0 {
+		r.pass("Metadata integrity check passed")
 	}
+}
 
-	// Check for cycles in the stack graph
+// checkCycles checks the stack graph has no cycles
+func checkCycles(eng engine.Engine, r *report) {
 	cycles := detectCycles(eng)
-	if len(cycles) > 0 {
-		for _, cycle := range cycles {
-			errors = append(errors, fmt.Sprintf("cycle detected in stack graph: %s", strings.Join(cycle, " -> ")))
-		}
-		splog.Error("  Found %d cycle(s) in stack graph", len(cycles))
-	} else {
-		splog.Info("  ✅ No cycles detected in stack graph")
+	for _, cycle := range cycles {
+		r.fail("cycle detected in stack graph: %s", strings.Join(cycle, " -> "))
+	}
+	if len(cycles) == 0 {
+		r.pass("No cycles detected in stack graph")
+	}
+}
+
+// checkMissingParentBranches checks every tracked branch's parent exists
+func checkMissingParentBranches(eng engine.Engine, r *report) {
+	allBranches, err := git.GetAllBranchNames()
+	if err != nil {
+		r.fail("failed to get branch names: %v", err)
+		return
 	}
 
-	// Check for missing parent branches
 	missingParents := checkMissingParents(eng, allBranches)
-	if len(missingParents) > 0 {
-		for _, branch := range missingParents {
-			branchObj := eng.GetBranch(branch)
-			parent := eng.GetParent(branchObj)
-			parentName := "unknown"
-			if parent != nil {
-				parentName = parent.GetName()
+	for _, branch := range missingParents {
+		parentName := "unknown"
+		if parent := eng.GetParent(eng.GetBranch(branch)); parent != nil {
+			parentName = parent.GetName()
+		}
+		r.warn("branch '%s' has parent '%s' that does not exist", branch, parentName)
+	}
+	if len(missingParents) == 0 {
+		r.pass("All parent branches exist")
+	}
+}
+
+// checkParentRevisions checks every tracked branch's stored parent revision is in its
+// history, recomputing it from the merge-base with the parent when fix is set
+func checkParentRevisions(eng engine.Engine, r *report, fix bool) {
+	allBranches, err := git.GetAllBranchNames()
+	if err != nil {
+		r.fail("failed to get branch names: %v", err)
+		return
+	}
+
+	// Only existing branches; deleted ones are reported as orphaned metadata
+	divergent := 0
+	for _, branchName := range allBranches {
+		branch := eng.GetBranch(branchName)
+		if branch.IsTrunk() || !branch.IsTracked() {
+			continue
+		}
+		meta, err := eng.ReadMetadataRef(branch.GetName())
+		if err != nil || meta.ParentBranchName == nil {
+			continue
+		}
+		if meta.ParentBranchRevision != nil {
+			if isAncestor, err := eng.IsAncestor(*meta.ParentBranchRevision, branch.GetName()); err == nil && isAncestor {
+				continue
 			}
-			warnings = append(warnings, fmt.Sprintf("branch '%s' has parent '%s' that does not exist", branch, parentName))
 		}
-		splog.Warn("  Found %d branch(es) with missing parents", len(missingParents))
-	} else {
-		splog.Info("  ✅ All parent branches exist")
+
+		divergent++
+		if !fix {
+			r.warn("branch '%s' has a parent revision that isn't in its history (run 'stackit doctor --fix' to repair)", branch.GetName())
+		} else if _, err := eng.RepairParentRevision(branch); err != nil {
+			r.warn("branch '%s' has a parent revision that isn't in its history (fix failed: %v)", branch.GetName(), err)
+		} else {
+			r.pass("Repaired the parent revision of %s", style.ColorBranchName(branch.GetName(), false))
+		}
 	}
 
-	return warnings, errors
+	if divergent == 0 {
+		r.pass("All parent revisions are in their branches' history")
+	}
 }
 
 // detectCycles detects cycles in the branch parent graph using DFS
@@ -0,0 +1,75 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+
+	"stackit.dev/stackit/internal/runtime"
+)
+
+// ExportGraphOptions contains options for the export-graph command
+type ExportGraphOptions struct {
+	// PRNumbers adds each branch's PR number to its node label
+	PRNumbers bool
+}
+
+// ExportGraphAction prints the branch tree as a Graphviz DOT graph, with an edge from each
+// branch to its children. The current branch is drawn bold and branches that need
+// restacking dashed. The output has no colors, so it can be piped straight into dot.
+func ExportGraphAction(ctx *runtime.Context, opts ExportGraphOptions) error {
+	eng := ctx.Engine
+
+	current := ""
+	if branch := eng.CurrentBranch(); branch != nil {
+		current = branch.GetName()
+	}
+
+	var buf strings.Builder
+	buf.WriteString("digraph stack {\n")
+	buf.WriteString("  node [shape=box];\n")
+
+	var edges []string
+	for branch := range eng.BranchesDepthFirst(eng.Trunk()) {
+		name := branch.GetName()
+
+		label := name
+		if opts.PRNumbers {
+			if prInfo, _ := eng.GetPrInfo(branch); prInfo != nil && prInfo.Number() != nil {
+				label = fmt.Sprintf("%s\n#%d", name, *prInfo.Number())
+			}
+		}
+
+		var styles []string
+		if name == current {
+			styles = append(styles, "bold")
+		}
+		if !branch.IsTrunk() && !branch.IsBranchUpToDate() {
+			styles = append(styles, "dashed")
+		}
+
+		attrs := "label=" + dotQuote(label)
+		if len(styles) > 0 {
+			attrs += ", style=" + dotQuote(strings.Join(styles, ","))
+		}
+		fmt.Fprintf(&buf, "  %s [%s];\n", dotQuote(name), attrs)
+
+		if !branch.IsTrunk() {
+			edges = append(edges, fmt.Sprintf("  %s -> %s;\n", dotQuote(branch.GetParentPrecondition()), dotQuote(name)))
+		}
+	}
+	for _, edge := range edges {
+		buf.WriteString(edge)
+	}
+	buf.WriteString("}\n")
+
+	ctx.Splog.Page(buf.String())
+	return nil
+}
+
+// dotQuote quotes s as a DOT string, escaping quotes and turning newlines into line breaks
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -6,6 +6,7 @@ import (
 
 	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/git"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui"
@@ -35,7 +36,7 @@ func foldWithKeep(gctx context.Context, ctx *runtime.Context, currentBranch, par
 		// Fast-forward failed, try regular merge
 		_, err = git.RunGitCommandWithContext(gctx, "merge", "--no-edit", parentBranch.GetName())
 		if err != nil {
-			return fmt.Errorf("failed to merge %s into %s due to conflicts. Please resolve the conflicts and run 'git commit', or abort with 'git merge --abort'", parentBranch.GetName(), currentBranch.GetName())
+			return stackiterrors.WithCategory(stackiterrors.ErrRebaseConflict, fmt.Errorf("failed to merge %s into %s due to conflicts. Please resolve the conflicts and run 'git commit', or abort with 'git merge --abort'", parentBranch.GetName(), currentBranch.GetName()))
 		}
 	}
 
@@ -44,8 +45,12 @@ func foldWithKeep(gctx context.Context, ctx *runtime.Context, currentBranch, par
 		return fmt.Errorf("failed to delete parent branch: %w", err)
 	}
 
-	// Rebuild engine to reflect the deletion
-	if err := eng.Rebuild(eng.Trunk().GetName()); err != nil {
+	// Reload the deleted parent and the children it handed to the grandparent
+	changed := []string{parentBranch.GetName()}
+	for _, child := range allChildren {
+		changed = append(changed, child.GetName())
+	}
+	if err := eng.RebuildBranches(changed...); err != nil {
 		return fmt.Errorf("failed to rebuild engine: %w", err)
 	}
 
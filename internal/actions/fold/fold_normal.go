@@ -6,6 +6,7 @@ import (
 
 	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/git"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui"
@@ -29,7 +30,7 @@ func foldNormal(gctx context.Context, ctx *runtime.Context, currentBranch, paren
 		// Fast-forward failed, try regular merge
 		_, err = git.RunGitCommandWithContext(gctx, "merge", "--no-edit", currentBranch.GetName())
 		if err != nil {
-			return fmt.Errorf("failed to merge %s into %s due to conflicts. Please resolve the conflicts and run 'git commit', or abort with 'git merge --abort'", currentBranch.GetName(), parentBranch.GetName())
+			return stackiterrors.WithCategory(stackiterrors.ErrRebaseConflict, fmt.Errorf("failed to merge %s into %s due to conflicts. Please resolve the conflicts and run 'git commit', or abort with 'git merge --abort'", currentBranch.GetName(), parentBranch.GetName()))
 		}
 	}
 
@@ -51,8 +52,12 @@ func foldNormal(gctx context.Context, ctx *runtime.Context, currentBranch, paren
 
 	// Restack all descendants of the parent
 	if len(descendants) > 0 {
-		// Rebuild engine to reflect the deletion
-		if err := eng.Rebuild(eng.Trunk().GetName()); err != nil {
+		// Reload the deleted branch and the descendants that may have been reparented
+		changed := []string{currentBranch.GetName()}
+		for _, descendant := range descendants {
+			changed = append(changed, descendant.GetName())
+		}
+		if err := eng.RebuildBranches(changed...); err != nil {
 			return fmt.Errorf("failed to rebuild engine: %w", err)
 		}
 
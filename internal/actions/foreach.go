@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui/style"
 )
@@ -26,7 +27,7 @@ func ForeachAction(ctx *runtime.Context, opts ForeachOptions) error {
 
 	currentBranch := eng.CurrentBranch()
 	if currentBranch == nil {
-		return fmt.Errorf("not on a branch")
+		return stackiterrors.ErrNotOnBranch
 	}
 
 	// Get branches based on scope
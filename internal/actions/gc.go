@@ -0,0 +1,113 @@
+package actions
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"stackit.dev/stackit/internal/config"
+	"stackit.dev/stackit/internal/git"
+	"stackit.dev/stackit/internal/runtime"
+	"stackit.dev/stackit/internal/tui/style"
+)
+
+// DefaultContinuationMaxAge is how old an interrupted operation's continuation state must be
+// before gc clears it
+const DefaultContinuationMaxAge = 7 * 24 * time.Hour
+
+// GCOptions contains options for the gc command
+type GCOptions struct {
+	Prune              bool          // Delete what was found; otherwise only report it
+	ContinuationMaxAge time.Duration // Clear continuation state older than this
+}
+
+// GCAction finds leftovers that accumulate over time: metadata for branches that no longer
+// exist, remote-tracking refs for branches deleted on the remote, and continuation state from
+// an interrupted operation that was never finished. Nothing is deleted unless opts.Prune is set.
+func GCAction(ctx *runtime.Context, opts GCOptions) error {
+	eng := ctx.Engine
+	splog := ctx.Splog
+
+	verb := "Would delete"
+	if opts.Prune {
+		verb = "Deleted"
+	}
+	found := 0
+
+	// Metadata for deleted branches
+	branchNames, err := git.GetAllBranchNames()
+	if err != nil {
+		return fmt.Errorf("failed to get branch names: %w", err)
+	}
+	branchSet := make(map[string]bool, len(branchNames))
+	for _, name := range branchNames {
+		branchSet[name] = true
+	}
+	metadataRefs, err := eng.ListMetadataRefs()
+	if err != nil {
+		return fmt.Errorf("failed to get metadata refs: %w", err)
+	}
+	orphaned := make([]string, 0, len(metadataRefs))
+	for branchName := range metadataRefs {
+		if !branchSet[branchName] {
+			orphaned = append(orphaned, branchName)
+		}
+	}
+	sort.Strings(orphaned)
+	for _, branchName := range orphaned {
+		if opts.Prune {
+			if err := eng.DeleteMetadataRef(eng.GetBranch(branchName)); err != nil {
+				return fmt.Errorf("failed to delete metadata for %s: %w", branchName, err)
+			}
+		}
+		splog.Info("%s metadata for deleted branch %s.", verb, style.ColorBranchName(branchName, false))
+		found++
+	}
+
+	// Remote-tracking refs for branches deleted on the remote
+	remotes, err := git.ListRemotes(ctx.Context)
+	if err != nil {
+		return fmt.Errorf("failed to list remotes: %w", err)
+	}
+	for _, remote := range remotes {
+		stale, err := git.StaleRemoteBranches(ctx.Context, remote)
+		if err != nil {
+			// An unreachable remote shouldn't stop the rest of the cleanup
+			splog.Warn("Skipping %s: %v", remote, err)
+			continue
+		}
+		if len(stale) == 0 {
+			continue
+		}
+		if opts.Prune {
+			if err := git.PruneStaleRemoteBranches(ctx.Context, remote); err != nil {
+				return err
+			}
+		}
+		for _, ref := range stale {
+			splog.Info("%s stale remote-tracking ref %s.", verb, ref)
+		}
+		found += len(stale)
+	}
+
+	// Continuation state from an operation that was abandoned rather than continued or aborted
+	if modTime, err := config.ContinuationStateModTime(ctx.RepoRoot); err == nil &&
+		time.Since(modTime) > opts.ContinuationMaxAge &&
+		!git.IsRebaseInProgress(ctx.Context) && !git.IsMergeInProgress(ctx.Context) {
+		if opts.Prune {
+			if err := config.ClearContinuationState(ctx.RepoRoot); err != nil {
+				return err
+			}
+		}
+		splog.Info("%s continuation state last updated %s.", verb, modTime.Format(time.DateTime))
+		found++
+	}
+
+	switch {
+	case found == 0:
+		splog.Info("Nothing to clean up.")
+	case !opts.Prune:
+		splog.Info("Run %s to delete these.", style.ColorCyan("stackit gc --prune"))
+	}
+	return nil
+}
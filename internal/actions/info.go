@@ -1,11 +1,14 @@
 package actions
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
+	"stackit.dev/stackit/internal/git"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui/style"
 )
@@ -17,6 +20,26 @@ type InfoOptions struct {
 	Diff       bool
 	Patch      bool
 	Stat       bool
+	JSON       bool
+}
+
+// InfoJSON is the structured output of `info --json`
+type InfoJSON struct {
+	Name          string   `json:"name"`
+	Parent        string   `json:"parent"`
+	Children      []string `json:"children"`
+	IsTrunk       bool     `json:"isTrunk"`
+	IsTracked     bool     `json:"isTracked"`
+	Scope         string   `json:"scope"`         // Effective scope, inherited from ancestors
+	ExplicitScope string   `json:"explicitScope"` // Scope set directly on this branch
+	PRNumber      *int     `json:"prNumber"`
+	PRState       string   `json:"prState"`
+	PRURL         string   `json:"prUrl"`
+	NeedsRestack  bool     `json:"needsRestack"`
+	CommitCount   int      `json:"commitCount"`
+	LinesAdded    int      `json:"linesAdded"`
+	LinesDeleted  int      `json:"linesDeleted"`
+	MatchesRemote bool     `json:"matchesRemote"`
 }
 
 // InfoAction displays information about a branch
@@ -28,7 +51,7 @@ func InfoAction(ctx *runtime.Context, opts InfoOptions) error {
 	if branchName == "" {
 		currentBranch := eng.CurrentBranch()
 		if currentBranch == nil {
-			return fmt.Errorf("not on a branch and no branch specified")
+			return fmt.Errorf("%w and no branch specified", stackiterrors.ErrNotOnBranch)
 		}
 		branchName = currentBranch.GetName()
 	}
@@ -42,6 +65,10 @@ func InfoAction(ctx *runtime.Context, opts InfoOptions) error {
 		}
 	}
 
+	if opts.JSON {
+		return infoJSON(ctx, branch)
+	}
+
 	// If stat is set without diff or patch, it implies diff
 	effectiveDiff := opts.Diff || (opts.Stat && !opts.Patch)
 	effectivePatch := opts.Patch && !opts.Diff
@@ -136,7 +163,7 @@ func InfoAction(ctx *runtime.Context, opts InfoOptions) error {
 			if err == nil {
 				parentSHA, err := eng.GetCommitSHA(branchName, 1)
 				if err == nil {
-					diffOutput, err := eng.ShowDiff(ctx.Context, parentSHA, headRevision, opts.Stat)
+					diffOutput, err := eng.ShowDiff(ctx.Context, parentSHA, headRevision, git.DiffOptions{Stat: opts.Stat})
 					if err == nil && diffOutput != "" {
 						outputLines = append(outputLines, diffOutput)
 					}
@@ -149,7 +176,7 @@ func InfoAction(ctx *runtime.Context, opts InfoOptions) error {
 				parentSHA, _ := eng.GetParentCommitSHA(oldestSHA)
 				branchRevision, err := branch.GetRevision()
 				if err == nil {
-					diffOutput, err := eng.ShowDiff(ctx.Context, parentSHA, branchRevision, opts.Stat)
+					diffOutput, err := eng.ShowDiff(ctx.Context, parentSHA, branchRevision, git.DiffOptions{Stat: opts.Stat})
 					if err == nil && diffOutput != "" {
 						outputLines = append(outputLines, diffOutput)
 					}
@@ -175,6 +202,58 @@ func InfoAction(ctx *runtime.Context, opts InfoOptions) error {
 	return nil
 }
 
+// infoJSON prints branch information as a JSON object
+func infoJSON(ctx *runtime.Context, branch engine.Branch) error {
+	eng := ctx.Engine
+	isTrunk := branch.IsTrunk()
+
+	info := InfoJSON{
+		Name:          branch.GetName(),
+		Children:      []string{},
+		IsTrunk:       isTrunk,
+		IsTracked:     branch.IsTracked(),
+		Scope:         branch.GetScope().String(),
+		ExplicitScope: eng.GetExplicitScopeInternal(branch.GetName()).String(),
+	}
+
+	for _, child := range branch.GetChildren() {
+		info.Children = append(info.Children, child.GetName())
+	}
+
+	if !isTrunk {
+		info.Parent = branch.GetParentPrecondition()
+		info.NeedsRestack = !branch.IsBranchUpToDate()
+
+		if count, err := branch.GetCommitCount(); err == nil {
+			info.CommitCount = count
+		}
+		if added, deleted, err := branch.GetDiffStats(); err == nil {
+			info.LinesAdded = added
+			info.LinesDeleted = deleted
+		}
+
+		if prInfo, _ := eng.GetPrInfo(branch); prInfo != nil {
+			info.PRNumber = prInfo.Number()
+			info.PRState = prInfo.State()
+			info.PRURL = prInfo.URL()
+		}
+	}
+
+	if matches, err := eng.BranchMatchesRemote(branch.GetName()); err == nil {
+		info.MatchesRemote = matches
+	}
+
+	jsonData, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal branch info: %w", err)
+	}
+
+	ctx.Splog.Page(string(jsonData))
+	ctx.Splog.Newline()
+
+	return nil
+}
+
 func getPRTitleLine(prInfo *engine.PrInfo) string {
 	if prInfo == nil || prInfo.Number() == nil || prInfo.Title() == "" {
 		return ""
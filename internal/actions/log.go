@@ -1,9 +1,14 @@
 package actions
 
 import (
+	"fmt"
 	"strings"
 	"sync"
+	"time"
 
+	"stackit.dev/stackit/internal/config"
+	"stackit.dev/stackit/internal/engine"
+	"stackit.dev/stackit/internal/github"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui"
 	"stackit.dev/stackit/internal/tui/components/tree"
@@ -11,15 +16,71 @@ import (
 
 // LogOptions contains options for the log command
 type LogOptions struct {
-	Style         string // "NORMAL" or "FULL"
-	Reverse       bool
-	Steps         *int
-	BranchName    string
-	ShowUntracked bool
+	Style          string // "NORMAL" or "FULL"
+	Reverse        bool
+	Steps          *int
+	BranchName     string
+	ShowUntracked  bool
+	HideMerged     bool
+	Watch          bool          // Re-render on an interval, refreshing PR states from GitHub
+	Interval       time.Duration // How often Watch refreshes
+	OnelineCommits bool          // List each branch's commits under it
+	MaxCommits     int           // Most commits OnelineCommits lists per branch; 0 lists them all
 }
 
 // LogAction displays the branch tree
 func LogAction(ctx *runtime.Context, opts LogOptions) error {
+	if opts.Watch {
+		if tui.IsTTY() && ctx.GitHubClient != nil {
+			return watchLog(ctx, opts)
+		}
+		ctx.Splog.Debug("--watch needs a terminal and GitHub access; showing the log once")
+	}
+
+	stackLines, err := renderLog(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	// Output the result
+	ctx.Splog.Page(strings.Join(stackLines, "\n"))
+	ctx.Splog.Newline()
+
+	return nil
+}
+
+// watchLog shows the log until the user quits, refreshing stored PR states from GitHub
+// every opts.Interval and highlighting the PRs whose state changed
+func watchLog(ctx *runtime.Context, opts LogOptions) error {
+	concurrency := 1
+	if cfg, err := config.LoadConfig(ctx.RepoRoot); err == nil {
+		concurrency = cfg.SubmitConcurrency()
+	}
+	return tui.RunWatchTUI("stackit log --watch", opts.Interval, func() (string, []string, error) {
+		changes, err := RefreshPrInfo(ctx.Context, ctx.Engine, ctx.GitHubClient, concurrency)
+		if err != nil {
+			return "", nil, err
+		}
+		stackLines, err := renderLog(ctx, opts)
+		if err != nil {
+			return "", nil, err
+		}
+
+		descriptions := make([]string, len(changes))
+		for i, change := range changes {
+			if change.OldState == "" {
+				descriptions[i] = fmt.Sprintf("%s: found PR #%d (%s)", change.Branch, change.Number, change.NewState)
+			} else {
+				descriptions[i] = fmt.Sprintf("%s: PR #%d is now %s (was %s)", change.Branch, change.Number,
+					change.NewState, change.OldState)
+			}
+		}
+		return strings.Join(stackLines, "\n"), descriptions, nil
+	})
+}
+
+// renderLog renders the branch tree as lines of output
+func renderLog(ctx *runtime.Context, opts LogOptions) ([]string, error) {
 	// Populate remote SHAs if needed (only for FULL mode)
 	if opts.Style == "FULL" {
 		if err := ctx.Engine.PopulateRemoteShas(); err != nil {
@@ -33,15 +94,29 @@ func LogAction(ctx *runtime.Context, opts LogOptions) error {
 	// Render the stack
 	// First, collect annotations for all branches in the stack
 	annotations := make(map[string]tree.BranchAnnotation)
+	merged := make(map[string]bool)
 	allBranches := ctx.Engine.AllBranches()
 
 	type result struct {
 		branchName string
 		annotation tree.BranchAnnotation
+		merged     bool
 	}
 	results := make(chan result, len(allBranches))
 	var wg sync.WaitGroup
 
+	// CI statuses (only in FULL mode), fetched for every branch at once where possible
+	var getChecksStatus func(branchName string) (*github.CheckStatus, error)
+	if opts.Style == "FULL" && ctx.GitHubClient != nil {
+		branchNames := make([]string, 0, len(allBranches))
+		for _, branch := range allBranches {
+			if !branch.IsTrunk() {
+				branchNames = append(branchNames, branch.GetName())
+			}
+		}
+		getChecksStatus = github.ChecksStatusFetcher(ctx.Context, ctx.GitHubClient, branchNames)
+	}
+
 	for _, branch := range allBranches {
 		wg.Add(1)
 		go func(bName string) {
@@ -63,6 +138,17 @@ func LogAction(ctx *runtime.Context, opts LogOptions) error {
 				}
 			}
 
+			// Commits listed under the branch
+			if opts.OnelineCommits && !branchObj.IsTrunk() {
+				if commits, err := branchObj.GetAllCommits(engine.CommitFormatReadable); err == nil {
+					if opts.MaxCommits > 0 && len(commits) > opts.MaxCommits {
+						annotation.MoreCommits = len(commits) - opts.MaxCommits
+						commits = commits[:opts.MaxCommits]
+					}
+					annotation.Commits = commits
+				}
+			}
+
 			// PR info (local metadata)
 			if !branchObj.IsTrunk() {
 				branch := ctx.Engine.GetBranch(bName)
@@ -71,12 +157,15 @@ func LogAction(ctx *runtime.Context, opts LogOptions) error {
 					annotation.PRNumber = prInfo.Number()
 					annotation.PRState = prInfo.State()
 					annotation.IsDraft = prInfo.IsDraft()
+					if prInfo.IsStale() && prInfo.State() == "OPEN" {
+						annotation.CustomLabel = "(needs re-submit)"
+					}
 				}
 			}
 
 			// CI status (only in FULL mode)
-			if opts.Style == "FULL" && !branchObj.IsTrunk() && ctx.GitHubClient != nil {
-				if status, err := ctx.GitHubClient.GetPRChecksStatus(ctx.Context, bName); err == nil && status != nil {
+			if getChecksStatus != nil && !branchObj.IsTrunk() {
+				if status, err := getChecksStatus(bName); err == nil && status != nil {
 					annotation.CheckStatus = "PASSING"
 					if status.Pending {
 						annotation.CheckStatus = "PENDING"
@@ -86,7 +175,15 @@ func LogAction(ctx *runtime.Context, opts LogOptions) error {
 				}
 			}
 
-			results <- result{bName, annotation}
+			// Merged status: merged branches are dimmed, or omitted with --hide-merged
+			isMerged := false
+			if !branchObj.IsTrunk() {
+				if status, err := ctx.Engine.GetDeletionStatus(ctx.Context, bName); err == nil {
+					isMerged = status.Merged
+				}
+			}
+
+			results <- result{bName, annotation, isMerged}
 		}(branch.GetName())
 	}
 
@@ -97,14 +194,17 @@ func LogAction(ctx *runtime.Context, opts LogOptions) error {
 
 	for res := range results {
 		annotations[res.branchName] = res.annotation
+		merged[res.branchName] = res.merged
 	}
 
 	renderer.SetAnnotations(annotations)
+	renderer.SetMergedPredicate(func(branchName string) bool { return merged[branchName] })
 
 	stackLines := renderer.RenderStack(opts.BranchName, tree.RenderOptions{
-		Short:   false, // We want the full tree characters with stats
-		Reverse: opts.Reverse,
-		Steps:   opts.Steps,
+		Short:      false, // We want the full tree characters with stats
+		Reverse:    opts.Reverse,
+		Steps:      opts.Steps,
+		HideMerged: opts.HideMerged,
 	})
 
 	// Add untracked branches if requested
@@ -117,11 +217,7 @@ func LogAction(ctx *runtime.Context, opts LogOptions) error {
 		}
 	}
 
-	// Output the result
-	ctx.Splog.Page(strings.Join(stackLines, "\n"))
-	ctx.Splog.Newline()
-
-	return nil
+	return stackLines, nil
 }
 
 func getUntrackedBranchNames(ctx *runtime.Context) []string {
@@ -2,10 +2,12 @@ package merge
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/engine"
 	"stackit.dev/stackit/internal/git"
 	"stackit.dev/stackit/internal/github"
@@ -106,7 +108,7 @@ func (c *ConsolidateMergeExecutor) preValidateStack(ctx context.Context, force b
 			return fmt.Errorf("failed to check remote tracking for %s: %w", branchInfo.BranchName, err)
 		}
 		if !matchesRemote {
-			diffInfo, _ := c.engine.GetBranchRemoteDifference(branchInfo.BranchName)
+			diffInfo, _ := c.engine.GetBranchRemoteDifference(branchInfo.BranchName, "")
 			if diffInfo != "" {
 				if !force {
 					return fmt.Errorf("branch %s differs from remote: %s, use --force to proceed", branchInfo.BranchName, diffInfo)
@@ -123,12 +125,15 @@ func (c *ConsolidateMergeExecutor) preValidateStack(ctx context.Context, force b
 		c.splog.Debug("✅ Branch %s is ready for consolidation", branchInfo.BranchName)
 	}
 
-	pullResult, err := c.engine.PullTrunk(ctx)
+	report, err := c.engine.PullTrunk(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to update trunk: %w", err)
 	}
-	if pullResult == engine.PullConflict {
-		return fmt.Errorf("trunk has conflicts with remote")
+	switch report.Result {
+	case engine.PullConflict:
+		return errors.New(actions.TrunkPullSummary(c.engine.Trunk().GetName(), report))
+	case engine.PullReset:
+		c.splog.Warn("%s", actions.TrunkPullSummary(c.engine.Trunk().GetName(), report))
 	}
 
 	return nil
@@ -162,7 +167,7 @@ func (c *ConsolidateMergeExecutor) createConsolidationBranch(ctx context.Context
 		}
 	}
 
-	if err := c.engine.PushBranch(ctx, branchName, c.engine.GetRemote(), false, false); err != nil {
+	if err := c.engine.PushBranch(ctx, branchName, c.engine.GetPushRemote(), false, false); err != nil {
 		return "", fmt.Errorf("failed to push consolidation branch %s: %w", branchName, err)
 	}
 
@@ -2,14 +2,17 @@ package merge
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
 
+	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/config"
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/github"
 	"stackit.dev/stackit/internal/tui"
 )
@@ -251,8 +254,14 @@ func ExecuteInWorktree(ctx context.Context, eng mergeExecuteEngine, splog *tui.S
 
 // calculateBaselineEstimate tries to find a branch with successful CI and use its timing as a baseline
 func calculateBaselineEstimate(ctx context.Context, plan *Plan, client github.Client, splog *tui.Splog) time.Duration {
+	branchNames := make([]string, len(plan.BranchesToMerge))
+	for i, branchInfo := range plan.BranchesToMerge {
+		branchNames[i] = branchInfo.BranchName
+	}
+	getChecksStatus := github.ChecksStatusFetcher(ctx, client, branchNames)
+
 	for _, branchInfo := range plan.BranchesToMerge {
-		status, err := client.GetPRChecksStatus(ctx, branchInfo.BranchName)
+		status, err := getChecksStatus(branchInfo.BranchName)
 		if err != nil || !status.Passing || status.Pending {
 			continue
 		}
@@ -494,23 +503,18 @@ func executeStep(ctx context.Context, step PlanStep, eng mergeExecuteEngine, spl
 		}
 
 	case StepPullTrunk:
-		pullResult, err := eng.PullTrunk(ctx)
+		report, err := eng.PullTrunk(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to pull trunk: %w", err)
 		}
-		switch pullResult {
-		case engine.PullDone:
-			trunk := eng.Trunk()
-			rev, _ := trunk.GetRevision()
-			revShort := rev
-			if len(rev) > 7 {
-				revShort = rev[:7]
-			}
-			splog.Debug("Trunk fast-forwarded to %s", revShort)
-		case engine.PullUnneeded:
-			splog.Debug("Trunk is up to date")
+		summary := actions.TrunkPullSummary(eng.Trunk().GetName(), report)
+		switch report.Result {
+		case engine.PullDone, engine.PullUnneeded, engine.PullRebased:
+			splog.Debug("%s", summary)
+		case engine.PullReset:
+			splog.Warn("%s", summary)
 		case engine.PullConflict:
-			return fmt.Errorf("trunk could not be fast-forwarded (conflict)")
+			return errors.New(summary)
 		}
 
 	case StepRestack:
@@ -523,8 +527,12 @@ func executeStep(ctx context.Context, step PlanStep, eng mergeExecuteEngine, spl
 			return fmt.Errorf("failed to restack: %w", err)
 		}
 
-		// Get the actual parent after restacking (may have been reparented)
-		// Use NewParent from result if reparented, otherwise get from engine
+		if result.Reparented {
+			splog.Info("Reparented %s from %s to %s (parent was merged/deleted).", step.BranchName, result.OldParent, result.NewParent)
+		}
+
+		// The parent the branch was restacked onto, which differs from the planned parent if
+		// it was reparented. NewParent is only empty if the restack stopped before reaching it.
 		actualParent := result.NewParent
 		if actualParent == "" {
 			branch := eng.GetBranch(step.BranchName)
@@ -540,7 +548,7 @@ func executeStep(ctx context.Context, step PlanStep, eng mergeExecuteEngine, spl
 		case engine.RestackDone:
 			// Success - now push the rebased branch and update PR base
 			// Force push is required since we rebased
-			if err := eng.PushBranch(ctx, step.BranchName, eng.GetRemote(), true, false); err != nil {
+			if err := eng.PushBranch(ctx, step.BranchName, eng.GetPushRemote(), true, false); err != nil {
 				return fmt.Errorf("failed to push rebased branch %s: %w", step.BranchName, err)
 			}
 			splog.Debug("Pushed rebased branch %s to remote", step.BranchName)
@@ -565,11 +573,11 @@ func executeStep(ctx context.Context, step PlanStep, eng mergeExecuteEngine, spl
 			if err := config.PersistContinuationState(repoRoot, continuation); err != nil {
 				return fmt.Errorf("failed to persist continuation: %w", err)
 			}
-			return fmt.Errorf("hit conflict restacking %s", step.BranchName)
+			return stackiterrors.WithCategory(stackiterrors.ErrRebaseConflict, fmt.Errorf("hit conflict restacking %s", step.BranchName))
 		case engine.RestackUnneeded:
 			// Already up to date, but still need to ensure PR base is correct
 			// Push in case local is ahead of remote
-			if err := eng.PushBranch(ctx, step.BranchName, eng.GetRemote(), true, false); err != nil {
+			if err := eng.PushBranch(ctx, step.BranchName, eng.GetPushRemote(), true, false); err != nil {
 				splog.Debug("Failed to push branch %s (may already be up to date): %v", step.BranchName, err)
 			}
 			// Update PR base to the actual parent (not always trunk)
@@ -672,7 +680,7 @@ func executeUpdatePRBase(ctx context.Context, eng mergeExecuteEngine, githubClie
 	}
 
 	if gitResult == engine.RestackConflict {
-		return fmt.Errorf("rebase conflict while rebasing %s onto %s", step.BranchName, trunkName)
+		return stackiterrors.WithCategory(stackiterrors.ErrRebaseConflict, fmt.Errorf("rebase conflict while rebasing %s onto %s", step.BranchName, trunkName))
 	}
 
 	// Update parent to trunk
@@ -829,42 +837,43 @@ func executeWaitCIWithProgress(ctx context.Context, step PlanStep, stepIndex int
 	}
 }
 
-// CheckSyncStatus checks if the repository is up to date with remote
+// CheckSyncStatus checks if the repository is up to date with remote, returning the
+// names of the stale branches. Use GetSyncStatus for per-branch ahead/behind counts.
 func CheckSyncStatus(ctx context.Context, eng engine.Engine, splog *tui.Splog) (bool, []string, error) {
-	needsSync := false
-	staleBranches := []string{}
+	status, err := GetSyncStatus(ctx, eng, splog)
+	if err != nil {
+		return false, nil, err
+	}
+	return status.NeedsSync, status.BranchNames(), nil
+}
+
+// GetSyncStatus checks if the repository is up to date with remote, pulling trunk and
+// counting how many commits each tracked branch is ahead of and behind its remote
+func GetSyncStatus(ctx context.Context, eng engine.Engine, splog *tui.Splog) (*actions.SyncStatus, error) {
+	status := &actions.SyncStatus{StaleBranches: []actions.StaleBranch{}}
 
 	// Check if trunk needs pulling
-	pullResult, err := eng.PullTrunk(ctx)
+	trunkName := eng.Trunk().GetName()
+	report, err := eng.PullTrunk(ctx)
 	if err != nil {
-		return false, nil, fmt.Errorf("failed to check trunk status: %w", err)
+		return nil, fmt.Errorf("failed to check trunk status: %w", err)
+	}
+	if report.Result == engine.PullReset {
+		// sync.trunkStrategy=reset-to-remote discarded local trunk commits; say so
+		splog.Warn("%s", actions.TrunkPullSummary(trunkName, report))
 	}
 
-	if pullResult == engine.PullDone {
-		needsSync = true
-		staleBranches = append(staleBranches, eng.Trunk().GetName())
+	if report.Result == engine.PullDone || report.Result == engine.PullRebased || report.Result == engine.PullReset {
+		status.NeedsSync = true
+		stale := actions.StaleBranch{BranchName: trunkName, Behind: report.Behind}
+		status.StaleBranches = append(status.StaleBranches, stale)
 	}
 
 	// Check all tracked branches
-	allBranches := eng.AllBranches()
-	for _, branch := range allBranches {
-		branchName := branch.GetName()
-		branch := eng.GetBranch(branchName)
-		if branch.IsTrunk() {
-			continue
-		}
-
-		matchesRemote, err := eng.BranchMatchesRemote(branchName)
-		if err != nil {
-			splog.Debug("Failed to check if %s matches remote: %v", branchName, err)
-			continue
-		}
-
-		if !matchesRemote {
-			needsSync = true
-			staleBranches = append(staleBranches, branchName)
-		}
+	if stale := actions.GetStaleBranches(eng, splog); len(stale) > 0 {
+		status.NeedsSync = true
+		status.StaleBranches = append(status.StaleBranches, stale...)
 	}
 
-	return needsSync, staleBranches, nil
+	return status, nil
 }
@@ -3,6 +3,9 @@ package merge
 import (
 	"fmt"
 
+	stackiterrors "stackit.dev/stackit/internal/errors"
+	"stackit.dev/stackit/internal/git"
+	"stackit.dev/stackit/internal/github"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui"
 )
@@ -13,9 +16,15 @@ type Options struct {
 	Confirm        bool
 	Strategy       Strategy
 	Force          bool
+	OnlyReady      bool // Merge only the bottom branches whose PRs are ready
 	UseWorktree    bool
 	Plan           *Plan // Optional pre-calculated plan
 	UndoStackDepth int   // Maximum undo stack depth (from config)
+	// RequireCleanStatus refuses to merge with staged, unstaged or untracked changes.
+	// Worktree merges are exempt since they don't touch the current working tree.
+	RequireCleanStatus bool
+	// BranchName merges the stack up to this branch instead of the current branch
+	BranchName string
 }
 
 // Action performs the merge operation using the plan/execute pattern
@@ -23,6 +32,18 @@ func Action(ctx *runtime.Context, opts Options) error {
 	eng := ctx.Engine
 	splog := ctx.Splog
 
+	if opts.BranchName != "" {
+		if err := validateTargetBranch(ctx, opts.BranchName); err != nil {
+			return err
+		}
+	}
+
+	if opts.RequireCleanStatus && !opts.DryRun && !opts.UseWorktree {
+		if err := CheckCleanStatus(ctx); err != nil {
+			return err
+		}
+	}
+
 	plan := opts.Plan
 	var validation *PlanValidation
 
@@ -39,19 +60,24 @@ func Action(ctx *runtime.Context, opts Options) error {
 		}
 
 		// 2. Check sync status
-		needsSync, staleBranches, err := CheckSyncStatus(ctx.Context, eng, splog)
-		if err == nil && needsSync {
+		syncStatus, err := GetSyncStatus(ctx.Context, eng, splog)
+		if err == nil && syncStatus.NeedsSync {
 			splog.Warn("Repository is not up to date with remote")
-			if len(staleBranches) > 0 {
-				splog.Info("Stale branches: %v", staleBranches)
+			if summary := syncStatus.Summary(); summary != "" {
+				splog.Info("%s.", summary)
+			}
+			if len(syncStatus.StaleBranches) > 0 {
+				splog.Info("Stale branches: %v", syncStatus.BranchNames())
 			}
 			splog.Tip("Run 'stackit sync' to update before merging")
 		}
 
 		// 3. Create merge plan
 		plan, validation, err = CreateMergePlan(ctx.Context, eng, splog, ctx.GitHubClient, CreatePlanOptions{
-			Strategy: strategy,
-			Force:    opts.Force,
+			Strategy:     strategy,
+			Force:        opts.Force,
+			TargetBranch: opts.BranchName,
+			OnlyReady:    opts.OnlyReady,
 		})
 		if err != nil {
 			return err
@@ -98,6 +124,13 @@ func Action(ctx *runtime.Context, opts Options) error {
 		return nil
 	}
 
+	// Merging goes through GitHub, so fail with setup guidance before changing anything
+	if ctx.GitHubClient == nil {
+		if err := github.CheckCLIAuth(ctx.Context); err != nil {
+			return err
+		}
+	}
+
 	// 6. Confirm if needed
 	if opts.Confirm {
 		confirmed, err := tui.PromptConfirm("Proceed with merge?", false)
@@ -130,3 +163,48 @@ func Action(ctx *runtime.Context, opts Options) error {
 	splog.Info("Merge completed successfully")
 	return nil
 }
+
+// CheckCleanStatus returns a validation error when the working tree has staged, unstaged
+// or untracked changes, which a merge's checkouts and restacks could lose or pick up
+func CheckCleanStatus(ctx *runtime.Context) error {
+	eng := ctx.Engine
+
+	staged, err := eng.HasStagedChanges(ctx.Context)
+	if err != nil {
+		return err
+	}
+	unstaged, err := eng.HasUnstagedChanges(ctx.Context)
+	if err != nil {
+		return err
+	}
+	untracked, err := git.HasUntrackedFiles(ctx.Context)
+	if err != nil {
+		return err
+	}
+	if !staged && !unstaged && !untracked {
+		return nil
+	}
+	return stackiterrors.NewValidationError("cannot merge with uncommitted or untracked changes; commit or stash them, " +
+		"or use --worktree or --allow-dirty (see merge.requireCleanStatus)")
+}
+
+// validateTargetBranch checks that a branch given with --branch can be merged: it must be a
+// tracked branch other than trunk with a PR
+func validateTargetBranch(ctx *runtime.Context, branchName string) error {
+	eng := ctx.Engine
+	branch := eng.GetBranch(branchName)
+	if branch.IsTrunk() {
+		return stackiterrors.NewValidationError("cannot merge trunk; pass a branch that has a PR")
+	}
+	if !branch.IsTracked() {
+		return stackiterrors.NewValidationError("branch %s is not tracked by stackit", branchName)
+	}
+	prInfo, err := eng.GetPrInfo(branch)
+	if err != nil {
+		return fmt.Errorf("failed to get PR info for %s: %w", branchName, err)
+	}
+	if prInfo == nil || prInfo.Number() == nil {
+		return stackiterrors.NewValidationError("branch %s has no PR; run stackit submit first", branchName)
+	}
+	return nil
+}
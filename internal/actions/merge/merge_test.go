@@ -1,11 +1,17 @@
 package merge_test
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/actions/merge"
+	"stackit.dev/stackit/internal/engine"
+	"stackit.dev/stackit/internal/tui"
 	"stackit.dev/stackit/testhelpers"
 	"stackit.dev/stackit/testhelpers/scenario"
 )
@@ -87,6 +93,53 @@ func TestAction(t *testing.T) {
 		require.Contains(t, err.Error(), "no open PRs found")
 	})
 
+	t.Run("refuses to start with a dirty working tree", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+			})
+		s.Checkout("branch1")
+
+		// Untracked file
+		require.NoError(t, os.WriteFile(filepath.Join(s.Scene.Dir, "scratch.txt"), []byte("wip"), 0o600))
+		err := merge.Action(s.Context, merge.Options{
+			Strategy:           merge.StrategyBottomUp,
+			RequireCleanStatus: true,
+		})
+		require.ErrorContains(t, err, "cannot merge with uncommitted or untracked changes")
+
+		// Staged change
+		s.RunGit("add", "scratch.txt")
+		err = merge.Action(s.Context, merge.Options{
+			Strategy:           merge.StrategyBottomUp,
+			RequireCleanStatus: true,
+		})
+		require.ErrorContains(t, err, "cannot merge with uncommitted or untracked changes")
+	})
+
+	t.Run("proceeds with a dirty working tree when clean status isn't required", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+			})
+		s.Checkout("branch1")
+		require.NoError(t, os.WriteFile(filepath.Join(s.Scene.Dir, "scratch.txt"), []byte("wip"), 0o600))
+
+		// Gets past the precondition and stops at planning, since branch1 has no PR
+		err := merge.Action(s.Context, merge.Options{
+			Strategy: merge.StrategyBottomUp,
+		})
+		require.ErrorContains(t, err, "no open PRs found")
+
+		// Worktree merges are exempt
+		err = merge.Action(s.Context, merge.Options{
+			Strategy:           merge.StrategyBottomUp,
+			UseWorktree:        true,
+			RequireCleanStatus: true,
+		})
+		require.ErrorContains(t, err, "no open PRs found")
+	})
+
 	t.Run("dry run mode reports PRs without merging", func(t *testing.T) {
 		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
 			WithStack(map[string]string{
@@ -240,3 +293,75 @@ func TestAction(t *testing.T) {
 		require.Equal(t, "branch-b", *updatedPRC.Base.Ref, "branch-c PR base should be branch-b (not main) to preserve stack structure")
 	})
 }
+
+func TestGetSyncStatus(t *testing.T) {
+	t.Run("reports ahead and behind counts relative to remote", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"diverged": "main",
+				"ahead":    "main",
+				"synced":   "main",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		for _, branch := range []string{"main", "diverged", "ahead", "synced"} {
+			require.NoError(t, s.Scene.Repo.PushBranch("origin", branch))
+		}
+
+		// diverged: one commit only on the remote, two only local
+		s.Checkout("diverged").
+			CommitChange("remote-only", "remote only")
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "diverged"))
+		s.RunGit("reset", "--hard", "HEAD~1").
+			CommitChange("local-1", "local 1").
+			CommitChange("local-2", "local 2")
+
+		// ahead: one local commit not yet pushed
+		s.Checkout("ahead").
+			CommitChange("unpushed", "unpushed")
+		s.Checkout("main").Rebuild()
+
+		status, err := merge.GetSyncStatus(s.Context.Context, s.Engine, s.Context.Splog)
+		require.NoError(t, err)
+		require.True(t, status.NeedsSync)
+		require.ElementsMatch(t, []actions.StaleBranch{
+			{BranchName: "diverged", Ahead: 2, Behind: 1},
+			{BranchName: "ahead", Ahead: 1, Behind: 0},
+		}, status.StaleBranches)
+		require.ElementsMatch(t, []string{"diverged", "ahead"}, status.BranchNames())
+		require.Equal(t, "1 branch behind remote, 2 ahead", status.Summary())
+
+		// The simple form reports the same branches
+		needsSync, staleBranches, err := merge.CheckSyncStatus(s.Context.Context, s.Engine, s.Context.Splog)
+		require.NoError(t, err)
+		require.True(t, needsSync)
+		require.ElementsMatch(t, []string{"diverged", "ahead"}, staleBranches)
+	})
+
+	t.Run("warns when reset-to-remote discards local trunk commits", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		s.Checkout("main").
+			CommitChange("remote-only", "remote only")
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "main"))
+		s.RunGit("reset", "--hard", "HEAD~1").
+			CommitChange("local-only", "local only")
+
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot:      s.Scene.Dir,
+			Trunk:         "main",
+			TrunkStrategy: engine.TrunkStrategyResetToRemote,
+		})
+		require.NoError(t, err)
+
+		var out bytes.Buffer
+		status, err := merge.GetSyncStatus(s.Context.Context, eng, tui.NewSplogWithWriter(&out))
+		require.NoError(t, err)
+		require.True(t, status.NeedsSync)
+		require.Contains(t, out.String(), "main had diverged from remote and was reset to")
+		require.Contains(t, out.String(), "1 local commit discarded")
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/github"
 	"stackit.dev/stackit/internal/tui"
 )
@@ -23,6 +24,21 @@ const (
 	StrategyConsolidate Strategy = "consolidate"
 )
 
+// ParseStrategy parses a strategy name as given with --strategy or merge.strategy,
+// also accepting the names without hyphens
+func ParseStrategy(name string) (Strategy, error) {
+	switch strings.ToLower(name) {
+	case "bottom-up", "bottomup":
+		return StrategyBottomUp, nil
+	case "top-down", "topdown":
+		return StrategyTopDown, nil
+	case "consolidate":
+		return StrategyConsolidate, nil
+	default:
+		return "", fmt.Errorf("invalid strategy: %s (must be 'bottom-up', 'top-down', or 'consolidate')", name)
+	}
+}
+
 // StepType represents the type of step in a merge plan
 type StepType string
 
@@ -76,12 +92,19 @@ type PlanStep struct {
 	WaitTimeout time.Duration // Timeout for waiting steps (e.g., CI checks)
 }
 
+// SkippedBranch is a branch left out of an --only-ready merge, with the reason why
+type SkippedBranch struct {
+	BranchName string
+	Reason     string
+}
+
 // Plan is the complete plan for a merge operation
 type Plan struct {
 	Strategy        Strategy
 	CurrentBranch   string
 	BranchesToMerge []BranchMergeInfo // Branches that will be merged (bottom to top)
 	UpstackBranches []string          // Branches above current that will be restacked
+	SkippedBranches []SkippedBranch   // Branches not merged because they weren't ready (--only-ready)
 	Steps           []PlanStep        // Ordered steps to execute
 	Warnings        []string          // Non-blocking warnings
 	Infos           []string          // Informational messages
@@ -102,6 +125,7 @@ type CreatePlanOptions struct {
 	Force        bool
 	Scope        string
 	TargetBranch string // Optional branch to merge from (instead of current)
+	OnlyReady    bool   // Merge only the bottom branches that are ready, stopping at the first that isn't
 }
 
 // mergePlanEngine is a minimal interface needed for creating a merge plan
@@ -123,7 +147,7 @@ func CreateMergePlan(ctx context.Context, eng mergePlanEngine, splog *tui.Splog,
 	} else {
 		cb := eng.CurrentBranch()
 		if cb == nil {
-			return nil, nil, fmt.Errorf("not on a branch")
+			return nil, nil, stackiterrors.ErrNotOnBranch
 		}
 		targetBranch = *cb
 	}
@@ -185,12 +209,35 @@ func CreateMergePlan(ctx context.Context, eng mergePlanEngine, splog *tui.Splog,
 		Warnings: []string{},
 	}
 
-	for _, branchName := range allBranches {
+	// With --only-ready, the first branch that isn't ready ends the merge; it and every
+	// branch above it are skipped and restacked instead
+	var skippedBranches []SkippedBranch
+	stopOnlyReady := func(i int, reason string) bool {
+		if !opts.OnlyReady {
+			return false
+		}
+		skippedBranches = append(skippedBranches, SkippedBranch{BranchName: allBranches[i], Reason: reason})
+		for _, above := range allBranches[i+1:] {
+			skippedBranches = append(skippedBranches, SkippedBranch{
+				BranchName: above,
+				Reason:     fmt.Sprintf("stacked on %s, which is not ready", allBranches[i]),
+			})
+		}
+		return true
+	}
+
+	readyCount := len(allBranches)
+branches:
+	for i, branchName := range allBranches {
 		// Get PR info
 		branch := eng.GetBranch(branchName)
 		prInfo, err := eng.GetPrInfo(branch)
 		if err != nil {
 			splog.Debug("Failed to get PR info for %s: %v", branchName, err)
+			if stopOnlyReady(i, fmt.Sprintf("failed to get PR info: %v", err)) {
+				readyCount = i
+				break
+			}
 			validation.Valid = false
 			validation.Errors = append(validation.Errors, fmt.Sprintf("Failed to get PR info for %s: %v", branchName, err))
 			continue
@@ -198,6 +245,10 @@ func CreateMergePlan(ctx context.Context, eng mergePlanEngine, splog *tui.Splog,
 
 		// Check if PR exists
 		if prInfo == nil || prInfo.Number() == nil {
+			if stopOnlyReady(i, "no associated PR") {
+				readyCount = i
+				break
+			}
 			validation.Valid = false
 			validation.Errors = append(validation.Errors, fmt.Sprintf("Branch %s has no associated PR", branchName))
 			continue
@@ -210,6 +261,10 @@ func CreateMergePlan(ctx context.Context, eng mergePlanEngine, splog *tui.Splog,
 				splog.Debug("Skipping %s: PR #%d is already merged", branchName, *prInfo.Number())
 				continue
 			}
+			if stopOnlyReady(i, fmt.Sprintf("PR #%d is %s (not open)", *prInfo.Number(), state)) {
+				readyCount = i
+				break
+			}
 			validation.Valid = false
 			validation.Errors = append(validation.Errors, fmt.Sprintf("Branch %s PR #%d is %s (not open)", branchName, *prInfo.Number(), state))
 			continue
@@ -217,6 +272,10 @@ func CreateMergePlan(ctx context.Context, eng mergePlanEngine, splog *tui.Splog,
 
 		// Check if draft
 		if prInfo.IsDraft() && !opts.Force {
+			if stopOnlyReady(i, fmt.Sprintf("PR #%d is a draft", *prInfo.Number())) {
+				readyCount = i
+				break
+			}
 			validation.Valid = false
 			validation.Errors = append(validation.Errors, fmt.Sprintf("Branch %s PR #%d is a draft", branchName, *prInfo.Number()))
 		}
@@ -229,7 +288,7 @@ func CreateMergePlan(ctx context.Context, eng mergePlanEngine, splog *tui.Splog,
 		}
 		if !matchesRemote && prInfo != nil && prInfo.Number() != nil {
 			// Get detailed difference information
-			diffInfo, _ := eng.GetBranchRemoteDifference(branchName)
+			diffInfo, _ := eng.GetBranchRemoteDifference(branchName, "")
 			if diffInfo != "" {
 				validation.Warnings = append(validation.Warnings, fmt.Sprintf("Branch %s differs from remote: %s", branchName, diffInfo))
 			} else {
@@ -239,15 +298,32 @@ func CreateMergePlan(ctx context.Context, eng mergePlanEngine, splog *tui.Splog,
 
 		// Get CI check status
 		checksStatus := ChecksNone
-		if githubClient != nil {
+		if githubClient == nil {
+			if stopOnlyReady(i, "couldn't read CI status: not connected to GitHub") {
+				readyCount = i
+				break
+			}
+		} else {
 			status, checkErr := githubClient.GetPRChecksStatus(ctx, branchName)
 			switch {
 			case checkErr != nil:
 				splog.Debug("Failed to get PR checks status for %s: %v", branchName, checkErr)
+				if stopOnlyReady(i, fmt.Sprintf("couldn't read CI status: %v", checkErr)) {
+					readyCount = i
+					break branches
+				}
 			case status.Pending:
 				checksStatus = ChecksPending
+				if stopOnlyReady(i, fmt.Sprintf("PR #%d has pending CI checks", *prInfo.Number())) {
+					readyCount = i
+					break branches
+				}
 			case !status.Passing:
 				checksStatus = ChecksFailing
+				if stopOnlyReady(i, fmt.Sprintf("PR #%d has failing CI checks", *prInfo.Number())) {
+					readyCount = i
+					break branches
+				}
 				if !opts.Force {
 					validation.Valid = false
 					validation.Errors = append(validation.Errors, fmt.Sprintf("Branch %s PR #%d has failing CI checks", branchName, *prInfo.Number()))
@@ -267,6 +343,15 @@ func CreateMergePlan(ctx context.Context, eng mergePlanEngine, splog *tui.Splog,
 		})
 	}
 
+	if len(skippedBranches) > 0 {
+		if len(branchesToMerge) == 0 {
+			return nil, validation, fmt.Errorf("no branches are ready to merge: %s %s", skippedBranches[0].BranchName, skippedBranches[0].Reason)
+		}
+		// Merge only the ready prefix; everything above it is restacked as upstack
+		allBranches = allBranches[:readyCount]
+		planCurrentBranch = branchesToMerge[len(branchesToMerge)-1].BranchName
+	}
+
 	// If no PRs to merge, return early
 	if len(branchesToMerge) == 0 {
 		return nil, validation, fmt.Errorf("no open PRs found to merge")
@@ -277,6 +362,9 @@ func CreateMergePlan(ctx context.Context, eng mergePlanEngine, splog *tui.Splog,
 	for _, branch := range allBranches {
 		mergedSet[branch] = true
 	}
+	for _, skipped := range skippedBranches {
+		mergedSet[skipped.BranchName] = true // Reported separately
+	}
 
 	for _, ancestor := range allBranches {
 		ancestorBranch := eng.GetBranch(ancestor)
@@ -344,6 +432,7 @@ func CreateMergePlan(ctx context.Context, eng mergePlanEngine, splog *tui.Splog,
 		CurrentBranch:   planCurrentBranch,
 		BranchesToMerge: branchesToMerge,
 		UpstackBranches: upstackBranches,
+		SkippedBranches: skippedBranches,
 		Steps:           steps,
 		Warnings:        validation.Warnings,
 		Infos:           validation.Infos,
@@ -548,6 +637,14 @@ func FormatMergePlan(plan *Plan, validation *PlanValidation) string {
 		result.WriteString("\n")
 	}
 
+	if len(plan.SkippedBranches) > 0 {
+		result.WriteString("Skipped (not ready):\n")
+		for _, skipped := range plan.SkippedBranches {
+			result.WriteString(fmt.Sprintf("  - %s: %s\n", skipped.BranchName, skipped.Reason))
+		}
+		result.WriteString("\n")
+	}
+
 	result.WriteString("Merge Plan:\n")
 	for i, step := range plan.Steps {
 		result.WriteString(fmt.Sprintf("  %d. %s\n", i+1, step.Description))
@@ -8,10 +8,28 @@ import (
 
 	"stackit.dev/stackit/internal/actions/merge"
 	"stackit.dev/stackit/internal/engine"
+	"stackit.dev/stackit/internal/github"
 	"stackit.dev/stackit/testhelpers"
 	"stackit.dev/stackit/testhelpers/scenario"
 )
 
+func TestParseStrategy(t *testing.T) {
+	for name, want := range map[string]merge.Strategy{
+		"bottom-up":   merge.StrategyBottomUp,
+		"BottomUp":    merge.StrategyBottomUp,
+		"top-down":    merge.StrategyTopDown,
+		"topdown":     merge.StrategyTopDown,
+		"consolidate": merge.StrategyConsolidate,
+	} {
+		got, err := merge.ParseStrategy(name)
+		require.NoError(t, err, name)
+		require.Equal(t, want, got, name)
+	}
+
+	_, err := merge.ParseStrategy("sideways")
+	require.ErrorContains(t, err, "invalid strategy: sideways")
+}
+
 func TestCreateMergePlan(t *testing.T) {
 	t.Run("creates plan for bottom-up strategy", func(t *testing.T) {
 		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
@@ -352,4 +370,164 @@ func TestCreateMergePlan(t *testing.T) {
 		require.Equal(t, merge.StepDeleteBranch, plan.Steps[1].StepType)
 		require.Equal(t, merge.StepDeleteBranch, plan.Steps[2].StepType)
 	})
+
+	t.Run("bottom-up and top-down plan different steps for the same stack", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "branch1",
+				"branch3": "branch2",
+			})
+		for i, name := range []string{"branch1", "branch2", "branch3"} {
+			require.NoError(t, s.Engine.UpsertPrInfo(s.Engine.GetBranch(name), testhelpers.NewTestPrInfo(101+i)))
+		}
+		s.Checkout("branch3")
+
+		stepTypes := func(strategy merge.Strategy) []merge.StepType {
+			t.Helper()
+			plan, _, err := merge.CreateMergePlan(s.Context.Context, s.Engine, s.Context.Splog, s.Context.GitHubClient, merge.CreatePlanOptions{
+				Strategy: strategy,
+			})
+			require.NoError(t, err)
+			require.Equal(t, strategy, plan.Strategy)
+			types := make([]merge.StepType, len(plan.Steps))
+			for i, step := range plan.Steps {
+				types[i] = step.StepType
+			}
+			return types
+		}
+
+		// Bottom-up merges every PR, restacking the next branch onto trunk after each merge
+		require.Equal(t, []merge.StepType{
+			merge.StepWaitCI, merge.StepMergePR, merge.StepPullTrunk, merge.StepRestack,
+			merge.StepWaitCI, merge.StepMergePR, merge.StepPullTrunk, merge.StepRestack,
+			merge.StepWaitCI, merge.StepMergePR, merge.StepPullTrunk,
+			merge.StepDeleteBranch, merge.StepDeleteBranch, merge.StepDeleteBranch,
+		}, stepTypes(merge.StrategyBottomUp))
+
+		// Top-down retargets the top PR onto trunk and merges only that one
+		require.Equal(t, []merge.StepType{
+			merge.StepUpdatePRBase, merge.StepUpdatePRBase,
+			merge.StepWaitCI, merge.StepMergePR, merge.StepPullTrunk,
+			merge.StepDeleteBranch, merge.StepDeleteBranch, merge.StepDeleteBranch,
+		}, stepTypes(merge.StrategyTopDown))
+	})
+
+	t.Run("only-ready merges the bottom branches with passing CI", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "branch1",
+				"branch3": "branch2",
+				"branch4": "branch3",
+			})
+
+		for i, name := range []string{"branch1", "branch2", "branch3", "branch4"} {
+			require.NoError(t, s.Engine.UpsertPrInfo(s.Engine.GetBranch(name), testhelpers.NewTestPrInfo(101+i)))
+		}
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		config.ChecksStatus["branch3"] = &github.CheckStatus{Passing: false}
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		githubClient := testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("branch4")
+
+		plan, validation, err := merge.CreateMergePlan(s.Context.Context, s.Engine, s.Context.Splog, githubClient, merge.CreatePlanOptions{
+			Strategy:  merge.StrategyBottomUp,
+			OnlyReady: true,
+		})
+		require.NoError(t, err)
+		require.True(t, validation.Valid, validation.Errors)
+
+		mergedBranches := []string{}
+		for _, step := range plan.Steps {
+			if step.StepType == merge.StepMergePR {
+				mergedBranches = append(mergedBranches, step.BranchName)
+			}
+		}
+		require.Equal(t, []string{"branch1", "branch2"}, mergedBranches)
+		require.Len(t, plan.BranchesToMerge, 2)
+		require.Equal(t, "branch2", plan.CurrentBranch)
+
+		// The rest of the stack is restacked rather than merged
+		require.Equal(t, []string{"branch3", "branch4"}, plan.UpstackBranches)
+		require.Equal(t, []merge.SkippedBranch{
+			{BranchName: "branch3", Reason: "PR #103 has failing CI checks"},
+			{BranchName: "branch4", Reason: "stacked on branch3, which is not ready"},
+		}, plan.SkippedBranches)
+		require.Contains(t, merge.FormatMergePlan(plan, validation), "branch3: PR #103 has failing CI checks")
+	})
+
+	t.Run("only-ready stops at a branch whose CI status can't be read", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "branch1",
+				"branch3": "branch2",
+			})
+
+		for i, name := range []string{"branch1", "branch2", "branch3"} {
+			require.NoError(t, s.Engine.UpsertPrInfo(s.Engine.GetBranch(name), testhelpers.NewTestPrInfo(101+i)))
+		}
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		config.ChecksUnavailable["branch2"] = true
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		githubClient := testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("branch3")
+
+		plan, _, err := merge.CreateMergePlan(s.Context.Context, s.Engine, s.Context.Splog, githubClient, merge.CreatePlanOptions{
+			Strategy:  merge.StrategyBottomUp,
+			OnlyReady: true,
+		})
+		require.NoError(t, err)
+		require.Len(t, plan.BranchesToMerge, 1)
+		require.Equal(t, "branch1", plan.BranchesToMerge[0].BranchName)
+		require.Equal(t, []string{"branch2", "branch3"}, plan.UpstackBranches)
+		require.Equal(t, []merge.SkippedBranch{
+			{BranchName: "branch2", Reason: "couldn't read CI status: failed to get check runs for branch2: 502 Bad Gateway"},
+			{BranchName: "branch3", Reason: "stacked on branch2, which is not ready"},
+		}, plan.SkippedBranches)
+	})
+
+	t.Run("only-ready merges nothing without a GitHub client", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+			})
+		require.NoError(t, s.Engine.UpsertPrInfo(s.Engine.GetBranch("branch1"), testhelpers.NewTestPrInfo(101)))
+		s.Checkout("branch1")
+
+		_, _, err := merge.CreateMergePlan(s.Context.Context, s.Engine, s.Context.Splog, nil, merge.CreatePlanOptions{
+			Strategy:  merge.StrategyBottomUp,
+			OnlyReady: true,
+		})
+		require.ErrorContains(t, err, "no branches are ready to merge: branch1 couldn't read CI status: not connected to GitHub")
+	})
+
+	t.Run("only-ready fails when the bottom branch is not ready", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "branch1",
+			})
+
+		require.NoError(t, s.Engine.UpsertPrInfo(s.Engine.GetBranch("branch1"), testhelpers.NewTestPrInfo(101)))
+		require.NoError(t, s.Engine.UpsertPrInfo(s.Engine.GetBranch("branch2"), testhelpers.NewTestPrInfo(102)))
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		config.ChecksStatus["branch1"] = &github.CheckStatus{Pending: true}
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		githubClient := testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("branch2")
+
+		_, _, err := merge.CreateMergePlan(s.Context.Context, s.Engine, s.Context.Splog, githubClient, merge.CreatePlanOptions{
+			Strategy:  merge.StrategyBottomUp,
+			OnlyReady: true,
+		})
+		require.ErrorContains(t, err, "no branches are ready to merge: branch1 PR #101 has pending CI checks")
+	})
 }
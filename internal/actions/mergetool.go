@@ -0,0 +1,56 @@
+package actions
+
+import (
+	"stackit.dev/stackit/internal/git"
+	"stackit.dev/stackit/internal/runtime"
+	"stackit.dev/stackit/internal/tui/style"
+	"stackit.dev/stackit/internal/utils"
+)
+
+// isInteractive reports whether a mergetool can be launched. It is a variable so tests
+// can run a scripted mergetool without a terminal.
+var isInteractive = utils.IsInteractive
+
+// resolveConflictsWithMergetool launches git's merge.tool each time a restack stops on a
+// conflict, continuing the restack whenever the tool resolves every conflicted file.
+// The last error is returned, with the continuation state already persisted for
+// stackit continue, when the session isn't interactive, no merge.tool is configured,
+// conflicts are left unresolved, or the restack stops without conflicted files or
+// stops again on the same commit.
+func resolveConflictsWithMergetool(ctx *runtime.Context, restackErr error) error {
+	splog := ctx.Splog
+	if !isInteractive() {
+		return restackErr
+	}
+	tool := git.GetMergeTool(ctx.Context)
+	if tool == "" {
+		splog.Info("No merge.tool is configured in git; resolve the conflicts manually.")
+		return restackErr
+	}
+
+	for restackErr != nil && (git.IsRebaseInProgress(ctx.Context) || git.IsMergeInProgress(ctx.Context)) {
+		// A stop without conflicts, e.g. from a failing hook, is nothing the tool can fix
+		if unmerged, _ := git.GetUnmergedFiles(ctx.Context); len(unmerged) == 0 {
+			return restackErr
+		}
+		stoppedAt, _ := git.GetRebaseHead()
+
+		splog.Info("Launching %s to resolve the conflicts...", style.ColorCyan(tool))
+		if err := git.RunMergetool(); err != nil {
+			splog.Debug("mergetool failed: %v", err)
+		}
+		if unmerged, _ := git.GetUnmergedFiles(ctx.Context); len(unmerged) > 0 {
+			splog.Info("%d file(s) are still conflicted.", len(unmerged))
+			return restackErr
+		}
+		restackErr = ContinueAction(ctx, ContinueOptions{})
+
+		// Stopping on the same commit again means another round won't get any further
+		if restackErr != nil {
+			if next, _ := git.GetRebaseHead(); next == stoppedAt {
+				return restackErr
+			}
+		}
+	}
+	return restackErr
+}
@@ -0,0 +1,128 @@
+package actions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/internal/config"
+	"stackit.dev/stackit/internal/engine"
+	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
+)
+
+func TestRestackMergetool(t *testing.T) {
+	t.Run("resolves conflicts and finishes the restack", func(t *testing.T) {
+		// a and a later commit on main change the same file, and a stub merge.tool
+		// resolves conflicts by writing "resolved"
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		s.CreateBranch("a").
+			CommitChange("shared", "a version").
+			TrackBranch("a", "main")
+		s.CreateBranch("b").
+			CommitChange("b", "b change").
+			TrackBranch("b", "a")
+		s.Checkout("main").
+			CommitChange("shared", "main version")
+		s.Checkout("b")
+		s.RunGit("config", "merge.tool", "stub").
+			RunGit("config", "mergetool.stub.cmd", `printf resolved > "$MERGED"`).
+			RunGit("config", "mergetool.stub.trustExitCode", "true").
+			RunGit("config", "mergetool.keepBackup", "false")
+		original := isInteractive
+		t.Cleanup(func() { isInteractive = original })
+		isInteractive = func() bool { return true }
+
+		require.NoError(t, RestackAction(s.Context, RestackOptions{
+			BranchName: "b",
+			Scope:      engine.StackRange{RecursiveParents: true, IncludeCurrent: true, RecursiveChildren: true},
+			Mergetool:  true,
+		}))
+
+		mainRev, err := s.Scene.Repo.GetRevision("main")
+		require.NoError(t, err)
+		aParent, err := s.Scene.Repo.GetRevision("a~1")
+		require.NoError(t, err)
+		require.Equal(t, mainRev, aParent, "a should be restacked onto main")
+		aRev, err := s.Scene.Repo.GetRevision("a")
+		require.NoError(t, err)
+		bParent, err := s.Scene.Repo.GetRevision("b~1")
+		require.NoError(t, err)
+		require.Equal(t, aRev, bParent, "b should be restacked onto a")
+
+		content, err := s.Scene.Repo.RunGitCommandAndGetOutput("show", "a:shared_test.txt")
+		require.NoError(t, err)
+		require.Equal(t, "resolved", content)
+		_, err = config.GetContinuationState(s.Scene.Dir)
+		require.Error(t, err, "continuation state should be cleared")
+	})
+
+	t.Run("is ignored when not interactive", func(t *testing.T) {
+		// a and a later commit on main change the same file, and a stub merge.tool
+		// resolves conflicts by writing "resolved"
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		s.CreateBranch("a").
+			CommitChange("shared", "a version").
+			TrackBranch("a", "main")
+		s.CreateBranch("b").
+			CommitChange("b", "b change").
+			TrackBranch("b", "a")
+		s.Checkout("main").
+			CommitChange("shared", "main version")
+		s.Checkout("b")
+		s.RunGit("config", "merge.tool", "stub").
+			RunGit("config", "mergetool.stub.cmd", `printf resolved > "$MERGED"`).
+			RunGit("config", "mergetool.stub.trustExitCode", "true").
+			RunGit("config", "mergetool.keepBackup", "false")
+		original := isInteractive
+		t.Cleanup(func() { isInteractive = original })
+		isInteractive = func() bool { return false }
+
+		err := RestackAction(s.Context, RestackOptions{
+			BranchName: "b",
+			Scope:      engine.StackRange{RecursiveParents: true, IncludeCurrent: true, RecursiveChildren: true},
+			Mergetool:  true,
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "restack stopped due to conflict on a")
+
+		_, err = os.Stat(filepath.Join(s.Scene.Dir, ".git", ".stackit_continue"))
+		require.NoError(t, err, "continuation state should be persisted")
+	})
+
+	t.Run("stops when continuing fails without conflicted files", func(t *testing.T) {
+		// a and a later commit on main change the same file, and a stub merge.tool
+		// resolves conflicts by writing "resolved"
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		s.CreateBranch("a").
+			CommitChange("shared", "a version").
+			TrackBranch("a", "main")
+		s.CreateBranch("b").
+			CommitChange("b", "b change").
+			TrackBranch("b", "a")
+		s.Checkout("main").
+			CommitChange("shared", "main version")
+		s.Checkout("b")
+		s.RunGit("config", "merge.tool", "stub").
+			RunGit("config", "mergetool.stub.cmd", `printf resolved > "$MERGED"`).
+			RunGit("config", "mergetool.stub.trustExitCode", "true").
+			RunGit("config", "mergetool.keepBackup", "false")
+		original := isInteractive
+		t.Cleanup(func() { isInteractive = original })
+		isInteractive = func() bool { return true }
+
+		// The conflict resolves, but committing it keeps failing
+		hook := filepath.Join(s.Scene.Dir, ".git", "hooks", "prepare-commit-msg")
+		require.NoError(t, os.WriteFile(hook, []byte("#!/bin/sh\nexit 1\n"), 0o755))
+
+		require.Error(t, RestackAction(s.Context, RestackOptions{
+			BranchName: "b",
+			Scope:      engine.StackRange{RecursiveParents: true, IncludeCurrent: true, RecursiveChildren: true},
+			Mergetool:  true,
+		}))
+		_, err := os.Stat(filepath.Join(s.Scene.Dir, ".git", ".stackit_continue"))
+		require.NoError(t, err, "continuation state should be persisted for stackit continue")
+	})
+}
@@ -2,7 +2,10 @@ package actions
 
 import (
 	"fmt"
+	"slices"
 
+	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/git"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui/style"
@@ -23,6 +26,10 @@ type ModifyOptions struct {
 	NoEdit       bool   // Don't edit commit message (computed from flags)
 	ResetAuthor  bool   // Reset author to current user
 	Verbose      int    // Show diff in commit message template (-v)
+	// TrailerTemplates are commit.trailers templates, whose placeholders are expanded
+	TrailerTemplates []string
+	// Trailers are --trailer flags, as "Key: value", added as given
+	Trailers []string
 
 	// Interactive rebase
 	InteractiveRebase bool // Start interactive rebase on branch commits
@@ -57,11 +64,11 @@ func ModifyAction(ctx *runtime.Context, opts ModifyOptions) error {
 
 	// Check if branch is empty when amending
 	if !opts.CreateCommit {
-		isEmpty, err := eng.IsBranchEmpty(gctx, currentBranch)
+		content, err := eng.GetBranchContent(gctx, currentBranch)
 		if err != nil {
 			return fmt.Errorf("failed to check if branch is empty: %w", err)
 		}
-		if isEmpty {
+		if content == engine.BranchContentNone {
 			// If branch is empty, we must create a new commit
 			opts.CreateCommit = true
 			splog.Info("Branch has no commits, creating new commit instead of amending.")
@@ -106,6 +113,7 @@ func ModifyAction(ctx *runtime.Context, opts ModifyOptions) error {
 		Edit:        opts.Edit,
 		Verbose:     opts.Verbose,
 		ResetAuthor: opts.ResetAuthor,
+		Trailers:    slices.Concat(ExpandCommitTrailers(gctx, opts.TrailerTemplates, currentBranch), opts.Trailers),
 	}
 
 	if err := git.CommitWithOptions(commitOpts); err != nil {
@@ -157,7 +165,7 @@ func interactiveRebaseAction(ctx *runtime.Context, _ ModifyOptions) error {
 	if err := git.RunGitCommandInteractive("rebase", "-i", parentName); err != nil {
 		// Check if rebase is in progress (conflict or user canceled)
 		if git.IsRebaseInProgress(gctx) {
-			return fmt.Errorf("interactive rebase paused. Resolve conflicts and run 'git rebase --continue' or 'git rebase --abort'")
+			return stackiterrors.WithCategory(stackiterrors.ErrRebaseConflict, fmt.Errorf("interactive rebase paused. Resolve conflicts and run 'git rebase --continue' or 'git rebase --abort'"))
 		}
 		// Rebase might have been aborted by user
 		return nil
@@ -6,6 +6,7 @@ import (
 
 	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui"
 	"stackit.dev/stackit/internal/tui/style"
@@ -29,7 +30,7 @@ func Action(ctx *runtime.Context, opts Options) error {
 	if source == "" {
 		currentBranch := eng.CurrentBranch()
 		if currentBranch == nil {
-			return fmt.Errorf("not on a branch and no source branch specified")
+			return fmt.Errorf("%w and no source branch specified", stackiterrors.ErrNotOnBranch)
 		}
 		source = currentBranch.GetName()
 	}
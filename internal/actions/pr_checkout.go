@@ -0,0 +1,173 @@
+package actions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"stackit.dev/stackit/internal/engine"
+	"stackit.dev/stackit/internal/git"
+	"stackit.dev/stackit/internal/github"
+	"stackit.dev/stackit/internal/runtime"
+	"stackit.dev/stackit/internal/tui/style"
+	"stackit.dev/stackit/internal/utils"
+)
+
+// maxPrCheckoutDepth bounds how many PRs below the requested one are checked out, in
+// case PR bases form a cycle
+const maxPrCheckoutDepth = 50
+
+// PrCheckoutOptions contains options for the pr checkout command
+type PrCheckoutOptions struct {
+	Number int
+}
+
+// PrCheckoutAction fetches a PR's head into a local branch, tracks it onto the branch its
+// PR is based on and checks it out. When the base is another PR's branch that isn't tracked
+// locally, that PR is checked out first, so a reviewer gets the whole stack below the PR.
+func PrCheckoutAction(ctx *runtime.Context, opts PrCheckoutOptions) error {
+	eng := ctx.Engine
+	splog := ctx.Splog
+
+	client := ctx.GitHubClient
+	if client == nil {
+		if err := github.CheckCLIAuth(ctx.Context); err != nil {
+			return err
+		}
+		return fmt.Errorf("failed to create GitHub client")
+	}
+
+	if utils.HasUncommittedChanges(ctx.Context) {
+		return fmt.Errorf("cannot check out a PR with uncommitted changes. Please commit or stash them first")
+	}
+
+	snapshotOpts := NewSnapshot("pr-checkout", WithArg(strconv.Itoa(opts.Number)))
+	if err := eng.TakeSnapshot(snapshotOpts); err != nil {
+		// Log but don't fail - snapshot is best effort
+		splog.Debug("Failed to take snapshot: %v", err)
+	}
+
+	branchName, err := checkoutPr(ctx, client, opts.Number, 0)
+	if err != nil {
+		return err
+	}
+
+	if err := eng.CheckoutBranch(ctx.Context, eng.GetBranch(branchName)); err != nil {
+		return err
+	}
+	splog.Info("Checked out PR #%d as %s.", opts.Number, style.ColorBranchName(branchName, true))
+	return nil
+}
+
+// checkoutPr creates or fast-forwards the local branch for a PR and tracks it onto the
+// branch of the PR's base, checking out the base's own PR first if it isn't tracked. It
+// returns the name of the local branch.
+func checkoutPr(ctx *runtime.Context, client github.Client, number int, depth int) (string, error) {
+	eng := ctx.Engine
+	splog := ctx.Splog
+
+	if depth > maxPrCheckoutDepth {
+		return "", fmt.Errorf("PR #%d is more than %d PRs above trunk", number, maxPrCheckoutDepth)
+	}
+
+	owner, repo := client.GetOwnerRepo()
+	pr, err := client.GetPullRequest(ctx.Context, owner, repo, number)
+	if err != nil {
+		return "", fmt.Errorf("failed to get PR #%d: %w", number, err)
+	}
+	if pr == nil {
+		return "", fmt.Errorf("PR #%d not found", number)
+	}
+
+	parentName, err := prCheckoutParent(ctx, client, pr, depth)
+	if err != nil {
+		return "", err
+	}
+
+	// Branches from forks are namespaced by their owner, so a fork's main or a branch
+	// named like one of ours doesn't collide with a local branch
+	branchName := pr.Head
+	source := git.GetRemote()
+	ref := "refs/heads/" + pr.Head
+	switch {
+	case pr.HeadRepo == "":
+		// The fork was deleted, so its branch only survives as the PR's head ref on the base
+		// repository; a branch of ours with the same name is unrelated
+		branchName = fmt.Sprintf("pr-%d/%s", number, pr.Head)
+		ref = fmt.Sprintf("refs/pull/%d/head", number)
+	case pr.CrossRepository:
+		forkOwner, _, _ := strings.Cut(pr.HeadRepo, "/")
+		branchName = forkOwner + "/" + pr.Head
+		source = pr.HeadCloneURL
+	}
+	if branchName == eng.Trunk().GetName() {
+		return "", fmt.Errorf("PR #%d is opened from trunk (%s)", number, branchName)
+	}
+
+	if _, err := git.RunGitCommandWithContext(ctx.Context, "fetch", source, ref); err != nil {
+		return "", fmt.Errorf("failed to fetch %s for PR #%d: %w", pr.Head, number, err)
+	}
+	headRev, err := git.RunGitCommandWithContext(ctx.Context, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to get the head of PR #%d: %w", number, err)
+	}
+
+	if err := updatePrBranch(ctx, branchName, headRev, number); err != nil {
+		return "", err
+	}
+
+	// The PR's base is authoritative, even if the branch needs restacking onto it
+	if err := eng.ForceTrackBranch(ctx.Context, branchName, parentName); err != nil {
+		return "", fmt.Errorf("failed to track %s onto %s: %w", branchName, parentName, err)
+	}
+	prInfo := engine.NewPrInfo(&pr.Number, pr.Title, pr.Body, pr.State, pr.Base, pr.HTMLURL, pr.Draft)
+	if err := eng.UpsertPrInfo(eng.GetBranch(branchName), prInfo); err != nil {
+		return "", fmt.Errorf("failed to save PR info for %s: %w", branchName, err)
+	}
+
+	splog.Info("Tracked PR #%d as %s on top of %s.", number, style.ColorBranchName(branchName, false),
+		style.ColorBranchName(parentName, false))
+	return branchName, nil
+}
+
+// prCheckoutParent returns the local branch a PR's branch should be tracked onto: trunk or
+// a tracked branch named like the PR's base, or else the branch of the base's own PR
+func prCheckoutParent(ctx *runtime.Context, client github.Client, pr *github.PullRequestInfo, depth int) (string, error) {
+	eng := ctx.Engine
+
+	base := eng.GetBranch(pr.Base)
+	if base.IsTrunk() || base.IsTracked() {
+		return pr.Base, nil
+	}
+
+	owner, repo := client.GetOwnerRepo()
+	basePr, err := client.GetPullRequestByBranch(ctx.Context, owner, repo, pr.Base)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the PR for %s: %w", pr.Base, err)
+	}
+	if basePr == nil || basePr.State != "OPEN" {
+		return "", fmt.Errorf("PR #%d is based on %s, which is neither trunk nor the branch of an open PR", pr.Number, pr.Base)
+	}
+	return checkoutPr(ctx, client, basePr.Number, depth+1)
+}
+
+// updatePrBranch points branchName at a PR's head revision, creating the branch if needed.
+// An existing branch is only fast-forwarded, so local commits on it are never lost.
+func updatePrBranch(ctx *runtime.Context, branchName, headRev string, number int) error {
+	// Look the branch up by its full ref; a bare name would resolve to the remote branch
+	localRev, err := git.GetRevision("refs/heads/" + branchName)
+	if err != nil {
+		return git.UpdateBranchRef(branchName, headRev)
+	}
+	if localRev == headRev {
+		return nil
+	}
+	if isAncestor, err := git.IsAncestor(localRev, headRev); err != nil || !isAncestor {
+		return fmt.Errorf("local branch %s has commits that aren't in PR #%d; rename or delete it first", branchName, number)
+	}
+
+	if current, err := git.GetCurrentBranch(); err == nil && current == branchName {
+		return git.HardReset(ctx.Context, headRev)
+	}
+	return git.UpdateBranchRef(branchName, headRev)
+}
@@ -0,0 +1,243 @@
+package actions_test
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v62/github"
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
+)
+
+func TestPrCheckoutAction(t *testing.T) {
+	t.Run("checks out a PR with the PRs it is stacked on", func(t *testing.T) {
+		// A teammate's stack (a on main, b on a) is on origin but not tracked or kept locally
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		s.RunGit("push", "origin", "main").
+			RunGit("checkout", "-b", "a").CommitChange("a", "a").
+			RunGit("checkout", "-b", "b").CommitChange("b", "b").
+			RunGit("push", "origin", "a", "b").
+			RunGit("checkout", "main").
+			RunGit("branch", "-D", "a", "b")
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		for branch, data := range map[string]testhelpers.SamplePRData{
+			"a": {Number: 101, Title: "a", Head: "a", Base: "main", State: "open"},
+			"b": {Number: 102, Title: "b", Head: "b", Base: "a", State: "open"},
+		} {
+			pr := testhelpers.NewSamplePullRequest(data)
+			pr.Head.Repo = &github.Repository{FullName: github.String("owner/repo")}
+			pr.Base.Repo = &github.Repository{FullName: github.String("owner/repo")}
+			config.PRs[branch] = pr
+		}
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		require.NoError(t, actions.PrCheckoutAction(s.Context, actions.PrCheckoutOptions{Number: 102}))
+
+		s.ExpectBranch("b").ExpectStackStructure(map[string]string{"a": "main", "b": "a"})
+		for branch, number := range map[string]int{"a": 101, "b": 102} {
+			prInfo, err := s.Engine.GetPrInfo(s.Engine.GetBranch(branch))
+			require.NoError(t, err)
+			require.NotNil(t, prInfo)
+			require.Equal(t, number, *prInfo.Number())
+		}
+	})
+
+	t.Run("tracks onto a base that is already tracked", func(t *testing.T) {
+		// A teammate's stack (a on main, b on a) is on origin but not tracked or kept locally
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		s.RunGit("push", "origin", "main").
+			RunGit("checkout", "-b", "a").CommitChange("a", "a").
+			RunGit("checkout", "-b", "b").CommitChange("b", "b").
+			RunGit("push", "origin", "a", "b").
+			RunGit("checkout", "main").
+			RunGit("branch", "-D", "a", "b")
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		for branch, data := range map[string]testhelpers.SamplePRData{
+			"a": {Number: 101, Title: "a", Head: "a", Base: "main", State: "open"},
+			"b": {Number: 102, Title: "b", Head: "b", Base: "a", State: "open"},
+		} {
+			pr := testhelpers.NewSamplePullRequest(data)
+			pr.Head.Repo = &github.Repository{FullName: github.String("owner/repo")}
+			pr.Base.Repo = &github.Repository{FullName: github.String("owner/repo")}
+			config.PRs[branch] = pr
+		}
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+		s.RunGit("branch", "a", "origin/a").Rebuild().TrackBranch("a", "main")
+
+		require.NoError(t, actions.PrCheckoutAction(s.Context, actions.PrCheckoutOptions{Number: 102}))
+
+		s.ExpectBranch("b").ExpectStackStructure(map[string]string{"a": "main", "b": "a"})
+		prInfo, err := s.Engine.GetPrInfo(s.Engine.GetBranch("a"))
+		require.NoError(t, err)
+		require.Nil(t, prInfo)
+	})
+
+	t.Run("fast-forwards an existing branch but keeps local commits", func(t *testing.T) {
+		// A teammate's stack (a on main, b on a) is on origin but not tracked or kept locally
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		s.RunGit("push", "origin", "main").
+			RunGit("checkout", "-b", "a").CommitChange("a", "a").
+			RunGit("checkout", "-b", "b").CommitChange("b", "b").
+			RunGit("push", "origin", "a", "b").
+			RunGit("checkout", "main").
+			RunGit("branch", "-D", "a", "b")
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		for branch, data := range map[string]testhelpers.SamplePRData{
+			"a": {Number: 101, Title: "a", Head: "a", Base: "main", State: "open"},
+			"b": {Number: 102, Title: "b", Head: "b", Base: "a", State: "open"},
+		} {
+			pr := testhelpers.NewSamplePullRequest(data)
+			pr.Head.Repo = &github.Repository{FullName: github.String("owner/repo")}
+			pr.Base.Repo = &github.Repository{FullName: github.String("owner/repo")}
+			config.PRs[branch] = pr
+		}
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+		s.RunGit("branch", "a", "origin/a~1").Rebuild()
+
+		require.NoError(t, actions.PrCheckoutAction(s.Context, actions.PrCheckoutOptions{Number: 101}))
+		s.ExpectBranch("a")
+		localRev, err := s.Scene.Repo.RunGitCommandAndGetOutput("rev-parse", "a")
+		require.NoError(t, err)
+		remoteRev, err := s.Scene.Repo.RunGitCommandAndGetOutput("rev-parse", "origin/a")
+		require.NoError(t, err)
+		require.Equal(t, remoteRev, localRev)
+
+		s.CommitChange("local", "local work").Checkout("main")
+		err = actions.PrCheckoutAction(s.Context, actions.PrCheckoutOptions{Number: 101})
+		require.ErrorContains(t, err, "local branch a has commits that aren't in PR #101")
+	})
+
+	t.Run("checks out a PR from a fork", func(t *testing.T) {
+		// A teammate's stack (a on main, b on a) is on origin but not tracked or kept locally
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		s.RunGit("push", "origin", "main").
+			RunGit("checkout", "-b", "a").CommitChange("a", "a").
+			RunGit("checkout", "-b", "b").CommitChange("b", "b").
+			RunGit("push", "origin", "a", "b").
+			RunGit("checkout", "main").
+			RunGit("branch", "-D", "a", "b")
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		for branch, data := range map[string]testhelpers.SamplePRData{
+			"a": {Number: 101, Title: "a", Head: "a", Base: "main", State: "open"},
+			"b": {Number: 102, Title: "b", Head: "b", Base: "a", State: "open"},
+		} {
+			pr := testhelpers.NewSamplePullRequest(data)
+			pr.Head.Repo = &github.Repository{FullName: github.String("owner/repo")}
+			pr.Base.Repo = &github.Repository{FullName: github.String("owner/repo")}
+			config.PRs[branch] = pr
+		}
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+		forkDir, err := s.Scene.Repo.CreateBareRemote("fork")
+		require.NoError(t, err)
+		s.RunGit("checkout", "-b", "a").CommitChange("fork", "fork change").
+			RunGit("push", "fork", "a").
+			RunGit("checkout", "main").
+			RunGit("branch", "-D", "a").
+			RunGit("remote", "remove", "fork")
+
+		// The fork's branch is named like a branch of the base repository
+		pr := testhelpers.NewSamplePullRequest(testhelpers.SamplePRData{
+			Number: 103, Title: "fork", Head: "a", Base: "main", State: "open",
+		})
+		pr.Head.Repo = &github.Repository{FullName: github.String("alice/repo"), CloneURL: github.String(forkDir)}
+		pr.Base.Repo = &github.Repository{FullName: github.String("owner/repo")}
+		config.PRs["alice:a"] = pr
+
+		require.NoError(t, actions.PrCheckoutAction(s.Context, actions.PrCheckoutOptions{Number: 103}))
+
+		s.ExpectBranch("alice/a").ExpectStackStructure(map[string]string{"alice/a": "main"})
+		subject, err := s.Scene.Repo.RunGitCommandAndGetOutput("log", "-1", "--format=%s", "alice/a")
+		require.NoError(t, err)
+		require.Equal(t, "fork change", subject)
+	})
+
+	t.Run("checks out a PR whose fork was deleted from its pull ref", func(t *testing.T) {
+		// A teammate's stack (a on main, b on a) is on origin but not tracked or kept locally
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		s.RunGit("push", "origin", "main").
+			RunGit("checkout", "-b", "a").CommitChange("a", "a").
+			RunGit("checkout", "-b", "b").CommitChange("b", "b").
+			RunGit("push", "origin", "a", "b").
+			RunGit("checkout", "main").
+			RunGit("branch", "-D", "a", "b")
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		for branch, data := range map[string]testhelpers.SamplePRData{
+			"a": {Number: 101, Title: "a", Head: "a", Base: "main", State: "open"},
+			"b": {Number: 102, Title: "b", Head: "b", Base: "a", State: "open"},
+		} {
+			pr := testhelpers.NewSamplePullRequest(data)
+			pr.Head.Repo = &github.Repository{FullName: github.String("owner/repo")}
+			pr.Base.Repo = &github.Repository{FullName: github.String("owner/repo")}
+			config.PRs[branch] = pr
+		}
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+		s.RunGit("checkout", "-b", "fork").CommitChange("fork", "fork change").
+			RunGit("push", "origin", "fork:refs/pull/104/head").
+			RunGit("checkout", "main").
+			RunGit("branch", "-D", "fork")
+
+		// GitHub reports no head repository once the fork is gone, and the branch is named
+		// like a branch of the base repository
+		config.PRs["ghost:a"] = testhelpers.NewSamplePullRequest(testhelpers.SamplePRData{
+			Number: 104, Title: "fork", Head: "a", Base: "main", State: "open",
+		})
+
+		require.NoError(t, actions.PrCheckoutAction(s.Context, actions.PrCheckoutOptions{Number: 104}))
+
+		s.ExpectBranch("pr-104/a").ExpectStackStructure(map[string]string{"pr-104/a": "main"})
+		subject, err := s.Scene.Repo.RunGitCommandAndGetOutput("log", "-1", "--format=%s", "pr-104/a")
+		require.NoError(t, err)
+		require.Equal(t, "fork change", subject)
+	})
+
+	t.Run("fails for an unknown PR", func(t *testing.T) {
+		// A teammate's stack (a on main, b on a) is on origin but not tracked or kept locally
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		s.RunGit("push", "origin", "main").
+			RunGit("checkout", "-b", "a").CommitChange("a", "a").
+			RunGit("checkout", "-b", "b").CommitChange("b", "b").
+			RunGit("push", "origin", "a", "b").
+			RunGit("checkout", "main").
+			RunGit("branch", "-D", "a", "b")
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		for branch, data := range map[string]testhelpers.SamplePRData{
+			"a": {Number: 101, Title: "a", Head: "a", Base: "main", State: "open"},
+			"b": {Number: 102, Title: "b", Head: "b", Base: "a", State: "open"},
+		} {
+			pr := testhelpers.NewSamplePullRequest(data)
+			pr.Head.Repo = &github.Repository{FullName: github.String("owner/repo")}
+			pr.Base.Repo = &github.Repository{FullName: github.String("owner/repo")}
+			config.PRs[branch] = pr
+		}
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		err = actions.PrCheckoutAction(s.Context, actions.PrCheckoutOptions{Number: 999})
+		require.ErrorContains(t, err, "PR #999 not found")
+	})
+}
@@ -10,6 +10,22 @@ import (
 const (
 	footerTitle  = "\n\n\n#### PR Dependency Tree\n\n"
 	footerFooter = "\n\nThis tree was auto-generated by [Stackit](https://github.com/jonnii/stackit)"
+
+	// footerCommentMarker identifies the dependency tree comment so it can be found and replaced
+	footerCommentMarker = "<!-- stackit:pr-dependency-tree -->"
+
+	// dependsOnMarker identifies the "depends on" note so it can be found and replaced
+	dependsOnMarker = "<!-- stackit:depends-on -->"
+)
+
+// FooterMode determines where the PR dependency tree is published
+type FooterMode string
+
+const (
+	// FooterModeBody appends the dependency tree to the PR body
+	FooterModeBody FooterMode = "body"
+	// FooterModeComment posts the dependency tree as a single PR comment, updated in place
+	FooterModeComment FooterMode = "comment"
 )
 
 // CreatePRBodyFooter creates a PR body footer with dependency tree
@@ -64,6 +80,39 @@ func UpdatePRBodyFooter(existingBody, footer string) string {
 	return existingBody + footer
 }
 
+// UpdatePRBodyDependsOn sets the note naming the PR that a stacked PR depends on, placing
+// it just above the dependency tree footer so footer updates keep it. A nil parentPR
+// removes the note, e.g. once the PR's parent has merged.
+func UpdatePRBodyDependsOn(existingBody string, parentPR *int) string {
+	body := existingBody
+	if start := strings.Index(body, "\n\n"+dependsOnMarker); start >= 0 {
+		end := len(body)
+		if newline := strings.Index(body[start+2:], "\n"); newline >= 0 {
+			end = start + 2 + newline
+		}
+		body = body[:start] + body[end:]
+	}
+	if parentPR == nil {
+		return body
+	}
+
+	note := fmt.Sprintf("\n\n%sDepends on #%d", dependsOnMarker, *parentPR)
+	if footerIndex := strings.Index(body, footerTitle); footerIndex >= 0 {
+		return body[:footerIndex] + note + body[footerIndex:]
+	}
+	return body + note
+}
+
+// CreatePRFooterComment creates the body of the dependency tree comment from a footer
+func CreatePRFooterComment(footer string) string {
+	return footerCommentMarker + "\n" + strings.TrimLeft(footer, "\n")
+}
+
+// IsPRFooterComment reports whether a comment is the dependency tree comment
+func IsPRFooterComment(body string) bool {
+	return strings.Contains(body, footerCommentMarker)
+}
+
 // findTerminalParent finds the terminal parent (parent of trunk) for a branch
 func findTerminalParent(currentBranch string, eng engine.BranchReader) string {
 	branch := eng.GetBranch(currentBranch)
@@ -2,6 +2,7 @@ package actions
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -13,50 +14,164 @@ import (
 
 var scopeRegex = regexp.MustCompile(`^\[[^\]]+\]\s*`)
 
-// UpdateStackPRMetadata updates PR titles and body footers for a list of branches
-func UpdateStackPRMetadata(ctx context.Context, branches []string, eng engine.Engine, githubClient github.Client, repoOwner, repoName string) {
+// PRMetadataOptions controls what UpdateStackPRMetadata changes on each PR
+type PRMetadataOptions struct {
+	FooterMode  FooterMode // Whether the dependency tree footer goes in the PR body or a comment
+	StripFooter bool       // Remove the footer, from the body and any footer comment, instead of writing it
+	SkipTitles  bool       // Leave titles alone and only refresh footers
+	FetchBodies bool       // Read bodies from GitHub first, so edits made there are kept
+	Concurrency int        // How many PRs to update at once (submit.concurrency); 0 means 1
+}
+
+// UpdateStackPRMetadata updates PR titles and dependency tree footers for a list of branches,
+// skipping branches without a PR. It returns how many PRs were changed, along with any errors.
+func UpdateStackPRMetadata(ctx context.Context, branches []string, eng engine.Engine, githubClient github.Client, repoOwner, repoName string, opts PRMetadataOptions) (int, error) {
+	slots := make(chan struct{}, max(opts.Concurrency, 1))
+
 	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+	updated := 0
 	for _, branchName := range branches {
 		wg.Add(1)
 		go func(name string) {
 			defer wg.Done()
-			branch := eng.GetBranch(name)
-			prInfo, err := eng.GetPrInfo(branch)
-			if err != nil || prInfo == nil || prInfo.Number() == nil {
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			changed, err := updatePRMetadata(ctx, name, eng, githubClient, repoOwner, repoName, opts)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to update %s's PR: %w", name, err))
 				return
 			}
-
-			scope := eng.GetScopeInternal(name)
-			updatedTitle := prInfo.Title()
-			if !scope.IsEmpty() {
-				if scopeRegex.MatchString(updatedTitle) {
-					if !strings.HasPrefix(strings.ToUpper(updatedTitle), "["+strings.ToUpper(scope.String())+"]") {
-						updatedTitle = scopeRegex.ReplaceAllString(updatedTitle, "["+scope.String()+"] ")
-					}
-				} else {
-					updatedTitle = fmt.Sprintf("[%s] %s", scope.String(), updatedTitle)
-				}
+			if changed {
+				updated++
 			}
+		}(branchName)
+	}
+	wg.Wait()
+	return updated, errors.Join(errs...)
+}
 
-			footer := CreatePRBodyFooter(name, eng)
-			updatedBody := UpdatePRBodyFooter(prInfo.Body(), footer)
+// updatePRMetadata updates one branch's PR, writing back only what changed, and reports
+// whether anything did
+func updatePRMetadata(ctx context.Context, name string, eng engine.Engine, githubClient github.Client, repoOwner, repoName string, opts PRMetadataOptions) (bool, error) {
+	branch := eng.GetBranch(name)
+	prInfo, err := eng.GetPrInfo(branch)
+	if err != nil || prInfo == nil || prInfo.Number() == nil {
+		return false, nil
+	}
+	number := *prInfo.Number()
 
-			if updatedTitle != prInfo.Title() || updatedBody != prInfo.Body() {
-				updateOpts := github.UpdatePROptions{}
-				if updatedTitle != prInfo.Title() {
-					updateOpts.Title = &updatedTitle
-				}
-				if updatedBody != prInfo.Body() {
-					updateOpts.Body = &updatedBody
-				}
+	body := prInfo.Body()
+	if opts.FetchBodies {
+		pr, err := githubClient.GetPullRequest(ctx, repoOwner, repoName, number)
+		if err != nil {
+			return false, err
+		}
+		body = pr.Body
+	}
 
-				if err := githubClient.UpdatePullRequest(ctx, repoOwner, repoName, *prInfo.Number(), updateOpts); err != nil {
-					return
-				}
+	updatedTitle := prInfo.Title()
+	if !opts.SkipTitles {
+		updatedTitle = scopedTitle(updatedTitle, eng.GetScopeInternal(name))
+	}
 
-				_ = eng.UpsertPrInfo(branch, prInfo.WithTitleAndBody(updatedTitle, updatedBody))
-			}
-		}(branchName)
+	var updatedBody string
+	commentChanged := false
+	switch {
+	case opts.StripFooter:
+		updatedBody = UpdatePRBodyFooter(body, "")
+		if commentChanged, err = deleteFooterComment(ctx, githubClient, repoOwner, repoName, number); err != nil {
+			return false, err
+		}
+	case opts.FooterMode == FooterModeComment:
+		// Drop any footer an earlier body-mode submit left behind
+		updatedBody = UpdatePRBodyFooter(body, "")
+		if commentChanged, err = upsertFooterComment(ctx, githubClient, repoOwner, repoName, number, CreatePRBodyFooter(name, eng)); err != nil {
+			return false, err
+		}
+	default:
+		updatedBody = UpdatePRBodyFooter(body, CreatePRBodyFooter(name, eng))
 	}
-	wg.Wait()
+
+	updateOpts := github.UpdatePROptions{}
+	if updatedTitle != prInfo.Title() {
+		updateOpts.Title = &updatedTitle
+	}
+	if updatedBody != body {
+		updateOpts.Body = &updatedBody
+	}
+	prChanged := updateOpts.Title != nil || updateOpts.Body != nil
+	if prChanged {
+		if err := githubClient.UpdatePullRequest(ctx, repoOwner, repoName, number, updateOpts); err != nil {
+			return false, err
+		}
+	}
+
+	if updatedTitle != prInfo.Title() || updatedBody != prInfo.Body() {
+		if err := eng.UpsertPrInfo(branch, prInfo.WithTitleAndBody(updatedTitle, updatedBody)); err != nil {
+			return false, err
+		}
+	}
+	return prChanged || commentChanged, nil
+}
+
+// scopedTitle prefixes title with the branch's scope, replacing a different scope prefix
+func scopedTitle(title string, scope engine.Scope) string {
+	if scope.IsEmpty() {
+		return title
+	}
+	if !scopeRegex.MatchString(title) {
+		return fmt.Sprintf("[%s] %s", scope.String(), title)
+	}
+	if strings.HasPrefix(strings.ToUpper(title), "["+strings.ToUpper(scope.String())+"]") {
+		return title
+	}
+	return scopeRegex.ReplaceAllString(title, "["+scope.String()+"] ")
+}
+
+// upsertFooterComment creates the PR's dependency tree comment, or updates the existing one
+// found by its marker, so repeated submits never post duplicates. It reports whether the
+// comment changed.
+func upsertFooterComment(ctx context.Context, githubClient github.Client, repoOwner, repoName string, prNumber int, footer string) (bool, error) {
+	body := CreatePRFooterComment(footer)
+
+	comments, err := githubClient.ListComments(ctx, repoOwner, repoName, prNumber)
+	if err != nil {
+		return false, err
+	}
+	for _, comment := range comments {
+		if !IsPRFooterComment(comment.Body) {
+			continue
+		}
+		if comment.Body == body {
+			return false, nil
+		}
+		return true, githubClient.UpdateComment(ctx, repoOwner, repoName, comment.ID, body)
+	}
+
+	return true, githubClient.AddComment(ctx, repoOwner, repoName, prNumber, body)
+}
+
+// deleteFooterComment removes the PR's dependency tree comment, found by its marker, and
+// reports whether there was one
+func deleteFooterComment(ctx context.Context, githubClient github.Client, repoOwner, repoName string, prNumber int) (bool, error) {
+	comments, err := githubClient.ListComments(ctx, repoOwner, repoName, prNumber)
+	if err != nil {
+		return false, err
+	}
+	deleted := false
+	for _, comment := range comments {
+		if !IsPRFooterComment(comment.Body) {
+			continue
+		}
+		if err := githubClient.DeleteComment(ctx, repoOwner, repoName, comment.ID); err != nil {
+			return deleted, err
+		}
+		deleted = true
+	}
+	return deleted, nil
 }
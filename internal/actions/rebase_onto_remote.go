@@ -0,0 +1,78 @@
+package actions
+
+import (
+	"fmt"
+
+	stackiterrors "stackit.dev/stackit/internal/errors"
+	"stackit.dev/stackit/internal/runtime"
+	"stackit.dev/stackit/internal/tui/style"
+	"stackit.dev/stackit/internal/utils"
+)
+
+// RebaseOntoRemoteOptions contains options for the rebase-onto-remote command
+type RebaseOntoRemoteOptions struct {
+	BranchName string
+}
+
+// RebaseOntoRemoteAction adopts a collaborator's pushed version of a shared branch: the
+// branch is reset to its remote tip, then its descendants are restacked so only their own
+// commits are replayed on top of it. Conflicts stop the restack for `stackit continue`.
+func RebaseOntoRemoteAction(ctx *runtime.Context, opts RebaseOntoRemoteOptions) error {
+	eng := ctx.Engine
+	splog := ctx.Splog
+
+	branchName := opts.BranchName
+	if branchName == "" {
+		currentBranch := eng.CurrentBranch()
+		if currentBranch == nil {
+			return fmt.Errorf("%w and no branch specified", stackiterrors.ErrNotOnBranch)
+		}
+		branchName = currentBranch.GetName()
+	}
+
+	branch := eng.GetBranch(branchName)
+	if branch.IsTrunk() {
+		return fmt.Errorf("cannot rebase trunk onto remote; use `stackit sync` instead")
+	}
+	if !branch.IsTracked() {
+		return fmt.Errorf("branch %s is not tracked", branchName)
+	}
+
+	if err := utils.CheckRebaseInProgress(ctx.Context); err != nil {
+		return err
+	}
+	if utils.HasUncommittedChanges(ctx.Context) {
+		return fmt.Errorf("cannot rebase onto remote with uncommitted changes. Please commit or stash them first")
+	}
+
+	snapshotOpts := NewSnapshot("rebase-onto-remote", WithArg(branchName))
+	if err := eng.TakeSnapshot(snapshotOpts); err != nil {
+		// Log but don't fail - snapshot is best effort
+		splog.Debug("Failed to take snapshot: %v", err)
+	}
+
+	oldRev, err := eng.ResetBranchToRemote(ctx.Context, branchName)
+	if err != nil {
+		return err
+	}
+	newRev, err := branch.GetRevision()
+	if err != nil {
+		return fmt.Errorf("failed to get revision for %s: %w", branchName, err)
+	}
+
+	if oldRev == newRev {
+		splog.Info("%s already matches the remote.", style.ColorBranchName(branchName, false))
+	} else {
+		if dropped, _, err := eng.GetDivergence(oldRev, newRev); err == nil && dropped > 0 {
+			splog.Warn("Replaced %d local commit(s) on %s that were not on the remote (recover them with `stackit undo`).",
+				dropped, branchName)
+		}
+		splog.Info("Reset %s to the remote (%s).", style.ColorBranchName(branchName, false), style.ColorDim(newRev[:7]))
+	}
+
+	upstack := eng.GetRelativeStackUpstack(branch)
+	if len(upstack) == 0 {
+		return nil
+	}
+	return RestackBranches(ctx.Context, upstack, eng, splog, ctx.RepoRoot)
+}
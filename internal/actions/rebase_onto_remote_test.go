@@ -0,0 +1,127 @@
+package actions_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
+)
+
+func TestRebaseOntoRemoteAction(t *testing.T) {
+	t.Run("replays children onto the remote tip", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"a": "main",
+				"b": "a",
+				"c": "b",
+			})
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		for _, branch := range []string{"main", "a", "b", "c"} {
+			require.NoError(t, s.Scene.Repo.PushBranch("origin", branch))
+		}
+
+		// A teammate pushes a commit to b that this clone doesn't have yet
+		s.Checkout("b").CommitChange("teammate", "teammate change")
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "b"))
+		remoteTip, err := s.Scene.Repo.GetRevision("b")
+		require.NoError(t, err)
+		s.RunGit("reset", "--hard", "HEAD~1").Checkout("c")
+
+		err = actions.RebaseOntoRemoteAction(s.Context, actions.RebaseOntoRemoteOptions{BranchName: "b"})
+		require.NoError(t, err)
+
+		bRev, err := s.Scene.Repo.GetRevision("b")
+		require.NoError(t, err)
+		require.Equal(t, remoteTip, bRev)
+
+		cParent, err := s.Scene.Repo.GetRevision("c~1")
+		require.NoError(t, err)
+		require.Equal(t, remoteTip, cParent, "c should be replayed directly onto the remote tip of b")
+
+		count, err := s.Scene.Repo.GetCommitCount("b", "c")
+		require.NoError(t, err)
+		require.Equal(t, 1, count, "only c's own commit should be replayed")
+
+		s.Rebuild().ExpectBranchFixed("c")
+		currentBranch, err := s.Scene.Repo.CurrentBranchName()
+		require.NoError(t, err)
+		require.Equal(t, "c", currentBranch)
+		require.FileExists(t, filepath.Join(s.Scene.Dir, "teammate_test.txt"))
+		require.FileExists(t, filepath.Join(s.Scene.Dir, "c_test.txt"))
+	})
+
+	t.Run("defaults to the current branch", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"a": "main",
+				"b": "a",
+				"c": "b",
+			})
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		for _, branch := range []string{"main", "a", "b", "c"} {
+			require.NoError(t, s.Scene.Repo.PushBranch("origin", branch))
+		}
+
+		// A teammate pushes a commit to b that this clone doesn't have yet
+		s.Checkout("b").CommitChange("teammate", "teammate change")
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "b"))
+		remoteTip, err := s.Scene.Repo.GetRevision("b")
+		require.NoError(t, err)
+		s.RunGit("reset", "--hard", "HEAD~1").Checkout("c")
+		s.Checkout("b")
+
+		err = actions.RebaseOntoRemoteAction(s.Context, actions.RebaseOntoRemoteOptions{})
+		require.NoError(t, err)
+
+		bRev, err := s.Scene.Repo.GetRevision("b")
+		require.NoError(t, err)
+		require.Equal(t, remoteTip, bRev)
+		s.Rebuild().ExpectBranchFixed("c")
+	})
+
+	t.Run("persists continuation state on conflict", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"a": "main",
+				"b": "a",
+				"c": "b",
+			})
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		for _, branch := range []string{"main", "a", "b", "c"} {
+			require.NoError(t, s.Scene.Repo.PushBranch("origin", branch))
+		}
+
+		// The teammate's commit adds the same file c adds, so replaying c conflicts
+		s.Checkout("b").CommitChange("c", "teammate change")
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "b"))
+		remoteTip, err := s.Scene.Repo.GetRevision("b")
+		require.NoError(t, err)
+		s.RunGit("reset", "--hard", "HEAD~1").Checkout("c")
+
+		err = actions.RebaseOntoRemoteAction(s.Context, actions.RebaseOntoRemoteOptions{BranchName: "b"})
+		require.Error(t, err)
+
+		bRev, err := s.Scene.Repo.GetRevision("b")
+		require.NoError(t, err)
+		require.Equal(t, remoteTip, bRev)
+
+		_, err = os.Stat(filepath.Join(s.Scene.Dir, ".git", ".stackit_continue"))
+		require.NoError(t, err, "continuation state should be persisted")
+	})
+
+	t.Run("rejects trunk", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+
+		err := actions.RebaseOntoRemoteAction(s.Context, actions.RebaseOntoRemoteOptions{BranchName: "main"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot rebase trunk onto remote")
+	})
+}
@@ -0,0 +1,155 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"stackit.dev/stackit/internal/config"
+	"stackit.dev/stackit/internal/engine"
+	"stackit.dev/stackit/internal/github"
+	"stackit.dev/stackit/internal/runtime"
+	"stackit.dev/stackit/internal/tui/style"
+)
+
+// PrStateChange is a PR whose state on GitHub differed from the state stored locally
+type PrStateChange struct {
+	Branch   string
+	Number   int
+	OldState string
+	NewState string
+}
+
+// RefreshPrInfo fetches the current PR of every tracked branch from GitHub and updates the
+// stored PR info (number, state, draft status, base and URL), keeping the title, body and
+// review defaults saved by earlier submits. It returns the PRs whose state changed, sorted
+// by branch name. concurrency caps how many PRs are fetched at once when they can't be
+// fetched in a single batch (submit.concurrency).
+func RefreshPrInfo(ctx context.Context, eng engine.Engine, client github.Client, concurrency int) ([]PrStateChange, error) {
+	var branches []engine.Branch
+	for _, branch := range eng.AllBranches() {
+		if !branch.IsTrunk() && branch.IsTracked() {
+			branches = append(branches, branch)
+		}
+	}
+
+	// Fetch every PR at once; the engine is only written to afterwards
+	prs, err := fetchBranchPRs(ctx, client, branches, concurrency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch PRs: %w", err)
+	}
+
+	changes := []PrStateChange{}
+	for i, branch := range branches {
+		pr := prs[i]
+		if pr == nil {
+			continue
+		}
+		number := pr.Number
+		stored, _ := eng.GetPrInfo(branch)
+
+		var updated *engine.PrInfo
+		oldState := ""
+		if stored == nil {
+			updated = engine.NewPrInfo(&number, pr.Title, pr.Body, pr.State, pr.Base, pr.HTMLURL, pr.Draft)
+		} else {
+			oldState = stored.State()
+			updated = stored.WithNumber(&number).WithState(pr.State).WithIsDraft(pr.Draft).
+				WithBase(pr.Base).WithURL(pr.HTMLURL)
+		}
+		if err := eng.UpsertPrInfo(branch, updated); err != nil {
+			return nil, fmt.Errorf("failed to save PR info for %s: %w", branch.GetName(), err)
+		}
+		if oldState != pr.State {
+			changes = append(changes, PrStateChange{
+				Branch:   branch.GetName(),
+				Number:   number,
+				OldState: oldState,
+				NewState: pr.State,
+			})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Branch < changes[j].Branch })
+	return changes, nil
+}
+
+// fetchBranchPRs returns the PR of each branch, or nil for branches without one. The PRs
+// come from a single batch request, or from one request per branch, at most concurrency at
+// a time, when batching is unavailable.
+func fetchBranchPRs(ctx context.Context, client github.Client, branches []engine.Branch, concurrency int) ([]*github.PullRequestInfo, error) {
+	branchNames := make([]string, len(branches))
+	for i, branch := range branches {
+		branchNames[i] = branch.GetName()
+	}
+
+	prs := make([]*github.PullRequestInfo, len(branches))
+	if statuses, err := client.GetBranchPRStatuses(ctx, branchNames); err == nil {
+		for i, branchName := range branchNames {
+			if status := statuses[branchName]; status != nil {
+				prs[i] = status.PR
+			}
+		}
+		return prs, nil
+	}
+
+	owner, repo := client.GetOwnerRepo()
+	errs := make([]error, len(branches))
+	slots := make(chan struct{}, max(concurrency, 1))
+	var wg sync.WaitGroup
+	for i, branchName := range branchNames {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			prs[i], errs[i] = client.GetPullRequestByBranch(ctx, owner, repo, branchName)
+		}()
+	}
+	wg.Wait()
+	return prs, errors.Join(errs...)
+}
+
+// RefreshAction updates the stored PR info of every tracked branch from GitHub, so `log`
+// and branch cleanup see PRs merged or closed on GitHub without running a full sync
+func RefreshAction(ctx *runtime.Context) error {
+	splog := ctx.Splog
+
+	client := ctx.GitHubClient
+	if client == nil {
+		if err := github.CheckCLIAuth(ctx.Context); err != nil {
+			return err
+		}
+		return fmt.Errorf("failed to create GitHub client")
+	}
+
+	concurrency := 1
+	if cfg, err := config.LoadConfig(ctx.RepoRoot); err == nil {
+		concurrency = cfg.SubmitConcurrency()
+	}
+	changes, err := RefreshPrInfo(ctx.Context, ctx.Engine, client, concurrency)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		splog.Info("PR info is up to date.")
+		return nil
+	}
+
+	closed := false
+	for _, change := range changes {
+		if change.OldState == "" {
+			splog.Info("%s: found PR #%d (%s).", style.ColorBranchName(change.Branch, false), change.Number, change.NewState)
+		} else {
+			splog.Info("%s: PR #%d is now %s (was %s).", style.ColorBranchName(change.Branch, false), change.Number,
+				change.NewState, change.OldState)
+		}
+		closed = closed || change.NewState == "MERGED" || change.NewState == "CLOSED"
+	}
+	if closed {
+		splog.Tip("Run `stackit sync` to delete branches whose PRs were merged or closed.")
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package actions_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/engine"
+	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
+)
+
+func TestRefreshPrInfo(t *testing.T) {
+	t.Run("picks up a PR merged on GitHub", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"a": "main",
+				"b": "a",
+			})
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		config.PRs["a"] = testhelpers.NewSamplePullRequest(testhelpers.SamplePRData{
+			Number: 101, Title: "a", Head: "a", Base: "main", State: "open",
+		})
+		config.PRs["b"] = testhelpers.NewSamplePullRequest(testhelpers.SamplePRData{
+			Number: 102, Title: "b", Head: "b", Base: "a", State: "open",
+		})
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		client := testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		// The PRs were opened elsewhere, so the first refresh finds them
+		changes, err := actions.RefreshPrInfo(context.Background(), s.Engine, client, 1)
+		require.NoError(t, err)
+		require.Equal(t, []actions.PrStateChange{
+			{Branch: "a", Number: 101, NewState: "OPEN"},
+			{Branch: "b", Number: 102, NewState: "OPEN"},
+		}, changes)
+
+		status, err := s.Engine.GetDeletionStatus(context.Background(), "a")
+		require.NoError(t, err)
+		require.False(t, status.SafeToDelete)
+
+		// a is merged on GitHub and b becomes a draft
+		config.PRs["a"] = testhelpers.NewSamplePullRequest(testhelpers.SamplePRData{
+			Number: 101, Title: "a", Head: "a", Base: "main", State: "closed", Merged: true,
+		})
+		config.PRs["b"] = testhelpers.NewSamplePullRequest(testhelpers.SamplePRData{
+			Number: 102, Title: "b", Head: "b", Base: "a", State: "open", Draft: true,
+		})
+
+		changes, err = actions.RefreshPrInfo(context.Background(), s.Engine, client, 1)
+		require.NoError(t, err)
+		require.Equal(t, []actions.PrStateChange{{Branch: "a", Number: 101, OldState: "OPEN", NewState: "MERGED"}}, changes)
+
+		prInfo, err := s.Engine.GetPrInfo(s.Engine.GetBranch("a"))
+		require.NoError(t, err)
+		require.Equal(t, "MERGED", prInfo.State())
+		prInfo, err = s.Engine.GetPrInfo(s.Engine.GetBranch("b"))
+		require.NoError(t, err)
+		require.True(t, prInfo.IsDraft())
+		require.Equal(t, "a", prInfo.Base())
+
+		status, err = s.Engine.GetDeletionStatus(context.Background(), "a")
+		require.NoError(t, err)
+		require.True(t, status.SafeToDelete)
+		require.True(t, status.Merged)
+	})
+
+	t.Run("keeps the title, body and review defaults from submit", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{"a": "main"})
+		number := 7
+		require.NoError(t, s.Engine.UpsertPrInfo(s.Engine.GetBranch("a"),
+			engine.NewPrInfo(&number, "Local title", "Local body", "OPEN", "main", "", false).
+				WithReviewersAndLabels([]string{"alice"}, nil, []string{"bug"})))
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		config.PRs["a"] = testhelpers.NewSamplePullRequest(testhelpers.SamplePRData{
+			Number: 7, Title: "Remote title", Head: "a", Base: "main", State: "closed",
+		})
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		client := testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		changes, err := actions.RefreshPrInfo(context.Background(), s.Engine, client, 1)
+		require.NoError(t, err)
+		require.Equal(t, []actions.PrStateChange{{Branch: "a", Number: 7, OldState: "OPEN", NewState: "CLOSED"}}, changes)
+
+		prInfo, err := s.Engine.GetPrInfo(s.Engine.GetBranch("a"))
+		require.NoError(t, err)
+		require.Equal(t, "Local title", prInfo.Title())
+		require.Equal(t, "Local body", prInfo.Body())
+		require.Equal(t, []string{"alice"}, prInfo.Reviewers())
+		require.Equal(t, []string{"bug"}, prInfo.Labels())
+	})
+
+	t.Run("fetches every PR with a single batch call", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"a": "main",
+				"b": "a",
+				"c": "b",
+			})
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		for number, branch := range map[int]string{101: "a", 102: "b", 103: "c"} {
+			config.PRs[branch] = testhelpers.NewSamplePullRequest(testhelpers.SamplePRData{
+				Number: number, Title: branch, Head: branch, State: "open",
+			})
+		}
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		client := testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		changes, err := actions.RefreshPrInfo(context.Background(), s.Engine, client, 1)
+		require.NoError(t, err)
+		require.Len(t, changes, 3)
+		require.Equal(t, 1, config.BatchPRStatusCalls)
+		require.Zero(t, config.PRByBranchCalls)
+
+		for number, branch := range map[int]string{101: "a", 102: "b", 103: "c"} {
+			prInfo, err := s.Engine.GetPrInfo(s.Engine.GetBranch(branch))
+			require.NoError(t, err)
+			require.NotNil(t, prInfo)
+			require.Equal(t, number, *prInfo.Number())
+		}
+	})
+
+	t.Run("falls back to a request per branch when batching is unavailable", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"a": "main",
+				"b": "a",
+			})
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		config.BatchPRStatusUnavailable = true
+		config.PRs["a"] = testhelpers.NewSamplePullRequest(testhelpers.SamplePRData{
+			Number: 101, Title: "a", Head: "a", State: "open",
+		})
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		client := testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		changes, err := actions.RefreshPrInfo(context.Background(), s.Engine, client, 1)
+		require.NoError(t, err)
+		require.Equal(t, []actions.PrStateChange{{Branch: "a", Number: 101, NewState: "OPEN"}}, changes)
+		require.Equal(t, 2, config.PRByBranchCalls)
+	})
+
+	t.Run("caps how many branches are fetched at once without batching", func(t *testing.T) {
+		stack := map[string]string{}
+		for i := range 12 {
+			stack[fmt.Sprintf("branch%02d", i)] = "main"
+		}
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).WithStack(stack)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		config.BatchPRStatusUnavailable = true
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		client := testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		_, err := actions.RefreshPrInfo(context.Background(), s.Engine, client, 3)
+		require.NoError(t, err)
+		require.Equal(t, 12, config.PRByBranchCalls)
+		require.LessOrEqual(t, config.MaxPRByBranchInFlight, 3)
+	})
+}
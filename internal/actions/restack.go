@@ -1,14 +1,29 @@
 package actions
 
 import (
+	"fmt"
+
 	"stackit.dev/stackit/internal/engine"
 	"stackit.dev/stackit/internal/runtime"
+	"stackit.dev/stackit/internal/tui/style"
 )
 
 // RestackOptions contains options for the restack command
 type RestackOptions struct {
 	BranchName string
 	Scope      engine.StackRange
+	// PreserveDates keeps committer dates equal to author dates, in addition to restack.preserveDates
+	PreserveDates bool
+	// KeepEmpty, when set, overrides whether rebases keep commits that are or become empty
+	KeepEmpty *bool
+	// Stat prints a summary table of what happened to each branch instead of a line per branch
+	Stat bool
+	// Preview predicts which branches would conflict without restacking anything
+	Preview bool
+	// Mergetool launches git's merge.tool on conflicts and continues the restack once resolved
+	Mergetool bool
+	// DryRun reports whether the branch needs restacking, and onto what, without restacking it
+	DryRun bool
 }
 
 // RestackAction performs the restack operation
@@ -16,8 +31,26 @@ func RestackAction(ctx *runtime.Context, opts RestackOptions) error {
 	eng := ctx.Engine
 	splog := ctx.Splog
 
+	if opts.PreserveDates {
+		eng.SetPreserveDates(true)
+	}
+	if opts.KeepEmpty != nil {
+		eng.SetKeepEmpty(*opts.KeepEmpty)
+	}
+
 	// Get branches to restack based on scope
 	branch := eng.GetBranch(opts.BranchName)
+	if opts.Preview {
+		predictions, err := eng.PreviewRestack(ctx.Context, branch, opts.Scope)
+		if err != nil {
+			return err
+		}
+		PrintRestackPreview(splog, predictions)
+		return nil
+	}
+	if opts.DryRun {
+		return restackDryRun(ctx, branch)
+	}
 	branches := branch.GetRelativeStack(opts.Scope)
 
 	if len(branches) == 0 {
@@ -35,5 +68,42 @@ func RestackAction(ctx *runtime.Context, opts RestackOptions) error {
 	}
 
 	// Call RestackBranches (from common.go)
-	return RestackBranches(ctx.Context, branches, eng, splog, ctx.RepoRoot)
+	var err error
+	if opts.Stat {
+		err = RestackBranchesWithSummary(ctx.Context, branches, eng, splog, ctx.RepoRoot)
+	} else {
+		err = RestackBranches(ctx.Context, branches, eng, splog, ctx.RepoRoot)
+	}
+	if err != nil && opts.Mergetool {
+		return resolveConflictsWithMergetool(ctx, err)
+	}
+	return err
+}
+
+// restackDryRun reports whether a single branch needs restacking and the parent tip it
+// would be rebased onto, without modifying anything
+func restackDryRun(ctx *runtime.Context, branch engine.Branch) error {
+	eng := ctx.Engine
+	if branch.IsTrunk() || !branch.IsTracked() {
+		ctx.Splog.Info("%s is not a tracked branch; nothing to restack.", style.ColorBranchName(branch.GetName(), false))
+		return nil
+	}
+
+	parent := eng.Trunk()
+	if p := eng.GetParent(branch); p != nil {
+		parent = *p
+	}
+	if branch.IsBranchUpToDate() {
+		ctx.Splog.Info("%s is up to date with %s.", style.ColorBranchName(branch.GetName(), false),
+			style.ColorBranchName(parent.GetName(), false))
+		return nil
+	}
+
+	parentRev, err := parent.GetRevision()
+	if err != nil {
+		return fmt.Errorf("failed to get revision for %s: %w", parent.GetName(), err)
+	}
+	ctx.Splog.Info("Would restack %s onto %s (%s).", style.ColorBranchName(branch.GetName(), false),
+		style.ColorBranchName(parent.GetName(), false), shortSHA(parentRev))
+	return nil
 }
@@ -0,0 +1,118 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"stackit.dev/stackit/internal/engine"
+	"stackit.dev/stackit/internal/tui"
+)
+
+// RestackOutcome is what happened to a single branch during a batch restack
+type RestackOutcome struct {
+	BranchName string
+	engine.RestackBranchResult
+}
+
+// RestackOutcomes returns the outcome of each branch the batch restack reached, in the
+// order the branches were passed. Branches after a conflict, and trunk, are left out.
+func RestackOutcomes(branches []engine.Branch, batchResult engine.RestackBatchResult) []RestackOutcome {
+	outcomes := make([]RestackOutcome, 0, len(batchResult.Results))
+	for _, branch := range branches {
+		result, ok := batchResult.Results[branch.GetName()]
+		if !ok {
+			continue
+		}
+		outcomes = append(outcomes, RestackOutcome{BranchName: branch.GetName(), RestackBranchResult: result})
+	}
+	return outcomes
+}
+
+// PrintRestackSummary prints a table with one row per restacked branch showing its
+// result, how its revision moved, and any change of parent
+func PrintRestackSummary(splog *tui.Splog, outcomes []RestackOutcome) {
+	if len(outcomes) == 0 {
+		return
+	}
+
+	var buf strings.Builder
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "BRANCH\tRESULT\tCHANGES")
+	for _, outcome := range outcomes {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", outcome.BranchName, outcome.Result, restackOutcomeChanges(outcome))
+	}
+	_ = w.Flush()
+
+	splog.Info("Restack summary:")
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		splog.Info("  %s", strings.TrimRight(line, " "))
+	}
+}
+
+// restackOutcomeChanges describes how a branch's revision and parent changed
+func restackOutcomeChanges(outcome RestackOutcome) string {
+	var changes []string
+	switch outcome.Result {
+	case engine.RestackDone:
+		if outcome.OldRevision != "" && outcome.NewRevision != "" {
+			changes = append(changes, fmt.Sprintf("%s → %s", shortSHA(outcome.OldRevision), shortSHA(outcome.NewRevision)))
+		}
+	case engine.RestackPruned:
+		changes = append(changes, fmt.Sprintf("deleted, children moved to %s", outcome.NewParent))
+	case engine.RestackUnneeded:
+		if outcome.RemoteOnlyParent != "" {
+			changes = append(changes, fmt.Sprintf("skipped, %s is only on the remote", outcome.RemoteOnlyParent))
+		}
+	}
+	if outcome.Reparented {
+		changes = append(changes, fmt.Sprintf("reparented %s → %s", outcome.OldParent, outcome.NewParent))
+	}
+	return strings.Join(changes, ", ")
+}
+
+// shortSHA abbreviates a commit SHA for display
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// PrintRestackPreview prints a table with one row per branch showing whether restacking
+// it is predicted to apply cleanly or conflict
+func PrintRestackPreview(splog *tui.Splog, predictions []engine.ConflictPrediction) {
+	if len(predictions) == 0 {
+		splog.Info("No branches to restack.")
+		return
+	}
+
+	var buf strings.Builder
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "BRANCH\tPREDICTION")
+	for _, prediction := range predictions {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", prediction.BranchName, describeConflictPrediction(prediction))
+	}
+	_ = w.Flush()
+
+	splog.Info("Restack preview:")
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		splog.Info("  %s", strings.TrimRight(line, " "))
+	}
+}
+
+// describeConflictPrediction summarizes a single branch's predicted restack result
+func describeConflictPrediction(prediction engine.ConflictPrediction) string {
+	switch {
+	case prediction.BlockedBy != "":
+		return fmt.Sprintf("blocked by %s", prediction.BlockedBy)
+	case prediction.Conflict && len(prediction.Files) > 0:
+		return fmt.Sprintf("conflict: %s", strings.Join(prediction.Files, ", "))
+	case prediction.Conflict:
+		return "conflict"
+	case prediction.NeedsRestack:
+		return "clean"
+	default:
+		return "up to date"
+	}
+}
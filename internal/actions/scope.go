@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/git"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui"
@@ -17,6 +18,7 @@ type ScopeOptions struct {
 	Scope string
 	Unset bool
 	Show  bool
+	Stack bool // Also apply Scope to descendants without an explicit scope
 }
 
 // ScopeAction implements the stackit scope command
@@ -31,7 +33,7 @@ func ScopeAction(ctx *runtime.Context, opts ScopeOptions) error {
 	// Handle Show
 	if opts.Show {
 		if isOnTrunk {
-			return fmt.Errorf("not on a branch")
+			return stackiterrors.ErrNotOnBranch
 		}
 		explicitScope := eng.GetExplicitScopeInternal(currentBranch)
 		resolvedScope := eng.GetScopeInternal(currentBranch)
@@ -73,6 +75,14 @@ func ScopeAction(ctx *runtime.Context, opts ScopeOptions) error {
 		return fmt.Errorf("cannot set scope on trunk")
 	}
 
+	if opts.Stack {
+		if err := eng.SetScopeForStack(currentBranch, opts.Scope); err != nil {
+			return fmt.Errorf("failed to set scope: %w", err)
+		}
+		splog.Info("Set scope for branch %s and its descendants to: %s", style.ColorBranchName(currentBranch, false), style.ColorDim(opts.Scope))
+		return nil
+	}
+
 	// Update the current branch's scope
 	oldScope := eng.GetScopeInternal(currentBranch)
 	newScope := engine.NewScope(opts.Scope)
@@ -8,6 +8,7 @@ import (
 
 	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/git"
 	"stackit.dev/stackit/internal/runtime"
 )
@@ -45,7 +46,7 @@ func Action(ctx *runtime.Context, opts Options) error {
 	// Get current branch
 	currentBranch := eng.CurrentBranch()
 	if currentBranch == nil {
-		return fmt.Errorf("not on a branch")
+		return stackiterrors.ErrNotOnBranch
 	}
 
 	// Check for uncommitted tracked changes
@@ -2,8 +2,11 @@ package actions
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui/style"
 )
@@ -23,7 +26,26 @@ func SquashAction(ctx *runtime.Context, opts SquashOptions) error {
 	// Get current branch
 	currentBranch := eng.CurrentBranch()
 	if currentBranch == nil {
-		return fmt.Errorf("not on a branch")
+		return fmt.Errorf("%w; check out the branch to squash first", stackiterrors.ErrNotOnBranch)
+	}
+	if currentBranch.IsTrunk() {
+		return stackiterrors.NewValidationError("cannot squash trunk branch")
+	}
+	if !currentBranch.IsTracked() {
+		return stackiterrors.NewValidationError("%s is not tracked; run 'stackit track' before squashing it", currentBranch.GetName())
+	}
+
+	messages, err := currentBranch.GetAllCommits(engine.CommitFormatMessage)
+	if err != nil {
+		return fmt.Errorf("failed to read commits of %s: %w", currentBranch.GetName(), err)
+	}
+	if len(messages) <= 1 {
+		splog.Info("%s has a single commit; nothing to squash.", style.ColorBranchName(currentBranch.GetName(), true))
+		return nil
+	}
+	message := opts.Message
+	if message == "" {
+		message = combinedSquashMessage(messages)
 	}
 
 	// Take snapshot before modifying the repository
@@ -38,8 +60,10 @@ func SquashAction(ctx *runtime.Context, opts SquashOptions) error {
 
 	// Squash current branch
 	if err := eng.SquashCurrentBranch(context, engine.SquashOptions{
-		Message: opts.Message,
+		Message: message,
 		NoEdit:  opts.NoEdit,
+		// A combined message is only a starting point, so it is opened for editing
+		Edit: opts.Message == "",
 	}); err != nil {
 		return fmt.Errorf("failed to squash branch: %w", err)
 	}
@@ -63,3 +87,17 @@ func SquashAction(ctx *runtime.Context, opts SquashOptions) error {
 
 	return nil
 }
+
+// combinedSquashMessage builds the default message of a squashed branch from its commit
+// messages, newest first: the oldest commit's subject, followed by the body of each commit
+// in the order they were made
+func combinedSquashMessage(messages []string) string {
+	subject, _, _ := strings.Cut(messages[len(messages)-1], "\n")
+	parts := []string{strings.TrimSpace(subject)}
+	for _, message := range slices.Backward(messages) {
+		if _, body, ok := strings.Cut(message, "\n"); ok && strings.TrimSpace(body) != "" {
+			parts = append(parts, strings.TrimSpace(body))
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
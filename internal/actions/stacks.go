@@ -0,0 +1,38 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"stackit.dev/stackit/internal/runtime"
+)
+
+// StacksAction lists the independent stacks branching off trunk, with how many branches
+// each has and the branches at its tips
+func StacksAction(ctx *runtime.Context) error {
+	splog := ctx.Splog
+
+	stacks := ctx.Engine.ListStacks()
+	if len(stacks) == 0 {
+		splog.Info("No stacks on %s.", ctx.Engine.Trunk().GetName())
+		return nil
+	}
+
+	var buf strings.Builder
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "STACK\tBRANCHES\tTIPS")
+	for _, stack := range stacks {
+		tips := make([]string, 0, len(stack.Tips))
+		for _, tip := range stack.Tips {
+			tips = append(tips, tip.GetName())
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\n", stack.Root.GetName(), stack.DescendantCount+1, strings.Join(tips, ", "))
+	}
+	_ = w.Flush()
+
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		splog.Info("%s", strings.TrimRight(line, " "))
+	}
+	return nil
+}
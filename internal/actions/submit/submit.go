@@ -5,48 +5,89 @@ import (
 	"context"
 	"errors"
 	"fmt"
-
+	"regexp"
+	"strings"
 	"sync"
 
 	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/config"
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/git"
 	"stackit.dev/stackit/internal/github"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui"
 	"stackit.dev/stackit/internal/tui/components/submit"
 	"stackit.dev/stackit/internal/tui/components/tree"
-	"stackit.dev/stackit/internal/utils"
 )
 
 // Options contains options for the submit command
 type Options struct {
-	Branch               string
-	Stack                bool
-	Force                bool
-	DryRun               bool
-	Confirm              bool
-	UpdateOnly           bool
-	Always               bool
-	Restack              bool
-	Draft                bool
-	Publish              bool
-	Edit                 bool
-	EditTitle            bool
-	EditDescription      bool
-	NoEdit               bool
-	NoEditTitle          bool
-	NoEditDescription    bool
-	Reviewers            string
-	TeamReviewers        string
-	MergeWhenReady       bool
-	RerequestReview      bool
-	View                 bool
-	Web                  bool
-	Comment              string
-	TargetTrunk          string
-	IgnoreOutOfSyncTrunk bool
-	SubmitFooter         bool // Whether to include PR footer (from config)
+	Branch                 string
+	Stack                  bool
+	StackFromTrunk         bool // Submit the whole stack from trunk's child up, including side branches
+	Force                  bool
+	DryRun                 bool
+	Confirm                bool
+	UpdateOnly             bool
+	UpdateDescriptionsOnly bool // Only refresh the bodies of existing PRs: no pushes, restacks or title changes
+	Always                 bool
+	Since                  string // Only submit branches whose tip isn't already contained in this ref
+	MaxPRs                 int    // Fail rather than open more than this many new PRs; 0 means no limit
+	Concurrency            int    // How many PRs to create or update at once (submit.concurrency); 0 means 1
+	Template               string // Name of the PR template to start new PR bodies from, for repos with several
+	Fill                   bool   // Take new PRs' titles and bodies from their commits without prompting
+	Restack                bool
+	NoRestackCheck         bool // Submit branches that need restacking without prompting or failing
+	Draft                  bool
+	Publish                bool
+	DraftDefault           bool   // Open new PRs as drafts unless --publish is given (submit.draftDefault)
+	WIPPattern             string // Open new PRs as drafts when the branch name or newest commit subject matches (submit.wipPattern)
+	Edit                   bool
+	EditTitle              bool
+	EditDescription        bool
+	NoEdit                 bool
+	NoEditTitle            bool
+	NoEditDescription      bool
+	Reviewers              string
+	TeamReviewers          string
+	CopyReviewersFrom      string // Also request the reviewers and apply the labels of this branch's PR
+	Labels                 []string
+	ReplaceLabels          bool
+	DependentLabels        bool   // Label every submitted PR with StackLabel and note the parent PR it depends on
+	StackLabel             string // Label applied with DependentLabels (submit.stackLabel)
+	Milestone              string
+	MergeWhenReady         bool
+	AutoMerge              github.AutoMergeMethod // Enable GitHub auto-merge with this method; empty leaves it off
+	RerequestReview        bool
+	View                   bool
+	Web                    bool
+	Comment                string
+	CommentOnce            bool   // Only post Comment on the current branch's PR
+	TargetTrunk            string // Open the PRs of branches on trunk against this remote branch instead; later submits keep it
+	IgnoreOutOfSyncTrunk   bool
+	NoVerify               bool // Skip the pre-push hook (--no-verify / submit.skipHooks)
+	SetUpstream            bool // Track the remote branch on a branch's first push (push.setUpstream)
+	SubmitFooter           bool // Whether to include PR footer (from config)
+	StripFooter            bool // Remove the footer from existing PR bodies instead of updating it
+	FooterMode             actions.FooterMode
+}
+
+// ConfigOptions returns the Options that come from configuration, which `stackit submit`
+// starts from before applying its flags. Commands that submit after their own work, such
+// as modify --submit, use them as they are.
+func ConfigOptions(cfg *config.Config) Options {
+	return Options{
+		MaxPRs:       cfg.SubmitMaxPRs(),
+		Concurrency:  cfg.SubmitConcurrency(),
+		DraftDefault: cfg.SubmitDraftDefault(),
+		WIPPattern:   cfg.SubmitWIPPattern(),
+		StackLabel:   cfg.SubmitStackLabel(),
+		NoVerify:     cfg.SubmitSkipHooks(),
+		SetUpstream:  cfg.PushSetUpstream(),
+		SubmitFooter: cfg.SubmitFooter(),
+		FooterMode:   actions.FooterMode(cfg.SubmitFooterMode()),
+	}
 }
 
 // Info contains information about a branch to submit
@@ -59,6 +100,9 @@ type Info struct {
 	Action     string // "create" or "update"
 	PRNumber   *int
 	Metadata   *PRMetadata
+	WasDraft   bool // Whether the existing PR was a draft before this submit
+	// TargetTrunk is the --target-trunk base to remember for a branch on trunk, if given
+	TargetTrunk string
 }
 
 // Action performs the submit operation
@@ -73,7 +117,29 @@ func Action(ctx *runtime.Context, opts Options) error {
 
 	// Validate flags
 	if opts.Draft && opts.Publish {
-		return fmt.Errorf("can't use both --publish and --draft flags in one command")
+		return stackiterrors.NewValidationError("can't use both --publish and --draft flags in one command")
+	}
+	if opts.CommentOnce && opts.Comment == "" {
+		return stackiterrors.NewValidationError("--comment-once requires --comment")
+	}
+	if opts.AutoMerge != "" && opts.Draft {
+		return stackiterrors.NewValidationError("can't use --auto-merge with --draft; draft PRs can't be auto-merged")
+	}
+	if opts.DependentLabels && opts.StackLabel == "" {
+		return stackiterrors.NewValidationError("--dependent-labels requires a label; set submit.stackLabel")
+	}
+	if opts.WIPPattern != "" {
+		if _, err := regexp.Compile(opts.WIPPattern); err != nil {
+			return stackiterrors.NewValidationError("invalid submit.wipPattern %q: %v", opts.WIPPattern, err)
+		}
+	}
+	if opts.UpdateDescriptionsOnly && (opts.DryRun || opts.Restack) {
+		return stackiterrors.NewValidationError("can't use --update-descriptions-only with --dry-run or --restack")
+	}
+	if opts.Since != "" {
+		if _, err := git.GetRef(opts.Since); err != nil {
+			return stackiterrors.NewValidationError("invalid --since ref %q: not a branch or commit", opts.Since)
+		}
 	}
 
 	// Get branches to submit
@@ -86,6 +152,10 @@ func Action(ctx *runtime.Context, opts Options) error {
 		return nil
 	}
 
+	if opts.UpdateDescriptionsOnly {
+		return updateDescriptionsOnly(ctx, branches, opts)
+	}
+
 	currentBranch := eng.CurrentBranch()
 
 	// Populate remote SHAs early for accurate display
@@ -97,6 +167,28 @@ func Action(ctx *runtime.Context, opts Options) error {
 	renderer := getStackTreeRenderer(branches, opts, eng)
 	ui.ShowStack(renderer, eng.Trunk().GetName())
 
+	if err := checkNewPRLimit(branches, opts, eng); err != nil {
+		return err
+	}
+
+	// Catch branches that would be submitted on stale bases
+	if !opts.Restack && !opts.NoRestackCheck && !opts.DryRun {
+		restack, err := checkBranchesNeedRestack(branches, eng, ui)
+		if err != nil {
+			return err
+		}
+		opts.Restack = restack
+	}
+
+	// Check GitHub access now, so a missing or logged-out CLI fails before restacking, metadata prompts or pushes
+	var githubClient github.Client
+	if !opts.DryRun {
+		githubClient, err = getGitHubClient(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Restack if requested
 	if opts.Restack {
 		ui.ShowRestackStart()
@@ -121,8 +213,16 @@ func Action(ctx *runtime.Context, opts Options) error {
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
+	var copied *CopiedMetadata
+	if opts.CopyReviewersFrom != "" {
+		copied, err = LoadCopiedMetadata(context, opts.CopyReviewersFrom, eng, githubClient)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Prepare branches for submit (show planning phase with current indicator)
-	submissionInfos, err := prepareBranchesForSubmit(branches, opts, eng, ctx, currentBranch.GetName(), ui)
+	submissionInfos, err := prepareBranchesForSubmit(branches, opts, copied, eng, ctx, currentBranch.GetName(), ui)
 	if err != nil {
 		return fmt.Errorf("failed to prepare branches: %w", err)
 	}
@@ -135,6 +235,17 @@ func Action(ctx *runtime.Context, opts Options) error {
 
 	if len(submissionInfos) == 0 {
 		ui.ShowNoChanges()
+		// Footers come off even when there is nothing to push
+		if opts.StripFooter {
+			if err := stripFootersOnly(ctx, branches, opts); err != nil {
+				splog.Warn("%v", err)
+			}
+		}
+		if opts.Web {
+			if prInfo, err := eng.GetPrInfo(*currentBranch); err == nil && prInfo != nil && prInfo.URL() != "" {
+				openInBrowser([]string{prInfo.URL()}, splog)
+			}
+		}
 		return nil
 	}
 
@@ -154,33 +265,54 @@ func Action(ctx *runtime.Context, opts Options) error {
 	// Start submission phase
 	ui.StartSubmitting(progressItems)
 
-	githubClient, err := getGitHubClient(ctx)
-	if err != nil {
-		return err
-	}
 	repoOwner, repoName := githubClient.GetOwnerRepo()
 
-	remote := eng.GetRemote()
+	remote := eng.GetPushRemote()
+
+	// Push branches one at a time from the bottom of the stack up. Pushes go through git,
+	// so pre-push hooks run for every branch; if a hook (or anything else) rejects a
+	// branch we stop there, leaving the branches above it unpushed, and only create or
+	// update PRs for the branches that made it to the remote.
+	pushedInfos := make([]Info, 0, len(submissionInfos))
+	var pushErr error
+	for _, info := range submissionInfos {
+		ui.UpdateSubmitItem(info.BranchName, "submitting", "", nil)
+		if err := pushBranchIfNeeded(context, info, opts, remote, eng); err != nil {
+			ui.UpdateSubmitItem(info.BranchName, "error", "", err)
+			pushErr = fmt.Errorf("submit stopped at %s: %w", info.BranchName, err)
+			break
+		}
+		pushedInfos = append(pushedInfos, info)
+	}
+
+	// PRs are created or updated opts.Concurrency at a time. Each branch waits for the branch
+	// it's stacked on, so a PR is never opened before the PR of its base exists.
+	pushedNames := make([]string, len(pushedInfos))
+	done := make(map[string]chan struct{}, len(pushedInfos))
+	for i, info := range pushedInfos {
+		pushedNames[i] = info.BranchName
+		done[info.BranchName] = make(chan struct{})
+	}
+	pushedParents := eng.BatchGetParents(pushedNames)
+	slots := make(chan struct{}, max(opts.Concurrency, 1))
+
 	var wg sync.WaitGroup
-	var submitErr error
-	var errMu sync.Mutex
+	var urlMu sync.Mutex
+	prURLs := make(map[string]string)
+	submitErrs := make([]error, len(pushedInfos))
+	draftChanges := make([]string, len(pushedInfos))
 
-	for _, submissionInfo := range submissionInfos {
+	for i, submissionInfo := range pushedInfos {
 		wg.Add(1)
-		go func(info Info) {
+		go func(i int, info Info) {
 			defer wg.Done()
+			defer close(done[info.BranchName])
 
-			ui.UpdateSubmitItem(info.BranchName, "submitting", "", nil)
-
-			if err := pushBranchIfNeeded(context, info, opts, remote, eng); err != nil {
-				ui.UpdateSubmitItem(info.BranchName, "error", "", err)
-				errMu.Lock()
-				if submitErr == nil {
-					submitErr = err
-				}
-				errMu.Unlock()
-				return
+			if parentDone, ok := done[pushedParents[info.BranchName]]; ok {
+				<-parentDone
 			}
+			slots <- struct{}{}
+			defer func() { <-slots }()
 
 			var prURL string
 			const (
@@ -190,48 +322,184 @@ func Action(ctx *runtime.Context, opts Options) error {
 			var err error
 			if info.Action == actionCreate {
 				prURL, err = createPullRequestQuiet(context, info, eng, githubClient, repoOwner, repoName)
+				// The PR exists and is recorded, so missing labels or a milestone don't fail the submit
+				if errors.Is(err, github.ErrPRSetupIncomplete) {
+					splog.Warn("%v", err)
+					err = nil
+				}
 			} else {
-				prURL, err = updatePullRequestQuiet(context, info, opts, eng, githubClient, repoOwner, repoName)
+				prURL, draftChanges[i], err = updatePullRequestQuiet(context, info, opts, eng, githubClient, repoOwner, repoName)
+			}
+
+			if err == nil && opts.AutoMerge != "" {
+				err = enableAutoMerge(context, info, opts.AutoMerge, eng, githubClient, repoOwner, repoName)
+				if errors.Is(err, github.ErrAutoMergeNotNeeded) {
+					splog.Warn("Auto-merge not enabled for %s: %v", info.BranchName, err)
+					err = nil
+				}
 			}
 
 			if err != nil {
 				ui.UpdateSubmitItem(info.BranchName, "error", "", err)
-				errMu.Lock()
-				if submitErr == nil {
-					submitErr = err
-				}
-				errMu.Unlock()
+				submitErrs[i] = err
 				return
 			}
 
 			ui.UpdateSubmitItem(info.BranchName, "done", prURL, nil)
 
+			urlMu.Lock()
+			prURLs[info.BranchName] = prURL
+			urlMu.Unlock()
+
+			if opts.Comment != "" && (!opts.CommentOnce || info.BranchName == currentBranch.GetName()) {
+				if err := commentOnPullRequest(context, info, opts.Comment, eng, githubClient, repoOwner, repoName); err != nil {
+					splog.Warn("%v", err)
+				}
+			}
+
 			// Open in browser if requested
-			if opts.View && prURL != "" {
-				if err := utils.OpenBrowser(prURL); err != nil {
+			if opts.View && !opts.Web && prURL != "" {
+				if err := OpenBrowser(prURL); err != nil {
 					splog.Debug("Failed to open browser: %v", err)
 				}
 			}
-		}(submissionInfo)
+		}(i, submissionInfo)
 	}
 	wg.Wait()
 
-	if submitErr != nil {
-		return submitErr
+	// Report draft state changes in stack order, once every update has finished
+	reportDraftChanges(ui, splog, pushedInfos, draftChanges, submitErrs)
+
+	// With --web, open pages in stack order once every PR has been created or updated
+	if opts.Web {
+		openInBrowser(webURLs(context, pushedInfos, prURLs, currentBranch.GetName(), opts, eng, remote, repoOwner, repoName), splog)
+	}
+
+	if pushErr != nil {
+		return pushErr
+	}
+	// Report the failure lowest in the stack, whichever finished first
+	for _, err := range submitErrs {
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.DependentLabels {
+		if err := applyStackLabels(context, branches, opts.StackLabel, eng, githubClient, repoOwner, repoName); err != nil {
+			splog.Warn("%v", err)
+		}
 	}
 
 	// Update PR body footers silently
-	if opts.SubmitFooter {
-		actions.UpdateStackPRMetadata(context, branches, eng, githubClient, repoOwner, repoName)
+	if opts.StripFooter {
+		if _, err := actions.UpdateStackPRMetadata(context, branches, eng, githubClient, repoOwner, repoName, actions.PRMetadataOptions{
+			StripFooter: true,
+			SkipTitles:  true,
+			FetchBodies: true,
+			Concurrency: opts.Concurrency,
+		}); err != nil {
+			splog.Warn("%v", err)
+		}
+	} else if opts.SubmitFooter {
+		footerMode := opts.FooterMode
+		if footerMode == "" {
+			footerMode = actions.FooterModeBody
+		}
+		if _, err := actions.UpdateStackPRMetadata(context, branches, eng, githubClient, repoOwner, repoName, actions.PRMetadataOptions{
+			FooterMode:  footerMode,
+			Concurrency: opts.Concurrency,
+		}); err != nil {
+			splog.Debug("Failed to update PR footers: %v", err)
+		}
 	}
 
 	return nil
 }
 
+// updateDescriptionsOnly refreshes the stack footers in the bodies (or footer comments) of
+// the branches' existing PRs, without pushing, restacking or changing titles
+func updateDescriptionsOnly(ctx *runtime.Context, branches []string, opts Options) error {
+	if opts.StripFooter {
+		return stripFootersOnly(ctx, branches, opts)
+	}
+	if !opts.SubmitFooter {
+		ctx.Splog.Info("PR footers are turned off (submit.footer), so there are no descriptions to update.")
+		return nil
+	}
+	githubClient, err := getGitHubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	footerMode := opts.FooterMode
+	if footerMode == "" {
+		footerMode = actions.FooterModeBody
+	}
+	repoOwner, repoName := githubClient.GetOwnerRepo()
+	updated, err := actions.UpdateStackPRMetadata(ctx.Context, branches, ctx.Engine, githubClient, repoOwner, repoName, actions.PRMetadataOptions{
+		FooterMode:  footerMode,
+		SkipTitles:  true,
+		FetchBodies: true,
+		Concurrency: opts.Concurrency,
+	})
+	if updated == 0 && err == nil {
+		ctx.Splog.Info("All PR descriptions are already up to date.")
+		return nil
+	}
+	ctx.Splog.Info("Updated the descriptions of %d PR(s).", updated)
+	return err
+}
+
+// stripFootersOnly removes the stack footers from the bodies (and footer comments) of the
+// branches' existing PRs
+func stripFootersOnly(ctx *runtime.Context, branches []string, opts Options) error {
+	githubClient, err := getGitHubClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	repoOwner, repoName := githubClient.GetOwnerRepo()
+	stripped, err := actions.UpdateStackPRMetadata(ctx.Context, branches, ctx.Engine, githubClient, repoOwner, repoName, actions.PRMetadataOptions{
+		StripFooter: true,
+		SkipTitles:  true,
+		FetchBodies: true,
+		Concurrency: opts.Concurrency,
+	})
+	if stripped == 0 && err == nil {
+		ctx.Splog.Info("No PRs have a footer to remove.")
+		return nil
+	}
+	ctx.Splog.Info("Removed the footer from %d PR(s).", stripped)
+	return err
+}
+
+// prBase returns the remote branch a PR should be opened against: the branch's parent, or
+// for a branch on trunk, the --target-trunk base, falling back to the one an earlier
+// submit stored. Local tracking is unaffected either way.
+func prBase(parent engine.Branch, prInfo *engine.PrInfo, opts Options) string {
+	switch {
+	case !parent.IsTrunk():
+		return parent.GetName()
+	case opts.TargetTrunk != "":
+		return opts.TargetTrunk
+	case prInfo != nil && prInfo.TargetTrunk() != "":
+		return prInfo.TargetTrunk()
+	default:
+		return parent.GetName()
+	}
+}
+
 // prepareBranchesForSubmit prepares submission info for each branch, outputting via UI
-func prepareBranchesForSubmit(branches []string, opts Options, eng engine.Engine, runtimeCtx *runtime.Context, currentBranch string, ui tui.SubmitUI) ([]Info, error) {
+func prepareBranchesForSubmit(branches []string, opts Options, copied *CopiedMetadata, eng engine.Engine, runtimeCtx *runtime.Context, currentBranch string, ui tui.SubmitUI) ([]Info, error) {
 	submissionInfos := make([]Info, 0, len(branches))
 
+	bodyTemplate, err := LoadPRTemplate(runtimeCtx.RepoRoot, opts.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	parents := eng.BatchGetParents(branches)
 	for _, branchName := range branches {
 		branch := eng.GetBranch(branchName)
 		status, err := eng.GetPRSubmissionStatus(branch)
@@ -246,12 +514,40 @@ func prepareBranchesForSubmit(branches []string, opts Options, eng engine.Engine
 		isCurrent := branchName == currentBranch
 
 		// Check if we should skip
+		if opts.Since != "" {
+			changed, err := branchChangedSince(branch, opts.Since, eng)
+			if err != nil {
+				return nil, err
+			}
+			if !changed {
+				ui.ShowBranchPlan(branchName, action, isCurrent, true, "no changes since "+opts.Since)
+				continue
+			}
+		}
+
 		if opts.UpdateOnly && action == "create" {
 			ui.ShowBranchPlan(branchName, action, isCurrent, true, "skipped, no existing PR")
 			continue
 		}
 
 		needsUpdate := status.NeedsUpdate
+		reason := status.Reason
+		parentBranch := eng.GetBranch(parents[branchName])
+		base := prBase(parentBranch, prInfo, opts)
+		targetTrunk := ""
+		if parentBranch.IsTrunk() {
+			targetTrunk = opts.TargetTrunk
+		}
+		if action == "update" && base != parents[branchName] && prInfo != nil {
+			// The status compares the PR's base with the local parent, so it doesn't know
+			// about the target trunk
+			matchesRemote, _ := eng.BranchMatchesRemote(branchName)
+			needsUpdate = prInfo.Base() != base || !matchesRemote
+			reason = ""
+			if !needsUpdate {
+				reason = "no changes"
+			}
+		}
 		if action == "update" {
 			// Check if draft status needs to change
 			draftStatusNeedsChange := false
@@ -263,15 +559,19 @@ func prepareBranchesForSubmit(branches []string, opts Options, eng engine.Engine
 				}
 			}
 
-			needsUpdate = needsUpdate || opts.Edit || opts.Always || draftStatusNeedsChange
+			// Labels and milestone are only reconciled on update when explicitly requested
+			labelsRequested := len(opts.Labels) > 0 || opts.ReplaceLabels || opts.Milestone != "" || copied != nil
+
+			needsUpdate = needsUpdate || opts.Edit || opts.Always || draftStatusNeedsChange || labelsRequested
 
 			if !needsUpdate && !opts.Draft && !opts.Publish {
-				ui.ShowBranchPlan(branchName, action, isCurrent, true, status.Reason)
+				ui.ShowBranchPlan(branchName, action, isCurrent, true, reason)
 				continue
 			}
 		}
 
-		// Prepare metadata
+		// Prepare metadata. --fill only applies to new PRs and replaces every prompt.
+		fill := opts.Fill && action == "create"
 		metadataOpts := MetadataOptions{
 			Edit:              opts.Edit && !opts.NoEdit,
 			EditTitle:         opts.EditTitle && !opts.NoEditTitle,
@@ -279,10 +579,20 @@ func prepareBranchesForSubmit(branches []string, opts Options, eng engine.Engine
 			NoEdit:            opts.NoEdit,
 			NoEditTitle:       opts.NoEditTitle,
 			NoEditDescription: opts.NoEditDescription,
-			Draft:             opts.Draft,
+			Draft:             opts.Draft || (action == "create" && draftNewPR(branchName, opts, eng)),
 			Publish:           opts.Publish,
 			Reviewers:         opts.Reviewers,
-			ReviewersPrompt:   opts.Reviewers == "" && opts.Edit,
+			ReviewersPrompt:   opts.Reviewers == "" && opts.Edit && !fill,
+			Labels:            opts.Labels,
+			ReplaceLabels:     opts.ReplaceLabels,
+			Milestone:         opts.Milestone,
+			BodyTemplate:      bodyTemplate,
+			Fill:              fill,
+		}
+		if copied != nil {
+			metadataOpts.Labels = mergeNames(copied.Labels, opts.Labels)
+			metadataOpts.CopiedReviewers = copied.Reviewers
+			metadataOpts.CopiedTeamReviewers = copied.TeamReviewers
 		}
 
 		ui.Pause()
@@ -295,19 +605,19 @@ func prepareBranchesForSubmit(branches []string, opts Options, eng engine.Engine
 		// Get SHAs
 		branchObj := eng.GetBranch(branchName)
 		headSHA, _ := branchObj.GetRevision()
-		parentBranchName := branchObj.GetParentPrecondition()
-		parentBranch := eng.GetBranch(parentBranchName)
 		baseSHA, _ := parentBranch.GetRevision()
 
 		submissionInfo := Info{
-			BranchName: branchName,
-			Head:       branchName,
-			Base:       parentBranchName,
-			HeadSHA:    headSHA,
-			BaseSHA:    baseSHA,
-			Action:     action,
-			PRNumber:   prNumber,
-			Metadata:   metadata,
+			BranchName:  branchName,
+			Head:        branchName,
+			Base:        base,
+			HeadSHA:     headSHA,
+			BaseSHA:     baseSHA,
+			Action:      action,
+			PRNumber:    prNumber,
+			Metadata:    metadata,
+			WasDraft:    prInfo != nil && prInfo.IsDraft(),
+			TargetTrunk: targetTrunk,
 		}
 
 		ui.ShowBranchPlan(branchName, action, isCurrent, false, "")
@@ -318,6 +628,21 @@ func prepareBranchesForSubmit(branches []string, opts Options, eng engine.Engine
 	return submissionInfos, nil
 }
 
+// branchChangedSince reports whether a branch has commits that aren't in since. A branch
+// whose tip is already contained in since, e.g. an earlier revision of a branch above it,
+// hasn't changed since then.
+func branchChangedSince(branch engine.Branch, since string, eng engine.Engine) (bool, error) {
+	revision, err := branch.GetRevision()
+	if err != nil {
+		return false, fmt.Errorf("failed to get revision for %s: %w", branch.GetName(), err)
+	}
+	contained, err := eng.IsAncestor(revision, since)
+	if err != nil {
+		return false, fmt.Errorf("failed to compare %s with %s: %w", branch.GetName(), since, err)
+	}
+	return !contained, nil
+}
+
 // getBranchesToSubmit returns the list of branches to submit based on options
 func getBranchesToSubmit(opts Options, eng engine.Engine) ([]string, error) {
 	// Get branch scope
@@ -325,13 +650,27 @@ func getBranchesToSubmit(opts Options, eng engine.Engine) ([]string, error) {
 	if branchName == "" {
 		currentBranch := eng.CurrentBranch()
 		if currentBranch == nil {
-			return nil, fmt.Errorf("not on a branch and no branch specified")
+			return nil, fmt.Errorf("%w and no branch specified", stackiterrors.ErrNotOnBranch)
 		}
 		branchName = currentBranch.GetName()
 	}
 
 	var allBranches []string
-	if opts.Stack {
+	if opts.StackFromTrunk {
+		// Everything stacked on the bottom branch, wherever the current branch sits in it
+		root := eng.GetBranch(branchName)
+		for {
+			parent := eng.GetParent(root)
+			if parent == nil || parent.IsTrunk() {
+				break
+			}
+			root = *parent
+		}
+		allBranches = []string{root.GetName()}
+		for _, b := range eng.GetRelativeStackUpstack(root) {
+			allBranches = append(allBranches, b.GetName())
+		}
+	} else if opts.Stack {
 		// Include descendants and ancestors
 		branch := eng.GetBranch(branchName)
 		stackBranches := eng.GetFullStack(branch)
@@ -364,12 +703,81 @@ func getBranchesToSubmit(opts Options, eng engine.Engine) ([]string, error) {
 	return branches, nil
 }
 
+// checkNewPRLimit stops a submit that would open more new PRs than opts.MaxPRs allows, before
+// anything is restacked or pushed. Branches that already have a PR are only updated, so they
+// don't count. --force skips the check.
+func checkNewPRLimit(branches []string, opts Options, eng engine.Engine) error {
+	if opts.MaxPRs <= 0 || opts.Force || opts.UpdateOnly || opts.DryRun {
+		return nil
+	}
+
+	var creates []string
+	for _, branchName := range branches {
+		branch := eng.GetBranch(branchName)
+		status, err := eng.GetPRSubmissionStatus(branch)
+		if err != nil {
+			return err
+		}
+		if status.Action != "create" {
+			continue
+		}
+		if opts.Since != "" {
+			changed, err := branchChangedSince(branch, opts.Since, eng)
+			if err != nil {
+				return err
+			}
+			if !changed {
+				continue
+			}
+		}
+		creates = append(creates, branchName)
+	}
+
+	if len(creates) <= opts.MaxPRs {
+		return nil
+	}
+	return stackiterrors.NewValidationError("submit would open %d new PRs (%s), more than the limit of %d. "+
+		"Submit a smaller part of the stack (e.g. without --stack, or with --branch), raise the limit with --max-prs, "+
+		"or use --force to submit anyway",
+		len(creates), strings.Join(creates, ", "), opts.MaxPRs)
+}
+
+// draftNewPR reports whether a new PR for the branch is opened as a draft. --draft and
+// --publish (or --auto-merge, which a draft can't use) always win; otherwise
+// submit.draftDefault applies, then the WIP pattern is matched against the branch name
+// and the subject of its newest commit.
+func draftNewPR(branchName string, opts Options, eng engine.BranchReader) bool {
+	switch {
+	case opts.Draft:
+		return true
+	case opts.Publish || opts.AutoMerge != "":
+		return false
+	case opts.DraftDefault:
+		return true
+	case opts.WIPPattern == "":
+		return false
+	}
+
+	wip, err := regexp.Compile(opts.WIPPattern)
+	if err != nil {
+		return false
+	}
+	if wip.MatchString(branchName) {
+		return true
+	}
+	subjects, err := eng.GetBranch(branchName).GetAllCommits(engine.CommitFormatSubject)
+	return err == nil && len(subjects) > 0 && wip.MatchString(subjects[0])
+}
+
 // getGitHubClient returns the GitHub client from context
 func getGitHubClient(ctx *runtime.Context) (github.Client, error) {
 	if ctx.GitHubClient != nil {
 		return ctx.GitHubClient, nil
 	}
-	return nil, fmt.Errorf("no GitHub client available - check your GITHUB_TOKEN")
+	if err := github.CheckCLIAuth(ctx.Context); err != nil {
+		return nil, err
+	}
+	return nil, stackiterrors.WithCategory(stackiterrors.ErrRemoteAuth, fmt.Errorf("no GitHub client available - check your GITHUB_TOKEN"))
 }
 
 // pushBranchIfNeeded pushes a branch to remote if needed
@@ -379,8 +787,22 @@ func pushBranchIfNeeded(ctx context.Context, submissionInfo Info, opts Options,
 		return nil
 	}
 
+	// A plain push is enough when the remote branch is an ancestor (e.g. after a merge restack)
 	forceWithLease := !opts.Force
-	if err := eng.PushBranch(ctx, submissionInfo.BranchName, remote, opts.Force, forceWithLease); err != nil {
+	if forceWithLease {
+		if fastForward, err := eng.BranchFastForwardsRemote(submissionInfo.BranchName); err == nil && fastForward {
+			forceWithLease = false
+		}
+	}
+
+	if err := eng.PushBranchWithOptions(ctx, git.PushOptions{
+		BranchName:     submissionInfo.BranchName,
+		Remote:         remote,
+		Force:          opts.Force,
+		ForceWithLease: forceWithLease,
+		NoVerify:       opts.NoVerify,
+		SetUpstream:    opts.SetUpstream,
+	}); err != nil {
 		if errors.Is(err, git.ErrStaleRemoteInfo) {
 			return fmt.Errorf("force-with-lease push of %s failed due to external changes to the remote branch. If you are collaborating on this stack, try 'stackit sync' to pull in changes. Alternatively, use the --force option to bypass the stale info warning", submissionInfo.BranchName)
 		}
@@ -389,7 +811,8 @@ func pushBranchIfNeeded(ctx context.Context, submissionInfo Info, opts Options,
 	return nil
 }
 
-// createPullRequestQuiet creates a new pull request without logging
+// createPullRequestQuiet creates a new pull request without logging. The PR is recorded
+// even when applying its labels or milestone fails, and that error is returned.
 func createPullRequestQuiet(ctx context.Context, submissionInfo Info, eng engine.Engine, githubClient github.Client, repoOwner, repoName string) (string, error) {
 	createOpts := github.CreatePROptions{
 		Title:         submissionInfo.Metadata.Title,
@@ -399,9 +822,11 @@ func createPullRequestQuiet(ctx context.Context, submissionInfo Info, eng engine
 		Draft:         submissionInfo.Metadata.IsDraft,
 		Reviewers:     submissionInfo.Metadata.Reviewers,
 		TeamReviewers: submissionInfo.Metadata.TeamReviewers,
+		Labels:        submissionInfo.Metadata.Labels,
+		Milestone:     submissionInfo.Metadata.Milestone,
 	}
 	pr, err := githubClient.CreatePullRequest(ctx, repoOwner, repoName, createOpts)
-	if err != nil {
+	if pr == nil {
 		return "", fmt.Errorf("failed to create PR for %s: %w", submissionInfo.BranchName, err)
 	}
 
@@ -409,6 +834,7 @@ func createPullRequestQuiet(ctx context.Context, submissionInfo Info, eng engine
 	prNumber := pr.Number
 	prURL := pr.HTMLURL
 	branch := eng.GetBranch(submissionInfo.BranchName)
+	headSHA, _ := branch.GetRevision()
 	_ = eng.UpsertPrInfo(branch, engine.NewPrInfo(
 		&prNumber,
 		submissionInfo.Metadata.Title,
@@ -417,13 +843,67 @@ func createPullRequestQuiet(ctx context.Context, submissionInfo Info, eng engine
 		submissionInfo.Base,
 		prURL,
 		submissionInfo.Metadata.IsDraft,
-	))
+	).WithHeadSHA(headSHA).WithTargetTrunk(submissionInfo.TargetTrunk))
 
+	if err != nil {
+		return prURL, fmt.Errorf("%s: %w", submissionInfo.BranchName, err)
+	}
 	return prURL, nil
 }
 
-// updatePullRequestQuiet updates an existing pull request without logging
-func updatePullRequestQuiet(ctx context.Context, submissionInfo Info, opts Options, eng engine.Engine, githubClient github.Client, repoOwner, repoName string) (string, error) {
+// commentOnPullRequest posts the --comment text on a branch's PR once it has been created or updated
+func commentOnPullRequest(ctx context.Context, submissionInfo Info, body string, eng engine.Engine, githubClient github.Client, repoOwner, repoName string) error {
+	prInfo, err := eng.GetPrInfo(eng.GetBranch(submissionInfo.BranchName))
+	if err != nil || prInfo == nil || prInfo.Number() == nil {
+		return fmt.Errorf("failed to comment on PR for %s: no PR number recorded", submissionInfo.BranchName)
+	}
+	if err := githubClient.AddComment(ctx, repoOwner, repoName, *prInfo.Number(), body); err != nil {
+		return fmt.Errorf("failed to comment on PR for %s: %w", submissionInfo.BranchName, err)
+	}
+	return nil
+}
+
+// enableAutoMerge turns on GitHub auto-merge for a branch's PR. Existing PRs that are
+// already set to auto-merge are left alone, so re-submitting doesn't change the method.
+func enableAutoMerge(ctx context.Context, submissionInfo Info, method github.AutoMergeMethod, eng engine.Engine, githubClient github.Client, repoOwner, repoName string) error {
+	prInfo, err := eng.GetPrInfo(eng.GetBranch(submissionInfo.BranchName))
+	if err != nil || prInfo == nil || prInfo.Number() == nil {
+		return fmt.Errorf("failed to enable auto-merge for %s: no PR number recorded", submissionInfo.BranchName)
+	}
+
+	if submissionInfo.Action != "create" {
+		pr, err := githubClient.GetPullRequestByBranch(ctx, repoOwner, repoName, submissionInfo.BranchName)
+		if err == nil && pr != nil && pr.AutoMergeEnabled {
+			return nil
+		}
+	}
+
+	if err := githubClient.EnableAutoMerge(ctx, repoOwner, repoName, *prInfo.Number(), method); err != nil {
+		return fmt.Errorf("failed to enable auto-merge for %s: %w", submissionInfo.BranchName, err)
+	}
+	return nil
+}
+
+// reportDraftChanges logs each PR whose draft state an update changed, pausing the UI so
+// the lines aren't swallowed by the progress display
+func reportDraftChanges(ui tui.SubmitUI, splog *tui.Splog, infos []Info, changes []string, errs []error) {
+	paused := false
+	for i, change := range changes {
+		if change == "" || errs[i] != nil {
+			continue
+		}
+		if !paused {
+			ui.Pause()
+			defer ui.Resume()
+			paused = true
+		}
+		splog.Info("%s: %s", infos[i].BranchName, change)
+	}
+}
+
+// updatePullRequestQuiet updates an existing pull request without logging. Besides the PR's
+// URL it returns how its draft state changed ("published" or "marked draft"), or "" if it didn't.
+func updatePullRequestQuiet(ctx context.Context, submissionInfo Info, opts Options, eng engine.Engine, githubClient github.Client, repoOwner, repoName string) (string, string, error) {
 	// Check if base changed
 	branch := eng.GetBranch(submissionInfo.BranchName)
 	prInfo, _ := eng.GetPrInfo(branch)
@@ -439,11 +919,24 @@ func updatePullRequestQuiet(ctx context.Context, submissionInfo Info, opts Optio
 		TeamReviewers:   submissionInfo.Metadata.TeamReviewers,
 		MergeWhenReady:  &opts.MergeWhenReady,
 		RerequestReview: opts.RerequestReview,
+		Labels:          submissionInfo.Metadata.Labels,
+		ReplaceLabels:   opts.ReplaceLabels,
+	}
+
+	if submissionInfo.Metadata.Milestone != "" {
+		updateOpts.Milestone = &submissionInfo.Metadata.Milestone
 	}
 
 	// Only update draft status if it's explicitly set via flags
+	draftChange := ""
 	if opts.Draft || opts.Publish {
 		updateOpts.Draft = &submissionInfo.Metadata.IsDraft
+		if submissionInfo.WasDraft != submissionInfo.Metadata.IsDraft {
+			draftChange = "published"
+			if submissionInfo.Metadata.IsDraft {
+				draftChange = "marked draft"
+			}
+		}
 	}
 
 	// Before updating the base, check if there are commits between the new base and head
@@ -473,7 +966,7 @@ func updatePullRequestQuiet(ctx context.Context, submissionInfo Info, opts Optio
 	}
 
 	if err := githubClient.UpdatePullRequest(ctx, repoOwner, repoName, *submissionInfo.PRNumber, updateOpts); err != nil {
-		return "", fmt.Errorf("failed to update PR for %s: %w", submissionInfo.BranchName, err)
+		return "", "", fmt.Errorf("failed to update PR for %s: %w", submissionInfo.BranchName, err)
 	}
 
 	// Get PR URL
@@ -489,6 +982,8 @@ func updatePullRequestQuiet(ctx context.Context, submissionInfo Info, opts Optio
 		}
 	}
 
+	// Remember what was pushed, so a later commit shows the PR as needing a re-submit
+	headSHA, _ := branch.GetRevision()
 	_ = eng.UpsertPrInfo(branch, engine.NewPrInfo(
 		submissionInfo.PRNumber,
 		submissionInfo.Metadata.Title,
@@ -497,9 +992,9 @@ func updatePullRequestQuiet(ctx context.Context, submissionInfo Info, opts Optio
 		baseToStore,
 		prURL,
 		submissionInfo.Metadata.IsDraft,
-	))
+	).WithHeadSHA(headSHA).WithTargetTrunk(submissionInfo.TargetTrunk))
 
-	return prURL, nil
+	return prURL, draftChange, nil
 }
 
 // getStackTreeRenderer returns the stack tree renderer with PR annotations
@@ -536,7 +1031,7 @@ func getStackTreeRenderer(branches []string, opts Options, eng engine.Engine) *t
 			annotation.IsDraft = prInfo.IsDraft()
 		} else if branchSet[branchName] {
 			annotation.PRAction = actionCreate
-			annotation.IsDraft = opts.Draft
+			annotation.IsDraft = draftNewPR(branchName, opts, eng)
 		}
 
 		annotations[branchName] = annotation
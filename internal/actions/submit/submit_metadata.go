@@ -2,11 +2,14 @@
 package submit
 
 import (
+	"context"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/github"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui"
@@ -86,6 +89,38 @@ func GetPRBody(branchName string, editInline bool, existingBody string, eng engi
 	return tui.OpenEditor(body, "stackit-pr-description-*.md")
 }
 
+// FillPRBody builds a PR body from a branch's commit messages. A single commit contributes
+// its message body; several commits get a bulleted summary of their subjects followed by
+// each full message, oldest first.
+func FillPRBody(branchName string, eng engine.BranchReader) (string, error) {
+	messages, err := eng.GetBranch(branchName).GetAllCommits(engine.CommitFormatMessage)
+	if err != nil {
+		return "", fmt.Errorf("failed to get commits for %s: %w", branchName, err)
+	}
+	if len(messages) == 0 {
+		return "", nil
+	}
+	if len(messages) == 1 {
+		parts := strings.SplitN(strings.TrimSpace(messages[0]), "\n", 2)
+		if len(parts) < 2 {
+			return "", nil
+		}
+		return strings.TrimSpace(parts[1]), nil
+	}
+
+	var summary, details strings.Builder
+	// GetAllCommits returns newest to oldest
+	for i := len(messages) - 1; i >= 0; i-- {
+		msg := strings.TrimSpace(messages[i])
+		if msg == "" {
+			continue
+		}
+		summary.WriteString("- " + strings.SplitN(msg, "\n", 2)[0] + "\n")
+		details.WriteString("\n" + msg + "\n")
+	}
+	return strings.TrimSpace(summary.String() + details.String()), nil
+}
+
 // GetReviewers gets reviewers from flag or prompts user
 func GetReviewers(reviewersFlag string, _ *runtime.Context) ([]string, []string, error) {
 	if reviewersFlag == "" {
@@ -117,30 +152,64 @@ func PreparePRMetadata(branchName string, opts MetadataOptions, eng engine.Engin
 	prInfo, _ := eng.GetPrInfo(branch)
 
 	metadata := &PRMetadata{
-		Title:   getStringValue(prInfo, "Title"),
-		Body:    getStringValue(prInfo, "Body"),
-		IsDraft: false,
+		Title:     getStringValue(prInfo, "Title"),
+		Body:      getStringValue(prInfo, "Body"),
+		IsDraft:   false,
+		Labels:    opts.Labels,
+		Milestone: opts.Milestone,
+	}
+
+	// Reviewers and labels from earlier submits are kept, with any given now added on top
+	if opts.ReplaceLabels {
+		metadata.Labels = append([]string{}, opts.Labels...)
+	} else if prInfo != nil {
+		metadata.Labels = mergeNames(prInfo.Labels(), opts.Labels)
 	}
 
 	shouldEditTitle := opts.EditTitle || (opts.Edit && !opts.NoEditTitle)
 	shouldEditBody := opts.EditDescription || (opts.Edit && !opts.NoEditDescription)
+	reviewersPrompt := opts.ReviewersPrompt
+	if tui.EditorDisabled() {
+		// --no-editor takes the defaults for everything that would otherwise be prompted for
+		shouldEditTitle, shouldEditBody, reviewersPrompt = false, false, false
+	}
 
 	scope := eng.GetScopeInternal(branchName)
 
-	if shouldEditTitle || (prInfo == nil || prInfo.Title() == "") {
-		title, err := GetPRTitle(branchName, shouldEditTitle, metadata.Title, scope.String(), eng)
+	if opts.Fill {
+		// Start over from the commits rather than anything saved by an earlier attempt
+		title, err := GetPRTitle(branchName, false, "", scope.String(), eng)
 		if err != nil {
 			return nil, err
 		}
-		metadata.Title = title
-	}
-
-	if shouldEditBody || (prInfo == nil || prInfo.Body() == "") {
-		finalBody, err := GetPRBody(branchName, shouldEditBody, metadata.Body, eng)
+		body, err := FillPRBody(branchName, eng)
 		if err != nil {
 			return nil, err
 		}
-		metadata.Body = finalBody
+		metadata.Title = title
+		metadata.Body = body
+	} else {
+		if shouldEditTitle || (prInfo == nil || prInfo.Title() == "") {
+			title, err := GetPRTitle(branchName, shouldEditTitle, metadata.Title, scope.String(), eng)
+			if err != nil {
+				return nil, err
+			}
+			metadata.Title = title
+		}
+
+		if shouldEditBody || (prInfo == nil || prInfo.Body() == "") {
+			// A new PR whose commits don't describe it starts from the repository's PR template
+			if metadata.Body == "" && opts.BodyTemplate != "" && (prInfo == nil || prInfo.Number() == nil) {
+				if commitBody, _ := GetPRBody(branchName, false, "", eng); strings.TrimSpace(commitBody) == "" {
+					metadata.Body = opts.BodyTemplate
+				}
+			}
+			finalBody, err := GetPRBody(branchName, shouldEditBody, metadata.Body, eng)
+			if err != nil {
+				return nil, err
+			}
+			metadata.Body = finalBody
+		}
 	}
 
 	switch {
@@ -154,7 +223,7 @@ func PreparePRMetadata(branchName string, opts MetadataOptions, eng engine.Engin
 		metadata.IsDraft = prInfo.IsDraft()
 	}
 
-	if opts.ReviewersPrompt {
+	if reviewersPrompt {
 		reviewers, teamReviewers, err := GetReviewersWithPrompt(opts.Reviewers, ctx)
 		if err != nil {
 			return nil, err
@@ -169,6 +238,19 @@ func PreparePRMetadata(branchName string, opts MetadataOptions, eng engine.Engin
 		metadata.Reviewers = reviewers
 		metadata.TeamReviewers = teamReviewers
 	}
+	metadata.Reviewers = mergeNames(opts.CopiedReviewers, metadata.Reviewers)
+	metadata.TeamReviewers = mergeNames(opts.CopiedTeamReviewers, metadata.TeamReviewers)
+
+	// Reviewers from earlier submits are remembered, but only requested when the PR is
+	// created; an existing PR already has them, and asking again is --rerequest-review's job
+	storedReviewers, storedTeamReviewers := metadata.Reviewers, metadata.TeamReviewers
+	if prInfo != nil {
+		storedReviewers = mergeNames(prInfo.Reviewers(), metadata.Reviewers)
+		storedTeamReviewers = mergeNames(prInfo.TeamReviewers(), metadata.TeamReviewers)
+		if prInfo.Number() == nil {
+			metadata.Reviewers, metadata.TeamReviewers = storedReviewers, storedTeamReviewers
+		}
+	}
 
 	// Save metadata to engine in case command fails
 	if err := eng.UpsertPrInfo(branch, engine.NewPrInfo(
@@ -179,7 +261,7 @@ func PreparePRMetadata(branchName string, opts MetadataOptions, eng engine.Engin
 		"",
 		"",
 		metadata.IsDraft,
-	)); err != nil {
+	).WithReviewersAndLabels(storedReviewers, storedTeamReviewers, metadata.Labels)); err != nil {
 		ctx.Splog.Debug("Failed to save PR metadata: %v", err)
 	}
 
@@ -198,6 +280,76 @@ type MetadataOptions struct {
 	Publish           bool
 	Reviewers         string
 	ReviewersPrompt   bool
+	Labels            []string
+	ReplaceLabels     bool
+	Milestone         string
+	BodyTemplate      string // Pull request template that new PR bodies start from
+	Fill              bool   // Take the title and body from the branch's commits without prompting
+
+	// Reviewers taken from another branch's PR, requested along with any given by flags
+	CopiedReviewers     []string
+	CopiedTeamReviewers []string
+}
+
+// CopiedMetadata is the reviewers and labels taken from another branch's PR
+type CopiedMetadata struct {
+	Reviewers     []string
+	TeamReviewers []string
+	Labels        []string
+}
+
+// LoadCopiedMetadata returns the reviewers and labels stored for a branch's PR by earlier
+// submits, or, when none were stored, those of its PR on GitHub. The client may be nil,
+// in which case only stored metadata is used.
+func LoadCopiedMetadata(ctx context.Context, branchName string, eng engine.Engine, client github.Client) (*CopiedMetadata, error) {
+	if prInfo, err := eng.GetPrInfo(eng.GetBranch(branchName)); err == nil && prInfo != nil {
+		copied := &CopiedMetadata{
+			Reviewers:     prInfo.Reviewers(),
+			TeamReviewers: prInfo.TeamReviewers(),
+			Labels:        prInfo.Labels(),
+		}
+		if !copied.isEmpty() {
+			return copied, nil
+		}
+	}
+
+	if client != nil {
+		owner, repo := client.GetOwnerRepo()
+		pr, err := client.GetPullRequestByBranch(ctx, owner, repo, branchName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the PR for %s: %w", branchName, err)
+		}
+		if pr != nil {
+			copied := &CopiedMetadata{
+				Reviewers:     pr.Reviewers,
+				TeamReviewers: pr.TeamReviewers,
+				Labels:        pr.Labels,
+			}
+			if !copied.isEmpty() {
+				return copied, nil
+			}
+		}
+	}
+
+	return nil, stackiterrors.NewValidationError("no reviewers or labels to copy from %s", branchName)
+}
+
+func (m *CopiedMetadata) isEmpty() bool {
+	return len(m.Reviewers) == 0 && len(m.TeamReviewers) == 0 && len(m.Labels) == 0
+}
+
+// mergeNames returns the stored names followed by any new ones not already among them
+func mergeNames(stored, added []string) []string {
+	if len(stored) == 0 {
+		return added
+	}
+	merged := append([]string{}, stored...)
+	for _, name := range added {
+		if !slices.Contains(merged, name) {
+			merged = append(merged, name)
+		}
+	}
+	return merged
 }
 
 // PRMetadata contains PR metadata
@@ -207,6 +359,8 @@ type PRMetadata struct {
 	IsDraft       bool
 	Reviewers     []string
 	TeamReviewers []string
+	Labels        []string
+	Milestone     string
 }
 
 // Helper to get string value from prInfo
@@ -2,11 +2,15 @@ package submit_test
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"stackit.dev/stackit/internal/actions/submit"
+	stackiterrors "stackit.dev/stackit/internal/errors"
+	"stackit.dev/stackit/internal/tui"
 	"stackit.dev/stackit/testhelpers"
 	"stackit.dev/stackit/testhelpers/scenario"
 )
@@ -155,6 +159,25 @@ func TestPreparePRMetadata_NoEdit(t *testing.T) {
 	})
 }
 
+func TestPreparePRMetadata_NoEditor(t *testing.T) {
+	s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+	s.CreateBranch(featureBranch).CommitChange("feature", "add feature").TrackBranch(featureBranch, "main")
+
+	marker := filepath.Join(t.TempDir(), "launched")
+	t.Setenv("GIT_EDITOR", "touch "+marker+" &&")
+	tui.SetEditorDisabled(true)
+	t.Cleanup(func() { tui.SetEditorDisabled(false) })
+
+	metadata, err := submit.PreparePRMetadata(featureBranch, submit.MetadataOptions{
+		EditDescription: true,
+		BodyTemplate:    "template body",
+	}, s.Engine, s.Context)
+	require.NoError(t, err)
+	require.NoFileExists(t, marker, "--no-editor should not launch the editor")
+	require.Equal(t, "add feature", metadata.Title)
+	require.Equal(t, "template body", metadata.Body)
+}
+
 func TestGetPRBody_MultipleCommits(t *testing.T) {
 	t.Run("returns a bulleted list of subjects for multiple commits", func(t *testing.T) {
 		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
@@ -178,3 +201,56 @@ func TestGetPRBody_MultipleCommits(t *testing.T) {
 		require.Equal(t, expectedBody, body)
 	})
 }
+
+func TestLoadPRTemplate(t *testing.T) {
+	t.Run("uses the single template", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(root, ".github"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "pull_request_template.md"), []byte("## Summary\n"), 0o600))
+
+		template, err := submit.LoadPRTemplate(root, "")
+		require.NoError(t, err)
+		require.Equal(t, "## Summary", template)
+	})
+
+	t.Run("selects a named template from the directory", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(root, ".github", "PULL_REQUEST_TEMPLATE"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "PULL_REQUEST_TEMPLATE.md"), []byte("default"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "PULL_REQUEST_TEMPLATE", "bugfix.md"), []byte("## Bug\n"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "PULL_REQUEST_TEMPLATE", "feature.md"), []byte("## Feature\n"), 0o600))
+
+		template, err := submit.LoadPRTemplate(root, "bugfix")
+		require.NoError(t, err)
+		require.Equal(t, "## Bug", template)
+
+		template, err = submit.LoadPRTemplate(root, "feature.md")
+		require.NoError(t, err)
+		require.Equal(t, "## Feature", template)
+
+		template, err = submit.LoadPRTemplate(root, "")
+		require.NoError(t, err)
+		require.Equal(t, "default", template)
+	})
+
+	t.Run("rejects an unknown template", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(root, ".github", "PULL_REQUEST_TEMPLATE"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "PULL_REQUEST_TEMPLATE", "bugfix.md"), []byte("## Bug"), 0o600))
+
+		_, err := submit.LoadPRTemplate(root, "release")
+		require.ErrorIs(t, err, stackiterrors.ErrValidation)
+		require.Contains(t, err.Error(), "available: bugfix")
+	})
+
+	t.Run("has no default among several templates", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(root, ".github", "PULL_REQUEST_TEMPLATE"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "PULL_REQUEST_TEMPLATE", "bugfix.md"), []byte("## Bug"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "PULL_REQUEST_TEMPLATE", "feature.md"), []byte("## Feature"), 0o600))
+
+		template, err := submit.LoadPRTemplate(root, "")
+		require.NoError(t, err)
+		require.Empty(t, template)
+	})
+}
@@ -0,0 +1,53 @@
+package submit
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/engine"
+	"stackit.dev/stackit/internal/github"
+)
+
+// applyStackLabels labels each submitted PR with stackLabel for --dependent-labels, and
+// notes the parent's PR in the body of every PR above the bottom of the stack. PRs that
+// already carry the label and the right note are left alone.
+func applyStackLabels(ctx context.Context, branches []string, stackLabel string, eng engine.Engine, githubClient github.Client, repoOwner, repoName string) error {
+	for _, branchName := range branches {
+		branch := eng.GetBranch(branchName)
+		prInfo, err := eng.GetPrInfo(branch)
+		if err != nil || prInfo == nil || prInfo.Number() == nil {
+			continue
+		}
+
+		var parentPR *int
+		if parent := eng.GetParent(branch); parent != nil && !parent.IsTrunk() {
+			if parentInfo, err := eng.GetPrInfo(*parent); err == nil && parentInfo != nil {
+				parentPR = parentInfo.Number()
+			}
+		}
+
+		body := actions.UpdatePRBodyDependsOn(prInfo.Body(), parentPR)
+		if body == prInfo.Body() && slices.Contains(prInfo.Labels(), stackLabel) {
+			continue
+		}
+
+		updateOpts := github.UpdatePROptions{Labels: []string{stackLabel}}
+		if body != prInfo.Body() {
+			updateOpts.Body = &body
+		}
+		if err := githubClient.UpdatePullRequest(ctx, repoOwner, repoName, *prInfo.Number(), updateOpts); err != nil {
+			return fmt.Errorf("failed to label PR #%d for %s: %w", *prInfo.Number(), branchName, err)
+		}
+
+		updated := prInfo.WithBody(body)
+		if !slices.Contains(prInfo.Labels(), stackLabel) {
+			updated = updated.WithReviewersAndLabels(prInfo.Reviewers(), prInfo.TeamReviewers(), append(slices.Clone(prInfo.Labels()), stackLabel))
+		}
+		if err := eng.UpsertPrInfo(branch, updated); err != nil {
+			return fmt.Errorf("failed to save PR info for %s: %w", branchName, err)
+		}
+	}
+	return nil
+}
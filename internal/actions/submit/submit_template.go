@@ -0,0 +1,100 @@
+package submit
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	stackiterrors "stackit.dev/stackit/internal/errors"
+)
+
+// prTemplateFiles are the locations GitHub reads a single pull request template from
+var prTemplateFiles = []string{
+	".github/PULL_REQUEST_TEMPLATE.md",
+	".github/pull_request_template.md",
+	"PULL_REQUEST_TEMPLATE.md",
+	"pull_request_template.md",
+	"docs/PULL_REQUEST_TEMPLATE.md",
+	"docs/pull_request_template.md",
+}
+
+// prTemplateDirs are the locations GitHub reads multiple pull request templates from
+var prTemplateDirs = []string{
+	".github/PULL_REQUEST_TEMPLATE",
+	".github/pull_request_template",
+	"PULL_REQUEST_TEMPLATE",
+	"docs/PULL_REQUEST_TEMPLATE",
+}
+
+// LoadPRTemplate returns the pull request template new PR bodies start from. With a name,
+// the template is picked from the multi-template directory; otherwise the single template
+// is used, or the only template in the directory. Returns "" if the repository has none.
+func LoadPRTemplate(repoRoot, name string) (string, error) {
+	templates := listPRTemplates(repoRoot)
+
+	if name != "" {
+		fileName := name
+		if filepath.Ext(fileName) == "" {
+			fileName += ".md"
+		}
+		for _, path := range templates {
+			if strings.EqualFold(filepath.Base(path), fileName) {
+				return readPRTemplate(path)
+			}
+		}
+		names := make([]string, 0, len(templates))
+		for _, path := range templates {
+			names = append(names, strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+		}
+		if len(names) == 0 {
+			return "", stackiterrors.NewValidationError("PR template %q not found: no templates in %s", name, prTemplateDirs[0])
+		}
+		return "", stackiterrors.NewValidationError("PR template %q not found (available: %s)", name, strings.Join(names, ", "))
+	}
+
+	for _, file := range prTemplateFiles {
+		template, err := readPRTemplate(filepath.Join(repoRoot, file))
+		if err == nil {
+			return template, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+	if len(templates) == 1 {
+		return readPRTemplate(templates[0])
+	}
+	return "", nil
+}
+
+// listPRTemplates returns the paths of the templates in the multi-template directories
+func listPRTemplates(repoRoot string) []string {
+	var templates []string
+	for _, dir := range prTemplateDirs {
+		entries, err := os.ReadDir(filepath.Join(repoRoot, dir))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ".md") {
+				templates = append(templates, filepath.Join(repoRoot, dir, entry.Name()))
+			}
+		}
+		if len(templates) > 0 {
+			break
+		}
+	}
+	sort.Strings(templates)
+	return templates
+}
+
+// readPRTemplate reads a template file, trimming trailing whitespace
+func readPRTemplate(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\n\r\t "), nil
+}
@@ -1,12 +1,22 @@
 package submit_test
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/actions/submit"
+	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
+	"stackit.dev/stackit/internal/github"
+	"stackit.dev/stackit/internal/tui"
 	"stackit.dev/stackit/testhelpers"
 	"stackit.dev/stackit/testhelpers/scenario"
 )
@@ -44,6 +54,40 @@ func TestActionWithMockedGitHub(t *testing.T) {
 		require.Equal(t, "feature", *config.CreatedPRs[0].Head.Ref, "PR should be for feature branch")
 	})
 
+	t.Run("records the submitted revision so later commits show the PR as stale", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"feature": "main",
+			})
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		prInfoOf := func() *engine.PrInfo {
+			t.Helper()
+			prInfo, err := s.Engine.GetPrInfo(s.Engine.GetBranch("feature"))
+			require.NoError(t, err)
+			require.NotNil(t, prInfo)
+			return prInfo
+		}
+
+		s.Checkout("feature")
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Draft: true}))
+		rev, err := s.Scene.Repo.GetRevision("feature")
+		require.NoError(t, err)
+		require.Equal(t, rev, prInfoOf().HeadSHA())
+		require.False(t, prInfoOf().IsStale())
+
+		s.Commit("more work").Rebuild()
+		require.True(t, prInfoOf().IsStale())
+
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true}))
+		require.False(t, prInfoOf().IsStale())
+	})
+
 	t.Run("updates existing PR", func(t *testing.T) {
 		// Skip this test for now - branch tracking issue needs to be resolved separately
 		t.Skip("Skipping due to branch tracking issue in test setup")
@@ -144,6 +188,127 @@ func TestActionWithMockedGitHub(t *testing.T) {
 		require.True(t, createdBranches["C2"])
 	})
 
+	t.Run("submits the whole stack from trunk up with --stack-from-trunk", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"P":     "main",
+				"C1":    "P",
+				"C1top": "C1",
+				"C2":    "P",
+				"other": "main",
+			})
+		s.Checkout("C1top")
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		mockConfig := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, mockConfig)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, mockConfig)
+
+		err = submit.Action(s.Context, submit.Options{
+			StackFromTrunk: true,
+			NoEdit:         true,
+			Draft:          true,
+		})
+		require.NoError(t, err)
+
+		// Everything stacked on P, but not the separate stack on main
+		createdBranches := []string{}
+		for _, pr := range mockConfig.CreatedPRs {
+			createdBranches = append(createdBranches, *pr.Head.Ref)
+		}
+		require.ElementsMatch(t, []string{"P", "C1", "C1top", "C2"}, createdBranches)
+	})
+
+	t.Run("creates PRs concurrently without opening a child before its parent", func(t *testing.T) {
+		stack := map[string]string{
+			"A": "main",
+			"B": "A",
+			"C": "A",
+			"D": "B",
+		}
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).WithStack(stack)
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		mockConfig := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, mockConfig)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, mockConfig)
+
+		s.Checkout("A")
+		err = submit.Action(s.Context, submit.Options{
+			Stack:       true,
+			Concurrency: 2,
+			NoEdit:      true,
+			Draft:       true,
+		})
+		require.NoError(t, err)
+
+		bases := map[string]string{}
+		order := map[string]int{}
+		for i, pr := range mockConfig.CreatedPRs {
+			bases[*pr.Head.Ref] = *pr.Base.Ref
+			order[*pr.Head.Ref] = i
+		}
+		require.Equal(t, stack, bases)
+		for child, parent := range stack {
+			if parent != "main" {
+				require.Less(t, order[parent], order[child], "%s's PR was opened before its parent %s's", child, parent)
+			}
+		}
+	})
+
+	t.Run("opens the bottom PR against the --target-trunk base and keeps it", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+				"B": "A",
+			})
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		mockConfig := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, mockConfig)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, mockConfig)
+
+		s.Checkout("B")
+		err = submit.Action(s.Context, submit.Options{
+			TargetTrunk: "staging",
+			NoEdit:      true,
+			Draft:       true,
+		})
+		require.NoError(t, err)
+
+		bases := map[string]string{}
+		for _, pr := range mockConfig.CreatedPRs {
+			bases[*pr.Head.Ref] = *pr.Base.Ref
+		}
+		require.Equal(t, map[string]string{"A": "staging", "B": "A"}, bases)
+		s.ExpectStackStructure(map[string]string{"A": "main", "B": "A"})
+
+		// A later submit without --target-trunk leaves the PR on staging
+		err = submit.Action(s.Context, submit.Options{
+			NoEdit: true,
+			Always: true,
+		})
+		require.NoError(t, err)
+		prInfo, err := s.Engine.GetPrInfo(s.Engine.GetBranch("A"))
+		require.NoError(t, err)
+		require.Equal(t, "staging", prInfo.Base())
+		require.Equal(t, "staging", *mockConfig.UpdatedPRs[*prInfo.Number()].Base.Ref)
+
+		// Naming trunk moves it back
+		err = submit.Action(s.Context, submit.Options{
+			TargetTrunk: "main",
+			NoEdit:      true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "main", *mockConfig.UpdatedPRs[*prInfo.Number()].Base.Ref)
+		prInfo, err = s.Engine.GetPrInfo(s.Engine.GetBranch("A"))
+		require.NoError(t, err)
+		require.Equal(t, "main", prInfo.Base())
+	})
+
 	t.Run("skips base update when no commits between base and head", func(t *testing.T) {
 		// This test covers the scenario where after reordering, a branch has no commits
 		// between it and its new base, which would cause GitHub to reject the PR update.
@@ -218,4 +383,1002 @@ func TestActionWithMockedGitHub(t *testing.T) {
 		require.True(t, exists, "PR %d should be in UpdatedPRs", prNumberB)
 		require.NotNil(t, updatedPR, "Updated PR should not be nil")
 	})
+	t.Run("applies labels and milestone when creating PR", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"feature": "main",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		err = submit.Action(s.Context, submit.Options{
+			NoEdit:    true,
+			Draft:     true,
+			Labels:    []string{"backend", "needs-review"},
+			Milestone: "v1.0",
+		})
+		require.NoError(t, err)
+
+		require.Len(t, config.CreatedPRs, 1)
+		prNumber := *config.CreatedPRs[0].Number
+		require.Equal(t, []string{"backend", "needs-review"}, config.Labels[prNumber])
+		require.Equal(t, "v1.0", config.Milestones[prNumber])
+	})
+
+	t.Run("records the created PR when its labels can't be applied", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"feature": "main",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		config.LabelsUnavailable = true
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		output := captureOutput(t, s, func() {
+			require.NoError(t, submit.Action(s.Context, submit.Options{
+				NoEdit: true,
+				Draft:  true,
+				Labels: []string{"backend"},
+			}))
+		})
+		require.Contains(t, output, "failed to add labels")
+
+		require.Len(t, config.CreatedPRs, 1)
+		prInfo, err := s.Engine.GetPrInfo(s.Engine.GetBranch("feature"))
+		require.NoError(t, err)
+		require.NotNil(t, prInfo.Number())
+		require.Equal(t, config.CreatedPRs[0].GetNumber(), *prInfo.Number())
+
+		// A retry updates the recorded PR instead of opening a duplicate
+		config.LabelsUnavailable = false
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Labels: []string{"backend"}}))
+		require.Len(t, config.CreatedPRs, 1)
+		require.Equal(t, []string{"backend"}, config.Labels[*prInfo.Number()])
+	})
+
+	t.Run("reconciles labels and milestone when updating PR", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"feature": "main",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		prNumber := 42
+		prData := testhelpers.DefaultPRData()
+		prData.Head = "feature"
+		prData.Number = prNumber
+		pr := testhelpers.NewSamplePullRequest(prData)
+		config.PRs["feature"] = pr
+		config.CreatedPRs = append(config.CreatedPRs, pr)
+		config.UpdatedPRs[prNumber] = pr
+		config.Labels[prNumber] = []string{"backend"}
+
+		err = s.Engine.UpsertPrInfo(s.Engine.GetBranch("feature"), testhelpers.NewTestPrInfoWithTitle(prNumber, prData.Title).
+			WithBody(prData.Body).
+			WithBase("main"))
+		require.NoError(t, err)
+
+		// Without label flags, labels are left alone on update
+		err = submit.Action(s.Context, submit.Options{NoEdit: true, Always: true})
+		require.NoError(t, err)
+		require.Equal(t, []string{"backend"}, config.Labels[prNumber])
+		require.Empty(t, config.Milestones[prNumber])
+
+		// Missing labels are added
+		err = submit.Action(s.Context, submit.Options{
+			NoEdit:    true,
+			Labels:    []string{"backend", "frontend"},
+			Milestone: "v2.0",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"backend", "frontend"}, config.Labels[prNumber])
+		require.Equal(t, "v2.0", config.Milestones[prNumber])
+
+		// --replace-labels drops labels that weren't passed
+		err = submit.Action(s.Context, submit.Options{
+			NoEdit:        true,
+			Labels:        []string{"frontend"},
+			ReplaceLabels: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"frontend"}, config.Labels[prNumber])
+	})
+	t.Run("--dependent-labels labels the stack and notes each parent PR", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		s.CreateBranch("parser").CommitChange("parser", "add parser").TrackBranch("parser", "main").
+			CreateBranch("lexer").CommitChange("lexer", "add lexer").TrackBranch("lexer", "parser")
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		opts := submit.Options{NoEdit: true, Stack: true, DependentLabels: true, StackLabel: "stacked"}
+		require.NoError(t, submit.Action(s.Context, opts))
+
+		prNumbers := map[string]int{}
+		for _, pr := range config.CreatedPRs {
+			prNumbers[pr.GetHead().GetRef()] = pr.GetNumber()
+		}
+		require.Len(t, prNumbers, 2)
+		require.Equal(t, []string{"stacked"}, config.Labels[prNumbers["parser"]])
+		require.Equal(t, []string{"stacked"}, config.Labels[prNumbers["lexer"]])
+
+		bodies := map[string]string{}
+		for _, branchName := range []string{"parser", "lexer"} {
+			prInfo, err := s.Engine.GetPrInfo(s.Engine.GetBranch(branchName))
+			require.NoError(t, err)
+			bodies[branchName] = prInfo.Body()
+		}
+		dependsOn := fmt.Sprintf("Depends on #%d", prNumbers["parser"])
+		require.Contains(t, bodies["lexer"], dependsOn)
+		require.Equal(t, bodies["lexer"], config.UpdatedPRs[prNumbers["lexer"]].GetBody())
+		require.NotContains(t, bodies["parser"], "Depends on")
+
+		// Submitting again doesn't duplicate the note
+		require.NoError(t, submit.Action(s.Context, opts))
+		prInfo, err := s.Engine.GetPrInfo(s.Engine.GetBranch("lexer"))
+		require.NoError(t, err)
+		require.Equal(t, 1, strings.Count(prInfo.Body(), dependsOn))
+	})
+
+	t.Run("stops at branch rejected by pre-push hook", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+				"B": "A",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		// Reject pushes of B only
+		hook := "#!/bin/sh\nwhile read local_ref local_sha remote_ref remote_sha; do\n  if [ \"$local_ref\" = \"refs/heads/B\" ]; then\n    echo \"B is not allowed\" >&2\n    exit 1\n  fi\ndone\nexit 0\n"
+		hookPath := filepath.Join(s.Scene.Dir, ".git", "hooks", "pre-push")
+		require.NoError(t, os.MkdirAll(filepath.Dir(hookPath), 0755))
+		require.NoError(t, os.WriteFile(hookPath, []byte(hook), 0755))
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("B")
+		err = submit.Action(s.Context, submit.Options{NoEdit: true, Draft: true})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "submit stopped at B")
+
+		// A was pushed before the hook rejected B, so only A gets a PR
+		require.Len(t, config.CreatedPRs, 1)
+		require.Equal(t, "A", *config.CreatedPRs[0].Head.Ref)
+
+		// --no-verify bypasses the hook
+		err = submit.Action(s.Context, submit.Options{NoEdit: true, Draft: true, NoVerify: true})
+		require.NoError(t, err)
+		require.Len(t, config.CreatedPRs, 2)
+		require.Equal(t, "B", *config.CreatedPRs[1].Head.Ref)
+	})
+
+	t.Run("opens PRs in stack order with --web --stack", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+				"B": "A",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		opened := stubOpenBrowser(t)
+
+		s.Checkout("A")
+		err = submit.Action(s.Context, submit.Options{NoEdit: true, Draft: true, Stack: true, Web: true})
+		require.NoError(t, err)
+
+		require.Equal(t, []string{
+			config.PRs["A"].GetHTMLURL(),
+			config.PRs["B"].GetHTMLURL(),
+		}, *opened)
+	})
+
+	t.Run("opens only the current branch's PR with --web", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+				"B": "A",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		opened := stubOpenBrowser(t)
+
+		s.Checkout("B")
+		err = submit.Action(s.Context, submit.Options{NoEdit: true, Draft: true, Web: true})
+		require.NoError(t, err)
+
+		require.Len(t, config.CreatedPRs, 2)
+		require.Equal(t, []string{config.PRs["B"].GetHTMLURL()}, *opened)
+	})
+
+	t.Run("posts --comment on every submitted PR", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+				"B": "A",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("A")
+		err = submit.Action(s.Context, submit.Options{NoEdit: true, Draft: true, Stack: true, Comment: "Ready for review"})
+		require.NoError(t, err)
+
+		require.Len(t, config.Comments, 2)
+		require.Equal(t, []string{"Ready for review"}, config.Comments[config.PRs["A"].GetNumber()])
+		require.Equal(t, []string{"Ready for review"}, config.Comments[config.PRs["B"].GetNumber()])
+	})
+
+	t.Run("posts --comment only on the current branch's PR with --comment-once", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+				"B": "A",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("B")
+		err = submit.Action(s.Context, submit.Options{NoEdit: true, Draft: true, Comment: "Fixed review feedback", CommentOnce: true})
+		require.NoError(t, err)
+
+		require.Len(t, config.CreatedPRs, 2)
+		require.Equal(t, map[int][]string{
+			config.PRs["B"].GetNumber(): {"Fixed review feedback"},
+		}, config.Comments)
+	})
+
+	t.Run("new PR bodies start from the PR template with the footer below it", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Join(s.Scene.Dir, ".github"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(s.Scene.Dir, ".github", "PULL_REQUEST_TEMPLATE.md"),
+			[]byte("## Summary\n\n## Test plan\n"), 0o600))
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("A")
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Draft: true, SubmitFooter: true}))
+
+		require.Equal(t, "## Summary\n\n## Test plan", config.PRs["A"].GetBody())
+
+		// The dependency footer is added below the template
+		body := config.UpdatedPRs[config.PRs["A"].GetNumber()].GetBody()
+		require.True(t, strings.HasPrefix(body, "## Summary\n\n## Test plan"), body)
+		require.Greater(t, strings.Index(body, "PR Dependency Tree"), strings.Index(body, "## Test plan"))
+	})
+
+	t.Run("the PR template doesn't replace a commit body or an existing PR's empty body", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		s.CreateBranch("A").
+			CommitChange("a", "Add widget\n\nThe widget does things.").
+			TrackBranch("A", "main").
+			CreateBranch("B").
+			CommitChange("b", "Wire up widget").
+			TrackBranch("B", "A")
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Join(s.Scene.Dir, ".github"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(s.Scene.Dir, ".github", "PULL_REQUEST_TEMPLATE.md"),
+			[]byte("## Summary\n"), 0o600))
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		// B's PR already exists with an empty body
+		config.PRs["B"] = testhelpers.NewSamplePullRequest(testhelpers.SamplePRData{
+			Number: 7, Title: "Wire up widget", Head: "B", Base: "A", State: "open",
+		})
+		require.NoError(t, s.Engine.UpsertPrInfo(s.Engine.GetBranch("B"), testhelpers.NewTestPrInfoWithTitle(7, "Wire up widget")))
+
+		s.Checkout("B")
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Draft: true}))
+
+		require.Equal(t, "The widget does things.", strings.TrimSpace(config.PRs["A"].GetBody()))
+		require.NotContains(t, config.UpdatedPRs[7].GetBody(), "## Summary")
+	})
+
+	t.Run("--fill takes titles and bodies from the commits", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		s.CreateBranch("A").
+			CommitChange("a", "Add widget\n\nThe widget does things.").
+			TrackBranch("A", "main").
+			CreateBranch("B").
+			CommitChange("b1", "Wire up widget").
+			CommitChange("b2", "Test widget\n\nCovers the edge cases.").
+			TrackBranch("B", "A")
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		// The commits win over the repository's PR template
+		require.NoError(t, os.MkdirAll(filepath.Join(s.Scene.Dir, ".github"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(s.Scene.Dir, ".github", "PULL_REQUEST_TEMPLATE.md"),
+			[]byte("## Summary\n"), 0o600))
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		require.NoError(t, submit.Action(s.Context, submit.Options{Fill: true, Edit: true, Draft: true}))
+
+		require.Equal(t, "Add widget", config.PRs["A"].GetTitle())
+		require.Equal(t, "The widget does things.", config.PRs["A"].GetBody())
+		require.Equal(t, "Wire up widget", config.PRs["B"].GetTitle())
+		require.Equal(t, "- Wire up widget\n- Test widget\n\nWire up widget\n\nTest widget\n\nCovers the edge cases.",
+			config.PRs["B"].GetBody())
+	})
+
+	t.Run("posts the dependency tree as a single comment in comment footer mode", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+				"B": "A",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		opts := submit.Options{NoEdit: true, Draft: true, Stack: true, SubmitFooter: true, FooterMode: actions.FooterModeComment}
+		s.Checkout("A")
+		require.NoError(t, submit.Action(s.Context, opts))
+
+		prB := config.PRs["B"].GetNumber()
+		require.Len(t, config.Comments[config.PRs["A"].GetNumber()], 1)
+		require.Len(t, config.Comments[prB], 1)
+		require.True(t, actions.IsPRFooterComment(config.Comments[prB][0]))
+		require.Contains(t, config.Comments[prB][0], "PR Dependency Tree")
+		require.NotContains(t, config.PRs["B"].GetBody(), "PR Dependency Tree")
+
+		// Growing the stack changes B's tree; re-submitting updates its comment in place
+		s.Checkout("B").
+			CreateBranch("C").
+			CommitChange("c", "change on C").
+			TrackBranch("C", "B").
+			Rebuild().
+			Checkout("A")
+		require.NoError(t, submit.Action(s.Context, opts))
+
+		prC := config.PRs["C"].GetNumber()
+		require.Len(t, config.Comments[prB], 1)
+		require.Contains(t, config.Comments[prB][0], fmt.Sprintf("**PR #%d**", prC))
+		require.Len(t, config.Comments[prC], 1)
+	})
+
+	t.Run("--update-descriptions-only refreshes PR bodies without pushing or retitling", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+				"B": "A",
+			})
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		for number, branch := range map[int]string{101: "A", 102: "B"} {
+			config.PRs[branch] = testhelpers.NewSamplePullRequest(testhelpers.SamplePRData{
+				Number: number, Title: branch + " title", Head: branch, Body: branch + " edited on GitHub", State: "open",
+			})
+			// The stored body is stale; the body on GitHub is the one to keep
+			require.NoError(t, s.Engine.UpsertPrInfo(s.Engine.GetBranch(branch),
+				testhelpers.NewTestPrInfoWithTitle(number, branch+" title").WithBody(branch+" body")))
+		}
+
+		s.Checkout("B")
+		require.NoError(t, submit.Action(s.Context, submit.Options{
+			UpdateDescriptionsOnly: true,
+			SubmitFooter:           true,
+		}))
+
+		for number, branch := range map[int]string{101: "A", 102: "B"} {
+			updated := config.UpdatedPRs[number]
+			require.NotNil(t, updated, "PR #%d should be updated", number)
+			require.Nil(t, updated.Title, "the title of PR #%d should be left alone", number)
+			require.True(t, strings.HasPrefix(updated.GetBody(), branch+" edited on GitHub"))
+			require.Contains(t, updated.GetBody(), "PR Dependency Tree")
+		}
+		require.Empty(t, config.CreatedPRs)
+
+		remoteRefs, err := s.Scene.Repo.RunGitCommandAndGetOutput("ls-remote", "--heads", "origin")
+		require.NoError(t, err)
+		require.Empty(t, remoteRefs, "nothing should be pushed")
+	})
+
+	t.Run("adds no footer when footers are turned off", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+				"B": "A",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("B")
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Draft: true}))
+
+		require.Len(t, config.CreatedPRs, 2)
+		for _, pr := range config.CreatedPRs {
+			require.NotContains(t, pr.GetBody(), "PR Dependency Tree")
+		}
+		for number, pr := range config.UpdatedPRs {
+			require.NotContains(t, pr.GetBody(), "PR Dependency Tree", "PR #%d", number)
+		}
+	})
+
+	t.Run("--strip-footer removes a footer added by an earlier submit", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+				"B": "A",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		for number, branch := range map[int]string{101: "A", 102: "B"} {
+			config.PRs[branch] = testhelpers.NewSamplePullRequest(testhelpers.SamplePRData{
+				Number: number, Title: branch + " title", Head: branch, Body: branch + " body", State: "open",
+			})
+			require.NoError(t, s.Engine.UpsertPrInfo(s.Engine.GetBranch(branch),
+				testhelpers.NewTestPrInfoWithTitle(number, branch+" title").WithBody(branch+" body")))
+		}
+
+		s.Checkout("B")
+		require.NoError(t, submit.Action(s.Context, submit.Options{UpdateDescriptionsOnly: true, SubmitFooter: true}))
+		for _, number := range []int{101, 102} {
+			require.Contains(t, config.UpdatedPRs[number].GetBody(), "PR Dependency Tree")
+		}
+
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, StripFooter: true}))
+
+		for number, branch := range map[int]string{101: "A", 102: "B"} {
+			require.Equal(t, branch+" body", config.UpdatedPRs[number].GetBody())
+			prInfo, err := s.Engine.GetPrInfo(s.Engine.GetBranch(branch))
+			require.NoError(t, err)
+			require.Equal(t, branch+" body", prInfo.Body())
+		}
+	})
+
+	t.Run("--strip-footer deletes the footer comment in comment footer mode", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+				"B": "A",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("A")
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Stack: true, SubmitFooter: true, FooterMode: actions.FooterModeComment}))
+		prA, prB := config.PRs["A"].GetNumber(), config.PRs["B"].GetNumber()
+		require.Len(t, config.Comments[prA], 1)
+		require.Len(t, config.Comments[prB], 1)
+
+		require.NoError(t, submit.Action(s.Context, submit.Options{
+			UpdateDescriptionsOnly: true,
+			Stack:                  true,
+			StripFooter:            true,
+			FooterMode:             actions.FooterModeComment,
+		}))
+
+		require.Empty(t, config.Comments[prA])
+		require.Empty(t, config.Comments[prB])
+	})
+
+	t.Run("enables auto-merge on created PRs and skips PRs already auto-merging", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+				"B": "A",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("A")
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Stack: true, AutoMerge: github.AutoMergeSquash}))
+
+		prA := config.PRs["A"].GetNumber()
+		prB := config.PRs["B"].GetNumber()
+		require.Equal(t, map[int]github.AutoMergeMethod{
+			prA: github.AutoMergeSquash,
+			prB: github.AutoMergeSquash,
+		}, config.AutoMergeMethods)
+
+		// Re-submitting leaves PRs that are already set to auto-merge alone
+		clear(config.AutoMergeMethods)
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Stack: true, Always: true, AutoMerge: github.AutoMergeRebase}))
+		require.Empty(t, config.AutoMergeMethods)
+	})
+
+	t.Run("fails clearly when the repository doesn't allow auto-merge", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		config.AutoMergeNotAllowed = true
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("A")
+		err = submit.Action(s.Context, submit.Options{NoEdit: true, AutoMerge: github.AutoMergeMerge})
+		require.ErrorIs(t, err, github.ErrAutoMergeNotAllowed)
+		require.ErrorContains(t, err, "failed to enable auto-merge for A")
+	})
+
+	t.Run("rejects --auto-merge with --draft", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+			})
+
+		s.Checkout("A")
+		err := submit.Action(s.Context, submit.Options{NoEdit: true, Draft: true, AutoMerge: github.AutoMergeMerge})
+		require.ErrorContains(t, err, "can't use --auto-merge with --draft")
+	})
+
+	t.Run("reuses reviewers and labels from an earlier submit", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("A")
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Reviewers: "alice,org/core", Labels: []string{"backend"}}))
+
+		prInfo, err := s.Engine.GetPrInfo(s.Engine.GetBranch("A"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"alice"}, prInfo.Reviewers())
+		require.Equal(t, []string{"org/core"}, prInfo.TeamReviewers())
+		require.Equal(t, []string{"backend"}, prInfo.Labels())
+
+		// Re-submitting without the flags keeps the stored reviewers without requesting them again
+		prNumber := config.PRs["A"].GetNumber()
+		clear(config.Reviewers)
+		clear(config.TeamReviewers)
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Always: true}))
+		require.Empty(t, config.Reviewers[prNumber])
+		require.Empty(t, config.TeamReviewers[prNumber])
+		prInfo, err = s.Engine.GetPrInfo(s.Engine.GetBranch("A"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"alice"}, prInfo.Reviewers())
+
+		// New reviewers are requested on their own and added to the stored ones
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Always: true, Reviewers: "bob"}))
+		require.Equal(t, []string{"bob"}, config.Reviewers[prNumber])
+		prInfo, err = s.Engine.GetPrInfo(s.Engine.GetBranch("A"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"alice", "bob"}, prInfo.Reviewers())
+		require.Equal(t, []string{"backend"}, prInfo.Labels())
+	})
+
+	t.Run("requests stored reviewers when the PR is created", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		// Reviewers saved by an earlier attempt that never opened the PR
+		branch := s.Engine.GetBranch("A")
+		require.NoError(t, s.Engine.UpsertPrInfo(branch, engine.NewPrInfo(nil, "", "", "", "", "", false).
+			WithReviewersAndLabels([]string{"alice"}, nil, nil)))
+
+		s.Checkout("A")
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true}))
+		require.Equal(t, []string{"alice"}, config.Reviewers[config.PRs["A"].GetNumber()])
+	})
+
+	t.Run("--copy-reviewers-from reuses another branch's reviewers and labels", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+				"B": "main",
+				"C": "main",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("A")
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Reviewers: "alice,org/core", Labels: []string{"backend"}}))
+
+		s.Checkout("B")
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, CopyReviewersFrom: "A", Reviewers: "bob"}))
+		prNumber := config.PRs["B"].GetNumber()
+		require.Equal(t, []string{"alice", "bob"}, config.Reviewers[prNumber])
+		require.Equal(t, []string{"org/core"}, config.TeamReviewers[prNumber])
+		require.Equal(t, []string{"backend"}, config.Labels[prNumber])
+
+		s.Checkout("C")
+		err = submit.Action(s.Context, submit.Options{NoEdit: true, CopyReviewersFrom: "main"})
+		require.ErrorContains(t, err, "no reviewers or labels to copy from main")
+	})
+
+	t.Run("--max-prs stops a submit that would open too many PRs", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+				"B": "A",
+				"C": "B",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("A")
+		err = submit.Action(s.Context, submit.Options{NoEdit: true, Stack: true, MaxPRs: 2})
+		require.ErrorIs(t, err, stackiterrors.ErrValidation)
+		require.ErrorContains(t, err, "submit would open 3 new PRs (A, B, C), more than the limit of 2")
+		require.ErrorContains(t, err, "--force")
+		require.Empty(t, config.CreatedPRs)
+
+		// Once A has a PR, updating it doesn't count against the limit
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, MaxPRs: 1}))
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Stack: true, MaxPRs: 2}))
+		require.Len(t, config.CreatedPRs, 3)
+	})
+
+	t.Run("--force bypasses --max-prs", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+				"B": "A",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("B")
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, MaxPRs: 1, Force: true}))
+		require.Len(t, config.CreatedPRs, 2)
+	})
+
+	t.Run("new PRs default to draft from config and WIP detection", func(t *testing.T) {
+		const wip = `(?i)\bwip\b`
+
+		t.Run("draftDefault opens a draft", func(t *testing.T) {
+			s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+			_, err := s.Scene.Repo.CreateBareRemote("origin")
+			require.NoError(t, err)
+			config := testhelpers.NewMockGitHubServerConfig()
+			rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+			s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+			s.CreateBranch("feature").CommitChange("feature", "add feature").TrackBranch("feature", "main")
+
+			require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, DraftDefault: true}))
+			drafts := map[string]bool{}
+			for _, pr := range config.CreatedPRs {
+				drafts[pr.GetHead().GetRef()] = pr.GetDraft()
+			}
+			require.Equal(t, map[string]bool{"feature": true}, drafts)
+		})
+
+		t.Run("a WIP branch name or commit opens a draft", func(t *testing.T) {
+			s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+			_, err := s.Scene.Repo.CreateBareRemote("origin")
+			require.NoError(t, err)
+			config := testhelpers.NewMockGitHubServerConfig()
+			rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+			s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+			s.CreateBranch("wip-parser").CommitChange("parser", "add parser").TrackBranch("wip-parser", "main").
+				CreateBranch("lexer").CommitChange("lexer", "WIP: lexer").TrackBranch("lexer", "wip-parser").
+				CreateBranch("wipe").CommitChange("wipe", "wipe the cache").TrackBranch("wipe", "lexer")
+
+			require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Stack: true, WIPPattern: wip}))
+			drafts := map[string]bool{}
+			for _, pr := range config.CreatedPRs {
+				drafts[pr.GetHead().GetRef()] = pr.GetDraft()
+			}
+			require.Equal(t, map[string]bool{"wip-parser": true, "lexer": true, "wipe": false}, drafts)
+		})
+
+		t.Run("--publish overrides both", func(t *testing.T) {
+			s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+			_, err := s.Scene.Repo.CreateBareRemote("origin")
+			require.NoError(t, err)
+			config := testhelpers.NewMockGitHubServerConfig()
+			rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+			s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+			s.CreateBranch("wip-feature").CommitChange("feature", "add feature").TrackBranch("wip-feature", "main")
+
+			require.NoError(t, submit.Action(s.Context, submit.Options{
+				NoEdit: true, Publish: true, DraftDefault: true, WIPPattern: wip,
+			}))
+			drafts := map[string]bool{}
+			for _, pr := range config.CreatedPRs {
+				drafts[pr.GetHead().GetRef()] = pr.GetDraft()
+			}
+			require.Equal(t, map[string]bool{"wip-feature": false}, drafts)
+		})
+
+		t.Run("an invalid pattern is rejected", func(t *testing.T) {
+			s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+			_, err := s.Scene.Repo.CreateBareRemote("origin")
+			require.NoError(t, err)
+			config := testhelpers.NewMockGitHubServerConfig()
+			rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+			s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+			s.CreateBranch("feature").CommitChange("feature", "add feature").TrackBranch("feature", "main")
+
+			err = submit.Action(s.Context, submit.Options{NoEdit: true, WIPPattern: "("})
+			require.ErrorIs(t, err, stackiterrors.ErrValidation)
+		})
+	})
+
+	t.Run("fails when branches need restacking in non-interactive mode", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+			})
+
+		s.Checkout("main").
+			CommitChange("main-update", "advance main")
+		s.Checkout("A")
+
+		err := submit.Action(s.Context, submit.Options{NoEdit: true})
+		require.ErrorIs(t, err, stackiterrors.ErrValidation)
+		require.ErrorContains(t, err, "can't submit branches that need restacking (A)")
+		require.ErrorContains(t, err, "--no-restack-check")
+	})
+
+	t.Run("--no-restack-check submits branches that need restacking", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("main").
+			CommitChange("main-update", "advance main")
+		s.Checkout("A")
+		stale, err := s.Engine.GetBranch("A").GetRevision()
+		require.NoError(t, err)
+
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, NoRestackCheck: true}))
+		require.NotNil(t, config.PRs["A"])
+
+		// The branch was submitted as-is rather than restacked
+		rev, err := s.Engine.GetBranch("A").GetRevision()
+		require.NoError(t, err)
+		require.Equal(t, stale, rev)
+	})
+
+	t.Run("--since skips branches that haven't changed", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+				"B": "A",
+				"C": "B",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("C")
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true}))
+		submitted, err := s.Engine.GetBranch("C").GetRevision()
+		require.NoError(t, err)
+
+		// Only the top branch changes after the first submit
+		s.CommitChange("C-followup", "follow up on C")
+		clear(config.UpdatedPRs)
+
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Always: true, Since: submitted}))
+		require.Contains(t, config.UpdatedPRs, config.PRs["C"].GetNumber())
+		require.NotContains(t, config.UpdatedPRs, config.PRs["A"].GetNumber())
+		require.NotContains(t, config.UpdatedPRs, config.PRs["B"].GetNumber())
+	})
+
+	t.Run("rejects an unknown --since ref", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+			})
+
+		s.Checkout("A")
+		err := submit.Action(s.Context, submit.Options{NoEdit: true, Since: "no-such-ref"})
+		require.ErrorIs(t, err, stackiterrors.ErrValidation)
+		require.ErrorContains(t, err, `invalid --since ref "no-such-ref"`)
+	})
+
+	t.Run("rejects --comment-once without --comment", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+			})
+
+		s.Checkout("A")
+		err := submit.Action(s.Context, submit.Options{NoEdit: true, CommentOnce: true})
+		require.ErrorContains(t, err, "--comment-once requires --comment")
+	})
+
+	t.Run("reports draft state changes only for PRs that changed", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"A": "main",
+				"B": "A",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		config := testhelpers.NewMockGitHubServerConfig()
+		rawClient, owner, repo := testhelpers.NewMockGitHubClient(t, config)
+		s.Context.GitHubClient = testhelpers.NewMockGitHubClientInterface(rawClient, owner, repo, config)
+
+		s.Checkout("B")
+		require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Draft: true}))
+
+		// Publishing A reports it
+		s.Checkout("A")
+		output := captureOutput(t, s, func() {
+			require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Publish: true}))
+		})
+		require.Contains(t, output, "A: published")
+
+		// A is already published, so only B's change is reported
+		s.Checkout("B")
+		output = captureOutput(t, s, func() {
+			require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Publish: true, Always: true}))
+		})
+		require.Contains(t, output, "B: published")
+		require.NotContains(t, output, "A: published")
+
+		// Converting the stack back to drafts is reported; doing it once more is quiet
+		output = captureOutput(t, s, func() {
+			require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Draft: true, Always: true}))
+		})
+		require.Contains(t, output, "A: marked draft")
+		require.Contains(t, output, "B: marked draft")
+
+		output = captureOutput(t, s, func() {
+			require.NoError(t, submit.Action(s.Context, submit.Options{NoEdit: true, Draft: true, Always: true}))
+		})
+		require.NotContains(t, output, "marked draft")
+		require.NotContains(t, output, "published")
+	})
+}
+
+// captureOutput runs fn with the scenario logging to a buffer and returns what was logged
+func captureOutput(t *testing.T, s *scenario.Scenario, fn func()) string {
+	t.Helper()
+	var buf bytes.Buffer
+	splog := s.Context.Splog
+	s.Context.Splog = tui.NewSplogWithWriter(&buf)
+	defer func() { s.Context.Splog = splog }()
+
+	fn()
+	return buf.String()
+}
+
+// stubOpenBrowser records the URLs submit asks to open instead of launching a browser
+func stubOpenBrowser(t *testing.T) *[]string {
+	t.Helper()
+	opened := []string{}
+	original := submit.OpenBrowser
+	submit.OpenBrowser = func(url string) error {
+		opened = append(opened, url)
+		return nil
+	}
+	t.Cleanup(func() { submit.OpenBrowser = original })
+	return &opened
 }
@@ -4,11 +4,14 @@ package submit
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/github"
 	"stackit.dev/stackit/internal/runtime"
+	"stackit.dev/stackit/internal/tui"
 	"stackit.dev/stackit/internal/tui/style"
 	"stackit.dev/stackit/internal/utils"
 )
@@ -53,16 +56,41 @@ func ValidateBranchesToSubmit(ctx context.Context, branches []string, eng engine
 	return nil
 }
 
+// checkBranchesNeedRestack looks for branches that are out of date with their parent, which
+// would otherwise be pushed with stale bases. Interactively it asks whether to restack them
+// first and returns the answer; otherwise it fails unless the check is bypassed.
+func checkBranchesNeedRestack(branches []string, eng engine.BranchReader, ui tui.SubmitUI) (bool, error) {
+	needsRestack := []string{}
+	for _, branchName := range branches {
+		if !eng.GetBranch(branchName).IsBranchUpToDate() {
+			needsRestack = append(needsRestack, branchName)
+		}
+	}
+	if len(needsRestack) == 0 {
+		return false, nil
+	}
+
+	if !utils.IsInteractive() {
+		return false, stackiterrors.NewValidationError("can't submit branches that need restacking (%s); run 'stackit restack' or pass --restack, or pass --no-restack-check to submit anyway",
+			strings.Join(needsRestack, ", "))
+	}
+
+	ui.Pause()
+	defer ui.Resume()
+	return tui.PromptConfirm(fmt.Sprintf("Some branches need restacking (%s). Restack before submitting?", strings.Join(needsRestack, ", ")), true)
+}
+
 // validateBaseRevisions ensures that for each branch:
 // 1. Its parent is trunk, OR
 // 2. We are submitting its parent before it and it does not need restacking, OR
 // 3. Its base matches the existing head for its parent's PR
 func validateBaseRevisions(branches []string, eng engine.Engine, runtimeCtx *runtime.Context) error {
 	validatedBranches := make(map[string]bool)
+	parents := eng.BatchGetParents(branches)
 
 	for _, branchName := range branches {
 		branch := eng.GetBranch(branchName)
-		parentBranchName := branch.GetParentPrecondition()
+		parentBranchName := parents[branchName]
 
 		parentBranch := eng.GetBranch(parentBranchName)
 		switch {
@@ -0,0 +1,62 @@
+package submit
+
+import (
+	"context"
+	"fmt"
+
+	"stackit.dev/stackit/internal/engine"
+	"stackit.dev/stackit/internal/git"
+	"stackit.dev/stackit/internal/github"
+	"stackit.dev/stackit/internal/tui"
+	"stackit.dev/stackit/internal/utils"
+)
+
+// OpenBrowser opens a URL in the user's browser for --view and --web.
+// Tests replace it to capture the requested URLs.
+var OpenBrowser = utils.OpenBrowser
+
+// webURLs returns the pages to open for --web, in stack order. With --stack or
+// --stack-from-trunk every pushed branch is opened; otherwise only the current branch.
+// Created PRs open the PR itself, and branches whose PR could not be created open the
+// compare page so the PR can be finished on the web.
+func webURLs(ctx context.Context, infos []Info, prURLs map[string]string, currentBranch string, opts Options, eng engine.Engine, remote, repoOwner, repoName string) []string {
+	urls := []string{}
+	for _, info := range infos {
+		if !opts.Stack && !opts.StackFromTrunk && info.BranchName != currentBranch {
+			continue
+		}
+		if prURL := prURLs[info.BranchName]; prURL != "" {
+			urls = append(urls, prURL)
+			continue
+		}
+		if info.Action == "create" {
+			urls = append(urls, compareURL(ctx, remote, repoOwner, repoName, info.Base, info.Head))
+			continue
+		}
+		if prInfo, err := eng.GetPrInfo(eng.GetBranch(info.BranchName)); err == nil && prInfo != nil && prInfo.URL() != "" {
+			urls = append(urls, prInfo.URL())
+		}
+	}
+	return urls
+}
+
+// compareURL builds the GitHub compare page for base...head on the remote's host
+func compareURL(ctx context.Context, remote, repoOwner, repoName, base, head string) string {
+	hostname := "github.com"
+	if remoteURL, err := git.RunGitCommandWithContext(ctx, "config", "--get", "remote."+remote+".url"); err == nil {
+		if repoInfo, err := github.ParseGitHubRemoteURL(remoteURL); err == nil && repoInfo.Hostname != "" {
+			hostname = repoInfo.Hostname
+		}
+	}
+	return fmt.Sprintf("https://%s/%s/%s/compare/%s...%s?expand=1",
+		hostname, repoOwner, repoName, base, head)
+}
+
+// openInBrowser opens each URL in turn, logging (but not failing on) browser errors
+func openInBrowser(urls []string, splog *tui.Splog) {
+	for _, u := range urls {
+		if err := OpenBrowser(u); err != nil {
+			splog.Debug("Failed to open browser: %v", err)
+		}
+	}
+}
@@ -5,9 +5,10 @@ import (
 	"stackit.dev/stackit/internal/runtime"
 )
 
-// cleanBranches handles cleaning merged/closed branches
-func cleanBranches(ctx *runtime.Context, opts *Options) (*actions.CleanBranchesResult, error) {
+// cleanBranches handles cleaning merged/closed branches, leaving the branches in keep alone
+func cleanBranches(ctx *runtime.Context, opts *Options, keep []string) (*actions.CleanBranchesResult, error) {
 	return actions.CleanBranches(ctx, actions.CleanBranchesOptions{
 		Force: opts.Force,
+		Keep:  keep,
 	})
 }
@@ -2,6 +2,7 @@ package sync
 
 import (
 	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/config"
 	"stackit.dev/stackit/internal/engine"
 	"stackit.dev/stackit/internal/github"
 	"stackit.dev/stackit/internal/runtime"
@@ -42,7 +43,14 @@ func syncGitHubInfo(ctx *runtime.Context, branchesToRestack *[]string) error {
 
 		// Update PR body footers if needed
 		if ctx.GitHubClient != nil {
-			actions.UpdateStackPRMetadata(gctx, branchNames, eng, ctx.GitHubClient, repoOwner, repoName)
+			opts := actions.PRMetadataOptions{FooterMode: actions.FooterModeBody}
+			if cfg, err := config.LoadConfig(ctx.RepoRoot); err == nil {
+				opts.FooterMode = actions.FooterMode(cfg.SubmitFooterMode())
+				opts.Concurrency = cfg.SubmitConcurrency()
+			}
+			if _, err := actions.UpdateStackPRMetadata(gctx, branchNames, eng, ctx.GitHubClient, repoOwner, repoName, opts); err != nil {
+				splog.Debug("Failed to update PR footers: %v", err)
+			}
 		}
 	}
 
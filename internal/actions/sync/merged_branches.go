@@ -0,0 +1,106 @@
+package sync
+
+import (
+	"fmt"
+	"strings"
+
+	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/runtime"
+	"stackit.dev/stackit/internal/tui"
+	"stackit.dev/stackit/internal/tui/style"
+	"stackit.dev/stackit/internal/utils"
+)
+
+// pruneMergedResult contains the result of pruning merged branches
+type pruneMergedResult struct {
+	// BranchesWithNewParents are children of merged branches that were reparented
+	BranchesWithNewParents []string
+	// Kept are merged branches that were not deleted (--no-prune or declined)
+	Kept []string
+}
+
+// pruneMergedBranches deletes tracked branches whose PRs have been merged.
+// Children of a merged branch are first moved onto the nearest ancestor that isn't
+// merged (trunk if the whole downstack is merged), mirroring restack's reparent logic.
+// The user is asked to confirm unless --force is set or the session is non-interactive.
+func pruneMergedBranches(ctx *runtime.Context, opts *Options) (*pruneMergedResult, error) {
+	eng := ctx.Engine
+	splog := ctx.Splog
+	gctx := ctx.Context
+
+	merged := []string{}
+	mergedSet := make(map[string]bool)
+	for _, branch := range eng.AllBranches() {
+		if branch.IsTrunk() {
+			continue
+		}
+		status, err := eng.GetDeletionStatus(gctx, branch.GetName())
+		if err != nil || !status.SafeToDelete || !status.Merged {
+			continue
+		}
+		splog.Debug("Found merged branch: %s", status.Reason)
+		merged = append(merged, branch.GetName())
+		mergedSet[branch.GetName()] = true
+	}
+
+	result := &pruneMergedResult{}
+	if len(merged) == 0 {
+		return result, nil
+	}
+
+	if opts.NoPrune {
+		splog.Info("Skipping %d merged %s (--no-prune).", len(merged), "branch"+actions.PluralSuffix(len(merged) != 1))
+		result.Kept = merged
+		return result, nil
+	}
+
+	if !opts.Force && utils.IsInteractive() {
+		confirmed, err := tui.PromptConfirm(fmt.Sprintf("Delete merged %s %s?", "branch"+actions.PluralSuffix(len(merged) != 1), strings.Join(merged, ", ")), true)
+		if err != nil {
+			return nil, err
+		}
+		if !confirmed {
+			splog.Info("Keeping merged branches.")
+			result.Kept = merged
+			return result, nil
+		}
+	}
+
+	trunkName := eng.Trunk().GetName()
+	for _, branchName := range merged {
+		branch := eng.GetBranch(branchName)
+
+		// Find nearest ancestor that isn't being pruned
+		newParentName := trunkName
+		for ancestor := eng.GetParent(branch); ancestor != nil && !ancestor.IsTrunk(); ancestor = eng.GetParent(*ancestor) {
+			if !mergedSet[ancestor.GetName()] {
+				newParentName = ancestor.GetName()
+				break
+			}
+		}
+
+		for _, child := range branch.GetChildren() {
+			if mergedSet[child.GetName()] {
+				continue
+			}
+			if err := eng.SetParent(gctx, child, eng.GetBranch(newParentName)); err != nil {
+				return nil, fmt.Errorf("failed to set parent for %s: %w", child.GetName(), err)
+			}
+			splog.Info("Set parent of %s to %s.",
+				style.ColorBranchName(child.GetName(), false),
+				style.ColorBranchName(newParentName, false))
+			result.BranchesWithNewParents = append(result.BranchesWithNewParents, child.GetName())
+		}
+	}
+
+	for _, branchName := range merged {
+		if err := eng.DeleteBranch(gctx, eng.GetBranch(branchName)); err != nil {
+			splog.Debug("Failed to delete %s: %v", branchName, err)
+			result.Kept = append(result.Kept, branchName)
+			continue
+		}
+		splog.Info("Deleted merged branch %s", style.ColorBranchName(branchName, false))
+	}
+
+	return result, nil
+}
@@ -3,6 +3,8 @@ package sync
 import (
 	"fmt"
 
+	"stackit.dev/stackit/internal/actions"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/utils"
 )
@@ -12,6 +14,14 @@ type Options struct {
 	All     bool
 	Force   bool
 	Restack bool
+	NoPrune bool // Keep branches whose PRs have been merged instead of deleting them
+	// UpdateRefs moves branches whose commits were rewritten in a child's history onto
+	// the rewritten commits, like git's rebase.updateRefs
+	UpdateRefs bool
+	// PullOnly updates trunk from the remote and stops, leaving branches and PR info alone
+	PullOnly bool
+	// RestackOnly restacks against the local trunk without fetching or talking to GitHub
+	RestackOnly bool
 }
 
 // Action performs the sync operation
@@ -27,32 +37,64 @@ func Action(ctx *runtime.Context, opts Options) error {
 		splog.Info("Syncing branches across all configured trunks...")
 	}
 
+	if opts.PullOnly && opts.RestackOnly {
+		return stackiterrors.NewValidationError("can't use both --pull-only and --restack-only")
+	}
+
 	// Check for uncommitted changes
 	if utils.HasUncommittedChanges(gctx) {
 		return fmt.Errorf("you have uncommitted changes. Please commit or stash them before syncing")
 	}
 
+	if opts.PullOnly {
+		return syncTrunk(ctx, &opts)
+	}
+
+	branchesToRestack := []string{}
+
+	// Follow rewritten commits before anything else moves the stacks
+	if opts.UpdateRefs {
+		moved, err := followRewrittenBranches(ctx)
+		if err != nil {
+			return err
+		}
+		for _, branchName := range moved {
+			for _, b := range eng.GetRelativeStackUpstack(eng.GetBranch(branchName)) {
+				branchesToRestack = append(branchesToRestack, b.GetName())
+			}
+		}
+	}
+
+	// Restacking alone works from what's already local
+	if opts.RestackOnly {
+		return restackBranches(ctx, branchesToRestack)
+	}
+
 	// Pull trunk
 	if err := syncTrunk(ctx, &opts); err != nil {
 		return err
 	}
 
-	// Clean branches (delete merged/closed)
-	branchesToRestack := []string{}
-
 	// Sync PR info
 	if err := syncGitHubInfo(ctx, &branchesToRestack); err != nil {
 		return err
 	}
 
-	// Clean branches (delete merged/closed)
-	cleanResult, err := cleanBranches(ctx, &opts)
+	// Prune merged branches, reparenting their children
+	pruneResult, err := pruneMergedBranches(ctx, &opts)
+	if err != nil {
+		return fmt.Errorf("failed to prune merged branches: %w", err)
+	}
+
+	// Clean branches (delete closed/empty); merged branches were handled above
+	cleanResult, err := cleanBranches(ctx, &opts, pruneResult.Kept)
 	if err != nil {
 		return fmt.Errorf("failed to clean branches: %w", err)
 	}
 
 	// Add branches with new parents to restack list
-	for _, branchName := range cleanResult.BranchesWithNewParents {
+	branchesWithNewParents := append(pruneResult.BranchesWithNewParents, cleanResult.BranchesWithNewParents...)
+	for _, branchName := range branchesWithNewParents {
 		branch := eng.GetBranch(branchName)
 		upstack := eng.GetRelativeStackUpstack(branch)
 		for _, b := range upstack {
@@ -61,6 +103,12 @@ func Action(ctx *runtime.Context, opts Options) error {
 		branchesToRestack = append(branchesToRestack, branchName)
 	}
 
+	// Report branches that differ from their remote counterparts
+	status := &actions.SyncStatus{StaleBranches: actions.GetStaleBranches(eng, splog)}
+	if summary := status.Summary(); summary != "" {
+		splog.Info("%s.", summary)
+	}
+
 	// Restack if requested
 	if !opts.Restack {
 		splog.Tip("Try the --restack flag to automatically restack the current stack.")
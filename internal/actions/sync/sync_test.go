@@ -1,10 +1,13 @@
 package sync
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"stackit.dev/stackit/internal/config"
+	"stackit.dev/stackit/internal/tui"
 	"stackit.dev/stackit/testhelpers"
 	"stackit.dev/stackit/testhelpers/scenario"
 )
@@ -21,6 +24,28 @@ func TestSyncAction(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("reports branches that differ from their remote", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "main",
+			})
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		for _, branch := range []string{"main", "branch1", "branch2"} {
+			require.NoError(t, s.Scene.Repo.PushBranch("origin", branch))
+		}
+		s.Checkout("branch1").
+			CommitChange("unpushed", "unpushed").
+			Checkout("main").
+			Rebuild()
+
+		var out bytes.Buffer
+		s.Context.Splog = tui.NewSplogWithWriter(&out)
+		require.NoError(t, Action(s.Context, Options{}))
+		require.Contains(t, out.String(), "1 branch ahead of remote.")
+	})
+
 	t.Run("fails when there are uncommitted changes", func(t *testing.T) {
 		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
 			WithUncommittedChange("unstaged")
@@ -171,4 +196,181 @@ func TestSyncAction(t *testing.T) {
 		// C2 should NOT be fixed
 		s.ExpectBranchNotFixed("C2")
 	})
+	t.Run("prunes merged branch and reparents its children to trunk", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"parent": "main",
+				"child":  "parent",
+			})
+
+		err := s.Engine.UpsertPrInfo(s.Engine.GetBranch("parent"), testhelpers.NewTestPrInfoMerged(1, "main"))
+		require.NoError(t, err)
+
+		err = Action(s.Context, Options{Force: true})
+		require.NoError(t, err)
+
+		branches, err := s.Scene.Repo.GetLocalBranches()
+		require.NoError(t, err)
+		require.NotContains(t, branches, "parent", "merged branch should be pruned")
+		require.Contains(t, branches, "child")
+		s.ExpectStackStructure(map[string]string{
+			"child": "main",
+		})
+	})
+
+	t.Run("keeps merged branches with NoPrune", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"parent": "main",
+				"child":  "parent",
+			})
+
+		err := s.Engine.UpsertPrInfo(s.Engine.GetBranch("parent"), testhelpers.NewTestPrInfoMerged(1, "main"))
+		require.NoError(t, err)
+
+		err = Action(s.Context, Options{NoPrune: true})
+		require.NoError(t, err)
+
+		branches, err := s.Scene.Repo.GetLocalBranches()
+		require.NoError(t, err)
+		require.Contains(t, branches, "parent", "merged branch should be kept")
+		s.ExpectStackStructure(map[string]string{
+			"parent": "main",
+			"child":  "parent",
+		})
+	})
+
+	t.Run("pull only updates trunk without restacking", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+			})
+		// origin's main moves on without the local main
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		s.RunGit("push", "origin", "main").
+			RunGit("checkout", "-b", "remote-main", "main").
+			CommitChange("remote", "remote change").
+			RunGit("push", "origin", "remote-main:main").
+			RunGit("checkout", "branch1").
+			RunGit("branch", "-D", "remote-main")
+		remoteRev, err := s.Scene.Repo.RunGitCommandAndGetOutput("rev-parse", "origin/main")
+		require.NoError(t, err)
+
+		require.NoError(t, Action(s.Context, Options{Restack: true, PullOnly: true}))
+
+		mainRev, err := s.Scene.Repo.RunGitCommandAndGetOutput("rev-parse", "main")
+		require.NoError(t, err)
+		require.Equal(t, remoteRev, mainRev)
+		require.False(t, s.Engine.GetBranch("branch1").IsBranchUpToDate(), "branch1 should not be restacked")
+	})
+
+	t.Run("restack only restacks onto local trunk without pulling", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+			})
+		// origin's main moves on, and so does the local main, separately
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		s.RunGit("push", "origin", "main").
+			RunGit("checkout", "-b", "remote-main", "main").
+			CommitChange("remote", "remote change").
+			RunGit("push", "origin", "remote-main:main").
+			RunGit("checkout", "main").
+			RunGit("branch", "-D", "remote-main").
+			CommitChange("local", "local change").
+			Checkout("branch1")
+		localRev, err := s.Scene.Repo.RunGitCommandAndGetOutput("rev-parse", "main")
+		require.NoError(t, err)
+
+		require.NoError(t, Action(s.Context, Options{RestackOnly: true}))
+
+		mainRev, err := s.Scene.Repo.RunGitCommandAndGetOutput("rev-parse", "main")
+		require.NoError(t, err)
+		require.Equal(t, localRev, mainRev, "main should not be pulled")
+		require.True(t, s.Engine.GetBranch("branch1").IsBranchUpToDate(), "branch1 should be restacked onto local main")
+	})
+
+	t.Run("restack only saves continuation state on a conflict", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			CreateBranch("branch1").CommitChange("shared", "branch1 change").TrackBranch("branch1", "main").
+			CreateBranch("branch2").CommitChange("other", "branch2 change").TrackBranch("branch2", "branch1").
+			Checkout("main").CommitChange("shared", "conflicting main change").
+			Checkout("branch2")
+
+		err := Action(s.Context, Options{RestackOnly: true})
+		require.Error(t, err)
+
+		continuation, err := config.GetContinuationState(s.Context.RepoRoot)
+		require.NoError(t, err)
+		require.Equal(t, []string{"branch2"}, continuation.BranchesToRestack)
+	})
+
+	t.Run("rejects pull only with restack only", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+
+		err := Action(s.Context, Options{PullOnly: true, RestackOnly: true})
+		require.ErrorContains(t, err, "can't use both --pull-only and --restack-only")
+	})
+
+	t.Run("update refs follows a stack rewritten from its top branch", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			CreateBranch("A").CommitChange("a1", "a1").CommitChange("a2", "a2").TrackBranch("A", "main").
+			CreateBranch("B").CommitChange("b1", "b1").TrackBranch("B", "A").
+			CreateBranch("C").CommitChange("c1", "c1").TrackBranch("C", "B")
+		a1, err := s.Scene.Repo.GetRevision("A~1")
+		require.NoError(t, err)
+		oldA, err := s.Scene.Repo.GetRevision("A")
+		require.NoError(t, err)
+		oldB, err := s.Scene.Repo.GetRevision("B")
+		require.NoError(t, err)
+		oldC, err := s.Scene.Repo.GetRevision("C")
+		require.NoError(t, err)
+
+		// Trunk moves on, then `git rebase -i main` on C rewords A's first commit,
+		// leaving A and B behind on the old commits
+		s.Checkout("main").CommitChange("trunk", "trunk change").
+			RunGit("checkout", "--detach", "main").
+			RunGit("cherry-pick", a1).
+			RunGit("commit", "--amend", "-m", "a1 reworded").
+			RunGit("cherry-pick", oldA, oldB, oldC).
+			RunGit("checkout", "-B", "C").
+			Rebuild()
+		newC, err := s.Scene.Repo.GetRevision("C")
+		require.NoError(t, err)
+
+		require.NoError(t, Action(s.Context, Options{NoPrune: true, UpdateRefs: true}))
+
+		subjects := func(rng string) string {
+			out, err := s.Scene.Repo.RunGitCommandAndGetOutput("log", "--format=%s", rng)
+			require.NoError(t, err)
+			return out
+		}
+		require.Equal(t, "a2\na1 reworded", subjects("main..A"))
+		require.Equal(t, "b1", subjects("A..B"))
+		require.Equal(t, "c1", subjects("B..C"))
+		newTip, err := s.Scene.Repo.GetRevision("C")
+		require.NoError(t, err)
+		require.Equal(t, newC, newTip, "the rewritten branch itself is left alone")
+		s.ExpectBranchFixed("A").ExpectBranchFixed("B").ExpectBranchFixed("C")
+	})
+
+	t.Run("update refs leaves a parent that was amended itself", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			CreateBranch("A").CommitChange("a1", "a1").TrackBranch("A", "main").
+			CreateBranch("B").CommitChange("b1", "b1").TrackBranch("B", "A")
+
+		// Rewording A without restacking B is an ordinary restack, not a rewrite of B
+		s.CheckoutQuiet("A").RunGit("commit", "--amend", "-m", "a1 reworded").Rebuild()
+		amended, err := s.Scene.Repo.GetRevision("A")
+		require.NoError(t, err)
+
+		require.NoError(t, Action(s.Context, Options{NoPrune: true, UpdateRefs: true}))
+
+		tip, err := s.Scene.Repo.GetRevision("A")
+		require.NoError(t, err)
+		require.Equal(t, amended, tip)
+		s.ExpectBranchNotFixed("B")
+	})
 }
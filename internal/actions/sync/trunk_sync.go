@@ -3,6 +3,7 @@ package sync
 import (
 	"fmt"
 
+	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/engine"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui/style"
@@ -18,26 +19,19 @@ func syncTrunk(ctx *runtime.Context, opts *Options) error {
 
 	// Pull trunk
 	splog.Info("Pulling %s from remote...", style.ColorBranchName(trunkName, false))
-	pullResult, err := eng.PullTrunk(gctx)
+	report, err := eng.PullTrunk(gctx)
 	if err != nil {
 		return fmt.Errorf("failed to pull trunk: %w", err)
 	}
 
-	switch pullResult {
-	case engine.PullDone:
-		trunk := eng.Trunk()
-		rev, _ := trunk.GetRevision()
-		revShort := rev
-		if len(rev) > 7 {
-			revShort = rev[:7]
-		}
-		splog.Info("%s fast-forwarded to %s.",
-			style.ColorBranchName(trunkName, true),
-			style.ColorDim(revShort))
-	case engine.PullUnneeded:
-		splog.Info("%s is up to date.", style.ColorBranchName(trunkName, true))
+	summary := actions.TrunkPullSummary(style.ColorBranchName(trunkName, true), report)
+	switch report.Result {
+	case engine.PullDone, engine.PullUnneeded, engine.PullRebased:
+		splog.Info("%s.", summary)
+	case engine.PullReset:
+		splog.Warn("%s.", summary)
 	case engine.PullConflict:
-		splog.Warn("%s could not be fast-forwarded.", style.ColorBranchName(trunkName, false))
+		splog.Warn("%s.", summary)
 
 		// Prompt to overwrite (or use force flag)
 		shouldReset := opts.Force
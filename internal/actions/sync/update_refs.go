@@ -0,0 +1,41 @@
+package sync
+
+import (
+	"fmt"
+
+	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/runtime"
+	"stackit.dev/stackit/internal/tui/style"
+)
+
+// followRewrittenBranches moves branches whose commits were rewritten in a child's history
+// (e.g. by `git rebase -i` run on the top of a stack) onto the rewritten commits, returning
+// the moved branches so the rest of their stacks can be restacked onto them
+func followRewrittenBranches(ctx *runtime.Context) ([]string, error) {
+	eng := ctx.Engine
+	splog := ctx.Splog
+
+	if err := eng.TakeSnapshot(actions.NewSnapshot("sync", actions.WithArg("--update-refs"))); err != nil {
+		// Log but don't fail - snapshot is best effort
+		splog.Debug("Failed to take snapshot: %v", err)
+	}
+
+	result, err := eng.UpdateRewrittenRefs(ctx.Context)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update rewritten branches: %w", err)
+	}
+
+	moved := make([]string, 0, len(result.Moved))
+	for _, followed := range result.Moved {
+		splog.Info("Moved %s to %s, its rewritten commit in %s.",
+			style.ColorBranchName(followed.Name, false),
+			style.ColorDim(followed.NewRevision[:7]),
+			style.ColorBranchName(followed.Child, false))
+		moved = append(moved, followed.Name)
+	}
+	for _, followed := range result.Unmatched {
+		splog.Warn("Couldn't find the tip of %s in the rewritten history of %s; left it in place. "+
+			"Check the stack before restacking %s.", followed.Name, followed.Child, followed.Child)
+	}
+	return moved, nil
+}
@@ -0,0 +1,109 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+
+	"stackit.dev/stackit/internal/engine"
+	"stackit.dev/stackit/internal/tui"
+)
+
+// StaleBranch is a branch that differs from its remote counterpart
+type StaleBranch struct {
+	BranchName string
+	Ahead      int // Commits on the local branch that aren't on the remote
+	Behind     int // Commits on the remote that aren't on the local branch
+}
+
+// SyncStatus describes how far the repository is from its remote
+type SyncStatus struct {
+	NeedsSync     bool
+	StaleBranches []StaleBranch
+}
+
+// BranchNames returns the names of the stale branches
+func (s *SyncStatus) BranchNames() []string {
+	names := make([]string, len(s.StaleBranches))
+	for i, b := range s.StaleBranches {
+		names[i] = b.BranchName
+	}
+	return names
+}
+
+// Summary describes the stale branches, e.g. "3 branches behind remote, 1 ahead".
+// A branch that has diverged counts as both behind and ahead.
+func (s *SyncStatus) Summary() string {
+	behind, ahead := 0, 0
+	for _, b := range s.StaleBranches {
+		if b.Behind > 0 {
+			behind++
+		}
+		if b.Ahead > 0 {
+			ahead++
+		}
+	}
+
+	var parts []string
+	if behind > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s behind remote", behind, Pluralize("branch", behind)))
+	}
+	if ahead > 0 {
+		if len(parts) == 0 {
+			parts = append(parts, fmt.Sprintf("%d %s ahead of remote", ahead, Pluralize("branch", ahead)))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d ahead", ahead))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// GetStaleBranches compares each tracked branch other than trunk with its remote
+// counterpart, returning the ones that differ with their ahead/behind counts. Remote SHAs
+// come from PopulateRemoteShas when it has run, and the remote tracking branches otherwise.
+func GetStaleBranches(eng engine.Engine, splog *tui.Splog) []StaleBranch {
+	stale := []StaleBranch{}
+	for _, branch := range eng.AllBranches() {
+		if branch.IsTrunk() {
+			continue
+		}
+		branchName := branch.GetName()
+
+		matchesRemote, err := eng.BranchMatchesRemote(branchName)
+		if err != nil {
+			splog.Debug("Failed to check if %s matches remote: %v", branchName, err)
+			continue
+		}
+		if matchesRemote {
+			continue
+		}
+
+		b := StaleBranch{BranchName: branchName}
+		if ahead, behind, err := eng.GetBranchRemoteDivergence(branchName); err != nil {
+			splog.Debug("Failed to count divergence of %s from remote: %v", branchName, err)
+		} else {
+			b.Ahead, b.Behind = ahead, behind
+		}
+		stale = append(stale, b)
+	}
+	return stale
+}
+
+// TrunkPullSummary describes a trunk pull in a sentence without a trailing period, naming
+// trunk and where it ended up
+func TrunkPullSummary(trunk string, r engine.TrunkPullReport) string {
+	switch r.Result {
+	case engine.PullUnneeded:
+		return fmt.Sprintf("%s is already up to date at %s", trunk, shortSHA(r.NewRev))
+	case engine.PullDone:
+		return fmt.Sprintf("%s fast-forwarded %d %s to %s", trunk, r.Behind, Pluralize("commit", r.Behind), shortSHA(r.NewRev))
+	case engine.PullRebased:
+		return fmt.Sprintf("%s had diverged from remote; %d local %s rebased onto %d remote %s, now at %s",
+			trunk, r.Ahead, Pluralize("commit", r.Ahead), r.Behind, Pluralize("commit", r.Behind), shortSHA(r.NewRev))
+	case engine.PullReset:
+		return fmt.Sprintf("%s had diverged from remote and was reset to %s; %d local %s discarded",
+			trunk, shortSHA(r.NewRev), r.Ahead, Pluralize("commit", r.Ahead))
+	default:
+		return fmt.Sprintf("%s has diverged from remote (%d local, %d remote %s) and was left at %s; manual action needed",
+			trunk, r.Ahead, r.Behind, Pluralize("commit", r.Behind), shortSHA(r.OldRev))
+	}
+}
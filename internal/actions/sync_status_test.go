@@ -0,0 +1,72 @@
+package actions_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/engine"
+)
+
+func TestTrunkPullSummary(t *testing.T) {
+	oldRev := "1111111aaaaaaa"
+	newRev := "2222222bbbbbbb"
+
+	tests := []struct {
+		name   string
+		report engine.TrunkPullReport
+		want   string
+	}{
+		{
+			name:   "up to date",
+			report: engine.TrunkPullReport{Result: engine.PullUnneeded, OldRev: newRev, NewRev: newRev},
+			want:   "main is already up to date at 2222222",
+		},
+		{
+			name:   "fast-forwarded",
+			report: engine.TrunkPullReport{Result: engine.PullDone, NewRev: newRev, Behind: 2},
+			want:   "main fast-forwarded 2 commits to 2222222",
+		},
+		{
+			name:   "rebased",
+			report: engine.TrunkPullReport{Result: engine.PullRebased, NewRev: newRev, Ahead: 1, Behind: 3},
+			want:   "main had diverged from remote; 1 local commit rebased onto 3 remote commits, now at 2222222",
+		},
+		{
+			name:   "reset",
+			report: engine.TrunkPullReport{Result: engine.PullReset, NewRev: newRev, Ahead: 2, Behind: 1},
+			want:   "main had diverged from remote and was reset to 2222222; 2 local commits discarded",
+		},
+		{
+			name:   "conflict",
+			report: engine.TrunkPullReport{Result: engine.PullConflict, OldRev: oldRev, Ahead: 1, Behind: 1},
+			want:   "main has diverged from remote (1 local, 1 remote commit) and was left at 1111111; manual action needed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, actions.TrunkPullSummary("main", tt.report))
+		})
+	}
+}
+
+func TestSyncStatusSummary(t *testing.T) {
+	status := &actions.SyncStatus{StaleBranches: []actions.StaleBranch{
+		{BranchName: "a", Behind: 1},
+		{BranchName: "b", Behind: 2, Ahead: 1},
+		{BranchName: "c", Behind: 1},
+	}}
+	require.Equal(t, "3 branches behind remote, 1 ahead", status.Summary())
+
+	status = &actions.SyncStatus{StaleBranches: []actions.StaleBranch{{BranchName: "a", Ahead: 1}}}
+	require.Equal(t, "1 branch ahead of remote", status.Summary())
+}
+
+func TestPluralize(t *testing.T) {
+	require.Equal(t, "branch", actions.Pluralize("branch", 1))
+	require.Equal(t, "branches", actions.Pluralize("branch", 2))
+	require.Equal(t, "commits", actions.Pluralize("commit", 0))
+	require.Equal(t, "children", actions.Pluralize("child", 3))
+}
@@ -2,6 +2,7 @@ package actions
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"stackit.dev/stackit/internal/git"
@@ -16,6 +17,8 @@ type TrackOptions struct {
 	BranchName string
 	Force      bool
 	Parent     string
+	Range      bool // Track every untracked branch between the base and BranchName
+	DryRun     bool // With Range, only print the inferred relationships
 }
 
 // TrackAction performs the track operation
@@ -23,6 +26,15 @@ func TrackAction(ctx *runtime.Context, opts TrackOptions) error {
 	eng := ctx.Engine
 	branchName := opts.BranchName
 
+	if opts.DryRun && !opts.Range {
+		return fmt.Errorf("--dry-run can only be used with --range")
+	}
+
+	// Handle --range flag (track all untracked branches below this one)
+	if opts.Range {
+		return trackRange(ctx, opts)
+	}
+
 	// Handle --parent flag (single branch tracking)
 	if opts.Parent != "" {
 		parent := opts.Parent
@@ -59,7 +71,10 @@ func TrackAction(ctx *runtime.Context, opts TrackOptions) error {
 		}
 
 		// Validate parent is an ancestor (unless force is used)
-		if !opts.Force {
+		trackBranch := eng.TrackBranch
+		if opts.Force {
+			trackBranch = eng.ForceTrackBranch
+		} else {
 			parentRev, err := git.GetRevision(parent)
 			if err != nil {
 				return fmt.Errorf("failed to get parent revision: %w", err)
@@ -78,7 +93,7 @@ func TrackAction(ctx *runtime.Context, opts TrackOptions) error {
 		}
 
 		// Track the branch
-		if err := eng.TrackBranch(ctx.Context, branchName, parent); err != nil {
+		if err := trackBranch(ctx.Context, branchName, parent); err != nil {
 			return fmt.Errorf("failed to track branch: %w", err)
 		}
 
@@ -106,6 +121,153 @@ func TrackAction(ctx *runtime.Context, opts TrackOptions) error {
 	return trackBranchRecursively(ctx, branchName)
 }
 
+// trackRange tracks every untracked branch between the base and opts.BranchName in one go.
+// It walks the branch's commits (newest first) down to the most recent tracked branch tip,
+// trunk, or --parent, and each untracked branch tip it passes becomes the child of the next
+// one found below it.
+func trackRange(ctx *runtime.Context, opts TrackOptions) error {
+	eng := ctx.Engine
+	branchName := opts.BranchName
+	trunk := eng.Trunk().GetName()
+
+	if eng.GetBranch(branchName).IsTracked() {
+		ctx.Splog.Info("%s is already tracked.", style.ColorBranchName(branchName, false))
+		return nil
+	}
+
+	// Index branch tips: tracked tips (and trunk) end the walk, untracked tips are collected
+	trackedTips := make(map[string]string)
+	untrackedTips := make(map[string][]string)
+	for _, branch := range eng.AllBranches() {
+		name := branch.GetName()
+		rev, err := git.GetRevision(name)
+		if err != nil {
+			continue
+		}
+		switch {
+		case name == branchName:
+			continue
+		case branch.IsTrunk() || branch.IsTracked():
+			if opts.Parent == "" {
+				trackedTips[rev] = name
+			}
+		default:
+			untrackedTips[rev] = append(untrackedTips[rev], name)
+		}
+	}
+
+	base := opts.Parent
+	var floor string
+	if base != "" {
+		parentBranch := eng.GetBranch(base)
+		if !parentBranch.IsTrunk() && !parentBranch.IsTracked() {
+			return fmt.Errorf("parent branch %s must be tracked (or be trunk)", base)
+		}
+		parentRev, err := git.GetRevision(base)
+		if err != nil {
+			return fmt.Errorf("parent branch %s does not exist", base)
+		}
+		isAnc, err := git.IsAncestor(parentRev, branchName)
+		if err != nil {
+			return fmt.Errorf("failed to check ancestry: %w", err)
+		}
+		if !isAnc {
+			return fmt.Errorf("parent branch %s is not an ancestor of %s", base, branchName)
+		}
+		floor = parentRev
+	} else {
+		mergeBase, err := git.GetMergeBase(branchName, trunk)
+		if err != nil {
+			return fmt.Errorf("failed to find merge base with %s: %w", trunk, err)
+		}
+		floor = mergeBase
+	}
+
+	history, err := git.GetCommitRangeSHAs(floor, branchName)
+	if err != nil {
+		return fmt.Errorf("failed to read history of %s: %w", branchName, err)
+	}
+
+	// Collect the chain from the top down, stopping at the first tracked tip
+	chain := []string{branchName}
+	for i, sha := range history {
+		if name, ok := trackedTips[sha]; ok {
+			base = name
+			break
+		}
+		if i == 0 {
+			// The branch's own tip; any other branch here is a duplicate, not a parent
+			continue
+		}
+		names := untrackedTips[sha]
+		if len(names) > 1 {
+			return fmt.Errorf("branches %s point at the same commit; track them individually with --parent", strings.Join(names, ", "))
+		}
+		if len(names) == 1 {
+			chain = append(chain, names[0])
+		}
+	}
+	if base == "" {
+		base = trunk
+	}
+
+	// Track from the bottom of the chain up
+	parent := base
+	for i := len(chain) - 1; i >= 0; i-- {
+		name := chain[i]
+		if opts.DryRun {
+			ctx.Splog.Info("Would track %s with parent %s.", style.ColorBranchName(name, false), style.ColorBranchName(parent, false))
+		} else {
+			if err := eng.TrackBranch(ctx.Context, name, parent); err != nil {
+				return fmt.Errorf("failed to track %s: %w", name, err)
+			}
+			ctx.Splog.Info("Tracked %s with parent %s.", style.ColorBranchName(name, false), style.ColorBranchName(parent, false))
+		}
+		parent = name
+	}
+
+	return nil
+}
+
+// UntrackedBranches returns the local branches that aren't tracked and haven't been merged
+// into trunk, sorted by name
+func UntrackedBranches(ctx *runtime.Context) []string {
+	eng := ctx.Engine
+	trunk := eng.Trunk().GetName()
+
+	var names []string
+	for _, branch := range eng.AllBranches() {
+		if branch.IsTrunk() || branch.IsTracked() {
+			continue
+		}
+		if merged, err := git.IsAncestor(branch.GetName(), trunk); err == nil && merged {
+			continue
+		}
+		names = append(names, branch.GetName())
+	}
+	slices.Sort(names)
+	return names
+}
+
+// TrackExistingBranches tracks every untracked branch as --range would, inferring parents
+// from the branches in its history. Branches whose parent can't be inferred are skipped
+// with a warning. It returns the number of branches tracked.
+func TrackExistingBranches(ctx *runtime.Context) int {
+	eng := ctx.Engine
+
+	untracked := UntrackedBranches(ctx)
+	for _, name := range untracked {
+		// An earlier branch's range may have tracked this one already
+		if eng.GetBranch(name).IsTracked() {
+			continue
+		}
+		if err := trackRange(ctx, TrackOptions{BranchName: name, Range: true}); err != nil {
+			ctx.Splog.Warn("Skipped %s: %v", style.ColorBranchName(name, false), err)
+		}
+	}
+	return len(untracked) - len(UntrackedBranches(ctx))
+}
+
 // trackBranchRecursively interactively tracks a branch and its descendants
 func trackBranchRecursively(ctx *runtime.Context, branchName string) error {
 	eng := ctx.Engine
@@ -23,6 +23,8 @@ This command cancels any in-progress operation (such as restack, sync, or merge)
 that has been paused due to a rebase conflict. Any changes made during the
 operation will be rolled back.`,
 		SilenceUsage: true,
+		// abort cleans up after a stopped operation, so it must run while git is mid-operation
+		Annotations: map[string]string{common.AllowGitOperationAnnotation: ""},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return common.Run(cmd, func(ctx *runtime.Context) error {
 				return actions.AbortAction(ctx, actions.AbortOptions{
@@ -43,8 +43,8 @@ to manage stacked branches, create commits, submit PRs, and more.`,
 		SilenceUsage: true,
 		RunE: func(_ *cobra.Command, _ []string) error {
 			// Get repo root
-			if err := git.InitDefaultRepo(); err != nil {
-				return fmt.Errorf("not a git repository: %w", err)
+			if err := git.EnsureRepository(); err != nil {
+				return err
 			}
 
 			repoRoot, err := git.GetRepoRoot()
@@ -5,16 +5,20 @@ import (
 	"github.com/spf13/cobra"
 
 	"stackit.dev/stackit/internal/actions/absorb"
+	"stackit.dev/stackit/internal/config"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/runtime"
+	"stackit.dev/stackit/internal/utils"
 )
 
 // NewAbsorbCmd creates the absorb command
 func NewAbsorbCmd() *cobra.Command {
 	var (
-		all    bool
-		dryRun bool
-		force  bool
-		patch  bool
+		all         bool
+		dryRun      bool
+		force       bool
+		patch       bool
+		interactive bool
 	)
 
 	cmd := &cobra.Command{
@@ -24,9 +28,14 @@ func NewAbsorbCmd() *cobra.Command {
 
 Relevance is calculated by checking the changes in each commit downstack from the current commit,
 and finding the first commit that each staged hunk (consecutive lines of changes) can be applied to deterministically.
-If there is no clear commit to absorb a hunk into, it will not be absorbed.
+A hunk that doesn't touch any commit's lines goes to the only commit that changed its file, if there is one.
+If there is no clear commit to absorb a hunk into, such as for a new file, it is listed and left staged;
+set absorb.newFileMode to "first" to absorb those hunks into the newest commit instead.
 
-Prompts for confirmation before amending the commits, and restacks the branches upstack of the current branch.`,
+Prompts for confirmation before amending the commits, and restacks the branches upstack of the current branch.
+
+With --interactive, each hunk is shown with its target commit and can be absorbed, skipped, or
+absorbed into a different downstack branch instead. Skipped hunks remain staged.`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			// Get context (demo or real)
@@ -35,12 +44,20 @@ Prompts for confirmation before amending the commits, and restacks the branches
 				return err
 			}
 
+			if interactive && !utils.IsInteractive() {
+				return stackiterrors.NewValidationError("--interactive requires an interactive terminal")
+			}
+
+			cfg, _ := config.LoadConfig(ctx.RepoRoot)
+
 			// Run absorb action
 			return absorb.Action(ctx, absorb.Options{
-				All:    all,
-				DryRun: dryRun,
-				Force:  force,
-				Patch:  patch,
+				All:                all,
+				DryRun:             dryRun,
+				Force:              force,
+				Patch:              patch,
+				Interactive:        interactive,
+				UnmatchedIntoFirst: cfg.AbsorbNewFileMode() == "first",
 			})
 		},
 	}
@@ -49,6 +66,7 @@ Prompts for confirmation before amending the commits, and restacks the branches
 	cmd.Flags().BoolVarP(&dryRun, "dry-run", "d", false, "Print which commits the hunks would be absorbed into, but do not actually absorb them.")
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Do not prompt for confirmation; apply the hunks to the commits immediately.")
 	cmd.Flags().BoolVarP(&patch, "patch", "p", false, "Pick hunks to stage before absorbing.")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Confirm each hunk and its target commit, optionally skipping it or choosing a different branch.")
 
 	return cmd
 }
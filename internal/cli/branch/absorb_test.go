@@ -404,15 +404,57 @@ func TestAbsorbCommand(t *testing.T) {
 		cmd.Dir = scene.Dir
 		output, err := cmd.CombinedOutput()
 
-		// Note: According to git-absorb behavior, new files that don't conflict with any commit
-		// can still be absorbed into the first commit. The key is whether they commute.
-		// For this test, we're checking that the command succeeds.
-		// If the file is absorbed, that's actually valid behavior if it doesn't conflict.
-		// Let's verify the command succeeds and check the final state
+		// A new file matches no commit, so it's reported and left staged rather than
+		// dumped into the first commit
 		require.NoError(t, err, "absorb should succeed: %s", string(output))
+		require.Contains(t, string(output), "could not be absorbed")
+		require.Contains(t, string(output), "newfile")
 
-		// The file might be absorbed or not - both are valid
-		// Just verify the command completed successfully
+		cmd = exec.Command("git", "show", "--name-only", "--format=", "feature")
+		cmd.Dir = scene.Dir
+		files, err := cmd.Output()
+		require.NoError(t, err)
+		require.NotContains(t, string(files), "newfile")
+	})
+
+	t.Run("absorb new file into first commit with absorb.newFileMode first", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, func(s *testhelpers.Scene) error {
+			if err := s.Repo.CreateChangeAndCommit("initial", "init"); err != nil {
+				return err
+			}
+			cmd := exec.Command(binaryPath, "init")
+			cmd.Dir = s.Dir
+			if err := cmd.Run(); err != nil {
+				return err
+			}
+			cmd = exec.Command(binaryPath, "config", "set", "absorb.newFileMode", "first")
+			cmd.Dir = s.Dir
+			if err := cmd.Run(); err != nil {
+				return err
+			}
+			if err := s.Repo.CreateChange("feature change 1", "test1", false); err != nil {
+				return err
+			}
+			cmd = exec.Command(binaryPath, "create", "feature", "-m", "feature change 1")
+			cmd.Dir = s.Dir
+			if err := cmd.Run(); err != nil {
+				return err
+			}
+			return s.Repo.CreateChange("new file change", "newfile", false)
+		})
+
+		cmd := exec.Command(binaryPath, "absorb", "--force")
+		cmd.Dir = scene.Dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "absorb should succeed: %s", string(output))
+		require.NotContains(t, string(output), "could not be absorbed")
+
+		cmd = exec.Command("git", "show", "--name-only", "--format=", "feature")
+		cmd.Dir = scene.Dir
+		files, err := cmd.Output()
+		require.NoError(t, err)
+		require.Contains(t, string(files), "newfile")
 	})
 
 	t.Run("absorb error - detached HEAD", func(t *testing.T) {
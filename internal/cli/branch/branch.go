@@ -0,0 +1,42 @@
+package branch
+
+import (
+	"github.com/spf13/cobra"
+
+	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/cli/common"
+	"stackit.dev/stackit/internal/runtime"
+)
+
+// NewBranchCmd creates the branch command
+func NewBranchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "branch",
+		Short: "Work with individual branches",
+	}
+
+	cmd.AddCommand(newCopyCmd())
+
+	return cmd
+}
+
+// newCopyCmd creates the branch copy command
+func newCopyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "copy <name>",
+		Short: "Create a branch at the current tip with the current branch's parent and scope",
+		Long: `Create a new branch at the current branch's tip and check it out, tracked on the same
+parent with the same scope, e.g. to try an alternative approach next to the original.
+
+The pull request association isn't copied, so submitting the copy opens a new PR.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return common.RunLocked(cmd, func(ctx *runtime.Context) error {
+				return actions.CopyAction(ctx, actions.CopyOptions{NewName: args[0]})
+			})
+		},
+	}
+
+	return cmd
+}
@@ -4,6 +4,7 @@ package branch
 import (
 	"github.com/spf13/cobra"
 
+	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/actions/create"
 	"stackit.dev/stackit/internal/cli/common"
 	"stackit.dev/stackit/internal/config"
@@ -13,13 +14,15 @@ import (
 // NewCreateCmd creates the create command
 func NewCreateCmd() *cobra.Command {
 	var (
-		all     bool
-		insert  bool
-		message string
-		patch   bool
-		scope   string
-		update  bool
-		verbose int
+		all      bool
+		insert   bool
+		before   bool
+		message  string
+		patch    bool
+		scope    string
+		update   bool
+		verbose  int
+		trailers []string
 	)
 
 	cmd := &cobra.Command{
@@ -32,7 +35,7 @@ If your working directory contains no changes, an empty branch will be created.
 If you have any unstaged changes, you will be asked whether you'd like to stage them.`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return common.Run(cmd, func(ctx *runtime.Context) error {
+			return common.RunLocked(cmd, func(ctx *runtime.Context) error {
 				// Get branch name from args
 				branchName := ""
 				if len(args) > 0 {
@@ -42,18 +45,26 @@ If you have any unstaged changes, you will be asked whether you'd like to stage
 				// Get config values
 				cfg, _ := config.LoadConfig(ctx.RepoRoot)
 				branchPattern := cfg.GetBranchPattern()
+				flagTrailers, err := actions.ParseTrailerFlags(trailers)
+				if err != nil {
+					return err
+				}
 
 				// Prepare options
 				opts := create.Options{
-					BranchName:    branchName,
-					Message:       message,
-					Scope:         scope,
-					All:           all,
-					Insert:        insert,
-					Patch:         patch,
-					Update:        update,
-					Verbose:       verbose,
-					BranchPattern: branchPattern,
+					BranchName:        branchName,
+					Message:           message,
+					Scope:             scope,
+					All:               all,
+					Insert:            insert,
+					Before:            before,
+					Patch:             patch,
+					Update:            update,
+					Verbose:           verbose,
+					BranchPattern:     branchPattern,
+					SuffixOnCollision: cfg.BranchOnCollision() == "suffix",
+					TrailerTemplates:  cfg.CommitTrailers(),
+					Trailers:          flagTrailers,
 				}
 
 				// Execute create action
@@ -65,10 +76,12 @@ If you have any unstaged changes, you will be asked whether you'd like to stage
 	// Add flags
 	cmd.Flags().BoolVarP(&all, "all", "a", false, "Stage all unstaged changes before creating the branch, including to untracked files")
 	cmd.Flags().BoolVarP(&insert, "insert", "i", false, "Insert this branch between the current branch and its child. If there are multiple children, prompts you to select which should be moved onto the new branch")
+	cmd.Flags().BoolVar(&before, "before", false, "Insert this branch between the current branch and its parent, so the current branch is restacked on top of it. Useful for adding a prerequisite")
 	cmd.Flags().StringVarP(&message, "message", "m", "", "Specify a commit message")
 	cmd.Flags().BoolVarP(&patch, "patch", "p", false, "Pick hunks to stage before committing")
 	cmd.Flags().StringVar(&scope, "scope", "", "Set a scope (e.g., Jira ticket ID, Linear ID) for the new branch. If not provided, inherits from parent branch")
 	cmd.Flags().BoolVarP(&update, "update", "u", false, "Stage all updates to tracked files before creating the branch")
+	cmd.Flags().StringArrayVar(&trailers, "trailer", nil, "Add a key=value trailer to the commit message, in addition to the commit.trailers config. Can be repeated")
 	cmd.Flags().CountVarP(&verbose, "verbose", "v", "Show unified diff between the HEAD commit and what would be committed at the bottom of the commit message template. If specified twice, show in addition the unified diff between what would be committed and the worktree files")
 
 	return cmd
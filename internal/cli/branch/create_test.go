@@ -1,12 +1,16 @@
 package branch_test
 
 import (
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
 )
 
 func TestCreateCommand(t *testing.T) {
@@ -786,4 +790,61 @@ func TestCreateCommand(t *testing.T) {
 		require.Contains(t, currentBranch, "PROJ-111")
 		require.Contains(t, currentBranch, "Add-feature")
 	})
+
+	t.Run("create refuses while a rebase is in progress", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, func(s *testhelpers.Scene) error {
+			return s.Repo.CreateChangeAndCommit("initial", "init")
+		})
+
+		cmd := exec.Command(binaryPath, "init")
+		cmd.Dir = scene.Dir
+		_, err := cmd.CombinedOutput()
+		require.NoError(t, err)
+
+		// Leave git looking like it stopped mid-rebase
+		require.NoError(t, os.MkdirAll(filepath.Join(scene.Dir, ".git", "rebase-merge"), 0o755))
+
+		cmd = exec.Command(binaryPath, "create", "feature")
+		cmd.Dir = scene.Dir
+		output, err := cmd.CombinedOutput()
+		require.Error(t, err)
+		require.Contains(t, string(output), "a git operation is in progress; run stackit continue or git rebase --abort")
+
+		currentBranch, err := scene.Repo.CurrentBranchName()
+		require.NoError(t, err)
+		require.Equal(t, "main", currentBranch)
+	})
+
+	t.Run("create adds configured and --trailer trailers to the commit message", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		s.RunCli("init").
+			RunCli("config", "set", "commit.trailers", "Signed-off-by: {name} <{email}>, Change-Id: {changeId}")
+
+		require.NoError(t, s.Scene.Repo.CreateChange("feature change", "feature", false))
+		s.RunCli("create", "feature", "-a", "-m", "Add feature\n\nThe body stays.", "--trailer", "Reviewed-by=Someone Else")
+
+		message, err := s.Scene.Repo.RunGitCommandAndGetOutput("log", "-1", "--format=%B", "feature")
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(message, "Add feature\n\nThe body stays.\n\n"), "message: %s", message)
+		require.Contains(t, message, "Signed-off-by: Test User <test@example.com>")
+		require.Contains(t, message, "Reviewed-by: Someone Else")
+		require.Regexp(t, `Change-Id: I[0-9a-f]{40}`, message)
+	})
+
+	t.Run("create adds --trailer values as given, without expanding placeholders", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		s.RunCli("init").
+			RunCli("config", "set", "commit.trailers", "Branch: {branch}")
+
+		require.NoError(t, s.Scene.Repo.CreateChange("feature change", "feature", false))
+		s.RunCli("create", "feature", "-a", "-m", "Add feature", "--trailer", "Template=uses {branch} and {changeId}")
+
+		message, err := s.Scene.Repo.RunGitCommandAndGetOutput("log", "-1", "--format=%B", "feature")
+		require.NoError(t, err)
+		require.Contains(t, message, "Branch: feature")
+		require.Contains(t, message, "Template: uses {branch} and {changeId}")
+	})
 }
@@ -15,6 +15,7 @@ func NewDeleteCmd() *cobra.Command {
 		downstack bool
 		force     bool
 		upstack   bool
+		stack     bool
 	)
 
 	cmd := &cobra.Command{
@@ -25,6 +26,10 @@ func NewDeleteCmd() *cobra.Command {
 Children will be restacked onto the parent branch. If the branch is not merged
 or closed, prompts for confirmation.
 
+With --stack, the branch and all of its descendants are deleted instead. If the
+current branch is among them, the branch's parent is checked out first. Prompts
+for confirmation unless --force is given.
+
 This command does not perform any action on GitHub or the remote repository.
 If you delete a branch with an open pull request, you will need to manually
 close the pull request.`,
@@ -37,6 +42,12 @@ close the pull request.`,
 				return err
 			}
 
+			unlock, err := common.LockRepo(cmd, ctx)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+
 			branchName := ""
 			if len(args) > 0 {
 				branchName = args[0]
@@ -47,6 +58,7 @@ close the pull request.`,
 				Downstack:  downstack,
 				Force:      force,
 				Upstack:    upstack,
+				Stack:      stack,
 			})
 		},
 	}
@@ -55,6 +67,7 @@ close the pull request.`,
 	cmd.Flags().BoolVar(&downstack, "downstack", false, "Also delete any ancestors of the specified branch.")
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Delete the branch even if it is not merged or closed.")
 	cmd.Flags().BoolVar(&upstack, "upstack", false, "Also delete any children of the specified branch.")
+	cmd.Flags().BoolVar(&stack, "stack", false, "Delete the specified branch and all of its descendants. Can't be combined with --upstack or --downstack.")
 
 	return cmd
 }
@@ -5,6 +5,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/actions/submit"
+	"stackit.dev/stackit/internal/cli/common"
+	"stackit.dev/stackit/internal/config"
 	"stackit.dev/stackit/internal/runtime"
 )
 
@@ -19,54 +22,72 @@ func NewModifyCmd() *cobra.Command {
 		noEdit            bool
 		patch             bool
 		resetAuthor       bool
+		doSubmit          bool
+		trailers          []string
 		update            bool
 		verbose           int
 	)
 
 	cmd := &cobra.Command{
 		Use:     "modify",
-		Aliases: []string{"m"},
+		Aliases: []string{"m", "amend"},
 		Short:   "Modify the current branch by amending its commit or creating a new commit",
 		Long: `Modify the current branch by amending its commit or creating a new commit.
 
-Automatically restacks descendants after the modification.
+Automatically restacks descendants after the modification. With --submit, the stack is
+then pushed and its pull requests are updated.
 
 Examples:
   stackit modify -a -m "Updated feature"  # Stage all and amend with message
   stackit modify -a                       # Stage all and amend (opens editor)
   stackit modify -p                       # Interactive patch staging then amend
   stackit modify -c -a -m "New commit"    # Create new commit instead of amending
+  stackit modify -a -n --submit           # Amend keeping the message, then push the stack
   stackit modify --interactive-rebase     # Interactive rebase on branch commits`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			// Get context (demo or real)
-			ctx, err := runtime.GetContext(cmd.Context())
-			if err != nil {
-				return err
-			}
+			return common.RunLocked(cmd, func(ctx *runtime.Context) error {
+				cfg, _ := config.LoadConfig(ctx.RepoRoot)
+				flagTrailers, err := actions.ParseTrailerFlags(trailers)
+				if err != nil {
+					return err
+				}
 
-			// Determine noEdit flag:
-			// - If --no-edit is explicitly set, use it
-			// - If message is provided, don't open editor (noEdit = true)
-			// - If --edit is set, open editor (noEdit = false)
-			// - Default: open editor when amending without message (noEdit = false)
-			noEditFlag := noEdit
-			if message != "" && !edit {
-				noEditFlag = true
-			}
+				// Determine noEdit flag:
+				// - If --no-edit is explicitly set, use it
+				// - If message is provided, don't open editor (noEdit = true)
+				// - If --edit is set, open editor (noEdit = false)
+				// - Default: open editor when amending without message (noEdit = false)
+				noEditFlag := noEdit
+				if message != "" && !edit {
+					noEditFlag = true
+				}
 
-			// Run modify action
-			return actions.ModifyAction(ctx, actions.ModifyOptions{
-				All:               all,
-				Update:            update,
-				Patch:             patch,
-				CreateCommit:      commit,
-				Message:           message,
-				Edit:              edit,
-				NoEdit:            noEditFlag,
-				ResetAuthor:       resetAuthor,
-				Verbose:           verbose,
-				InteractiveRebase: interactiveRebase,
+				// Run modify action
+				if err := actions.ModifyAction(ctx, actions.ModifyOptions{
+					All:               all,
+					Update:            update,
+					Patch:             patch,
+					CreateCommit:      commit,
+					Message:           message,
+					Edit:              edit,
+					NoEdit:            noEditFlag,
+					ResetAuthor:       resetAuthor,
+					Verbose:           verbose,
+					TrailerTemplates:  cfg.CommitTrailers(),
+					Trailers:          flagTrailers,
+					InteractiveRebase: interactiveRebase,
+				}); err != nil {
+					return err
+				}
+				if !doSubmit {
+					return nil
+				}
+
+				opts := submit.ConfigOptions(cfg)
+				opts.Stack = true
+				opts.NoEdit = true
+				return submit.Action(ctx, opts)
 			})
 		},
 	}
@@ -80,6 +101,8 @@ Examples:
 	cmd.Flags().BoolVarP(&noEdit, "no-edit", "n", false, "Don't modify the existing commit message. Takes precedence over --edit.")
 	cmd.Flags().BoolVarP(&patch, "patch", "p", false, "Pick hunks to stage before committing.")
 	cmd.Flags().BoolVar(&resetAuthor, "reset-author", false, "Set the author of the commit to the current user if amending.")
+	cmd.Flags().BoolVar(&doSubmit, "submit", false, "Push the stack and update its pull requests after restacking.")
+	cmd.Flags().StringArrayVar(&trailers, "trailer", nil, "Add a key=value trailer to the commit message, in addition to the commit.trailers config. Can be repeated.")
 	cmd.Flags().BoolVarP(&update, "update", "u", false, "Stage all updates to tracked files before committing.")
 	cmd.Flags().CountVarP(&verbose, "verbose", "v", "Show unified diff between the HEAD commit and what would be committed at the bottom of the commit message template.")
 
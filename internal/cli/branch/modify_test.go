@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
 )
 
 func TestModifyCommand(t *testing.T) {
@@ -312,4 +313,61 @@ func TestModifyCommand(t *testing.T) {
 		output := testhelpers.Must(cmd.CombinedOutput())
 		require.Contains(t, string(output), "1", "should have one commit")
 	})
+
+	t.Run("amend is an alias of modify", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		require.NoError(t, s.Scene.Repo.CreateChange("parent change", "parent", false))
+		s.RunCli("create", "parent", "-a", "-m", "parent message")
+		require.NoError(t, s.Scene.Repo.CreateChange("child change", "child", false))
+		s.RunCli("create", "child", "-a", "-m", "child message").
+			RunCli("checkout", "parent")
+
+		require.NoError(t, s.Scene.Repo.CreateChange("more parent changes", "parent", false))
+		s.RunCli("amend", "-a", "-n").
+			ExpectBranch("parent")
+
+		message, err := s.Scene.Repo.RunGitCommandAndGetOutput("log", "-1", "--format=%s", "parent")
+		require.NoError(t, err)
+		require.Equal(t, "parent message", message)
+		parentRev, err := s.Scene.Repo.GetRevision("parent")
+		require.NoError(t, err)
+		childParent, err := s.Scene.Repo.GetRevision("child~1")
+		require.NoError(t, err)
+		require.Equal(t, parentRev, childParent, "child should be restacked onto the amended parent")
+	})
+
+	t.Run("modify keeps a configured Change-Id", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		require.NoError(t, s.Scene.Repo.CreateChange("feature change", "feature", false))
+		s.RunCli("create", "feature", "-a", "-m", "feature message").
+			RunCli("config", "set", "commit.trailers", "Change-Id: {changeId}").
+			RunCli("modify", "-m", "feature message")
+
+		first, err := s.Scene.Repo.RunGitCommandAndGetOutput("log", "-1", "--format=%(trailers:key=Change-Id,valueonly)", "feature")
+		require.NoError(t, err)
+		require.Regexp(t, `^I[0-9a-f]{40}$`, first)
+
+		require.NoError(t, s.Scene.Repo.CreateChange("more feature changes", "feature", false))
+		s.RunCli("modify", "-a", "-n", "--trailer", "Reviewed-by=someone")
+		second, err := s.Scene.Repo.RunGitCommandAndGetOutput("log", "-1", "--format=%(trailers:key=Change-Id,valueonly)", "feature")
+		require.NoError(t, err)
+		require.Equal(t, first, second)
+		reviewedBy, err := s.Scene.Repo.RunGitCommandAndGetOutput("log", "-1", "--format=%(trailers:key=Reviewed-by,valueonly)", "feature")
+		require.NoError(t, err)
+		require.Equal(t, "someone", reviewedBy)
+	})
+
+	t.Run("modify adds --trailer values as given, without expanding placeholders", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		require.NoError(t, s.Scene.Repo.CreateChange("feature change", "feature", false))
+		s.RunCli("create", "feature", "-a", "-m", "feature message").
+			RunCli("modify", "-m", "feature message", "--trailer", "See=docs/{name}.md")
+
+		see, err := s.Scene.Repo.RunGitCommandAndGetOutput("log", "-1", "--format=%(trailers:key=See,valueonly)", "feature")
+		require.NoError(t, err)
+		require.Equal(t, "docs/{name}.md", see)
+	})
 }
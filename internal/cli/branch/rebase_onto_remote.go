@@ -0,0 +1,39 @@
+package branch
+
+import (
+	"github.com/spf13/cobra"
+
+	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/cli/common"
+	"stackit.dev/stackit/internal/runtime"
+)
+
+// NewRebaseOntoRemoteCmd creates the rebase-onto-remote command
+func NewRebaseOntoRemoteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rebase-onto-remote [branch]",
+		Short: "Adopt the remote version of a branch and restack its children onto it",
+		Long: `Adopt the remote version of a branch and restack its children onto it.
+
+Use this when a teammate has pushed changes to a branch in your stack. The branch
+(the current branch if none is given) is fetched and reset to its remote tip, and its
+descendants are rebased so only their own commits are replayed on top of it. Local
+commits on the branch that were not pushed are replaced; use 'stackit undo' to recover them.
+
+If conflicts are encountered, resolve them and run 'stackit continue'.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: common.CompleteBranches,
+		SilenceUsage:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return common.RunLocked(cmd, func(ctx *runtime.Context) error {
+				opts := actions.RebaseOntoRemoteOptions{}
+				if len(args) > 0 {
+					opts.BranchName = args[0]
+				}
+				return actions.RebaseOntoRemoteAction(ctx, opts)
+			})
+		},
+	}
+
+	return cmd
+}
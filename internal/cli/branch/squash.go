@@ -22,7 +22,11 @@ func NewSquashCmd() *cobra.Command {
 		Long: `Squash all commits in the current branch into a single commit and restack upstack branches.
 
 This command combines all commits in the current branch into a single commit. After squashing,
-all upstack branches (children) are automatically restacked.`,
+all upstack branches (children) are automatically restacked.
+
+Without --message, the squashed commit gets the first commit's subject followed by the
+bodies of all the branch's commits, opened in your editor unless --no-edit is given. A
+branch with a single commit is left as it is.`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			// Get context (demo or real)
@@ -46,8 +50,8 @@ all upstack branches (children) are automatically restacked.`,
 	}
 
 	cmd.Flags().StringVarP(&message, "message", "m", "", "The updated message for the commit.")
-	cmd.Flags().BoolVar(&edit, "edit", true, "Modify the existing commit message.")
-	cmd.Flags().BoolVarP(&noEdit, "no-edit", "n", false, "Don't modify the existing commit message. Takes precedence over --edit")
+	cmd.Flags().BoolVar(&edit, "edit", true, "Edit the combined commit message.")
+	cmd.Flags().BoolVarP(&noEdit, "no-edit", "n", false, "Don't edit the combined commit message. Takes precedence over --edit")
 
 	return cmd
 }
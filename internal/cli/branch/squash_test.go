@@ -171,6 +171,62 @@ func TestSquashCommand(t *testing.T) {
 		require.Contains(t, string(output), "b change", "branch B should still have its commit")
 	})
 
+	t.Run("squash combines commit messages and restacks children", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, func(s *testhelpers.Scene) error {
+			if err := s.Repo.CreateChangeAndCommit("initial", "init"); err != nil {
+				return err
+			}
+			if err := s.Repo.CreateChange("feature change 1", "test1", false); err != nil {
+				return err
+			}
+			cmd := exec.Command(binaryPath, "create", "feature", "-m", "feature change 1\n\nFirst body.")
+			cmd.Dir = s.Dir
+			if err := cmd.Run(); err != nil {
+				return err
+			}
+			if err := s.Repo.CreateChangeAndCommit("feature change 2", "feature change 2"); err != nil {
+				return err
+			}
+			if err := s.Repo.CreateChange("feature change 3", "test3", false); err != nil {
+				return err
+			}
+			if err := s.Repo.RunGitCommand("add", "-A"); err != nil {
+				return err
+			}
+			if err := s.Repo.RunGitCommand("commit", "-m", "feature change 3", "-m", "Third body."); err != nil {
+				return err
+			}
+			if err := s.Repo.CreateChange("child change", "child", false); err != nil {
+				return err
+			}
+			cmd = exec.Command(binaryPath, "create", "child", "-m", "child change")
+			cmd.Dir = s.Dir
+			if err := cmd.Run(); err != nil {
+				return err
+			}
+			return s.Repo.CheckoutBranch("feature")
+		})
+
+		cmd := exec.Command(binaryPath, "squash", "--no-edit")
+		cmd.Dir = scene.Dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "squash command failed: %s", string(output))
+
+		cmd = exec.Command("git", "log", "--format=%B", "main..feature")
+		cmd.Dir = scene.Dir
+		output = testhelpers.Must(cmd.CombinedOutput())
+		require.Equal(t, "feature change 1\n\nFirst body.\n\nThird body.", strings.TrimSpace(string(output)))
+
+		cmd = exec.Command("git", "log", "--format=%s", "feature..child")
+		cmd.Dir = scene.Dir
+		output = testhelpers.Must(cmd.CombinedOutput())
+		require.Equal(t, "child change", strings.TrimSpace(string(output)))
+		cmd = exec.Command("git", "merge-base", "--is-ancestor", "feature", "child")
+		cmd.Dir = scene.Dir
+		require.NoError(t, cmd.Run(), "child should be restacked onto the squashed feature")
+	})
+
 	t.Run("squash errors on trunk branch", func(t *testing.T) {
 		t.Parallel()
 		scene := testhelpers.NewSceneParallel(t, func(s *testhelpers.Scene) error {
@@ -246,6 +302,7 @@ func TestSquashCommand(t *testing.T) {
 		output, err := cmd.CombinedOutput()
 
 		require.NoError(t, err, "squash should work with single commit: %s", string(output))
+		require.Contains(t, string(output), "nothing to squash")
 
 		// Verify still has one commit
 		cmd = exec.Command("git", "log", "--oneline", "main..feature")
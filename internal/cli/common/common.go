@@ -4,10 +4,51 @@ package common
 import (
 	"github.com/spf13/cobra"
 
+	"stackit.dev/stackit/internal/config"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/git"
 	"stackit.dev/stackit/internal/runtime"
+	"stackit.dev/stackit/internal/utils"
 )
 
+// ForceUnlockFlag is the root persistent flag that clears a lock left behind by another operation
+const ForceUnlockFlag = "force-unlock"
+
+// AllowGitOperationAnnotation marks a command, and its subcommands, that may run while a
+// rebase, merge or cherry-pick is in progress, e.g. one that only reads or that cleans up after it
+const AllowGitOperationAnnotation = "stackit/allow-git-operation"
+
+// CheckNoGitOperationInProgress fails with ErrGitOperationInProgress when git is mid-rebase,
+// mid-merge or mid-cherry-pick, so commands don't build on a half-finished operation. It
+// passes for commands with AllowGitOperationAnnotation, cobra's help and completion commands,
+// a command asked to --abort, and demo mode, which has no repository.
+func CheckNoGitOperationInProgress(cmd *cobra.Command) error {
+	if utils.IsDemoMode() || allowsGitOperation(cmd) {
+		return nil
+	}
+	if abort, _ := cmd.Flags().GetBool("abort"); abort {
+		return nil
+	}
+	if git.IsGitOperationInProgress(cmd.Context()) {
+		return stackiterrors.ErrGitOperationInProgress
+	}
+	return nil
+}
+
+// allowsGitOperation reports whether cmd or one of its parents opts out of the check
+func allowsGitOperation(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if _, allowed := c.Annotations[AllowGitOperationAnnotation]; allowed {
+			return true
+		}
+		switch c.Name() {
+		case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+			return true
+		}
+	}
+	return false
+}
+
 // Run is a helper that provides a runtime context to a command's execution function
 func Run(cmd *cobra.Command, fn func(ctx *runtime.Context) error) error {
 	ctx, err := runtime.GetContext(cmd.Context())
@@ -17,6 +58,44 @@ func Run(cmd *cobra.Command, fn func(ctx *runtime.Context) error) error {
 	return fn(ctx)
 }
 
+// RunLocked is like Run, but holds the repository lock while fn runs so that
+// concurrent mutating commands fail fast instead of interleaving
+func RunLocked(cmd *cobra.Command, fn func(ctx *runtime.Context) error) error {
+	return Run(cmd, func(ctx *runtime.Context) error {
+		unlock, err := LockRepo(cmd, ctx)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+		return fn(ctx)
+	})
+}
+
+// LockRepo takes the repository lock for a mutating command, clearing any existing
+// lock first when --force-unlock is set. The returned function releases the lock.
+func LockRepo(cmd *cobra.Command, ctx *runtime.Context) (func(), error) {
+	// Demo mode has no repository to lock
+	if ctx.RepoRoot == "" {
+		return func() {}, nil
+	}
+
+	if forceUnlock, _ := cmd.Flags().GetBool(ForceUnlockFlag); forceUnlock {
+		if err := config.ForceUnlock(ctx.RepoRoot); err != nil {
+			return nil, err
+		}
+	}
+
+	lock, err := config.AcquireRepoLock(ctx.RepoRoot, cmd.CommandPath(), config.DefaultLockStaleAfter)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		if err := lock.Release(); err != nil {
+			ctx.Splog.Debug("%v", err)
+		}
+	}, nil
+}
+
 // CompleteBranches is a helper for cobra.ValidArgsFunction and RegisterFlagCompletionFunc
 // that returns all branch names in the repository.
 func CompleteBranches(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
@@ -3,11 +3,14 @@ package cli
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/cli/common"
 	"stackit.dev/stackit/internal/config"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/git"
 	"stackit.dev/stackit/internal/tui"
 	configtui "stackit.dev/stackit/internal/tui/config"
@@ -22,6 +25,10 @@ func newConfigCmd() *cobra.Command {
 		Short: "Get and set repository configuration",
 		Long: `Get and set repository configuration values.
 
+Settings are resolved from the repository config, then the global config
+($XDG_CONFIG_HOME/stackit/config.json or ~/.config/stackit/config.json),
+then the built-in default. Use --global with set and unset to change the global config.
+
 When run without subcommands, opens an interactive TUI for editing configuration.
 Use --list to print all configuration values instead.
 
@@ -30,13 +37,39 @@ Examples:
   stackit config --list             # Print all config values
   stackit config get branch.pattern
   stackit config set branch.pattern "{username}/{date}/{message}"
+  stackit config set branch.onCollision suffix
   stackit config get submit.footer
-  stackit config set submit.footer false`,
+  stackit config set submit.footer false
+  stackit config set submit.footerMode comment
+  stackit config set submit.skipHooks true
+  stackit config set submit.maxPrs 10
+  stackit config set submit.concurrency 4
+  stackit config set submit.draftDefault true
+  stackit config set submit.wipPattern '(?i)\bwip\b'
+  stackit config set submit.stackLabel stacked-pr
+  stackit config set push.setUpstream false
+  stackit config set restack.strategy merge
+  stackit config set restack.preserveDates true
+  stackit config set restack.pruneEmpty merged
+  stackit config set restack.postHook "npm install --package-lock-only"
+  stackit config set checkout.autostash true
+  stackit config set commit.trailers "Signed-off-by: {name} <{email}>"
+  stackit config set absorb.newFileMode first
+  stackit config set sync.trunkStrategy rebase
+  stackit config set merge.strategy top-down
+  stackit config set merge.requireCleanStatus false
+  stackit config set git.timeout.local 30s
+  stackit config set git.timeout.network 15m
+  stackit config set --global restack.strategy merge
+  stackit config get --show-source restack.strategy
+  stackit config unset restack.strategy`,
 		SilenceUsage: true,
+		// Configuration doesn't touch the working tree
+		Annotations: map[string]string{common.AllowGitOperationAnnotation: ""},
 		RunE: func(_ *cobra.Command, _ []string) error {
 			// Get repo root
-			if err := git.InitDefaultRepo(); err != nil {
-				return fmt.Errorf("not a git repository: %w", err)
+			if err := git.EnsureRepository(); err != nil {
+				return err
 			}
 
 			repoRoot, err := git.GetRepoRoot()
@@ -58,21 +91,29 @@ Examples:
 
 	cmd.AddCommand(newConfigGetCmd())
 	cmd.AddCommand(newConfigSetCmd())
+	cmd.AddCommand(newConfigUnsetCmd())
 
 	return cmd
 }
 
 // newConfigGetCmd creates the config get command
 func newConfigGetCmd() *cobra.Command {
+	var showSource bool
+
 	cmd := &cobra.Command{
-		Use:          "get <key>",
-		Short:        "Get a configuration value",
+		Use:   "get <key>",
+		Short: "Get a configuration value",
+		Long: `Get the effective value of a configuration key.
+
+The repository config takes precedence over the global config, which takes precedence
+over the built-in default. Use --show-source to also print where the value came from
+(repo, global or default).`,
 		Args:         cobra.ExactArgs(1),
 		SilenceUsage: true,
 		RunE: func(_ *cobra.Command, args []string) error {
 			// Get repo root
-			if err := git.InitDefaultRepo(); err != nil {
-				return fmt.Errorf("not a git repository: %w", err)
+			if err := git.EnsureRepository(); err != nil {
+				return err
 			}
 
 			repoRoot, err := git.GetRepoRoot()
@@ -86,46 +127,94 @@ func newConfigGetCmd() *cobra.Command {
 				return fmt.Errorf("failed to load config: %w", err)
 			}
 
+			var value any
 			switch key {
 			case "branch.pattern":
-				fmt.Println(cfg.BranchNamePattern())
+				value = cfg.BranchNamePattern()
+			case "branch.onCollision":
+				value = cfg.BranchOnCollision()
 			case "submit.footer":
-				fmt.Println(cfg.SubmitFooter())
+				value = cfg.SubmitFooter()
+			case "submit.footerMode":
+				value = cfg.SubmitFooterMode()
+			case "submit.skipHooks":
+				value = cfg.SubmitSkipHooks()
+			case "submit.maxPrs":
+				value = cfg.SubmitMaxPRs()
+			case "submit.concurrency":
+				value = cfg.SubmitConcurrency()
+			case "submit.draftDefault":
+				value = cfg.SubmitDraftDefault()
+			case "submit.wipPattern":
+				value = cfg.SubmitWIPPattern()
+			case "submit.stackLabel":
+				value = cfg.SubmitStackLabel()
+			case "push.setUpstream":
+				value = cfg.PushSetUpstream()
+			case "restack.strategy":
+				value = cfg.RestackStrategy()
+			case "restack.preserveDates":
+				value = cfg.RestackPreserveDates()
+			case "restack.pruneEmpty":
+				value = cfg.RestackPruneEmpty()
+			case "restack.postHook":
+				value = cfg.RestackPostHook()
+			case "checkout.autostash":
+				value = cfg.CheckoutAutostash()
+			case "commit.trailers":
+				value = strings.Join(cfg.CommitTrailers(), ", ")
+			case "absorb.newFileMode":
+				value = cfg.AbsorbNewFileMode()
+			case "sync.trunkStrategy":
+				value = cfg.SyncTrunkStrategy()
+			case "merge.strategy":
+				value = cfg.MergeStrategy()
+			case "merge.requireCleanStatus":
+				value = cfg.MergeRequireCleanStatus()
+			case "git.timeout.local":
+				value = cfg.GitTimeoutLocal()
+			case "git.timeout.network":
+				value = cfg.GitTimeoutNetwork()
 			default:
-				return fmt.Errorf("unknown configuration key: %s", key)
+				return stackiterrors.NewValidationError("unknown configuration key: %s", key)
 			}
 
+			if !showSource {
+				fmt.Println(value)
+				return nil
+			}
+			source, err := cfg.Source(key)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%v\t%s\n", value, source)
 			return nil
 		},
 	}
 
+	cmd.Flags().BoolVar(&showSource, "show-source", false, "Also print where the value came from (repo, shared, global or default)")
+
 	return cmd
 }
 
 // newConfigSetCmd creates the config set command
 func newConfigSetCmd() *cobra.Command {
+	var scope configScopeFlags
+
 	cmd := &cobra.Command{
-		Use:          "set <key> <value>",
-		Short:        "Set a configuration value",
+		Use:   "set <key> <value>",
+		Short: "Set a configuration value",
+		Long: `Set a configuration value in the repository config, or with --global in the
+global config shared by all repositories. Repository values override global ones.`,
 		Args:         cobra.ExactArgs(2),
 		SilenceUsage: true,
 		RunE: func(_ *cobra.Command, args []string) error {
-			// Get repo root
-			if err := git.InitDefaultRepo(); err != nil {
-				return fmt.Errorf("not a git repository: %w", err)
-			}
-
-			repoRoot, err := git.GetRepoRoot()
-			if err != nil {
-				return fmt.Errorf("failed to get repo root: %w", err)
-			}
-
 			key := args[0]
 			value := args[1]
 
-			cfg, err := config.LoadConfig(repoRoot)
+			cfg, err := scope.load()
 			if err != nil {
-				return fmt.Errorf("failed to load config: %w", err)
+				return err
 			}
 
 			splog := tui.NewSplog()
@@ -139,23 +228,295 @@ func newConfigSetCmd() *cobra.Command {
 					return fmt.Errorf("failed to save config: %w", err)
 				}
 				splog.Info("Set branch.pattern to: %s", value)
+			case "branch.onCollision":
+				if err := cfg.SetBranchOnCollision(value); err != nil {
+					return fmt.Errorf("failed to set branch.onCollision: %w", err)
+				}
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set branch.onCollision to: %s", value)
 			case "submit.footer":
 				enabled, err := strconv.ParseBool(value)
 				if err != nil {
-					return fmt.Errorf("invalid value for submit.footer: %s (must be 'true' or 'false')", value)
+					return stackiterrors.NewValidationError("invalid value for submit.footer: %s (must be 'true' or 'false')", value)
 				}
 				cfg.SetSubmitFooter(enabled)
 				if err := cfg.Save(); err != nil {
 					return fmt.Errorf("failed to save config: %w", err)
 				}
 				splog.Info("Set submit.footer to: %v", enabled)
+			case "submit.footerMode":
+				if err := cfg.SetSubmitFooterMode(value); err != nil {
+					return fmt.Errorf("failed to set submit.footerMode: %w", err)
+				}
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set submit.footerMode to: %s", value)
+			case "submit.skipHooks":
+				skip, err := strconv.ParseBool(value)
+				if err != nil {
+					return stackiterrors.NewValidationError("invalid value for submit.skipHooks: %s (must be 'true' or 'false')", value)
+				}
+				cfg.SetSubmitSkipHooks(skip)
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set submit.skipHooks to: %v", skip)
+			case "submit.maxPrs":
+				maxPRs, err := strconv.Atoi(value)
+				if err != nil {
+					return stackiterrors.NewValidationError("invalid value for submit.maxPrs: %s (must be a number)", value)
+				}
+				if err := cfg.SetSubmitMaxPRs(maxPRs); err != nil {
+					return fmt.Errorf("failed to set submit.maxPrs: %w", err)
+				}
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set submit.maxPrs to: %d", maxPRs)
+			case "submit.concurrency":
+				concurrency, err := strconv.Atoi(value)
+				if err != nil {
+					return stackiterrors.NewValidationError("invalid value for submit.concurrency: %s (must be a number)", value)
+				}
+				if err := cfg.SetSubmitConcurrency(concurrency); err != nil {
+					return fmt.Errorf("failed to set submit.concurrency: %w", err)
+				}
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set submit.concurrency to: %d", concurrency)
+			case "submit.draftDefault":
+				draft, err := strconv.ParseBool(value)
+				if err != nil {
+					return stackiterrors.NewValidationError("invalid value for submit.draftDefault: %s (must be 'true' or 'false')", value)
+				}
+				cfg.SetSubmitDraftDefault(draft)
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set submit.draftDefault to: %v", draft)
+			case "submit.wipPattern":
+				if err := cfg.SetSubmitWIPPattern(value); err != nil {
+					return stackiterrors.NewValidationError("%v", err)
+				}
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set submit.wipPattern to: %s", value)
+			case "submit.stackLabel":
+				if err := cfg.SetSubmitStackLabel(value); err != nil {
+					return stackiterrors.NewValidationError("%v", err)
+				}
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set submit.stackLabel to: %s", value)
+			case "push.setUpstream":
+				setUpstream, err := strconv.ParseBool(value)
+				if err != nil {
+					return stackiterrors.NewValidationError("invalid value for push.setUpstream: %s (must be 'true' or 'false')", value)
+				}
+				cfg.SetPushSetUpstream(setUpstream)
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set push.setUpstream to: %v", setUpstream)
+			case "restack.strategy":
+				if err := cfg.SetRestackStrategy(value); err != nil {
+					return fmt.Errorf("failed to set restack.strategy: %w", err)
+				}
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set restack.strategy to: %s", value)
+			case "restack.preserveDates":
+				preserve, err := strconv.ParseBool(value)
+				if err != nil {
+					return stackiterrors.NewValidationError("invalid value for restack.preserveDates: %s (must be 'true' or 'false')", value)
+				}
+				cfg.SetRestackPreserveDates(preserve)
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set restack.preserveDates to: %v", preserve)
+			case "restack.pruneEmpty":
+				if err := cfg.SetRestackPruneEmpty(value); err != nil {
+					return fmt.Errorf("failed to set restack.pruneEmpty: %w", err)
+				}
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set restack.pruneEmpty to: %s", value)
+			case "restack.postHook":
+				cfg.SetRestackPostHook(value)
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set restack.postHook to: %s", value)
+			case "checkout.autostash":
+				autostash, err := strconv.ParseBool(value)
+				if err != nil {
+					return stackiterrors.NewValidationError("invalid value for checkout.autostash: %s (must be 'true' or 'false')", value)
+				}
+				cfg.SetCheckoutAutostash(autostash)
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set checkout.autostash to: %v", autostash)
+			case "commit.trailers":
+				// A comma-separated list; an empty value adds no trailers
+				var trailers []string
+				for _, trailer := range strings.Split(value, ",") {
+					if trailer = strings.TrimSpace(trailer); trailer != "" {
+						trailers = append(trailers, trailer)
+					}
+				}
+				if err := cfg.SetCommitTrailers(trailers); err != nil {
+					return stackiterrors.WithCategory(stackiterrors.ErrValidation, err)
+				}
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set commit.trailers to: %s", strings.Join(trailers, ", "))
+			case "absorb.newFileMode":
+				if err := cfg.SetAbsorbNewFileMode(value); err != nil {
+					return fmt.Errorf("failed to set absorb.newFileMode: %w", err)
+				}
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set absorb.newFileMode to: %s", value)
+			case "sync.trunkStrategy":
+				if err := cfg.SetSyncTrunkStrategy(value); err != nil {
+					return fmt.Errorf("failed to set sync.trunkStrategy: %w", err)
+				}
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set sync.trunkStrategy to: %s", value)
+			case "merge.strategy":
+				if err := cfg.SetMergeStrategy(value); err != nil {
+					return fmt.Errorf("failed to set merge.strategy: %w", err)
+				}
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set merge.strategy to: %s", value)
+			case "merge.requireCleanStatus":
+				requireClean, err := strconv.ParseBool(value)
+				if err != nil {
+					return stackiterrors.NewValidationError("invalid value for merge.requireCleanStatus: %s (must be 'true' or 'false')", value)
+				}
+				cfg.SetMergeRequireCleanStatus(requireClean)
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set merge.requireCleanStatus to: %v", requireClean)
+			case "git.timeout.local":
+				if err := cfg.SetGitTimeoutLocal(value); err != nil {
+					return fmt.Errorf("failed to set git.timeout.local: %w", err)
+				}
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set git.timeout.local to: %s", value)
+			case "git.timeout.network":
+				if err := cfg.SetGitTimeoutNetwork(value); err != nil {
+					return fmt.Errorf("failed to set git.timeout.network: %w", err)
+				}
+				if err := cfg.Save(); err != nil {
+					return fmt.Errorf("failed to save config: %w", err)
+				}
+				splog.Info("Set git.timeout.network to: %s", value)
 			default:
-				return fmt.Errorf("unknown configuration key: %s", key)
+				return stackiterrors.NewValidationError("unknown configuration key: %s", key)
 			}
 
 			return nil
 		},
 	}
 
+	scope.register(cmd)
+
 	return cmd
 }
+
+// newConfigUnsetCmd creates the config unset command
+func newConfigUnsetCmd() *cobra.Command {
+	var scope configScopeFlags
+
+	cmd := &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Remove a configuration value",
+		Long: `Remove a configuration value from the repository config, or with --global from
+the global config, so the next config in precedence order applies.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			key := args[0]
+
+			cfg, err := scope.load()
+			if err != nil {
+				return err
+			}
+
+			if err := cfg.Unset(key); err != nil {
+				return stackiterrors.NewValidationError("%v", err)
+			}
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+			tui.NewSplog().Info("Unset %s", key)
+			return nil
+		},
+	}
+
+	scope.register(cmd)
+
+	return cmd
+}
+
+// configScopeFlags selects which config file set and unset write to
+type configScopeFlags struct {
+	global bool
+	local  bool
+}
+
+func (f *configScopeFlags) register(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&f.global, "global", false, "Use the global config shared by all repositories")
+	cmd.Flags().BoolVar(&f.local, "local", false, "Use the repository config (default)")
+}
+
+// load loads the config for the selected scope
+func (f *configScopeFlags) load() (*config.Config, error) {
+	if f.global && f.local {
+		return nil, stackiterrors.NewValidationError("only one of --global or --local can be specified")
+	}
+
+	if f.global {
+		cfg, err := config.LoadGlobalConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load global config: %w", err)
+		}
+		return cfg, nil
+	}
+
+	// Get repo root
+	if err := git.EnsureRepository(); err != nil {
+		return nil, err
+	}
+
+	repoRoot, err := git.GetRepoRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repo root: %w", err)
+	}
+
+	cfg, err := config.LoadConfig(repoRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return cfg, nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
 )
 
 func TestConfigCommand(t *testing.T) {
@@ -303,3 +304,37 @@ func TestConfigCommand(t *testing.T) {
 		require.Equal(t, "true", strings.TrimSpace(string(output)))
 	})
 }
+
+func TestConfigSetGlobal(t *testing.T) {
+	binaryPath := getStackitBinary(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+	s.RunCli("init")
+
+	output, err := s.RunCliAndGetOutput("config", "get", "--show-source", "restack.strategy")
+	require.NoError(t, err, output)
+	require.Equal(t, "rebase\tdefault", strings.TrimSpace(output))
+
+	s.RunCli("config", "set", "--global", "restack.strategy", "merge")
+	output, err = s.RunCliAndGetOutput("config", "get", "--show-source", "restack.strategy")
+	require.NoError(t, err, output)
+	require.Equal(t, "merge\tglobal", strings.TrimSpace(output))
+
+	// The repo value wins over the global one
+	s.RunCli("config", "set", "--local", "restack.strategy", "rebase")
+	output, err = s.RunCliAndGetOutput("config", "get", "--show-source", "restack.strategy")
+	require.NoError(t, err, output)
+	require.Equal(t, "rebase\trepo", strings.TrimSpace(output))
+	output, err = s.RunCliAndGetOutput("config", "get", "restack.strategy")
+	require.NoError(t, err, output)
+	require.Equal(t, "rebase", strings.TrimSpace(output))
+
+	s.RunCli("config", "unset", "restack.strategy")
+	output, err = s.RunCliAndGetOutput("config", "get", "--show-source", "restack.strategy")
+	require.NoError(t, err, output)
+	require.Equal(t, "merge\tglobal", strings.TrimSpace(output))
+
+	output, err = s.RunCliAndGetOutput("config", "set", "--global", "--local", "restack.strategy", "merge")
+	require.Error(t, err)
+	require.Contains(t, output, "only one of --global or --local")
+}
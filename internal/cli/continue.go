@@ -18,6 +18,8 @@ func newContinueCmd() *cobra.Command {
 		Long: `Continues the most recent Stackit command halted by a rebase conflict.
 This command will continue the rebase and resume restacking remaining branches.`,
 		SilenceUsage: true,
+		// continue resumes a stopped operation, so it must run while git is mid-operation
+		Annotations: map[string]string{common.AllowGitOperationAnnotation: ""},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return common.Run(cmd, func(ctx *runtime.Context) error {
 				return actions.ContinueAction(ctx, actions.ContinueOptions{
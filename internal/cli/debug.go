@@ -4,6 +4,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/cli/common"
 	"stackit.dev/stackit/internal/runtime"
 )
 
@@ -29,6 +30,8 @@ The output includes:
 Output is formatted as pretty-printed JSON for easy reading and parsing.`,
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
+		// Debug output is most useful when something is stuck mid-operation
+		Annotations: map[string]string{common.AllowGitOperationAnnotation: ""},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			// Get context (demo or real)
 			ctx, err := runtime.GetContext(cmd.Context())
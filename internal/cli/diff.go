@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/cli/common"
+	"stackit.dev/stackit/internal/runtime"
+)
+
+// newDiffCmd creates the diff command
+func newDiffCmd() *cobra.Command {
+	var (
+		stat     bool
+		nameOnly bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff [branch]",
+		Short: "Show the changes introduced by a branch",
+		Long: `Show the changes introduced by a single branch, diffed against the parent revision
+it was last restacked onto. Only the branch's own changes are shown, even if its parent
+has since moved and the branch needs restacking.
+
+If no branch is specified, diffs the current branch.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: common.CompleteBranches,
+		SilenceUsage:      true,
+		Annotations:       map[string]string{common.AllowGitOperationAnnotation: ""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, err := runtime.GetContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			branchName := ""
+			if len(args) > 0 {
+				branchName = args[0]
+			}
+
+			return actions.DiffAction(ctx, actions.DiffOptions{
+				BranchName: branchName,
+				Stat:       stat,
+				NameOnly:   nameOnly,
+			})
+		},
+	}
+
+	cmd.Flags().BoolVarP(&stat, "stat", "s", false, "Show a diffstat instead of a full diff")
+	cmd.Flags().BoolVar(&nameOnly, "name-only", false, "Show only the names of changed files")
+
+	return cmd
+}
@@ -0,0 +1,69 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
+)
+
+func TestDiffCommand(t *testing.T) {
+	t.Parallel()
+	binaryPath := getStackitBinary(t)
+
+	t.Run("diffs the current branch against its parent", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		require.NoError(t, s.Scene.Repo.CreateChange("feature change", "feature", false))
+		s.RunCli("create", "feature", "-m", "feature change")
+
+		for _, flags := range [][]string{nil, {"--stat"}, {"--name-only"}} {
+			gitArgs := append([]string{"-c", "color.ui=always", "--no-pager", "diff", "--no-ext-diff"}, flags...)
+			expected, err := s.Scene.Repo.RunGitCommandAndGetOutput(append(gitArgs, "main", "feature", "--")...)
+			require.NoError(t, err)
+			output, err := s.RunCliAndGetOutput(append([]string{"diff"}, flags...)...)
+			require.NoError(t, err, "stackit diff %v failed: %s", flags, output)
+			require.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(output), "stackit diff %v", flags)
+		}
+
+		output, err := s.RunCliAndGetOutput("diff", "--name-only")
+		require.NoError(t, err, output)
+		require.Contains(t, output, "feature")
+	})
+
+	t.Run("diffs against the stored parent revision after the parent moves", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		require.NoError(t, s.Scene.Repo.CreateChange("a change", "a", false))
+		s.RunCli("create", "a", "-m", "a change")
+		require.NoError(t, s.Scene.Repo.CreateChange("b change", "b", false))
+		s.RunCli("create", "b", "-m", "b change")
+
+		oldParent, err := s.Scene.Repo.GetRevision("a")
+		require.NoError(t, err)
+
+		// Move the parent without restacking b
+		s.RunGit("checkout", "a")
+		require.NoError(t, s.Scene.Repo.CreateChangeAndCommit("a follow-up", "a2"))
+		s.RunGit("checkout", "main")
+
+		nameOnly, err := s.RunCliAndGetOutput("diff", "b", "--name-only")
+		require.NoError(t, err, nameOnly)
+		expected, err := s.Scene.Repo.RunGitCommandAndGetOutput("--no-pager", "diff", "--no-ext-diff", "--name-only", oldParent, "b", "--")
+		require.NoError(t, err)
+		require.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(nameOnly))
+		againstTip, err := s.Scene.Repo.RunGitCommandAndGetOutput("--no-pager", "diff", "--no-ext-diff", "--name-only", "a", "b", "--")
+		require.NoError(t, err)
+		require.NotEqual(t, strings.TrimSpace(againstTip), strings.TrimSpace(nameOnly),
+			"diffing against the parent's current tip would include its new changes")
+
+		output, err := s.RunCliAndGetOutput("diff", "b")
+		require.NoError(t, err, output)
+		expected, err = s.Scene.Repo.RunGitCommandAndGetOutput("-c", "color.ui=always", "--no-pager", "diff", "--no-ext-diff", oldParent, "b", "--")
+		require.NoError(t, err)
+		require.Equal(t, strings.TrimSpace(expected), strings.TrimSpace(output))
+	})
+}
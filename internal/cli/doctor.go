@@ -14,16 +14,27 @@ func newDoctorCmd() *cobra.Command {
 	var fix bool
 
 	cmd := &cobra.Command{
-		Use:   "doctor",
-		Short: "Diagnose common issues with your stackit setup",
+		Use:     "doctor",
+		Aliases: []string{"validate"},
+		Short:   "Diagnose common issues with your stackit setup",
 		Long: `Run diagnostic checks on your stackit environment and repository.
 
 The doctor command checks:
   - Environment: Git version, GitHub CLI, and authentication
   - Repository: Git repository status, remote configuration, and trunk branch
-  - Stack State: Metadata integrity, cycle detection, and missing parent branches`,
+  - Stack State: Metadata integrity, cycle detection, missing parent branches, and
+    parent revisions that are no longer in a branch's history
+
+With --fix, orphaned metadata is pruned and stale parent revisions are recomputed from
+the merge-base of the branch and its parent, so restacks don't replay old commits.
+'stackit validate --fix' does the same.
+
+Every check runs and prints its own pass, warning, or failure line. Doctor exits
+with an error when any check fails; warnings alone don't fail it.`,
 		Args:         cobra.NoArgs,
 		SilenceUsage: true,
+		// Diagnosing a stuck operation is part of doctor's job
+		Annotations: map[string]string{common.AllowGitOperationAnnotation: ""},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return common.Run(cmd, func(ctx *runtime.Context) error {
 				// Get config values
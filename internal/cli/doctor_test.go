@@ -0,0 +1,36 @@
+package cli_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
+)
+
+func TestValidateCommand(t *testing.T) {
+	binaryPath := getStackitBinary(t)
+
+	t.Run("validate --fix repairs a parent revision that isn't in the branch's history", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithBinaryPath(binaryPath).
+			WithStack(map[string]string{
+				"branch1": "main",
+			})
+		mergeBase, err := s.Scene.Repo.GetRevision("main")
+		require.NoError(t, err)
+		require.NoError(t, s.Engine.UpdateParentRevision("branch1", "0123456789abcdef0123456789abcdef01234567"))
+
+		// Other checks, like GitHub authentication, can fail here, so only the output is checked
+		output, _ := s.RunCliAndGetOutput("validate")
+		require.Contains(t, output, "branch 'branch1' has a parent revision that isn't in its history")
+
+		output, _ = s.RunCliAndGetOutput("validate", "--fix")
+		require.Contains(t, output, "Repaired the parent revision of branch1")
+
+		meta, err := s.Engine.ReadMetadataRef("branch1")
+		require.NoError(t, err)
+		require.Equal(t, mergeBase, *meta.ParentBranchRevision)
+	})
+}
@@ -0,0 +1,104 @@
+package cli_test
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	stackiterrors "stackit.dev/stackit/internal/errors"
+	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
+)
+
+func TestExitCodes(t *testing.T) {
+	t.Parallel()
+	binaryPath := getStackitBinary(t)
+
+	t.Run("not initialized", func(t *testing.T) {
+		t.Parallel()
+		// A plain git repository without stackit initialization
+		tmpDir := t.TempDir()
+		require.NoError(t, exec.Command("git", "init", tmpDir, "-b", "main").Run())
+		require.NoError(t, exec.Command("git", "-C", tmpDir, "commit", "--allow-empty", "-m", "initial").Run())
+
+		cmd := exec.Command(binaryPath, "log")
+		cmd.Dir = tmpDir
+		output, err := cmd.CombinedOutput()
+		var exitErr *exec.ExitError
+		require.True(t, errors.As(err, &exitErr), "expected log to fail: %s", output)
+		require.Equal(t, stackiterrors.ExitCodeNotInitialized, exitErr.ExitCode(), string(output))
+		require.Contains(t, string(output), "stackit not initialized. Run 'stackit init' first")
+	})
+
+	t.Run("not a git repository", func(t *testing.T) {
+		t.Parallel()
+		cmd := exec.Command(binaryPath, "absorb")
+		cmd.Dir = t.TempDir()
+		output, err := cmd.CombinedOutput()
+		var exitErr *exec.ExitError
+		require.True(t, errors.As(err, &exitErr), "expected absorb to fail: %s", output)
+		require.Equal(t, stackiterrors.ExitCodeNotGitRepository, exitErr.ExitCode(), string(output))
+		require.Contains(t, string(output), "not a git repository")
+		require.NotContains(t, string(output), "not initialized")
+
+		// A git repository that hasn't been initialized is reported differently
+		repoDir := t.TempDir()
+		require.NoError(t, exec.Command("git", "init", repoDir, "-b", "main").Run())
+		cmd = exec.Command(binaryPath, "absorb")
+		cmd.Dir = repoDir
+		output, err = cmd.CombinedOutput()
+		require.True(t, errors.As(err, &exitErr), "expected absorb to fail: %s", output)
+		require.Equal(t, stackiterrors.ExitCodeNotInitialized, exitErr.ExitCode(), string(output))
+		require.Contains(t, string(output), "stackit not initialized. Run 'stackit init' first")
+		require.NotContains(t, string(output), "not a git repository")
+	})
+
+	t.Run("not on a branch", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		s.RunCli("init").
+			RunGit("checkout", "--detach", "HEAD")
+
+		output, err := s.RunCliAndGetOutput("info")
+		var exitErr *exec.ExitError
+		require.True(t, errors.As(err, &exitErr), "expected info to fail: %s", output)
+		require.Equal(t, stackiterrors.ExitCodeNotOnBranch, exitErr.ExitCode(), output)
+		require.Contains(t, output, "not on a branch and no branch specified")
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		s.RunCli("init")
+
+		output, err := s.RunCliAndGetOutput("log", "--no-such-flag")
+		var exitErr *exec.ExitError
+		require.True(t, errors.As(err, &exitErr), "expected log to fail: %s", output)
+		require.Equal(t, stackiterrors.ExitCodeValidation, exitErr.ExitCode(), output)
+
+		output, err = s.RunCliAndGetOutput("config", "get", "no.such.key")
+		require.True(t, errors.As(err, &exitErr), "expected config get to fail: %s", output)
+		require.Equal(t, stackiterrors.ExitCodeValidation, exitErr.ExitCode(), output)
+		require.Contains(t, output, "unknown configuration key: no.such.key")
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		s.RunCli("init")
+		require.NoError(t, s.Scene.Repo.CreateAndCheckoutBranch("feature"))
+		require.NoError(t, s.Scene.Repo.CreateChangeAndCommit("feature change", "shared"))
+		s.RunCli("track", "--parent", "main").
+			RunGit("checkout", "main")
+		require.NoError(t, s.Scene.Repo.CreateChangeAndCommit("main change", "shared"))
+		s.RunGit("checkout", "feature")
+
+		output, err := s.RunCliAndGetOutput("restack")
+		var exitErr *exec.ExitError
+		require.True(t, errors.As(err, &exitErr), "expected restack to fail: %s", output)
+		require.Equal(t, stackiterrors.ExitCodeConflict, exitErr.ExitCode(), output)
+		require.Contains(t, output, "restack stopped due to conflict on feature")
+	})
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/cli/common"
+	stackiterrors "stackit.dev/stackit/internal/errors"
+	"stackit.dev/stackit/internal/runtime"
+)
+
+// newExportGraphCmd creates the export-graph command
+func newExportGraphCmd() *cobra.Command {
+	var (
+		dot       bool
+		prNumbers bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export-graph",
+		Short: "Print the branch tree as a Graphviz graph",
+		Long: `Print the branch tree as a graph for visualization tools.
+
+With --dot, prints a Graphviz DOT graph with a node per branch and an edge from each
+branch to its children. The current branch is drawn bold and branches that need
+restacking are dashed. The output is plain text, so it can be piped into dot:
+
+  stackit export-graph --dot | dot -Tsvg > stack.svg`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		Annotations:  map[string]string{common.AllowGitOperationAnnotation: ""},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if !dot {
+				return stackiterrors.NewValidationError("choose an output format; only --dot is supported")
+			}
+			return common.Run(cmd, func(ctx *runtime.Context) error {
+				return actions.ExportGraphAction(ctx, actions.ExportGraphOptions{
+					PRNumbers: prNumbers,
+				})
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&dot, "dot", false, "Print the graph in Graphviz DOT format")
+	cmd.Flags().BoolVar(&prNumbers, "pr", false, "Add each branch's PR number to its node")
+
+	return cmd
+}
@@ -0,0 +1,78 @@
+package cli_test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/testhelpers"
+)
+
+func TestExportGraphCommand(t *testing.T) {
+	t.Parallel()
+	binaryPath := getStackitBinary(t)
+
+	t.Run("prints the stack as a DOT graph", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, func(s *testhelpers.Scene) error {
+			if err := s.Repo.CreateChangeAndCommit("initial", "init"); err != nil {
+				return err
+			}
+			for _, name := range []string{"a", "b"} {
+				if err := s.Repo.CreateChange(name+" change", name, false); err != nil {
+					return err
+				}
+				cmd := exec.Command(binaryPath, "create", name, "-m", name+" change")
+				cmd.Dir = s.Dir
+				if err := cmd.Run(); err != nil {
+					return err
+				}
+			}
+			if err := s.Repo.CheckoutBranch("main"); err != nil {
+				return err
+			}
+			if err := s.Repo.CreateChange("c change", "c", false); err != nil {
+				return err
+			}
+			cmd := exec.Command(binaryPath, "create", "c", "-m", "c change")
+			cmd.Dir = s.Dir
+			return cmd.Run()
+		})
+		// Moving a leaves b needing a restack
+		require.NoError(t, scene.Repo.CheckoutBranch("a"))
+		require.NoError(t, scene.Repo.CreateChangeAndCommit("a again", "a2"))
+
+		cmd := exec.Command(binaryPath, "export-graph", "--dot")
+		cmd.Dir = scene.Dir
+		output, err := cmd.Output()
+		require.NoError(t, err, "export-graph failed: %s", string(output))
+
+		require.Equal(t, `digraph stack {
+  node [shape=box];
+  "main" [label="main"];
+  "a" [label="a", style="bold"];
+  "b" [label="b", style="dashed"];
+  "c" [label="c"];
+  "main" -> "a";
+  "a" -> "b";
+  "main" -> "c";
+}
+`, string(output))
+	})
+
+	t.Run("requires a format", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, func(s *testhelpers.Scene) error {
+			return s.Repo.CreateChangeAndCommit("initial", "init")
+		})
+
+		for _, args := range [][]string{{"export-graph"}, {"export-graph", "--dot=false"}} {
+			cmd := exec.Command(binaryPath, args...)
+			cmd.Dir = scene.Dir
+			output, err := cmd.CombinedOutput()
+			require.Error(t, err)
+			require.Contains(t, string(output), "only --dot is supported")
+		}
+	})
+}
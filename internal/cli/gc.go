@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/cli/common"
+	"stackit.dev/stackit/internal/runtime"
+)
+
+// newGCCmd creates the gc command
+func newGCCmd() *cobra.Command {
+	var (
+		prune              bool
+		continuationMaxAge time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Clean up stale stackit metadata and remote-tracking refs",
+		Long: `Clean up leftovers that accumulate over time:
+  - Metadata for branches that no longer exist
+  - Remote-tracking refs for branches deleted on the remote
+  - Continuation state from an interrupted operation that was never continued or aborted
+
+Only reports what would be deleted unless --prune is passed.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		// gc clears continuation state and must work whatever state git is in
+		Annotations: map[string]string{common.AllowGitOperationAnnotation: ""},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return common.RunLocked(cmd, func(ctx *runtime.Context) error {
+				return actions.GCAction(ctx, actions.GCOptions{
+					Prune:              prune,
+					ContinuationMaxAge: continuationMaxAge,
+				})
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&prune, "prune", false, "Delete what was found instead of only reporting it.")
+	cmd.Flags().DurationVar(&continuationMaxAge, "continuation-max-age", actions.DefaultContinuationMaxAge, "Clear continuation state that hasn't been updated for this long.")
+
+	return cmd
+}
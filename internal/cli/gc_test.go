@@ -0,0 +1,64 @@
+package cli_test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
+)
+
+func TestGCCommand(t *testing.T) {
+	t.Parallel()
+	binaryPath := getStackitBinary(t)
+
+	t.Run("reports orphaned metadata and deletes it with --prune", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		require.NoError(t, s.Scene.Repo.CreateChange("feature change", "feature", false))
+		s.RunCli("create", "feature", "-m", "feature change")
+
+		// Deleting the branch with plain git leaves its metadata behind
+		s.RunGit("checkout", "main").
+			RunGit("branch", "-D", "feature")
+
+		output, err := s.RunCliAndGetOutput("gc")
+		require.NoError(t, err, "gc failed: %s", output)
+		require.Contains(t, output, "Would delete metadata for deleted branch feature")
+		require.Contains(t, output, "stackit gc --prune")
+		_, err = s.Scene.Repo.RunGitCommandAndGetOutput("show-ref", "--verify", "refs/stackit/metadata/feature")
+		require.NoError(t, err, "dry run should keep the metadata ref")
+
+		output, err = s.RunCliAndGetOutput("gc", "--prune")
+		require.NoError(t, err, "gc --prune failed: %s", output)
+		require.Contains(t, output, "Deleted metadata for deleted branch feature")
+		_, err = s.Scene.Repo.RunGitCommandAndGetOutput("show-ref", "--verify", "refs/stackit/metadata/feature")
+		require.Error(t, err, "--prune should delete the metadata ref")
+
+		output, err = s.RunCliAndGetOutput("gc")
+		require.NoError(t, err, "gc failed: %s", output)
+		require.Contains(t, output, "Nothing to clean up.")
+	})
+
+	t.Run("reports remote-tracking refs for branches deleted on the remote", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		bareDir, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "main"))
+		require.NoError(t, s.Scene.Repo.CreateAndCheckoutBranch("old"))
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "old"))
+		s.RunGit("checkout", "main")
+		require.NoError(t, exec.Command("git", "-C", bareDir, "branch", "-D", "old").Run())
+
+		output, err := s.RunCliAndGetOutput("gc")
+		require.NoError(t, err, "gc failed: %s", output)
+		require.Contains(t, output, "Would delete stale remote-tracking ref origin/old")
+
+		s.RunCli("gc", "--prune")
+		_, err = s.Scene.Repo.RunGitCommandAndGetOutput("show-ref", "--verify", "refs/remotes/origin/old")
+		require.Error(t, err, "--prune should delete the stale remote-tracking ref")
+	})
+}
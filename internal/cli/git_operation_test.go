@@ -0,0 +1,47 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
+)
+
+func TestGitOperationInProgress(t *testing.T) {
+	t.Parallel()
+	binaryPath := getStackitBinary(t)
+
+	inProgress := "a git operation is in progress; run stackit continue or git rebase --abort"
+
+	t.Run("commands refuse to run mid-rebase", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		s.RunCli("create", "a", "-m", "a")
+		// Leave git looking like it stopped mid-rebase
+		require.NoError(t, os.MkdirAll(filepath.Join(s.Scene.Dir, ".git", "rebase-merge"), 0o755))
+
+		for _, args := range [][]string{{"checkout", "main"}, {"modify", "-m", "b"}, {"delete", "a", "--force"}, {"restack"}} {
+			output, err := s.RunCliAndGetOutput(args...)
+			require.Error(t, err, "stackit %v", args)
+			require.Contains(t, output, inProgress, "stackit %v", args)
+		}
+		s.ExpectBranch("a")
+	})
+
+	t.Run("read-only and recovery commands still run mid-rebase", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		s.RunCli("create", "a", "-m", "a")
+		// Leave git looking like it stopped mid-rebase
+		require.NoError(t, os.MkdirAll(filepath.Join(s.Scene.Dir, ".git", "rebase-merge"), 0o755))
+
+		for _, args := range [][]string{{"log"}, {"log", "full"}, {"info"}, {"parent"}, {"config", "get", "submit.footer"}, {"restack", "--abort"}, {"help"}} {
+			output, _ := s.RunCliAndGetOutput(args...)
+			require.NotContains(t, output, inProgress, "stackit %v", args)
+		}
+	})
+}
@@ -15,6 +15,7 @@ func newInfoCmd() *cobra.Command {
 		diff  bool
 		patch bool
 		stat  bool
+		json  bool
 	)
 
 	cmd := &cobra.Command{
@@ -24,10 +25,12 @@ func newInfoCmd() *cobra.Command {
 		Long: `Display information about a branch, including branch relationships,
 PR status, and optionally diffs or patches.
 
-If no branch is specified, displays information about the current branch.`,
+If no branch is specified, displays information about the current branch.
+Use --json for machine-readable output.`,
 		Args:              cobra.MaximumNArgs(1),
 		ValidArgsFunction: common.CompleteBranches,
 		SilenceUsage:      true,
+		Annotations:       map[string]string{common.AllowGitOperationAnnotation: ""},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx, err := runtime.GetContext(cmd.Context())
 			if err != nil {
@@ -45,6 +48,7 @@ If no branch is specified, displays information about the current branch.`,
 				Diff:       diff,
 				Patch:      patch,
 				Stat:       stat,
+				JSON:       json,
 			})
 		},
 	}
@@ -53,6 +57,7 @@ If no branch is specified, displays information about the current branch.`,
 	cmd.Flags().BoolVarP(&diff, "diff", "d", false, "Show the diff between this branch and its parent. Takes precedence over patch")
 	cmd.Flags().BoolVarP(&patch, "patch", "p", false, "Show the changes made by each commit")
 	cmd.Flags().BoolVarP(&stat, "stat", "s", false, "Show a diffstat instead of a full diff. Modifies either --patch or --diff. If neither is passed, implies --diff")
+	cmd.Flags().BoolVar(&json, "json", false, "Output branch information as JSON")
 
 	return cmd
 }
@@ -1,12 +1,14 @@
 package cli_test
 
 import (
+	"encoding/json"
 	"os/exec"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/testhelpers"
 )
 
@@ -362,4 +364,55 @@ func TestInfoCommand(t *testing.T) {
 		require.Error(t, err, "info should fail when stackit not initialized")
 		require.Contains(t, string(output), "not initialized", "should mention not initialized")
 	})
+	t.Run("info --json outputs structured branch info", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, func(s *testhelpers.Scene) error {
+			if err := s.Repo.CreateChangeAndCommit("initial", "init"); err != nil {
+				return err
+			}
+			if err := s.Repo.CreateChange("a change", "a", false); err != nil {
+				return err
+			}
+			cmd := exec.Command(binaryPath, "create", "a", "-m", "a change")
+			cmd.Dir = s.Dir
+			if err := cmd.Run(); err != nil {
+				return err
+			}
+			if err := s.Repo.CreateChange("b change", "b", false); err != nil {
+				return err
+			}
+			cmd = exec.Command(binaryPath, "create", "b", "-m", "b change")
+			cmd.Dir = s.Dir
+			return cmd.Run()
+		})
+
+		cmd := exec.Command(binaryPath, "info", "a", "--json")
+		cmd.Dir = scene.Dir
+		output, err := cmd.Output()
+		require.NoError(t, err, "info --json failed: %s", string(output))
+
+		var info actions.InfoJSON
+		require.NoError(t, json.Unmarshal(output, &info), "output should be valid JSON: %s", string(output))
+		require.Equal(t, "a", info.Name)
+		require.Equal(t, "main", info.Parent)
+		require.Equal(t, []string{"b"}, info.Children)
+		require.False(t, info.IsTrunk)
+		require.True(t, info.IsTracked)
+		require.False(t, info.NeedsRestack)
+		require.Equal(t, 1, info.CommitCount)
+		require.Nil(t, info.PRNumber)
+
+		// Trunk has no parent
+		cmd = exec.Command(binaryPath, "info", "main", "--json")
+		cmd.Dir = scene.Dir
+		output, err = cmd.Output()
+		require.NoError(t, err, "info --json failed: %s", string(output))
+
+		var trunkInfo actions.InfoJSON
+		require.NoError(t, json.Unmarshal(output, &trunkInfo), "output should be valid JSON: %s", string(output))
+		require.Equal(t, "main", trunkInfo.Name)
+		require.Empty(t, trunkInfo.Parent)
+		require.True(t, trunkInfo.IsTrunk)
+		require.Equal(t, []string{"a"}, trunkInfo.Children)
+	})
 }
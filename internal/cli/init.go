@@ -4,12 +4,16 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/spf13/cobra"
 
+	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/config"
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/git"
+	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui"
 	"stackit.dev/stackit/internal/tui/style"
 )
@@ -23,9 +27,14 @@ func isInteractive() bool {
 	return (fileInfo.Mode() & os.ModeCharDevice) != 0
 }
 
-// InferTrunk attempts to infer the trunk branch name
+// InferTrunk attempts to infer the trunk branch name, preferring the remote's default branch
 func InferTrunk(ctx context.Context, branchNames []string) string {
-	remoteBranch, err := git.FindRemoteBranch(ctx, "origin")
+	remote := git.GetRemote()
+	if defaultBranch := git.GetRemoteDefaultBranch(ctx, remote); defaultBranch != "" && slices.Contains(branchNames, defaultBranch) {
+		return defaultBranch
+	}
+
+	remoteBranch, err := git.FindRemoteBranch(ctx, remote)
 	if err == nil && remoteBranch != "" {
 		for _, name := range branchNames {
 			if name == remoteBranch {
@@ -41,6 +50,15 @@ func InferTrunk(ctx context.Context, branchNames []string) string {
 	return ""
 }
 
+// inferTrunkWithConfig prefers the trunk committed in .stackit.yml, when it is a local
+// branch, to the inferred one
+func inferTrunkWithConfig(ctx context.Context, cfg *config.Config, branchNames []string) string {
+	if shared := cfg.SharedTrunk(); slices.Contains(branchNames, shared) {
+		return shared
+	}
+	return InferTrunk(ctx, branchNames)
+}
+
 // selectTrunkBranch prompts user to select trunk branch (simplified for now)
 func selectTrunkBranch(branchNames []string, inferredTrunk string, interactive bool) (string, error) {
 	if !interactive {
@@ -66,8 +84,8 @@ func selectTrunkBranch(branchNames []string, inferredTrunk string, interactive b
 // Returns the repo root path. This is used by commands that need stackit
 // to be initialized but want to auto-initialize for convenience.
 func EnsureInitialized(ctx context.Context) (string, error) {
-	if err := git.InitDefaultRepo(); err != nil {
-		return "", fmt.Errorf("not a git repository: %w", err)
+	if err := git.EnsureRepository(); err != nil {
+		return "", err
 	}
 
 	repoRoot, err := git.GetRepoRoot()
@@ -75,7 +93,10 @@ func EnsureInitialized(ctx context.Context) (string, error) {
 		return "", fmt.Errorf("failed to get repo root: %w", err)
 	}
 
-	cfg, _ := config.LoadConfig(repoRoot)
+	cfg, err := config.LoadConfig(repoRoot)
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
 	if !cfg.IsInitialized() {
 		splog := tui.NewSplog()
 		splog.Info("Stackit has not been initialized, attempting to setup now...")
@@ -89,7 +110,7 @@ func EnsureInitialized(ctx context.Context) (string, error) {
 			return "", fmt.Errorf("no branches found in current repo; cannot initialize Stackit.\nPlease create your first commit and then re-run stackit init")
 		}
 
-		trunkName := InferTrunk(ctx, branchNames)
+		trunkName := inferTrunkWithConfig(ctx, cfg, branchNames)
 		if trunkName == "" {
 			trunkName = "main"
 			found := false
@@ -116,9 +137,11 @@ func EnsureInitialized(ctx context.Context) (string, error) {
 // newInitCmd creates the init command
 func newInitCmd() *cobra.Command {
 	var (
-		trunk         string
-		reset         bool
-		noInteractive bool
+		trunk          string
+		reset          bool
+		preservePRInfo bool
+		noInteractive  bool
+		trackExisting  bool
 	)
 
 	cmd := &cobra.Command{
@@ -127,8 +150,12 @@ func newInitCmd() *cobra.Command {
 		Short:        "Initialize Stackit in the current repository",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			if err := git.InitDefaultRepo(); err != nil {
-				return fmt.Errorf("not a git repository: %w", err)
+			if preservePRInfo && !reset {
+				return stackiterrors.NewValidationError("--preserve-pr-info can only be used with --reset")
+			}
+
+			if err := git.EnsureRepository(); err != nil {
+				return err
 			}
 
 			repoRoot, err := git.GetRepoRoot()
@@ -151,12 +178,12 @@ func newInitCmd() *cobra.Command {
 			}
 
 			splog := tui.NewSplog()
+			interactive := !noInteractive && isInteractive()
 
 			trunkName := trunk
 			if trunkName == "" {
-				inferredTrunk := InferTrunk(cmd.Context(), branchNames)
+				inferredTrunk := inferTrunkWithConfig(cmd.Context(), cfg, branchNames)
 
-				interactive := !noInteractive && isInteractive()
 				selected, err := selectTrunkBranch(branchNames, inferredTrunk, interactive)
 				if err != nil {
 					return err
@@ -204,10 +231,14 @@ func newInitCmd() *cobra.Command {
 			}
 
 			if reset {
-				if err := eng.Reset(trunkName); err != nil {
+				if err := eng.ResetWithOptions(trunkName, engine.ResetOptions{PreservePRInfo: preservePRInfo}); err != nil {
 					return fmt.Errorf("failed to reset branches: %w", err)
 				}
-				splog.Info("All branches have been untracked")
+				if preservePRInfo {
+					splog.Info("All branches have been untracked; PR associations were kept")
+				} else {
+					splog.Info("All branches have been untracked")
+				}
 			} else {
 				if err := eng.Rebuild(trunkName); err != nil {
 					return fmt.Errorf("failed to rebuild engine: %w", err)
@@ -215,13 +246,32 @@ func newInitCmd() *cobra.Command {
 				splog.Info("Stackit initialized successfully!")
 			}
 
+			// Offer to adopt branches created before stackit on a first run
+			ctx := runtime.NewContextWithRepoRoot(eng, repoRoot)
+			untracked := actions.UntrackedBranches(ctx)
+			if len(untracked) == 0 {
+				return nil
+			}
+			if !trackExisting && !wasInitialized && interactive {
+				trackExisting, err = tui.PromptConfirm(fmt.Sprintf("Track your %d existing branches, inferring their parents?", len(untracked)), true)
+				if err != nil {
+					return err
+				}
+			}
+			if trackExisting {
+				tracked := actions.TrackExistingBranches(ctx)
+				splog.Info("Tracked %d existing branches", tracked)
+			}
+
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&trunk, "trunk", "", "The name of your trunk branch")
 	cmd.Flags().BoolVar(&reset, "reset", false, "Untrack all branches")
+	cmd.Flags().BoolVar(&preservePRInfo, "preserve-pr-info", false, "With --reset, keep each branch's PR association so it doesn't need to be re-submitted")
 	cmd.Flags().BoolVar(&noInteractive, "no-interactive", false, "Disable interactive prompts")
+	cmd.Flags().BoolVar(&trackExisting, "track-existing", false, "Track existing branches, inferring each one's parent from its history")
 
 	return cmd
 }
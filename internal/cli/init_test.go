@@ -5,12 +5,14 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"stackit.dev/stackit/internal/config"
 	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
 )
 
 func TestInitCommand(t *testing.T) {
@@ -114,6 +116,78 @@ func TestInitCommand(t *testing.T) {
 		require.Equal(t, "main", *cfg.Trunk)
 	})
 
+	t.Run("infers trunk from the remote's default branch", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, nil).WithBinaryPath(binaryPath)
+		require.NoError(t, s.Scene.Repo.CreateChangeAndCommit("initial", "init"))
+		_ = os.Remove(filepath.Join(s.Scene.Dir, ".git", ".stackit_config"))
+
+		// The repository has both a main and a master branch, and the remote's default is master
+		require.NoError(t, s.Scene.Repo.CreateBranch("master"))
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		s.RunGit("push", "origin", "master").
+			RunGit("symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/master")
+
+		s.RunCli("init", "--no-interactive")
+
+		cfg := readRepoConfig(t, s.Scene.Dir)
+		require.NotNil(t, cfg.Trunk)
+		require.Equal(t, "master", *cfg.Trunk)
+	})
+
+	t.Run("re-running init keeps tracked branches", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, nil).WithBinaryPath(binaryPath)
+		require.NoError(t, s.Scene.Repo.CreateChangeAndCommit("initial", "init"))
+		s.RunCli("init", "--no-interactive", "--trunk", "main")
+
+		require.NoError(t, s.Scene.Repo.CreateAndCheckoutBranch("feature"))
+		require.NoError(t, s.Scene.Repo.CreateChangeAndCommit("feature", "feature"))
+		s.RunCli("track", "--parent", "main")
+
+		output, err := s.RunCliAndGetOutput("init", "--no-interactive")
+		require.NoError(t, err, "init command failed: %s", output)
+		require.Contains(t, output, "Reinitializing Stackit")
+		output, err = s.RunCliAndGetOutput("parent")
+		require.NoError(t, err, "parent command failed: %s", output)
+		require.Equal(t, "main", strings.TrimSpace(output))
+
+		s.RunCli("init", "--no-interactive", "--reset")
+		output, err = s.RunCliAndGetOutput("parent")
+		require.NoError(t, err, "parent command failed: %s", output)
+		require.Contains(t, output, "has no parent (untracked branch)")
+	})
+
+	t.Run("tracks existing branches with --track-existing", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, nil).WithBinaryPath(binaryPath)
+		require.NoError(t, s.Scene.Repo.CreateChangeAndCommit("initial", "init"))
+		_ = os.Remove(filepath.Join(s.Scene.Dir, ".git", ".stackit_config"))
+
+		// A stack built with plain git before stackit was set up, plus a merged branch
+		require.NoError(t, s.Scene.Repo.CreateBranch("merged"))
+		require.NoError(t, s.Scene.Repo.CreateAndCheckoutBranch("a"))
+		require.NoError(t, s.Scene.Repo.CreateChangeAndCommit("a", "a"))
+		require.NoError(t, s.Scene.Repo.CreateAndCheckoutBranch("b"))
+		require.NoError(t, s.Scene.Repo.CreateChangeAndCommit("b", "b"))
+
+		output, err := s.RunCliAndGetOutput("init", "--no-interactive", "--trunk", "main", "--track-existing")
+		require.NoError(t, err, "init command failed: %s", output)
+		require.Contains(t, output, "Tracked 2 existing branches")
+
+		for branch, want := range map[string]string{"a": "main", "b": "a"} {
+			s.RunGit("checkout", branch)
+			output, err := s.RunCliAndGetOutput("parent")
+			require.NoError(t, err, "parent command failed: %s", output)
+			require.Equal(t, want, strings.TrimSpace(output), "parent of %s", branch)
+		}
+		s.RunGit("checkout", "merged")
+		output, err = s.RunCliAndGetOutput("parent")
+		require.NoError(t, err, "parent command failed: %s", output)
+		require.Contains(t, output, "has no parent (untracked branch)")
+	})
+
 	t.Run("fails when not in git repository", func(t *testing.T) {
 		t.Parallel()
 		tmpDir := t.TempDir()
@@ -0,0 +1,82 @@
+package cli_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/testhelpers"
+)
+
+func TestRepoLockCommand(t *testing.T) {
+	t.Parallel()
+	binaryPath := getStackitBinary(t)
+
+	t.Run("concurrent mutating command fails fast", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, func(s *testhelpers.Scene) error {
+			return s.Repo.CreateChangeAndCommit("initial", "init")
+		})
+
+		// Make the first checkout block so the first command holds the lock for a while
+		marker := filepath.Join(scene.Dir, ".git", "hook-started")
+		hook := "#!/bin/sh\nif [ ! -f .git/hook-started ]; then touch .git/hook-started; sleep 3; fi\n"
+		require.NoError(t, os.WriteFile(filepath.Join(scene.Dir, ".git", "hooks", "post-checkout"), []byte(hook), 0700))
+
+		first := exec.Command(binaryPath, "create", "first", "-m", "first")
+		first.Dir = scene.Dir
+		require.NoError(t, first.Start())
+
+		require.Eventually(t, func() bool {
+			_, err := os.Stat(marker)
+			return err == nil
+		}, 10*time.Second, 20*time.Millisecond)
+
+		start := time.Now()
+		second := exec.Command(binaryPath, "create", "second", "-m", "second")
+		second.Dir = scene.Dir
+		output, err := second.CombinedOutput()
+		require.Error(t, err, "second command should fail while the first holds the lock")
+		require.Contains(t, string(output), "another stackit operation is in progress")
+		require.Less(t, time.Since(start), 2*time.Second, "second command should fail fast")
+
+		require.NoError(t, first.Wait())
+
+		// The lock is released once the first command finishes
+		cmd := exec.Command(binaryPath, "create", "second", "-m", "second")
+		cmd.Dir = scene.Dir
+		output, err = cmd.CombinedOutput()
+		require.NoError(t, err, "create failed after lock release: %s", string(output))
+
+		cmd = exec.Command(binaryPath, "parent")
+		cmd.Dir = scene.Dir
+		output, err = cmd.CombinedOutput()
+		require.NoError(t, err)
+		require.Equal(t, "first\n", string(output))
+	})
+
+	t.Run("force-unlock clears a leftover lock", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, func(s *testhelpers.Scene) error {
+			return s.Repo.CreateChangeAndCommit("initial", "init")
+		})
+
+		lock := `{"command":"stackit sync","pid":1,"startedAt":"` + time.Now().Format(time.RFC3339) + `"}`
+		require.NoError(t, os.WriteFile(filepath.Join(scene.Dir, ".git", ".stackit_lock"), []byte(lock), 0600))
+
+		cmd := exec.Command(binaryPath, "create", "feature", "-m", "feature")
+		cmd.Dir = scene.Dir
+		output, err := cmd.CombinedOutput()
+		require.Error(t, err)
+		require.Contains(t, string(output), "stackit sync")
+
+		cmd = exec.Command(binaryPath, "create", "feature", "-m", "feature", "--force-unlock")
+		cmd.Dir = scene.Dir
+		output, err = cmd.CombinedOutput()
+		require.NoError(t, err, "create --force-unlock failed: %s", string(output))
+	})
+}
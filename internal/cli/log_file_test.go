@@ -0,0 +1,42 @@
+package cli_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/testhelpers"
+)
+
+func TestLogFileFlag(t *testing.T) {
+	t.Parallel()
+	binaryPath := getStackitBinary(t)
+
+	t.Run("--log-file captures debug output that the terminal does not show", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, func(s *testhelpers.Scene) error {
+			return s.Repo.CreateChangeAndCommit("initial", "init")
+		})
+
+		cmd := exec.Command(binaryPath, "init")
+		cmd.Dir = scene.Dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+
+		logPath := filepath.Join(t.TempDir(), "stackit.log")
+		cmd = exec.Command(binaryPath, "info", "main", "--log-file", logPath)
+		cmd.Dir = scene.Dir
+		cmd.Env = append(os.Environ(), "DEBUG=", "STACKIT_NO_LOGGING=1")
+		output, err = cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		require.NotContains(t, string(output), "Running stackit")
+
+		data, err := os.ReadFile(logPath)
+		require.NoError(t, err)
+		require.Contains(t, string(data), "DEBUG Running stackit info main --log-file")
+		require.NotContains(t, string(data), "\x1b[")
+	})
+}
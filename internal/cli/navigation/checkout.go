@@ -5,6 +5,7 @@ import (
 
 	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/cli/common"
+	"stackit.dev/stackit/internal/config"
 	"stackit.dev/stackit/internal/runtime"
 )
 
@@ -15,6 +16,7 @@ func NewCheckoutCmd() *cobra.Command {
 		showUntracked bool
 		stack         bool
 		trunk         bool
+		autostash     bool
 	)
 
 	cmd := &cobra.Command{
@@ -42,6 +44,7 @@ by typing. Use flags to customize which branches are shown.`,
 					All:           all,
 					StackOnly:     stack,
 					CheckoutTrunk: trunk,
+					Autostash:     autostashEnabled(ctx, autostash),
 				}
 
 				// Execute checkout action
@@ -55,6 +58,20 @@ by typing. Use flags to customize which branches are shown.`,
 	cmd.Flags().BoolVarP(&showUntracked, "show-untracked", "u", false, "Include untracked branches in interactive selection")
 	cmd.Flags().BoolVarP(&stack, "stack", "s", false, "Only show ancestors and descendants of the current branch in interactive selection")
 	cmd.Flags().BoolVarP(&trunk, "trunk", "t", false, "Checkout the current trunk")
+	addAutostashFlag(cmd, &autostash)
 
 	return cmd
 }
+
+func addAutostashFlag(cmd *cobra.Command, autostash *bool) {
+	cmd.Flags().BoolVar(autostash, "autostash", false, "Stash local changes that block the checkout and restore them on the new branch. Defaults to the checkout.autostash config.")
+}
+
+// autostashEnabled reports whether a checkout should stash blocking changes, from --autostash or checkout.autostash
+func autostashEnabled(ctx *runtime.Context, flag bool) bool {
+	if flag {
+		return true
+	}
+	cfg, err := config.LoadConfig(ctx.RepoRoot)
+	return err == nil && cfg.CheckoutAutostash()
+}
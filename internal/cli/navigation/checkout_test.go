@@ -1,6 +1,8 @@
 package navigation_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -163,4 +165,46 @@ func TestCheckoutCommand(t *testing.T) {
 		s.RunCli("checkout", "a")
 		s.ExpectBranch("a")
 	})
+
+	t.Run("checkout with a dirty tree", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		sharedPath := filepath.Join(s.Scene.Dir, "shared.txt")
+		require.NoError(t, os.WriteFile(sharedPath, []byte("one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\n"), 0600))
+		s.RunGit("add", "shared.txt").RunGit("commit", "-m", "add shared")
+
+		// Branch a changes the first line of the file
+		require.NoError(t, os.WriteFile(sharedPath, []byte("ONE\ntwo\nthree\nfour\nfive\nsix\nseven\neight\n"), 0600))
+		s.RunGit("add", "shared.txt").
+			RunCli("create", "a", "-m", "a").
+			RunGit("checkout", "main")
+
+		// A local change to the last line blocks checking out a
+		require.NoError(t, os.WriteFile(sharedPath, []byte("one\ntwo\nthree\nfour\nfive\nsix\nseven\nEIGHT\n"), 0600))
+
+		output, err := s.RunCliAndGetOutput("checkout", "a")
+		require.Error(t, err, "checkout should fail with blocking local changes: %s", output)
+		require.Contains(t, output, "--autostash")
+		s.ExpectBranch("main")
+
+		output, err = s.RunCliAndGetOutput("checkout", "a", "--autostash")
+		require.NoError(t, err, "checkout --autostash failed: %s", output)
+		s.ExpectBranch("a")
+
+		// Both a's change and the local change are in the working tree
+		content, err := os.ReadFile(sharedPath)
+		require.NoError(t, err)
+		require.Contains(t, string(content), "ONE")
+		require.Contains(t, string(content), "EIGHT")
+
+		// checkout.autostash does the same without the flag
+		s.RunCli("config", "set", "checkout.autostash", "true")
+		output, err = s.RunCliAndGetOutput("down")
+		require.NoError(t, err, "down with checkout.autostash failed: %s", output)
+		s.ExpectBranch("main")
+		content, err = os.ReadFile(sharedPath)
+		require.NoError(t, err)
+		require.NotContains(t, string(content), "ONE")
+		require.Contains(t, string(content), "EIGHT")
+	})
 }
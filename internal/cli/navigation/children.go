@@ -22,6 +22,7 @@ Lists all branches that have the current branch as their parent in the stack.
 This is useful for understanding the structure of your stack and seeing which
 branches depend on the current branch.`,
 		SilenceUsage: true,
+		Annotations:  map[string]string{common.AllowGitOperationAnnotation: ""},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return common.Run(cmd, func(ctx *runtime.Context) error {
 				// Get current branch
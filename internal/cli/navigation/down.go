@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/cli/common"
 	"stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/runtime"
@@ -15,7 +16,8 @@ import (
 // NewDownCmd creates the down command
 func NewDownCmd() *cobra.Command {
 	var (
-		steps int
+		steps     int
+		autostash bool
 	)
 
 	cmd := &cobra.Command{
@@ -80,8 +82,8 @@ as an argument to move multiple levels at once.`,
 				}
 
 				// Checkout the target branch
-				if err := ctx.Engine.CheckoutBranch(ctx.Context, targetBranch); err != nil {
-					return fmt.Errorf("failed to checkout branch %s: %w", targetBranch.GetName(), err)
+				if err := actions.CheckoutWithAutostash(ctx, targetBranch, autostashEnabled(ctx, autostash)); err != nil {
+					return err
 				}
 
 				ctx.Splog.Info("Checked out %s.", style.ColorBranchName(targetBranch.GetName(), false))
@@ -92,6 +94,7 @@ as an argument to move multiple levels at once.`,
 
 	// Add flags
 	cmd.Flags().IntVarP(&steps, "steps", "n", 1, "The number of levels to traverse downstack.")
+	addAutostashFlag(cmd, &autostash)
 
 	return cmd
 }
@@ -2,12 +2,13 @@
 package navigation
 
 import (
-	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/cli/common"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/runtime"
 )
 
@@ -19,6 +20,8 @@ func NewLogCmd() *cobra.Command {
 		Use:          "log",
 		Short:        "Log all branches tracked by Stackit, showing dependencies and info for each",
 		SilenceUsage: true,
+		// Read-only, so it and log full also work mid-rebase
+		Annotations: map[string]string{common.AllowGitOperationAnnotation: ""},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return executeLog(cmd, f, "NORMAL")
 		},
@@ -51,16 +54,36 @@ type logFlags struct {
 	stack         bool
 	steps         int
 	showUntracked bool
+	hideMerged    bool
+	watch         bool
+	interval      time.Duration
+	commits       bool
+	maxCommits    int
 }
 
+// minWatchInterval keeps --watch from polling GitHub too often
+const minWatchInterval = time.Second
+
 func addLogFlags(cmd *cobra.Command, f *logFlags) {
 	cmd.Flags().BoolVarP(&f.reverse, "reverse", "r", false, "Print the log upside down. Handy when you have a lot of branches!")
 	cmd.Flags().BoolVarP(&f.stack, "stack", "s", false, "Only show ancestors and descendants of the current branch")
 	cmd.Flags().IntVarP(&f.steps, "steps", "n", 0, "Only show this many levels upstack and downstack. Implies --stack")
 	cmd.Flags().BoolVarP(&f.showUntracked, "show-untracked", "u", false, "Include untracked branches in interactive selection")
+	cmd.Flags().BoolVar(&f.hideMerged, "hide-merged", false, "Hide branches that have been merged, attaching their children to the nearest visible ancestor")
+	cmd.Flags().BoolVarP(&f.watch, "watch", "w", false, "Keep the log on screen, refreshing PR states from GitHub and highlighting changes until you quit. Shows the log once when not in a terminal")
+	cmd.Flags().DurationVar(&f.interval, "interval", 10*time.Second, "How often --watch refreshes, e.g. 30s or 1m")
+	cmd.Flags().BoolVar(&f.commits, "oneline-commits", false, "List each branch's commits, one line each, under the branch")
+	cmd.Flags().IntVar(&f.maxCommits, "max-commits", 0, "Most commits --oneline-commits lists per branch, summarizing the rest (0 lists them all)")
 }
 
 func executeLog(cmd *cobra.Command, f *logFlags, style string) error {
+	if f.interval < minWatchInterval {
+		return stackiterrors.NewValidationError("--interval must be at least %s", minWatchInterval)
+	}
+	if f.maxCommits < 0 {
+		return stackiterrors.NewValidationError("--max-commits can't be negative")
+	}
+
 	return common.Run(cmd, func(ctx *runtime.Context) error {
 		eng := ctx.Engine
 
@@ -70,17 +93,22 @@ func executeLog(cmd *cobra.Command, f *logFlags, style string) error {
 		if f.stack || f.steps > 0 {
 			currentBranch := eng.CurrentBranch()
 			if currentBranch == nil {
-				return fmt.Errorf("not on a branch")
+				return stackiterrors.ErrNotOnBranch
 			}
 			branchName = currentBranch.GetName()
 		}
 
 		// Prepare options
 		opts := actions.LogOptions{
-			Style:         style,
-			Reverse:       f.reverse,
-			BranchName:    branchName,
-			ShowUntracked: f.showUntracked,
+			Style:          style,
+			Reverse:        f.reverse,
+			BranchName:     branchName,
+			ShowUntracked:  f.showUntracked,
+			HideMerged:     f.hideMerged,
+			Watch:          f.watch,
+			Interval:       f.interval,
+			OnelineCommits: f.commits,
+			MaxCommits:     f.maxCommits,
 		}
 
 		if f.steps > 0 {
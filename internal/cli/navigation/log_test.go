@@ -1,6 +1,7 @@
 package navigation_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -75,4 +76,121 @@ func TestLogCommand(t *testing.T) {
 		require.NoError(t, err, "log command failed: %s", output)
 		require.Contains(t, output, "feature")
 	})
+
+	t.Run("log with --stack omits sibling stacks", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+
+		// Create two stacks: main -> feature-base -> feature-top and main -> sibling-stack
+		s.RunCli("create", "feature-base", "-m", "feature-base").
+			RunCli("create", "feature-top", "-m", "feature-top").
+			RunGit("checkout", "main").
+			RunCli("create", "sibling-stack", "-m", "sibling-stack").
+			RunGit("checkout", "feature-base")
+
+		output, err := s.RunCliAndGetOutput("log", "--stack")
+		require.NoError(t, err, "log command failed: %s", output)
+		require.Contains(t, output, "main")
+		require.Contains(t, output, "feature-base")
+		require.Contains(t, output, "feature-top")
+		require.NotContains(t, output, "sibling-stack")
+
+		output, err = s.RunCliAndGetOutput("log")
+		require.NoError(t, err, "log command failed: %s", output)
+		require.Contains(t, output, "sibling-stack")
+	})
+
+	t.Run("log with --reverse flips the vertical order", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		s.RunCli("create", "feature-base", "-m", "feature-base").
+			RunCli("create", "feature-top", "-m", "feature-top")
+
+		output, err := s.RunCliAndGetOutput("log")
+		require.NoError(t, err, "log command failed: %s", output)
+		require.Less(t, strings.Index(output, "feature-top"), strings.Index(output, "feature-base"))
+		require.Less(t, strings.Index(output, "feature-base"), strings.Index(output, "main"))
+
+		output, err = s.RunCliAndGetOutput("log", "--reverse")
+		require.NoError(t, err, "log command failed: %s", output)
+		require.Less(t, strings.Index(output, "main"), strings.Index(output, "feature-base"))
+		require.Less(t, strings.Index(output, "feature-base"), strings.Index(output, "feature-top"))
+	})
+
+	t.Run("log with --use-trunk stops ancestry at the release branch", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		s.RunCli("create", "release/1.x", "-m", "release").
+			RunCli("create", "fix-base", "-m", "fix-base").
+			RunCli("create", "fix-top", "-m", "fix-top")
+
+		output, err := s.RunCliAndGetOutput("log", "--stack", "--use-trunk", "release/1.x")
+		require.NoError(t, err, "log command failed: %s", output)
+		require.Contains(t, output, "release/1.x")
+		require.Contains(t, output, "fix-base")
+		require.Contains(t, output, "fix-top")
+		require.NotContains(t, output, "main")
+
+		// The configured trunk is unchanged for later commands
+		output, err = s.RunCliAndGetOutput("log", "--stack")
+		require.NoError(t, err, "log command failed: %s", output)
+		require.Contains(t, output, "main")
+
+		output, err = s.RunCliAndGetOutput("log", "--use-trunk", "release/2.x")
+		require.Error(t, err)
+		require.Contains(t, output, "trunk override release/2.x is not a local branch")
+	})
+
+	t.Run("log --watch renders once when not in a terminal", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		s.RunCli("create", "feature", "-m", "feature")
+
+		output, err := s.RunCliAndGetOutput("log", "--watch", "--interval", "2s")
+		require.NoError(t, err, "log command failed: %s", output)
+		require.Equal(t, 1, strings.Count(output, "feature"), output)
+		require.Contains(t, output, "main")
+	})
+
+	t.Run("log --oneline-commits lists each branch's commits", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		s.RunGit("commit", "--allow-empty", "-m", "trunk work").
+			RunCli("create", "feature", "-m", "feature").
+			CommitChange("parser", "add parser").
+			CommitChange("lexer", "add lexer")
+
+		output, err := s.RunCliAndGetOutput("log", "--oneline-commits")
+		require.NoError(t, err, "log command failed: %s", output)
+		require.Contains(t, output, "add parser")
+		require.Contains(t, output, "add lexer")
+		require.Less(t, strings.Index(output, "add lexer"), strings.Index(output, "add parser"), "newest commit first")
+		require.NotContains(t, output, "trunk work", "trunk lists no commits")
+
+		output, err = s.RunCliAndGetOutput("log", "--oneline-commits", "--max-commits", "1")
+		require.NoError(t, err, "log command failed: %s", output)
+		require.Contains(t, output, "add lexer")
+		require.NotContains(t, output, "add parser")
+		require.Contains(t, output, "… 1 more")
+
+		output, err = s.RunCliAndGetOutput("log")
+		require.NoError(t, err, "log command failed: %s", output)
+		require.NotContains(t, output, "add lexer")
+	})
+
+	t.Run("log --interval must be a duration of at least a second", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+
+		output, err := s.RunCliAndGetOutput("log", "--watch", "--interval", "1m")
+		require.NoError(t, err, "log command failed: %s", output)
+
+		output, err = s.RunCliAndGetOutput("log", "--watch", "--interval", "500ms")
+		require.Error(t, err)
+		require.Contains(t, output, "--interval must be at least 1s")
+
+		output, err = s.RunCliAndGetOutput("log", "--watch", "--interval", "often")
+		require.Error(t, err)
+		require.Contains(t, output, "invalid argument \"often\" for \"--interval\"")
+	})
 }
@@ -22,6 +22,7 @@ Displays the name of the branch that is the parent of the current branch
 in the stack. This is useful for understanding the structure of your stack
 and seeing which branch the current branch is based on.`,
 		SilenceUsage: true,
+		Annotations:  map[string]string{common.AllowGitOperationAnnotation: ""},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return common.Run(cmd, func(ctx *runtime.Context) error {
 				// Get current branch
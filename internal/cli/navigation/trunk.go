@@ -29,6 +29,8 @@ func NewTrunkCmd() *cobra.Command {
 By default, displays the trunk branch that the current branch's stack is based on.
 Use --all to see all configured trunk branches, or --add to add an additional trunk.`,
 		SilenceUsage: true,
+		// Shows or configures trunk without touching the working tree
+		Annotations: map[string]string{common.AllowGitOperationAnnotation: ""},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return common.Run(cmd, func(ctx *runtime.Context) error {
 				// Handle --add flag
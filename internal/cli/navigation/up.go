@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"stackit.dev/stackit/internal/actions"
 	"stackit.dev/stackit/internal/cli/common"
 	"stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/runtime"
@@ -18,8 +19,9 @@ import (
 // NewUpCmd creates the up command
 func NewUpCmd() *cobra.Command {
 	var (
-		steps    int
-		toBranch string
+		steps     int
+		toBranch  string
+		autostash bool
 	)
 
 	cmd := &cobra.Command{
@@ -131,8 +133,8 @@ the --to flag is used to specify a target branch to navigate towards.`,
 
 				// Checkout the target branch
 				targetBranchObj := ctx.Engine.GetBranch(targetBranch)
-				if err := ctx.Engine.CheckoutBranch(ctx.Context, targetBranchObj); err != nil {
-					return fmt.Errorf("failed to checkout branch %s: %w", targetBranch, err)
+				if err := actions.CheckoutWithAutostash(ctx, targetBranchObj, autostashEnabled(ctx, autostash)); err != nil {
+					return err
 				}
 
 				ctx.Splog.Info("Checked out %s.", style.ColorBranchName(targetBranch, false))
@@ -144,6 +146,7 @@ the --to flag is used to specify a target branch to navigate towards.`,
 	// Add flags
 	cmd.Flags().IntVarP(&steps, "steps", "n", 1, "The number of levels to traverse upstack.")
 	cmd.Flags().StringVar(&toBranch, "to", "", "Target branch to navigate towards. When multiple children exist, selects the path leading to this branch.")
+	addAutostashFlag(cmd, &autostash)
 
 	_ = cmd.RegisterFlagCompletionFunc("to", common.CompleteBranches)
 
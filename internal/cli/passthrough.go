@@ -19,7 +19,6 @@ var gitCommandAllowlist = []string{
 	"cherry-pick",
 	"clean",
 	"clone",
-	"diff",
 	"difftool",
 	"fetch",
 	"format-patch",
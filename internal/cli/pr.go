@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/cli/common"
+	"stackit.dev/stackit/internal/runtime"
+)
+
+// newPrCmd creates the pr command
+func newPrCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pr",
+		Short: "Work with pull requests opened by others",
+	}
+
+	cmd.AddCommand(newPrCheckoutCmd())
+
+	return cmd
+}
+
+// newPrCheckoutCmd creates the pr checkout command
+func newPrCheckoutCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "checkout <number>",
+		Short: "Fetch a PR and track it with its stack",
+		Long: `Fetch a pull request's branch, track it onto the branch its PR is based on, and check it out.
+
+If the PR is based on another PR's branch that isn't tracked locally, that PR is checked
+out too, so reviewing a teammate's stacked PR sets up the whole stack below it. Branches
+from forks are named <owner>/<branch>. An existing local branch is only fast-forwarded.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			number, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+			if err != nil || number <= 0 {
+				return fmt.Errorf("invalid PR number: %s", args[0])
+			}
+			return common.RunLocked(cmd, func(ctx *runtime.Context) error {
+				return actions.PrCheckoutAction(ctx, actions.PrCheckoutOptions{Number: number})
+			})
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/cli/common"
+	"stackit.dev/stackit/internal/runtime"
+)
+
+// newRefreshCmd creates the refresh command
+func newRefreshCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "refresh",
+		Short: "Update stored PR states from GitHub",
+		Long: `Fetch the current PR of every tracked branch from GitHub and update the stored state,
+draft status and base, so log and branch cleanup see PRs merged or closed on GitHub.
+Unlike sync, it doesn't pull trunk, delete branches or restack.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return common.RunLocked(cmd, func(ctx *runtime.Context) error {
+				return actions.RefreshAction(ctx)
+			})
+		},
+	}
+
+	return cmd
+}
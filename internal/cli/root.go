@@ -6,8 +6,12 @@ import (
 	"github.com/spf13/cobra"
 
 	"stackit.dev/stackit/internal/cli/branch"
+	"stackit.dev/stackit/internal/cli/common"
 	"stackit.dev/stackit/internal/cli/navigation"
 	"stackit.dev/stackit/internal/cli/stack"
+	stackiterrors "stackit.dev/stackit/internal/errors"
+	"stackit.dev/stackit/internal/runtime"
+	"stackit.dev/stackit/internal/tui"
 )
 
 // NewRootCmd creates the root cobra command
@@ -25,18 +29,43 @@ Commit:  ` + commit + `
 		Date:    ` + date,
 	}
 
+	// Flag parsing errors are usage errors; tag them so they exit with the validation code
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return stackiterrors.WithCategory(stackiterrors.ErrValidation, err)
+	})
+	rootCmd.PersistentFlags().String("log-file", "", "Also write all output, including debug messages, to this file (or set STACKIT_LOG_FILE)")
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+		if logFile, _ := cmd.Flags().GetString("log-file"); logFile != "" {
+			tui.SetLogFilePath(logFile)
+		}
+		if trunk, _ := cmd.Flags().GetString("use-trunk"); trunk != "" {
+			runtime.SetTrunkOverride(trunk)
+		}
+		if noEditor, _ := cmd.Flags().GetBool("no-editor"); noEditor {
+			tui.SetEditorDisabled(true)
+		}
+		return common.CheckNoGitOperationInProgress(cmd)
+	}
+	rootCmd.PersistentFlags().String("use-trunk", "", "Use this branch as trunk for this command only, e.g. a release branch")
+	rootCmd.PersistentFlags().Bool("no-editor", false, "Never open an editor or prompt for PR metadata; use the defaults instead")
+	rootCmd.PersistentFlags().Bool(common.ForceUnlockFlag, false, "Remove the lock left by another stackit operation that is no longer running")
+
 	rootCmd.AddCommand(newAbortCmd())
 	rootCmd.AddCommand(branch.NewAbsorbCmd())
 	rootCmd.AddCommand(newAgentCmd())
 	rootCmd.AddCommand(navigation.NewBottomCmd())
+	rootCmd.AddCommand(branch.NewBranchCmd())
 	rootCmd.AddCommand(navigation.NewCheckoutCmd())
 	rootCmd.AddCommand(navigation.NewChildrenCmd())
 	rootCmd.AddCommand(newContinueCmd())
 	rootCmd.AddCommand(branch.NewCreateCmd())
 	rootCmd.AddCommand(newDebugCmd())
 	rootCmd.AddCommand(branch.NewDeleteCmd())
+	rootCmd.AddCommand(newDiffCmd())
 	rootCmd.AddCommand(newDoctorCmd())
 	rootCmd.AddCommand(navigation.NewDownCmd())
+	rootCmd.AddCommand(newExportGraphCmd())
+	rootCmd.AddCommand(newGCCmd())
 	rootCmd.AddCommand(branch.NewFoldCmd())
 	rootCmd.AddCommand(stack.NewForeachCmd())
 	rootCmd.AddCommand(newInfoCmd())
@@ -47,12 +76,16 @@ Commit:  ` + commit + `
 	rootCmd.AddCommand(stack.NewMoveCmd())
 	rootCmd.AddCommand(navigation.NewParentCmd())
 	rootCmd.AddCommand(branch.NewPopCmd())
+	rootCmd.AddCommand(newPrCmd())
+	rootCmd.AddCommand(branch.NewRebaseOntoRemoteCmd())
+	rootCmd.AddCommand(newRefreshCmd())
 	rootCmd.AddCommand(branch.NewRenameCmd())
 	rootCmd.AddCommand(stack.NewReorderCmd())
 	rootCmd.AddCommand(stack.NewRestackCmd())
 	rootCmd.AddCommand(branch.NewSplitCmd())
 	rootCmd.AddCommand(branch.NewSquashCmd())
 	rootCmd.AddCommand(newScopeCmd())
+	rootCmd.AddCommand(stack.NewStacksCmd())
 	rootCmd.AddCommand(stack.NewSubmitCmd())
 	rootCmd.AddCommand(stack.NewSyncCmd())
 	rootCmd.AddCommand(navigation.NewTopCmd())
@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 
 	"stackit.dev/stackit/internal/actions"
@@ -10,8 +12,10 @@ import (
 // newScopeCmd creates the scope command
 func newScopeCmd() *cobra.Command {
 	var (
+		set   string
 		unset bool
 		show  bool
+		stack bool
 	)
 
 	cmd := &cobra.Command{
@@ -23,7 +27,12 @@ explicit override for the current branch and all its descendants.
 
 To create a new branch with a scope, use 'stackit create --scope <name>'.
 
-Use 'none' or 'clear' as the scope name to explicitly break the inheritance chain.`,
+Use 'none' or 'clear' as the scope name to explicitly break the inheritance chain.
+
+Use --stack to also write the scope to every descendant that doesn't have an explicit
+scope of its own, e.g. after a project key changes:
+
+  stackit scope --set PROJ-9 --stack`,
 		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -33,8 +42,11 @@ Use 'none' or 'clear' as the scope name to explicitly break the inheritance chai
 				return err
 			}
 
-			var scope string
+			scope := set
 			if len(args) > 0 {
+				if set != "" && set != args[0] {
+					return fmt.Errorf("scope given both as an argument and with --set")
+				}
 				scope = args[0]
 			}
 
@@ -46,12 +58,15 @@ Use 'none' or 'clear' as the scope name to explicitly break the inheritance chai
 				Scope: scope,
 				Unset: unset,
 				Show:  show,
+				Stack: stack,
 			}
 
 			return actions.ScopeAction(ctx, opts)
 		},
 	}
 
+	cmd.Flags().StringVar(&set, "set", "", "Set the scope (alternative to passing it as an argument)")
+	cmd.Flags().BoolVar(&stack, "stack", false, "Also apply the scope to descendants that don't have an explicit scope")
 	cmd.Flags().BoolVar(&unset, "unset", false, "Remove the explicit scope override from the current branch")
 	cmd.Flags().BoolVar(&show, "show", false, "Show the current scope for this branch")
 
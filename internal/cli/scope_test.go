@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
 )
 
 func TestScopeCommand(t *testing.T) {
@@ -75,4 +76,28 @@ func TestScopeCommand(t *testing.T) {
 		require.Error(t, err, "scope show should fail on trunk")
 		require.Contains(t, string(output), "not on a branch")
 	})
+
+	t.Run("scope --set --stack applies to descendants without an override", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		s.RunCli("init")
+		require.NoError(t, s.Scene.Repo.CreateChange("a", "a", false))
+		s.RunCli("create", "a", "-m", "a", "--scope", "PROJ-1")
+		require.NoError(t, s.Scene.Repo.CreateChange("b", "b", false))
+		s.RunCli("create", "b", "-m", "b")
+		require.NoError(t, s.Scene.Repo.CreateChange("c", "c", false))
+		s.RunCli("create", "c", "-m", "c", "--scope", "OTHER-1")
+
+		s.RunCli("checkout", "a").
+			RunCli("scope", "--set", "PROJ-9", "--stack")
+
+		s.RunCli("checkout", "b")
+		output, err := s.RunCliAndGetOutput("scope", "--show")
+		require.NoError(t, err, output)
+		require.Contains(t, output, "explicit scope: PROJ-9")
+		s.RunCli("checkout", "c")
+		output, err = s.RunCliAndGetOutput("scope", "--show")
+		require.NoError(t, err, output)
+		require.Contains(t, output, "explicit scope: OTHER-1")
+	})
 }
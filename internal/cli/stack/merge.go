@@ -3,13 +3,14 @@ package stack
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/spf13/cobra"
 
 	"stackit.dev/stackit/internal/actions/merge"
+	"stackit.dev/stackit/internal/cli/common"
 	"stackit.dev/stackit/internal/config"
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui"
 	"stackit.dev/stackit/internal/tui/components/tree"
@@ -19,12 +20,15 @@ import (
 // NewMergeCmd creates the merge command
 func NewMergeCmd() *cobra.Command {
 	var (
-		dryRun   bool
-		yes      bool
-		force    bool
-		strategy string
-		worktree bool
-		scope    string
+		dryRun     bool
+		yes        bool
+		force      bool
+		strategy   string
+		worktree   bool
+		scope      string
+		onlyReady  bool
+		allowDirty bool
+		branch     string
 	)
 
 	cmd := &cobra.Command{
@@ -35,6 +39,13 @@ This command merges PRs for all branches in the stack from trunk up to (and incl
 
 If --scope is specified, all branches with that scope will be merged.
 
+If --only-ready is specified, PRs are merged from the bottom of the stack up only while
+they are ready (open, not a draft, and CI passing). The first branch that isn't ready
+and everything above it are skipped and restacked instead.
+
+With --branch, the stack from trunk up to the given branch is merged instead, without
+checking the branch out. This is useful for scripted merges.
+
 If no flags or arguments are provided, an interactive wizard will guide you through the merge process.`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -44,46 +55,62 @@ If no flags or arguments are provided, an interactive wizard will guide you thro
 				return err
 			}
 
+			unlock, err := common.LockRepo(cmd, ctx)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+
+			// Get config values
+			cfg, _ := config.LoadConfig(ctx.RepoRoot)
+			undoStackDepth := cfg.UndoStackDepth()
+			requireCleanStatus := cfg.MergeRequireCleanStatus() && !allowDirty
+
 			// Handle 'stackit merge this'
 			if len(args) > 0 && args[0] == "this" {
-				return runInteractiveMergeWizard(ctx, dryRun, force, "")
+				if err := checkCleanBeforeWizard(ctx, requireCleanStatus, dryRun, worktree); err != nil {
+					return err
+				}
+				return runInteractiveMergeWizard(ctx, dryRun, force, allowDirty, "")
 			}
 
 			// Determine if we should run in interactive mode
 			// Interactive if no flags are provided (except dry-run and scope which are always allowed)
-			interactive := strategy == "" && !yes && !force && scope == "" && len(args) == 0
+			interactive := strategy == "" && !yes && !force && !onlyReady && scope == "" && branch == "" && len(args) == 0
+			if branch != "" && (scope != "" || len(args) > 0) {
+				return stackiterrors.NewValidationError("--branch can't be combined with --scope or 'this'")
+			}
 
-			// Parse strategy
+			// Parse strategy, falling back to merge.strategy
 			var mergeStrategy merge.Strategy
 			if strategy != "" {
-				switch strings.ToLower(strategy) {
-				case "bottom-up", "bottomup":
-					mergeStrategy = merge.StrategyBottomUp
-				case "top-down", "topdown":
-					mergeStrategy = merge.StrategyTopDown
-				case "consolidate":
-					mergeStrategy = merge.StrategyConsolidate
-				default:
-					return fmt.Errorf("invalid strategy: %s (must be 'bottom-up', 'top-down', or 'consolidate')", strategy)
+				mergeStrategy, err = merge.ParseStrategy(strategy)
+				if err != nil {
+					return stackiterrors.WithCategory(stackiterrors.ErrValidation, err)
+				}
+			} else if configured := cfg.MergeStrategy(); configured != "" {
+				mergeStrategy, err = merge.ParseStrategy(configured)
+				if err != nil {
+					return stackiterrors.WithCategory(stackiterrors.ErrValidation, fmt.Errorf("merge.strategy: %w", err))
 				}
 			}
 
 			// Run interactive wizard if needed
 			if interactive {
-				return runMergeTypeSelector(ctx, dryRun, force)
+				if err := checkCleanBeforeWizard(ctx, requireCleanStatus, dryRun, worktree); err != nil {
+					return err
+				}
+				return runMergeTypeSelector(ctx, dryRun, force, allowDirty)
 			}
 
-			// Get config values
-			cfg, _ := config.LoadConfig(ctx.RepoRoot)
-			undoStackDepth := cfg.UndoStackDepth()
-
 			// Create plan if scope is specified
 			var plan *merge.Plan
 			if scope != "" {
 				p, _, err := merge.CreateMergePlan(ctx.Context, ctx.Engine, ctx.Splog, ctx.GitHubClient, merge.CreatePlanOptions{
-					Strategy: mergeStrategy,
-					Force:    force,
-					Scope:    scope,
+					Strategy:  mergeStrategy,
+					Force:     force,
+					Scope:     scope,
+					OnlyReady: onlyReady,
 				})
 				if err != nil {
 					return err
@@ -93,34 +120,49 @@ If no flags or arguments are provided, an interactive wizard will guide you thro
 
 			// Run merge action
 			return merge.Action(ctx, merge.Options{
-				DryRun:         dryRun,
-				Confirm:        !yes, // If --yes is set, don't confirm
-				Strategy:       mergeStrategy,
-				Force:          force,
-				UseWorktree:    worktree,
-				Plan:           plan,
-				UndoStackDepth: undoStackDepth,
+				DryRun:             dryRun,
+				Confirm:            !yes, // If --yes is set, don't confirm
+				Strategy:           mergeStrategy,
+				Force:              force,
+				OnlyReady:          onlyReady,
+				UseWorktree:        worktree,
+				Plan:               plan,
+				UndoStackDepth:     undoStackDepth,
+				RequireCleanStatus: requireCleanStatus,
+				BranchName:         branch,
 			})
 		},
 	}
 
-	cmd.Flags().StringVar(&strategy, "strategy", "", "Merge strategy: 'bottom-up' (merge each PR from bottom), 'top-down' (squash into one PR), or 'consolidate' (single atomic merge). Interactive if not specified.")
+	cmd.Flags().StringVar(&branch, "branch", "", "Merge the stack up to this branch instead of the current branch, without checking it out")
+	cmd.Flags().StringVar(&strategy, "strategy", "", "Merge strategy: 'bottom-up' (merge each PR from bottom), 'top-down' (squash into one PR), or 'consolidate' (single atomic merge). Defaults to the merge.strategy config value, or interactive if neither is set.")
 	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompt")
 	cmd.Flags().BoolVar(&force, "force", false, "Skip validation checks (draft PRs, failing CI)")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show merge plan without executing")
 	cmd.Flags().BoolVar(&worktree, "worktree", false, "Execute the merge and restack in a temporary worktree to avoid interfering with current branch")
 	cmd.Flags().StringVar(&scope, "scope", "", "Bulk-merge all branches within the specified scope")
+	cmd.Flags().BoolVar(&allowDirty, "allow-dirty", false, "Merge even with uncommitted or untracked changes (overrides merge.requireCleanStatus)")
+	cmd.Flags().BoolVar(&onlyReady, "only-ready", false, "Merge only the bottom PRs that are ready (CI passing), stopping at the first that isn't")
 
 	return cmd
 }
 
+// checkCleanBeforeWizard runs the merge.requireCleanStatus check up front, so the interactive
+// wizard doesn't ask every question only for the merge to be refused at the end
+func checkCleanBeforeWizard(ctx *runtime.Context, requireCleanStatus, dryRun, worktree bool) error {
+	if !requireCleanStatus || dryRun || worktree {
+		return nil
+	}
+	return merge.CheckCleanStatus(ctx)
+}
+
 // runInteractiveMergeWizard runs the interactive merge wizard
-func runInteractiveMergeWizard(ctx *runtime.Context, dryRun bool, forceFlag bool, scope string) error {
-	return runInteractiveMergeWizardForBranch(ctx, dryRun, forceFlag, scope, "")
+func runInteractiveMergeWizard(ctx *runtime.Context, dryRun bool, forceFlag bool, allowDirty bool, scope string) error {
+	return runInteractiveMergeWizardForBranch(ctx, dryRun, forceFlag, allowDirty, scope, "")
 }
 
 // runInteractiveMergeWizardForBranch runs the interactive merge wizard for a specific branch (if scope is empty)
-func runInteractiveMergeWizardForBranch(ctx *runtime.Context, dryRun bool, forceFlag bool, scope string, targetBranchName string) error {
+func runInteractiveMergeWizardForBranch(ctx *runtime.Context, dryRun bool, forceFlag bool, allowDirty bool, scope string, targetBranchName string) error {
 	eng := ctx.Engine
 	splog := ctx.Splog
 
@@ -223,10 +265,20 @@ func runInteractiveMergeWizardForBranch(ctx *runtime.Context, dryRun bool, force
 		splog.Newline()
 	}
 
+	cfg, _ := config.LoadConfig(ctx.RepoRoot)
+
 	// Determine merge strategy
 	var mergeStrategy merge.Strategy
-	// If only a single PR, automatically use top-down strategy
-	if len(plan.BranchesToMerge) == 1 {
+	if configured := cfg.MergeStrategy(); configured != "" {
+		parsed, err := merge.ParseStrategy(configured)
+		if err != nil {
+			return stackiterrors.WithCategory(stackiterrors.ErrValidation, fmt.Errorf("merge.strategy: %w", err))
+		}
+		mergeStrategy = parsed
+		splog.Info("✅ Strategy: %s (from merge.strategy)", mergeStrategy)
+		splog.Newline()
+	} else if len(plan.BranchesToMerge) == 1 {
+		// If only a single PR, automatically use top-down strategy
 		mergeStrategy = merge.StrategyTopDown
 		splog.Info("✅ Strategy: %s (auto-selected for single PR)", mergeStrategy)
 		splog.Newline()
@@ -303,19 +355,16 @@ func runInteractiveMergeWizardForBranch(ctx *runtime.Context, dryRun bool, force
 		return fmt.Errorf("worktree confirmation canceled: %w", err)
 	}
 
-	// Get config values
-	cfg, _ := config.LoadConfig(ctx.RepoRoot)
-	undoStackDepth := cfg.UndoStackDepth()
-
 	// Execute the plan
 	mergeOpts := merge.Options{
-		DryRun:         dryRun,
-		Confirm:        false, // Already confirmed
-		Strategy:       mergeStrategy,
-		Force:          forceFlag,
-		UseWorktree:    useWorktree,
-		Plan:           plan,
-		UndoStackDepth: undoStackDepth,
+		DryRun:             dryRun,
+		Confirm:            false, // Already confirmed
+		Strategy:           mergeStrategy,
+		Force:              forceFlag,
+		UseWorktree:        useWorktree,
+		Plan:               plan,
+		UndoStackDepth:     cfg.UndoStackDepth(),
+		RequireCleanStatus: cfg.MergeRequireCleanStatus() && !allowDirty,
 	}
 
 	if err := merge.Action(ctx, mergeOpts); err != nil {
@@ -326,7 +375,7 @@ func runInteractiveMergeWizardForBranch(ctx *runtime.Context, dryRun bool, force
 }
 
 // runMergeTypeSelector runs an interactive selector to choose what to merge
-func runMergeTypeSelector(ctx *runtime.Context, dryRun bool, force bool) error {
+func runMergeTypeSelector(ctx *runtime.Context, dryRun bool, force bool, allowDirty bool) error {
 	eng := ctx.Engine
 
 	options := []tui.SelectOption{
@@ -342,7 +391,7 @@ func runMergeTypeSelector(ctx *runtime.Context, dryRun bool, force bool) error {
 
 	switch selected {
 	case "this":
-		return runInteractiveMergeWizard(ctx, dryRun, force, "")
+		return runInteractiveMergeWizard(ctx, dryRun, force, allowDirty, "")
 	case "scope":
 		// Get all unique scopes
 		scopes := make(map[string]bool)
@@ -367,7 +416,7 @@ func runMergeTypeSelector(ctx *runtime.Context, dryRun bool, force bool) error {
 			return err
 		}
 
-		return runInteractiveMergeWizard(ctx, dryRun, force, selectedScope)
+		return runInteractiveMergeWizard(ctx, dryRun, force, allowDirty, selectedScope)
 
 	case "stack":
 		// Get all leaf branches (branches with no children)
@@ -389,7 +438,7 @@ func runMergeTypeSelector(ctx *runtime.Context, dryRun bool, force bool) error {
 			return err
 		}
 
-		return runInteractiveMergeWizardForBranch(ctx, dryRun, force, "", selectedBranch)
+		return runInteractiveMergeWizardForBranch(ctx, dryRun, force, allowDirty, "", selectedBranch)
 	}
 
 	return nil
@@ -0,0 +1,118 @@
+package stack_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
+)
+
+func TestMergeCommand(t *testing.T) {
+	t.Parallel()
+	binaryPath := testhelpers.GetSharedBinaryPath()
+	if binaryPath == "" {
+		if err := testhelpers.GetBinaryError(); err != nil {
+			t.Fatalf("failed to build stackit binary: %v", err)
+		}
+		t.Fatal("stackit binary not built")
+	}
+
+	t.Run("merge rejects an invalid merge.strategy in the repo config", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		s.RunCli("create", "a", "-m", "a")
+
+		// Hand-edit the config, bypassing the validation in `config set`
+		configPath := filepath.Join(s.Scene.Dir, ".git", ".stackit_config")
+		cfg := map[string]any{}
+		if data, err := os.ReadFile(configPath); err == nil {
+			require.NoError(t, json.Unmarshal(data, &cfg))
+		}
+		cfg["merge.strategy"] = "sideways"
+		data, err := json.Marshal(cfg)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(configPath, data, 0600))
+
+		output, err := s.RunCliAndGetOutput("merge", "--yes", "--dry-run")
+		require.Error(t, err)
+		require.Contains(t, output, "merge.strategy: invalid strategy: sideways")
+	})
+
+	t.Run("merge refuses a dirty tree before starting the interactive wizard", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		s.RunCli("create", "a", "-m", "a")
+		require.NoError(t, s.Scene.Repo.CreateChange("dirty", "dirty", true))
+
+		output, err := s.RunCliAndGetOutput("merge")
+		require.Error(t, err)
+		require.Contains(t, output, "cannot merge with uncommitted or untracked changes")
+		require.NotContains(t, output, "What would you like to merge")
+	})
+}
+
+func TestMergeBranchCommand(t *testing.T) {
+	binaryPath := testhelpers.GetSharedBinaryPath()
+	if binaryPath == "" {
+		if err := testhelpers.GetBinaryError(); err != nil {
+			t.Fatalf("failed to build stackit binary: %v", err)
+		}
+		t.Fatal("stackit binary not built")
+	}
+
+	t.Run("merge --branch plans the stack of a branch that isn't checked out", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithBinaryPath(binaryPath).
+			WithStack(map[string]string{
+				"a": "main",
+				"b": "a",
+				"c": "b",
+			}).
+			Checkout("main")
+		for number, branch := range map[int]string{101: "a", 102: "b", 103: "c"} {
+			require.NoError(t, s.Engine.UpsertPrInfo(s.Engine.GetBranch(branch), testhelpers.NewTestPrInfo(number)))
+		}
+
+		output, err := s.RunCliAndGetOutput("merge", "--branch", "b", "--dry-run", "--strategy", "bottom-up")
+		require.NoError(t, err, "merge --branch failed: %s", output)
+
+		require.Contains(t, output, "Current Branch: b")
+		require.Contains(t, output, "#101")
+		require.Contains(t, output, "#102")
+		require.NotContains(t, output, "#103")
+		s.ExpectBranch("main")
+	})
+
+	t.Run("merge --branch rejects a branch without a PR", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithBinaryPath(binaryPath).
+			WithStack(map[string]string{
+				"a": "main",
+				"b": "a",
+			}).
+			Checkout("main")
+
+		output, err := s.RunCliAndGetOutput("merge", "--branch", "b", "--dry-run")
+		require.Error(t, err)
+		require.Contains(t, output, "branch b has no PR")
+	})
+
+	t.Run("merge --branch rejects an untracked branch", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithBinaryPath(binaryPath).
+			WithStack(map[string]string{
+				"a": "main",
+			}).
+			Checkout("main").
+			RunGit("branch", "untracked")
+
+		output, err := s.RunCliAndGetOutput("merge", "--branch", "untracked", "--dry-run")
+		require.Error(t, err)
+		require.Contains(t, output, "branch untracked is not tracked by stackit")
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"stackit.dev/stackit/internal/actions/move"
 	"stackit.dev/stackit/internal/cli/common"
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/runtime"
 	"stackit.dev/stackit/internal/tui"
 	"stackit.dev/stackit/internal/tui/style"
@@ -36,7 +37,7 @@ If no branch is passed in, opens an interactive selector to choose the target br
 				if sourceBranch == "" {
 					currentBranch := ctx.Engine.CurrentBranch()
 					if currentBranch == nil {
-						return fmt.Errorf("not on a branch and no source branch specified")
+						return fmt.Errorf("%w and no source branch specified", stackiterrors.ErrNotOnBranch)
 					}
 					sourceBranch = currentBranch.GetName()
 				}
@@ -7,17 +7,26 @@ import (
 	"github.com/spf13/cobra"
 
 	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/cli/common"
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/runtime"
 )
 
 // NewRestackCmd creates the restack command
 func NewRestackCmd() *cobra.Command {
 	var (
-		branch    string
-		downstack bool
-		only      bool
-		upstack   bool
+		branch        string
+		downstack     bool
+		only          bool
+		upstack       bool
+		preserveDates bool
+		keepEmpty     bool
+		stat          bool
+		preview       bool
+		mergetool     bool
+		abort         bool
+		dryRun        bool
 	)
 
 	cmd := &cobra.Command{
@@ -41,6 +50,9 @@ If conflicts are encountered, you will be prompted to resolve them via an intera
 			if scopeFlags > 1 {
 				return fmt.Errorf("only one of --downstack, --only, or --upstack can be specified")
 			}
+			if dryRun && !only {
+				return fmt.Errorf("--dry-run can only be used with --only")
+			}
 
 			// Get context (demo or real)
 			ctx, err := runtime.GetContext(cmd.Context())
@@ -48,12 +60,23 @@ If conflicts are encountered, you will be prompted to resolve them via an intera
 				return err
 			}
 
+			unlock, err := common.LockRepo(cmd, ctx)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+
+			// A stopped restack may leave HEAD detached, so this comes before resolving the branch
+			if abort {
+				return actions.AbortAction(ctx, actions.AbortOptions{Force: true})
+			}
+
 			// Determine target branch
 			targetBranch := branch
 			if targetBranch == "" {
 				currentBranch := ctx.Engine.CurrentBranch()
 				if currentBranch == nil {
-					return fmt.Errorf("not on a branch and --branch not specified")
+					return fmt.Errorf("%w and --branch not specified", stackiterrors.ErrNotOnBranch)
 				}
 				targetBranch = currentBranch.GetName()
 			}
@@ -65,10 +88,22 @@ If conflicts are encountered, you will be prompted to resolve them via an intera
 				RecursiveChildren: !only && !downstack, // Default or upstack
 			}
 
+			// Only override git's handling of empty commits when asked to
+			var keepEmptyOpt *bool
+			if cmd.Flags().Changed("keep-empty") {
+				keepEmptyOpt = &keepEmpty
+			}
+
 			// Run restack action
 			return actions.RestackAction(ctx, actions.RestackOptions{
-				BranchName: targetBranch,
-				Scope:      rng,
+				BranchName:    targetBranch,
+				Scope:         rng,
+				PreserveDates: preserveDates,
+				KeepEmpty:     keepEmptyOpt,
+				Stat:          stat,
+				Preview:       preview,
+				Mergetool:     mergetool,
+				DryRun:        dryRun,
 			})
 		},
 	}
@@ -77,6 +112,14 @@ If conflicts are encountered, you will be prompted to resolve them via an intera
 	cmd.Flags().BoolVar(&downstack, "downstack", false, "Only restack this branch and its ancestors.")
 	cmd.Flags().BoolVar(&only, "only", false, "Only restack this branch.")
 	cmd.Flags().BoolVar(&upstack, "upstack", false, "Only restack this branch and its descendants.")
+	cmd.Flags().BoolVar(&preserveDates, "preserve-dates", false, "Keep each rewritten commit's committer date equal to its author date. Defaults to the restack.preserveDates config.")
+	cmd.Flags().BoolVar(&keepEmpty, "keep-empty", true, "Keep commits that become empty while rebasing. With --keep-empty=false they are dropped, so a branch whose changes are already in its parent ends up empty. Defaults to git's behavior.")
+
+	cmd.Flags().BoolVar(&stat, "stat", false, "Print a summary table of which branches moved, were already up to date, or hit a conflict.")
+	cmd.Flags().BoolVar(&preview, "preview", false, "Predict which branches would hit a conflict without restacking anything or touching the working tree.")
+	cmd.Flags().BoolVar(&mergetool, "mergetool", false, "On a conflict, launch git's merge.tool and continue the restack once every file is resolved. Ignored when not interactive.")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "With --only, report whether the branch needs restacking and onto which commit, without restacking it.")
+	cmd.Flags().BoolVar(&abort, "abort", false, "Cancel a restack stopped by a conflict, like stackit abort --force, putting back the branches it already moved.")
 
 	return cmd
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
 )
 
 func TestRestackCommand(t *testing.T) {
@@ -157,6 +158,211 @@ func TestRestackCommand(t *testing.T) {
 		require.Contains(t, string(output), "does not need to be restacked", "branch should not need restacking")
 	})
 
+	t.Run("restack --only --dry-run reports whether the branch needs restacking", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		require.NoError(t, s.Scene.Repo.CreateChange("feature change", "test", false))
+		s.RunCli("create", "feature", "-m", "feature change")
+
+		output, err := s.RunCliAndGetOutput("restack", "--only", "--dry-run")
+		require.NoError(t, err, "restack --only --dry-run failed: %s", output)
+		require.Contains(t, output, "feature is up to date with main")
+
+		s.RunGit("checkout", "main")
+		require.NoError(t, s.Scene.Repo.CreateChangeAndCommit("main change", "main"))
+		mainRev, err := s.Scene.Repo.RunGitCommandAndGetOutput("rev-parse", "--short=7", "main")
+		require.NoError(t, err)
+		s.RunGit("checkout", "feature")
+		featureBefore, err := s.Scene.Repo.GetRevision("feature")
+		require.NoError(t, err)
+
+		output, err = s.RunCliAndGetOutput("restack", "--only", "--dry-run")
+		require.NoError(t, err, "restack --only --dry-run failed: %s", output)
+		require.Contains(t, output, "Would restack feature onto main ("+mainRev+")")
+
+		featureAfter, err := s.Scene.Repo.GetRevision("feature")
+		require.NoError(t, err)
+		require.Equal(t, featureBefore, featureAfter, "dry run should not restack feature")
+
+		output, err = s.RunCliAndGetOutput("restack", "--dry-run")
+		require.Error(t, err)
+		require.Contains(t, output, "--dry-run can only be used with --only")
+	})
+
+	t.Run("restack --stat summarizes which branches moved", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, func(s *testhelpers.Scene) error {
+			if err := s.Repo.CreateChangeAndCommit("initial", "init"); err != nil {
+				return err
+			}
+			for _, name := range []string{"branch1", "branch2", "branch3"} {
+				if err := s.Repo.CreateChange(name+" change", name, false); err != nil {
+					return err
+				}
+				cmd := exec.Command(binaryPath, "create", name, "-m", name+" change")
+				cmd.Dir = s.Dir
+				if err := cmd.Run(); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+		// Add a commit to branch2 outside of stackit so only branch3 needs restacking
+		require.NoError(t, scene.Repo.CheckoutBranch("branch2"))
+		require.NoError(t, scene.Repo.CreateChangeAndCommit("branch2 follow-up", "branch2-extra"))
+		require.NoError(t, scene.Repo.CheckoutBranch("branch1"))
+
+		cmd := exec.Command(binaryPath, "restack", "--stat")
+		cmd.Dir = scene.Dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "restack command failed: %s", string(output))
+
+		require.Contains(t, string(output), "Restack summary:")
+		require.Regexp(t, `branch1\s+Unneeded`, string(output))
+		require.Regexp(t, `branch2\s+Unneeded`, string(output))
+		require.Regexp(t, `branch3\s+Done\s+[0-9a-f]{7} → [0-9a-f]{7}`, string(output))
+		require.NotContains(t, string(output), "Restacked branch3")
+	})
+
+	t.Run("restack runs restack.postHook on each restacked branch", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		for _, name := range []string{"branch1", "branch2", "branch3"} {
+			require.NoError(t, s.Scene.Repo.CreateChange(name+" change", name, false))
+			s.RunCli("create", name, "-m", name+" change")
+		}
+		s.RunGit("checkout", "main")
+		require.NoError(t, s.Scene.Repo.CreateChangeAndCommit("main change", "main"))
+
+		// The hook commits a file naming the branch it ran on
+		s.RunCli("config", "set", "restack.postHook",
+			`echo "$STACKIT_BRANCH" > hook-output && git add hook-output && git commit -qm "hook on $STACKIT_BRANCH"`)
+
+		s.RunGit("checkout", "branch3")
+		output, err := s.RunCliAndGetOutput("restack")
+		require.NoError(t, err, "restack command failed: %s", output)
+
+		for _, name := range []string{"branch1", "branch2", "branch3"} {
+			content, err := s.Scene.Repo.RunGitCommandAndGetOutput("show", name+":hook-output")
+			require.NoError(t, err, "hook output missing on %s", name)
+			require.Equal(t, name, content)
+		}
+
+		// A failing hook stops the restack before the next branch
+		s.RunCli("config", "set", "restack.postHook", `test "$STACKIT_BRANCH" != branch2`)
+		s.RunGit("checkout", "main")
+		require.NoError(t, s.Scene.Repo.CreateChangeAndCommit("another main change", "main2"))
+		branch3Before, err := s.Scene.Repo.GetRevision("branch3")
+		require.NoError(t, err)
+
+		s.RunGit("checkout", "branch3")
+		output, err = s.RunCliAndGetOutput("restack")
+		require.Error(t, err, "restack should fail when the hook fails: %s", output)
+		require.Contains(t, output, "post-restack hook failed on branch2")
+
+		branch3After, err := s.Scene.Repo.GetRevision("branch3")
+		require.NoError(t, err)
+		require.Equal(t, branch3Before, branch3After, "branch3 should not be restacked after the hook failed")
+
+		// Once the hook passes, continue restacks the remaining branches
+		s.RunCli("config", "unset", "restack.postHook")
+		output, err = s.RunCliAndGetOutput("continue")
+		require.NoError(t, err, "continue failed: %s", output)
+
+		branch3After, err = s.Scene.Repo.GetRevision("branch3")
+		require.NoError(t, err)
+		require.NotEqual(t, branch3Before, branch3After, "continue should restack branch3")
+		_, err = os.Stat(filepath.Join(s.Scene.Dir, ".git", ".stackit_continue"))
+		require.True(t, os.IsNotExist(err), "continuation state should be cleared")
+	})
+
+	t.Run("restack --preview predicts conflicts without restacking", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, func(s *testhelpers.Scene) error {
+			if err := s.Repo.CreateChangeAndCommit("initial", "init"); err != nil {
+				return err
+			}
+			for _, name := range []string{"branch1", "branch2"} {
+				if err := s.Repo.CreateChange(name+" change", name, false); err != nil {
+					return err
+				}
+				cmd := exec.Command(binaryPath, "create", name, "-m", name+" change")
+				cmd.Dir = s.Dir
+				if err := cmd.Run(); err != nil {
+					return err
+				}
+			}
+			if err := s.Repo.CheckoutBranch("main"); err != nil {
+				return err
+			}
+			// Conflicts with branch2's change to the same file
+			return s.Repo.CreateChangeAndCommit("main change", "branch2")
+		})
+
+		require.NoError(t, scene.Repo.CheckoutBranch("branch2"))
+		branch2Before, err := scene.Repo.RunGitCommandAndGetOutput("rev-parse", "branch2")
+		require.NoError(t, err)
+
+		cmd := exec.Command(binaryPath, "restack", "--preview")
+		cmd.Dir = scene.Dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "restack --preview failed: %s", output)
+		require.Contains(t, string(output), "Restack preview:")
+		require.Regexp(t, `branch1\s+clean`, string(output))
+		require.Regexp(t, `branch2\s+conflict: branch2_test.txt`, string(output))
+
+		branch2After, err := scene.Repo.RunGitCommandAndGetOutput("rev-parse", "branch2")
+		require.NoError(t, err)
+		require.Equal(t, branch2Before, branch2After, "preview should not restack branch2")
+	})
+
+	t.Run("restack --abort restores the branches a conflicted restack moved", func(t *testing.T) {
+		t.Parallel()
+		s := scenario.NewScenarioParallel(t, testhelpers.BasicSceneSetup).WithBinaryPath(binaryPath)
+		require.NoError(t, s.Scene.Repo.CreateChange("branch1 change", "branch1", false))
+		s.RunCli("create", "branch1", "-a", "-m", "branch1 change")
+		require.NoError(t, s.Scene.Repo.CreateChange("branch2 change", "branch2", false))
+		s.RunCli("create", "branch2", "-a", "-m", "branch2 change").
+			RunCli("checkout", "main")
+		// Conflicts with branch2's change to the same file
+		require.NoError(t, s.Scene.Repo.CreateChangeAndCommit("main change", "branch2"))
+		s.RunCli("checkout", "branch2")
+
+		branch1Before, err := s.Scene.Repo.GetRevision("branch1")
+		require.NoError(t, err)
+		branch2Before, err := s.Scene.Repo.GetRevision("branch2")
+		require.NoError(t, err)
+
+		output, err := s.RunCliAndGetOutput("restack")
+		require.Error(t, err, "restack should stop on the conflict: %s", output)
+		branch1Stopped, err := s.Scene.Repo.GetRevision("branch1")
+		require.NoError(t, err)
+		require.NotEqual(t, branch1Before, branch1Stopped, "branch1 should have been restacked")
+
+		output, err = s.RunCliAndGetOutput("restack", "--abort")
+		require.NoError(t, err, "restack --abort failed: %s", output)
+		require.Contains(t, output, "Successfully aborted and restored repository state.")
+
+		s.ExpectBranch("branch2")
+		branch1After, err := s.Scene.Repo.GetRevision("branch1")
+		require.NoError(t, err)
+		require.Equal(t, branch1Before, branch1After)
+		branch2After, err := s.Scene.Repo.GetRevision("branch2")
+		require.NoError(t, err)
+		require.Equal(t, branch2Before, branch2After)
+		status, err := s.Scene.Repo.RunGitCommandAndGetOutput("status", "--porcelain")
+		require.NoError(t, err)
+		require.Empty(t, status)
+		_, err = os.Stat(filepath.Join(s.Scene.Dir, ".git", ".stackit_continue"))
+		require.True(t, os.IsNotExist(err), "continuation state should be cleared")
+
+		// A second abort has nothing to do
+		output, err = s.RunCliAndGetOutput("restack", "--abort")
+		require.NoError(t, err, "restack --abort failed: %s", output)
+		require.Contains(t, output, "No operation in progress to abort.")
+	})
+
 	t.Run("restack with downstack flag", func(t *testing.T) {
 		t.Parallel()
 		scene := testhelpers.NewSceneParallel(t, func(s *testhelpers.Scene) error {
@@ -0,0 +1,27 @@
+package stack
+
+import (
+	"github.com/spf13/cobra"
+
+	"stackit.dev/stackit/internal/actions"
+	"stackit.dev/stackit/internal/cli/common"
+)
+
+// NewStacksCmd creates the stacks command
+func NewStacksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stacks",
+		Short: "List the independent stacks branching off trunk",
+		Long: `List the independent stacks branching off trunk.
+
+Each of trunk's children starts a stack. For each one, shows how many branches it has
+and the branches at its tips.`,
+		SilenceUsage: true,
+		Annotations:  map[string]string{common.AllowGitOperationAnnotation: ""},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return common.Run(cmd, actions.StacksAction)
+		},
+	}
+
+	return cmd
+}
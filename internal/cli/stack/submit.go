@@ -8,18 +8,27 @@ import (
 	"stackit.dev/stackit/internal/cli/common"
 	"stackit.dev/stackit/internal/config"
 	_ "stackit.dev/stackit/internal/demo" // Register demo engine factory
+	stackiterrors "stackit.dev/stackit/internal/errors"
+	"stackit.dev/stackit/internal/github"
 	"stackit.dev/stackit/internal/runtime"
 )
 
 type submitFlags struct {
 	branch               string
 	stack                bool
+	stackFromTrunk       bool
 	force                bool
 	dryRun               bool
 	confirm              bool
 	updateOnly           bool
+	descriptionsOnly     bool
 	always               bool
+	since                string
+	template             string
+	maxPRs               int
+	fill                 bool
 	restack              bool
+	noRestackCheck       bool
 	draft                bool
 	publish              bool
 	edit                 bool
@@ -30,27 +39,44 @@ type submitFlags struct {
 	noEditDescription    bool
 	reviewers            string
 	teamReviewers        string
+	copyReviewersFrom    string
+	labels               []string
+	replaceLabels        bool
+	dependentLabels      bool
+	milestone            string
 	mergeWhenReady       bool
+	autoMerge            string
 	rerequestReview      bool
 	view                 bool
 	web                  bool
 	comment              string
+	commentOnce          bool
 	targetTrunk          string
 	ignoreOutOfSyncTrunk bool
+	noVerify             bool
+	noFooter             bool
+	stripFooter          bool
 	cli                  bool
 }
 
 func addSubmitFlags(cmd *cobra.Command, f *submitFlags) {
 	cmd.Flags().StringVar(&f.branch, "branch", "", "Which branch to run this command from. Defaults to the current branch.")
 	cmd.Flags().BoolVarP(&f.stack, "stack", "s", false, "Submit descendants of the current branch in addition to its ancestors.")
+	cmd.Flags().BoolVar(&f.stackFromTrunk, "stack-from-trunk", false, "Submit every branch in the current branch's stack, from the branch on trunk up, including branches stacked beside the current one.")
 	cmd.Flags().BoolVarP(&f.force, "force", "f", false, "Force push: overwrites the remote branch with your local branch. Otherwise defaults to --force-with-lease.")
 	cmd.Flags().BoolVar(&f.dryRun, "dry-run", false, "Reports the PRs that would be submitted and terminates. No branches are restacked or pushed and no PRs are opened or updated.")
 	cmd.Flags().BoolVarP(&f.confirm, "confirm", "c", false, "Reports the PRs that would be submitted and asks for confirmation before pushing branches and opening/updating PRs.")
 	cmd.Flags().BoolVarP(&f.updateOnly, "update-only", "u", false, "Only push branches and update PRs for branches that already have PRs open.")
+	cmd.Flags().BoolVar(&f.descriptionsOnly, "update-descriptions-only", false, "Only refresh the stack footer in the descriptions of existing PRs. Nothing is pushed or restacked and titles are left alone.")
 	cmd.Flags().BoolVar(&f.always, "always", false, "Always push updates, even if the branch has not changed.")
+	cmd.Flags().StringVar(&f.since, "since", "", "Only submit branches that changed after this branch or commit, skipping those whose tip it already contains.")
+	cmd.Flags().StringVar(&f.template, "template", "", "Start new PR bodies from this template in .github/PULL_REQUEST_TEMPLATE/. Defaults to .github/PULL_REQUEST_TEMPLATE.md when the repository has one.")
+	cmd.Flags().IntVar(&f.maxPRs, "max-prs", 0, "Fail instead of opening more than this many new PRs; updates to existing PRs don't count. 0 means no limit, and --force skips the check. Defaults to the submit.maxPrs config value.")
+	cmd.Flags().BoolVar(&f.fill, "fill", false, "Take the title and description of new PRs from their commits without prompting: the first commit's subject and the commit messages, with a summary of subjects for multi-commit branches.")
 	cmd.Flags().BoolVar(&f.restack, "restack", false, "Restack branches before submitting.")
-	cmd.Flags().BoolVarP(&f.draft, "draft", "d", false, "If set, all new PRs will be created in draft mode.")
-	cmd.Flags().BoolVarP(&f.publish, "publish", "p", false, "If set, publishes all PRs being submitted.")
+	cmd.Flags().BoolVar(&f.noRestackCheck, "no-restack-check", false, "Submit even if branches need restacking. Otherwise you are asked to restack them first, or the submit fails when not interactive.")
+	cmd.Flags().BoolVarP(&f.draft, "draft", "d", false, "If set, all new PRs will be created in draft mode. Defaults to the submit.draftDefault config value.")
+	cmd.Flags().BoolVarP(&f.publish, "publish", "p", false, "If set, publishes all PRs being submitted, overriding submit.draftDefault and submit.wipPattern.")
 	cmd.Flags().BoolVarP(&f.edit, "edit", "e", false, "Input metadata for all PRs interactively.")
 	cmd.Flags().BoolVar(&f.editTitle, "edit-title", false, "Input the PR title interactively.")
 	cmd.Flags().BoolVar(&f.editDescription, "edit-description", false, "Input the PR description interactively.")
@@ -59,50 +85,101 @@ func addSubmitFlags(cmd *cobra.Command, f *submitFlags) {
 	cmd.Flags().BoolVar(&f.noEditDescription, "no-edit-description", false, "Don't prompt for the PR description.")
 	cmd.Flags().StringVar(&f.reviewers, "reviewers", "", "If set without an argument, prompt to manually set reviewers. Alternatively, accepts a comma separated string of reviewers.")
 	cmd.Flags().StringVar(&f.teamReviewers, "team-reviewers", "", "Comma separated list of team slugs.")
+	cmd.Flags().StringVar(&f.copyReviewersFrom, "copy-reviewers-from", "", "Request the reviewers and apply the labels of this branch's PR on the PRs being submitted, along with any given by --reviewers and --label.")
+	cmd.Flags().StringArrayVar(&f.labels, "label", nil, "Add a label to the PRs being submitted. Can be repeated. Applied to existing PRs only when set.")
+	cmd.Flags().BoolVar(&f.replaceLabels, "replace-labels", false, "Replace the labels on existing PRs with the ones given via --label instead of adding to them.")
+	cmd.Flags().BoolVar(&f.dependentLabels, "dependent-labels", false, "Label every submitted PR with the submit.stackLabel config value and note the PR each one depends on in its body.")
+	cmd.Flags().StringVar(&f.milestone, "milestone", "", "Assign the PRs being submitted to the open milestone with this title.")
 	cmd.Flags().BoolVar(&f.mergeWhenReady, "merge-when-ready", false, "If set, marks all PRs being submitted as merge when ready.")
+	cmd.Flags().StringVar(&f.autoMerge, "auto-merge", "", "Enable GitHub auto-merge on each PR, merging with the given method (merge, squash or rebase) once branch protection requirements pass.")
+	cmd.Flags().Lookup("auto-merge").NoOptDefVal = "merge"
 	cmd.Flags().BoolVar(&f.rerequestReview, "rerequest-review", false, "Rerequest review from current reviewers.")
 	cmd.Flags().BoolVarP(&f.view, "view", "v", false, "Open the PR in your browser after submitting.")
-	cmd.Flags().BoolVarP(&f.web, "web", "w", false, "Open a web browser to edit PR metadata.")
-	cmd.Flags().StringVar(&f.comment, "comment", "", "Add a comment on the PR with the given message.")
-	cmd.Flags().StringVarP(&f.targetTrunk, "target-trunk", "t", "", "Which trunk to open PRs against on remote.")
+	cmd.Flags().BoolVarP(&f.web, "web", "w", false, "Open the current branch's PR in your browser after submitting (every PR in stack order with --stack). Branches whose PR could not be created open the compare page.")
+	cmd.Flags().StringVar(&f.comment, "comment", "", "Add a comment with the given message on each PR that is created or updated.")
+	cmd.Flags().BoolVar(&f.commentOnce, "comment-once", false, "Only post --comment on the current branch's PR.")
+	cmd.Flags().StringVarP(&f.targetTrunk, "target-trunk", "t", "", "Which trunk to open PRs against on remote: the PR of the branch at the bottom of the stack targets this remote branch instead of trunk, e.g. staging, and keeps it on later submits. PRs above it still target their parents, and local tracking is unchanged.")
 	cmd.Flags().BoolVar(&f.ignoreOutOfSyncTrunk, "ignore-out-of-sync-trunk", false, "Perform the submit operation even if the trunk branch is out of sync with its upstream branch.")
+	cmd.Flags().BoolVar(&f.noVerify, "no-verify", false, "Skip the pre-push hook when pushing branches. Defaults to the submit.skipHooks config value.")
+	cmd.Flags().BoolVar(&f.noFooter, "no-footer", false, "Don't add or update the stack dependency footer; existing footers are left untouched. Defaults to the inverse of the submit.footer config value.")
+	cmd.Flags().BoolVar(&f.stripFooter, "strip-footer", false, "Remove the stack dependency footer from existing PRs, from their descriptions or footer comments, instead of updating it.")
 	cmd.Flags().BoolVar(&f.cli, "cli", false, "Edit PR metadata via the CLI instead of on web.")
 }
 
 func executeSubmit(cmd *cobra.Command, f *submitFlags) error {
-	return common.Run(cmd, func(ctx *runtime.Context) error {
+	return common.RunLocked(cmd, func(ctx *runtime.Context) error {
 		// Get config values
+		var autoMerge github.AutoMergeMethod
+		if f.autoMerge != "" {
+			method, err := github.ParseAutoMergeMethod(f.autoMerge)
+			if err != nil {
+				return stackiterrors.NewValidationError("%v", err)
+			}
+			autoMerge = method
+		}
+
 		cfg, _ := config.LoadConfig(ctx.RepoRoot)
-		submitFooter := cfg.SubmitFooter()
+		defaults := submit.ConfigOptions(cfg)
+		submitFooter := defaults.SubmitFooter && !f.noFooter && !f.stripFooter
+		noVerify := defaults.NoVerify
+		if cmd.Flags().Changed("no-verify") {
+			noVerify = f.noVerify
+		}
+		maxPRs := defaults.MaxPRs
+		if cmd.Flags().Changed("max-prs") {
+			maxPRs = f.maxPRs
+		}
 
 		// Run submit action
 		opts := submit.Options{
-			Branch:               f.branch,
-			Stack:                f.stack,
-			Force:                f.force,
-			DryRun:               f.dryRun,
-			Confirm:              f.confirm,
-			UpdateOnly:           f.updateOnly,
-			Always:               f.always,
-			Restack:              f.restack,
-			Draft:                f.draft,
-			Publish:              f.publish,
-			Edit:                 f.edit,
-			EditTitle:            f.editTitle,
-			EditDescription:      f.editDescription,
-			NoEdit:               f.noEdit,
-			NoEditTitle:          f.noEditTitle,
-			NoEditDescription:    f.noEditDescription,
-			Reviewers:            f.reviewers,
-			TeamReviewers:        f.teamReviewers,
-			MergeWhenReady:       f.mergeWhenReady,
-			RerequestReview:      f.rerequestReview,
-			View:                 f.view,
-			Web:                  f.web,
-			Comment:              f.comment,
-			TargetTrunk:          f.targetTrunk,
-			IgnoreOutOfSyncTrunk: f.ignoreOutOfSyncTrunk,
-			SubmitFooter:         submitFooter,
+			Branch:                 f.branch,
+			Stack:                  f.stack,
+			StackFromTrunk:         f.stackFromTrunk,
+			Force:                  f.force,
+			DryRun:                 f.dryRun,
+			Confirm:                f.confirm,
+			UpdateOnly:             f.updateOnly,
+			UpdateDescriptionsOnly: f.descriptionsOnly,
+			Always:                 f.always,
+			Since:                  f.since,
+			MaxPRs:                 maxPRs,
+			Concurrency:            defaults.Concurrency,
+			Template:               f.template,
+			Fill:                   f.fill,
+			Restack:                f.restack,
+			NoRestackCheck:         f.noRestackCheck,
+			Draft:                  f.draft,
+			Publish:                f.publish,
+			DraftDefault:           defaults.DraftDefault,
+			WIPPattern:             defaults.WIPPattern,
+			Edit:                   f.edit,
+			EditTitle:              f.editTitle,
+			EditDescription:        f.editDescription,
+			NoEdit:                 f.noEdit,
+			NoEditTitle:            f.noEditTitle,
+			NoEditDescription:      f.noEditDescription,
+			Reviewers:              f.reviewers,
+			TeamReviewers:          f.teamReviewers,
+			CopyReviewersFrom:      f.copyReviewersFrom,
+			Labels:                 f.labels,
+			ReplaceLabels:          f.replaceLabels,
+			DependentLabels:        f.dependentLabels,
+			StackLabel:             defaults.StackLabel,
+			Milestone:              f.milestone,
+			MergeWhenReady:         f.mergeWhenReady,
+			AutoMerge:              autoMerge,
+			RerequestReview:        f.rerequestReview,
+			View:                   f.view,
+			Web:                    f.web,
+			Comment:                f.comment,
+			CommentOnce:            f.commentOnce,
+			TargetTrunk:            f.targetTrunk,
+			IgnoreOutOfSyncTrunk:   f.ignoreOutOfSyncTrunk,
+			NoVerify:               noVerify,
+			SetUpstream:            defaults.SetUpstream,
+			SubmitFooter:           submitFooter,
+			StripFooter:            f.stripFooter,
+			FooterMode:             defaults.FooterMode,
 		}
 
 		return submit.Action(ctx, opts)
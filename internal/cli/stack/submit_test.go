@@ -1,7 +1,9 @@
 package stack_test
 
 import (
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -186,4 +188,32 @@ func TestSubmitCommand(t *testing.T) {
 		require.Contains(t, outputStr, "branch2", "should include current branch")
 		require.Contains(t, outputStr, "branch3", "should include descendant branch with ss")
 	})
+
+	t.Run("submit refuses while a rebase is in progress", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, nil)
+
+		err := scene.Repo.CreateChangeAndCommit("initial", "init")
+		require.NoError(t, err)
+
+		cmd := exec.Command(binaryPath, "init")
+		cmd.Dir = scene.Dir
+		_, err = cmd.CombinedOutput()
+		require.NoError(t, err)
+
+		cmd = exec.Command(binaryPath, "create", "branch1")
+		cmd.Dir = scene.Dir
+		_, err = cmd.CombinedOutput()
+		require.NoError(t, err)
+
+		// Leave git looking like it stopped mid-rebase
+		require.NoError(t, os.MkdirAll(filepath.Join(scene.Dir, ".git", "rebase-merge"), 0o755))
+
+		cmd = exec.Command(binaryPath, "submit", "--dry-run", "--no-edit", "--draft")
+		cmd.Dir = scene.Dir
+		cmd.Env = append(cmd.Environ(), "STACKIT_NON_INTERACTIVE=1")
+		output, err := cmd.CombinedOutput()
+		require.Error(t, err)
+		require.Contains(t, string(output), "a git operation is in progress; run stackit continue or git rebase --abort")
+	})
 }
@@ -12,9 +12,13 @@ import (
 // NewSyncCmd creates the sync command
 func NewSyncCmd() *cobra.Command {
 	var (
-		all     bool
-		force   bool
-		restack bool
+		all         bool
+		force       bool
+		restack     bool
+		pruneMerged bool
+		updateRefs  bool
+		pullOnly    bool
+		restackOnly bool
 	)
 
 	cmd := &cobra.Command{
@@ -22,32 +26,47 @@ func NewSyncCmd() *cobra.Command {
 		Short: "Sync all branches with remote",
 		Long: `Sync all branches with remote, prompting to delete any branches for PRs that have been merged or closed. 
 Restacks all branches in your repository that can be restacked without conflicts.
-If trunk cannot be fast-forwarded to match remote, overwrites trunk with the remote version.`,
+If trunk cannot be fast-forwarded to match remote, overwrites trunk with the remote version.
+
+Use --pull-only and --restack-only to run the two halves separately, e.g. to look at
+what changed on trunk before restacking onto it.`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			return common.Run(cmd, func(ctx *runtime.Context) error {
+			return common.RunLocked(cmd, func(ctx *runtime.Context) error {
 				// Run sync action
 				return sync.Action(ctx, sync.Options{
-					All:     all,
-					Force:   force,
-					Restack: restack,
+					All:         all,
+					Force:       force,
+					Restack:     restack,
+					NoPrune:     !pruneMerged,
+					UpdateRefs:  updateRefs,
+					PullOnly:    pullOnly,
+					RestackOnly: restackOnly,
 				})
 			})
 		},
 	}
 
-	var noRestack bool
+	var noRestack, noPrune bool
 
 	cmd.Flags().BoolVarP(&all, "all", "a", false, "Sync branches across all configured trunks")
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Don't prompt for confirmation before overwriting or deleting a branch")
 	cmd.Flags().BoolVar(&restack, "restack", true, "Restack any branches that can be restacked without conflicts")
 	cmd.Flags().BoolVar(&noRestack, "no-restack", false, "Skip restacking branches")
+	cmd.Flags().BoolVar(&pruneMerged, "prune-merged", true, "Delete branches whose PRs have been merged, moving their children onto trunk")
+	cmd.Flags().BoolVar(&noPrune, "no-prune", false, "Keep branches whose PRs have been merged")
+	cmd.Flags().BoolVar(&pullOnly, "pull-only", false, "Only update trunk from the remote; don't sync PRs, delete merged branches, or restack")
+	cmd.Flags().BoolVar(&restackOnly, "restack-only", false, "Only restack, against the local trunk, without fetching or syncing PRs")
+	cmd.Flags().BoolVar(&updateRefs, "update-refs", false, "Move branches whose commits were rewritten on top of them (e.g. by git rebase -i on the top branch) onto the rewritten commits, matched by patch ID")
 
-	// Apply --no-restack flag
+	// Apply --no-restack and --no-prune flags
 	cmd.PreRun = func(_ *cobra.Command, _ []string) {
 		if noRestack {
 			restack = false
 		}
+		if noPrune {
+			pruneMerged = false
+		}
 	}
 
 	return cmd
@@ -12,8 +12,10 @@ import (
 // newTrackCmd creates the track command
 func newTrackCmd() *cobra.Command {
 	var (
-		force  bool
-		parent string
+		force     bool
+		parent    string
+		rangeFlag bool
+		dryRun    bool
 	)
 
 	cmd := &cobra.Command{
@@ -21,7 +23,10 @@ func newTrackCmd() *cobra.Command {
 		Short: "Start tracking a branch with stackit by selecting its parent",
 		Long: `Start tracking the current (or provided) branch with stackit by selecting its parent.
 Can recursively track a stack of branches by specifying each branch's parent interactively.
-This command can also be used to fix corrupted stackit metadata.`,
+This command can also be used to fix corrupted stackit metadata.
+
+With --range, every untracked branch between trunk (or the most recent tracked branch, or
+--parent) and the branch is tracked at once, inferring each parent from commit history.`,
 		ValidArgsFunction: common.CompleteBranches,
 		SilenceUsage:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -48,14 +53,19 @@ This command can also be used to fix corrupted stackit metadata.`,
 				BranchName: branchName,
 				Force:      force,
 				Parent:     parent,
+				Range:      rangeFlag,
+				DryRun:     dryRun,
 			})
 		},
 	}
 
 	// Add flags
-	cmd.Flags().BoolVarP(&force, "force", "f", false, "Sets the parent to the most recent tracked ancestor of the branch being tracked to skip prompts. Takes precedence over --parent")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Sets the parent to the most recent tracked ancestor of the branch being tracked to skip prompts. With --parent, tracks the branch even if the parent is not an ancestor of it")
 	cmd.Flags().StringVarP(&parent, "parent", "p", "", "The tracked branch's parent. Must be set to a tracked branch. If provided, only one branch can be tracked at a time.")
 
+	cmd.Flags().BoolVar(&rangeFlag, "range", false, "Track every untracked branch between the base and this branch, inferring parents from commit history. With --parent, the bottom branch is tracked onto that parent.")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "With --range, print the inferred parents without tracking anything.")
+
 	_ = cmd.RegisterFlagCompletionFunc("parent", common.CompleteBranches)
 
 	return cmd
@@ -439,4 +439,84 @@ func TestTrackCommand(t *testing.T) {
 		require.NoError(t, err, "parent command failed: %s", string(output))
 		require.Equal(t, "a", strings.TrimSpace(string(output)))
 	})
+
+	t.Run("track --range tracks a chain of untracked branches", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, func(s *testhelpers.Scene) error {
+			return s.Repo.CreateChangeAndCommit("initial", "init")
+		})
+
+		cmd := exec.Command(binaryPath, "init")
+		cmd.Dir = scene.Dir
+		_, err := cmd.CombinedOutput()
+		require.NoError(t, err)
+
+		// Two untracked branches chained off trunk: main -> a -> b
+		require.NoError(t, scene.Repo.CreateAndCheckoutBranch("a"))
+		require.NoError(t, scene.Repo.CreateChangeAndCommit("a content", "a"))
+		require.NoError(t, scene.Repo.CreateAndCheckoutBranch("b"))
+		require.NoError(t, scene.Repo.CreateChangeAndCommit("b content", "b"))
+
+		// Dry run only previews the inferred relationships
+		cmd = exec.Command(binaryPath, "track", "--range", "--dry-run")
+		cmd.Dir = scene.Dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "track --dry-run failed: %s", string(output))
+		require.Contains(t, string(output), "Would track a with parent main")
+		require.Contains(t, string(output), "Would track b with parent a")
+
+		cmd = exec.Command(binaryPath, "parent")
+		cmd.Dir = scene.Dir
+		output, err = cmd.CombinedOutput()
+		require.NoError(t, err, "parent command failed: %s", string(output))
+		require.Contains(t, string(output), "untracked branch", "dry run should not track b")
+
+		cmd = exec.Command(binaryPath, "track", "--range")
+		cmd.Dir = scene.Dir
+		output, err = cmd.CombinedOutput()
+		require.NoError(t, err, "track --range failed: %s", string(output))
+
+		cmd = exec.Command(binaryPath, "parent")
+		cmd.Dir = scene.Dir
+		output, err = cmd.CombinedOutput()
+		require.NoError(t, err, "parent command failed: %s", string(output))
+		require.Equal(t, "a", strings.TrimSpace(string(output)))
+
+		require.NoError(t, scene.Repo.CheckoutBranch("a"))
+		cmd = exec.Command(binaryPath, "parent")
+		cmd.Dir = scene.Dir
+		output, err = cmd.CombinedOutput()
+		require.NoError(t, err, "parent command failed: %s", string(output))
+		require.Equal(t, "main", strings.TrimSpace(string(output)))
+	})
+
+	t.Run("track --range stops at the most recent tracked branch", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, func(s *testhelpers.Scene) error {
+			return s.Repo.CreateChangeAndCommit("initial", "init")
+		})
+
+		cmd := exec.Command(binaryPath, "init")
+		cmd.Dir = scene.Dir
+		_, err := cmd.CombinedOutput()
+		require.NoError(t, err)
+
+		require.NoError(t, scene.Repo.CreateChange("a content", "a", false))
+		cmd = exec.Command(binaryPath, "create", "a", "-m", "Add a")
+		cmd.Dir = scene.Dir
+		_, err = cmd.CombinedOutput()
+		require.NoError(t, err)
+
+		require.NoError(t, scene.Repo.CreateAndCheckoutBranch("b"))
+		require.NoError(t, scene.Repo.CreateChangeAndCommit("b content", "b"))
+		require.NoError(t, scene.Repo.CreateAndCheckoutBranch("c"))
+		require.NoError(t, scene.Repo.CreateChangeAndCommit("c content", "c"))
+
+		cmd = exec.Command(binaryPath, "track", "--range")
+		cmd.Dir = scene.Dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "track --range failed: %s", string(output))
+		require.Contains(t, string(output), "Tracked b with parent a")
+		require.Contains(t, string(output), "Tracked c with parent b")
+	})
 }
@@ -27,6 +27,8 @@ represents the state of the repository before a modifying Stackit command (like
 If you specify a snapshot ID with --snapshot, it will restore to that specific
 state without prompting.`,
 		SilenceUsage: true,
+		// undo is a way back out of a stopped operation, so it must run while git is mid-operation
+		Annotations: map[string]string{common.AllowGitOperationAnnotation: ""},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			return common.Run(cmd, func(ctx *runtime.Context) error {
 				// Run undo action
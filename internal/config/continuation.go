@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 // ContinuationState represents the state of a command that was interrupted by a rebase conflict
@@ -13,6 +14,9 @@ type ContinuationState struct {
 	BranchesToSync        []string `json:"branchesToSync,omitempty"` // For future sync command
 	CurrentBranchOverride string   `json:"currentBranchOverride,omitempty"`
 	RebasedBranchBase     string   `json:"rebasedBranchBase,omitempty"`
+	// PostRestackHookFailed records that the restack.postHook failed on CurrentBranchOverride
+	// after it was restacked, so continuing resumes with BranchesToRestack without a rebase
+	PostRestackHookFailed bool `json:"postRestackHookFailed,omitempty"`
 }
 
 // GetContinuationState reads the continuation state from disk
@@ -43,6 +47,15 @@ func PersistContinuationState(repoRoot string, state *ContinuationState) error {
 	return os.WriteFile(configPath, data, 0600)
 }
 
+// ContinuationStateModTime returns when the continuation state was last written
+func ContinuationStateModTime(repoRoot string) (time.Time, error) {
+	info, err := os.Stat(filepath.Join(repoRoot, ".git", ".stackit_continue"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
 // ClearContinuationState removes the continuation state file
 func ClearContinuationState(repoRoot string) error {
 	configPath := filepath.Join(repoRoot, ".git", ".stackit_continue")
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// ValueSource describes where the effective value of a setting came from
+type ValueSource string
+
+const (
+	// SourceRepo means the value is set in the repository config
+	SourceRepo ValueSource = "repo"
+	// SourceShared means the value is set in the .stackit.yml committed to the repository
+	SourceShared ValueSource = "shared"
+	// SourceGlobal means the value is set in the user's global config
+	SourceGlobal ValueSource = "global"
+	// SourceDefault means the value is not set anywhere and the built-in default applies
+	SourceDefault ValueSource = "default"
+)
+
+// settingFields maps each user-settable configuration key to its field name in a config file
+var settingFields = map[string]string{
+	"branch.pattern":           "branchNamePattern",
+	"branch.onCollision":       "branch.onCollision",
+	"submit.footer":            "submit.footer",
+	"submit.footerMode":        "submit.footerMode",
+	"submit.skipHooks":         "submit.skipHooks",
+	"submit.maxPrs":            "submit.maxPrs",
+	"submit.concurrency":       "submit.concurrency",
+	"submit.draftDefault":      "submit.draftDefault",
+	"submit.wipPattern":        "submit.wipPattern",
+	"submit.stackLabel":        "submit.stackLabel",
+	"push.setUpstream":         "push.setUpstream",
+	"restack.strategy":         "restack.strategy",
+	"restack.preserveDates":    "restack.preserveDates",
+	"restack.pruneEmpty":       "restack.pruneEmpty",
+	"restack.postHook":         "restack.postHook",
+	"checkout.autostash":       "checkout.autostash",
+	"commit.trailers":          "commit.trailers",
+	"absorb.newFileMode":       "absorb.newFileMode",
+	"sync.trunkStrategy":       "sync.trunkStrategy",
+	"merge.strategy":           "merge.strategy",
+	"merge.requireCleanStatus": "merge.requireCleanStatus",
+	"git.timeout.local":        "git.timeout.local",
+	"git.timeout.network":      "git.timeout.network",
+}
+
+// SettingKeys returns the user-settable configuration keys in sorted order
+func SettingKeys() []string {
+	keys := make([]string, 0, len(settingFields))
+	for key := range settingFields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// GlobalConfigPath returns the path of the user's global config file,
+// $XDG_CONFIG_HOME/stackit/config.json or ~/.config/stackit/config.json
+func GlobalConfigPath() (string, error) {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to determine home directory: %w", err)
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "stackit", "config.json"), nil
+}
+
+// LoadGlobalConfig loads the user's global config. Its getters ignore any repository
+// config, and Save writes to the global config file.
+func LoadGlobalConfig() (*Config, error) {
+	globalPath, err := GlobalConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	return loadGlobalConfig(globalPath)
+}
+
+func loadGlobalConfig(globalPath string) (*Config, error) {
+	data, err := readConfigFile(globalPath)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{path: globalPath, data: data}, nil
+}
+
+// Source reports where the effective value of key comes from: the config's own scope,
+// .stackit.yml, the global config, or the built-in default
+func (c *Config) Source(key string) (ValueSource, error) {
+	field, ok := settingFields[key]
+	if !ok {
+		return "", fmt.Errorf("unknown configuration key: %s", key)
+	}
+
+	set, err := isFieldSet(c.data, field)
+	if err != nil {
+		return "", err
+	}
+	if set {
+		if c.global == nil {
+			return SourceGlobal, nil
+		}
+		return SourceRepo, nil
+	}
+
+	if c.shared != nil {
+		set, err := isFieldSet(c.shared, field)
+		if err != nil {
+			return "", err
+		}
+		if set {
+			return SourceShared, nil
+		}
+	}
+
+	if c.global != nil {
+		set, err := isFieldSet(c.global, field)
+		if err != nil {
+			return "", err
+		}
+		if set {
+			return SourceGlobal, nil
+		}
+	}
+	return SourceDefault, nil
+}
+
+// Unset removes key from the config's own scope, so the global config or the
+// built-in default applies again
+func (c *Config) Unset(key string) error {
+	field, ok := settingFields[key]
+	if !ok {
+		return fmt.Errorf("unknown configuration key: %s", key)
+	}
+
+	fields, err := configFields(c.data)
+	if err != nil {
+		return err
+	}
+	delete(fields, field)
+
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var data RepoConfig
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	*c.data = data
+	return nil
+}
+
+// lookup returns a setting from the config's own scope, falling back to .stackit.yml and then the global config
+func lookup[T any](c *Config, field func(*RepoConfig) *T) (T, bool) {
+	if v := field(c.data); v != nil {
+		return *v, true
+	}
+	if c.shared != nil {
+		if v := field(c.shared); v != nil {
+			return *v, true
+		}
+	}
+	if c.global != nil {
+		if v := field(c.global); v != nil {
+			return *v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// configFields returns the fields set in a config, keyed by their name in the config file
+func configFields(data *RepoConfig) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	return fields, nil
+}
+
+func isFieldSet(data *RepoConfig, field string) (bool, error) {
+	fields, err := configFields(data)
+	if err != nil {
+		return false, err
+	}
+	_, ok := fields[field]
+	return ok, nil
+}
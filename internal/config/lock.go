@@ -0,0 +1,115 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	stackiterrors "stackit.dev/stackit/internal/errors"
+)
+
+// DefaultLockStaleAfter is how long a lock may be held before another operation may take it over
+const DefaultLockStaleAfter = 30 * time.Minute
+
+// lockInfo is the content of the lock file, describing the operation holding it
+type lockInfo struct {
+	Command   string    `json:"command"`
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// RepoLock is an advisory per-repository lock held for the duration of a mutating operation
+type RepoLock struct {
+	path string
+}
+
+func lockPath(repoRoot string) string {
+	return filepath.Join(repoRoot, ".git", ".stackit_lock")
+}
+
+// AcquireRepoLock takes the repository lock for command. It fails fast with an
+// OperationInProgressError if another operation holds the lock, unless that lock is
+// older than staleAfter, in which case it is taken over.
+func AcquireRepoLock(repoRoot, command string, staleAfter time.Duration) (*RepoLock, error) {
+	path := lockPath(repoRoot)
+	data, err := json.Marshal(lockInfo{Command: command, PID: os.Getpid(), StartedAt: time.Now()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal lock: %w", err)
+	}
+
+	// Retry once after clearing a stale lock
+	for attempt := 0; attempt < 2; attempt++ {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			_, writeErr := f.Write(data)
+			closeErr := f.Close()
+			if writeErr != nil || closeErr != nil {
+				_ = os.Remove(path)
+				return nil, fmt.Errorf("failed to write lock file: %w", errors.Join(writeErr, closeErr))
+			}
+			return &RepoLock{path: path}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create lock file: %w", err)
+		}
+
+		holder, readErr := readLock(path)
+		if readErr != nil {
+			// Lock was released between our create and read; try again
+			if os.IsNotExist(readErr) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read lock file: %w", readErr)
+		}
+		if time.Since(holder.StartedAt) < staleAfter {
+			return nil, &stackiterrors.OperationInProgressError{
+				Command:   holder.Command,
+				PID:       holder.PID,
+				StartedAt: holder.StartedAt,
+			}
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove stale lock file: %w", err)
+		}
+	}
+
+	return nil, fmt.Errorf("failed to acquire lock: %w", stackiterrors.ErrOperationInProgress)
+}
+
+// Release removes the lock file
+func (l *RepoLock) Release() error {
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to release lock: %w", err)
+	}
+	return nil
+}
+
+// ForceUnlock removes the repository lock regardless of which operation holds it
+func ForceUnlock(repoRoot string) error {
+	err := os.Remove(lockPath(repoRoot))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove lock file: %w", err)
+	}
+	return nil
+}
+
+// readLock reads the lock file. A lock file that can't be parsed (e.g. one still being
+// written, or a partial write from a crashed process) is dated by its modification time.
+func readLock(path string) (lockInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return lockInfo{}, err
+	}
+	var info lockInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		stat, statErr := os.Stat(path)
+		if statErr != nil {
+			return lockInfo{}, statErr
+		}
+		return lockInfo{Command: "unknown", StartedAt: stat.ModTime()}, nil
+	}
+	return info, nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	stackiterrors "stackit.dev/stackit/internal/errors"
+	"stackit.dev/stackit/testhelpers"
+)
+
+func TestRepoLock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("second acquire fails until the lock is released", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, nil)
+
+		lock, err := AcquireRepoLock(scene.Dir, "stackit restack", DefaultLockStaleAfter)
+		require.NoError(t, err)
+
+		_, err = AcquireRepoLock(scene.Dir, "stackit submit", DefaultLockStaleAfter)
+		require.ErrorIs(t, err, stackiterrors.ErrOperationInProgress)
+		var inProgress *stackiterrors.OperationInProgressError
+		require.True(t, errors.As(err, &inProgress))
+		require.Equal(t, "stackit restack", inProgress.Command)
+		require.Equal(t, os.Getpid(), inProgress.PID)
+
+		require.NoError(t, lock.Release())
+
+		lock, err = AcquireRepoLock(scene.Dir, "stackit submit", DefaultLockStaleAfter)
+		require.NoError(t, err)
+		require.NoError(t, lock.Release())
+	})
+
+	t.Run("takes over a stale lock", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, nil)
+
+		_, err := AcquireRepoLock(scene.Dir, "stackit sync", DefaultLockStaleAfter)
+		require.NoError(t, err)
+
+		time.Sleep(10 * time.Millisecond)
+		lock, err := AcquireRepoLock(scene.Dir, "stackit sync", time.Millisecond)
+		require.NoError(t, err)
+		require.NoError(t, lock.Release())
+	})
+
+	t.Run("force unlock clears a held lock", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, nil)
+
+		_, err := AcquireRepoLock(scene.Dir, "stackit merge", DefaultLockStaleAfter)
+		require.NoError(t, err)
+
+		require.NoError(t, ForceUnlock(scene.Dir))
+
+		lock, err := AcquireRepoLock(scene.Dir, "stackit create", DefaultLockStaleAfter)
+		require.NoError(t, err)
+		require.NoError(t, lock.Release())
+	})
+}
@@ -7,46 +7,112 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"slices"
+	"strings"
+	"time"
+
+	"stackit.dev/stackit/internal/git"
 )
 
-// Config represents a repository configuration with getters and setters
+// Config represents a repository configuration with getters and setters.
+// Getters resolve a setting from the repository config, then the shared .stackit.yml
+// committed to the repository, then the global config, then the built-in default.
+// Setters write to the config's own scope.
 type Config struct {
-	repoRoot string
+	path     string
 	data     *RepoConfig
+	shared   *RepoConfig // nil for the global config itself
+	global   *RepoConfig // nil for the global config itself
+	warnings []string
 }
 
 // LoadConfig creates a new Config instance from a repository root
 func LoadConfig(repoRoot string) (*Config, error) {
+	globalPath, err := GlobalConfigPath()
+	if err != nil {
+		// Without a home directory there is no global config; use repo settings alone
+		globalPath = ""
+	}
+	return loadConfig(repoRoot, globalPath)
+}
+
+func loadConfig(repoRoot, globalPath string) (*Config, error) {
 	data, err := GetRepoConfig(repoRoot)
 	if err != nil {
 		return nil, err
 	}
 
+	shared := &RepoConfig{}
+	var warnings []string
+	if repoRoot != "" {
+		shared, warnings, err = readSharedConfig(repoRoot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A broken global config shouldn't stop every repository from working; skip it instead
+	global := &RepoConfig{}
+	if globalPath != "" {
+		if g, err := readConfigFile(globalPath); err != nil {
+			warnings = append(warnings, fmt.Sprintf("Ignoring the global config: %v", err))
+		} else {
+			global = g
+		}
+	}
+
 	return &Config{
-		repoRoot: repoRoot,
+		path:     repoConfigPath(repoRoot),
 		data:     data,
+		shared:   shared,
+		global:   global,
+		warnings: warnings,
 	}, nil
 }
 
+// Warnings returns problems found while loading the config that didn't stop it from
+// loading, such as unknown keys in .stackit.yml
+func (c *Config) Warnings() []string {
+	return c.warnings
+}
+
 // Save persists the configuration to disk
 func (c *Config) Save() error {
-	configPath := filepath.Join(c.repoRoot, ".git", ".stackit_config")
-
 	configJSON, err := json.MarshalIndent(c.data, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	return os.WriteFile(configPath, configJSON, 0600)
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	return os.WriteFile(c.path, configJSON, 0600)
 }
 
 // Trunk returns the primary trunk branch name, or "main" as default
 func (c *Config) Trunk() string {
+	if trunk := c.configuredTrunk(); trunk != "" {
+		return trunk
+	}
+	return "main"
+}
+
+// SharedTrunk returns the trunk set in .stackit.yml, or "" if it doesn't set one
+func (c *Config) SharedTrunk() string {
+	if c.shared != nil && c.shared.Trunk != nil {
+		return *c.shared.Trunk
+	}
+	return ""
+}
+
+// configuredTrunk returns the primary trunk set in the repository config or, failing
+// that, in .stackit.yml
+func (c *Config) configuredTrunk() string {
 	if c.data.Trunk != nil && *c.data.Trunk != "" {
 		return *c.data.Trunk
 	}
-	return "main"
+	return c.SharedTrunk()
 }
 
 // SetTrunk sets the primary trunk branch name
@@ -61,8 +127,8 @@ func (c *Config) SetTrunk(trunkName string) {
 // AllTrunks returns all configured trunk branches
 func (c *Config) AllTrunks() []string {
 	var trunks []string
-	if c.data.Trunk != nil && *c.data.Trunk != "" {
-		trunks = append(trunks, *c.data.Trunk)
+	if trunk := c.configuredTrunk(); trunk != "" {
+		trunks = append(trunks, trunk)
 	}
 
 	// Add additional trunks (avoiding duplicates)
@@ -108,7 +174,7 @@ func (c *Config) IsInitialized() bool {
 
 // BranchNamePattern returns the branch name pattern from config, or default if not set
 func (c *Config) BranchNamePattern() string {
-	return c.data.GetBranchPattern().String()
+	return c.GetBranchPattern().String()
 }
 
 // SetBranchNamePattern sets the branch name pattern in the config
@@ -124,10 +190,28 @@ func (c *Config) SetBranchNamePattern(pattern string) error {
 	return nil
 }
 
+// BranchOnCollision returns what create does when a generated branch name is taken:
+// "error" (the default) or "suffix", to append -2, -3, ... until the name is free
+func (c *Config) BranchOnCollision() string {
+	if v, ok := lookup(c, func(d *RepoConfig) *string { return d.BranchOnCollision }); ok && v != "" {
+		return v
+	}
+	return "error"
+}
+
+// SetBranchOnCollision sets what create does when a generated branch name is taken
+func (c *Config) SetBranchOnCollision(mode string) error {
+	if mode != "error" && mode != "suffix" {
+		return fmt.Errorf("invalid branch.onCollision value %q (must be 'error' or 'suffix')", mode)
+	}
+	c.data.BranchOnCollision = &mode
+	return nil
+}
+
 // SubmitFooter returns whether PR footer is enabled, or true by default
 func (c *Config) SubmitFooter() bool {
-	if c.data.SubmitFooter != nil {
-		return *c.data.SubmitFooter
+	if v, ok := lookup(c, func(d *RepoConfig) *bool { return d.SubmitFooter }); ok {
+		return v
 	}
 	return true
 }
@@ -137,10 +221,344 @@ func (c *Config) SetSubmitFooter(enabled bool) {
 	c.data.SubmitFooter = &enabled
 }
 
+// SubmitFooterMode returns where the PR dependency tree is published ("body" or "comment"), or "body" by default
+func (c *Config) SubmitFooterMode() string {
+	if v, ok := lookup(c, func(d *RepoConfig) *string { return d.SubmitFooterMode }); ok && v != "" {
+		return v
+	}
+	return "body"
+}
+
+// SetSubmitFooterMode sets where the PR dependency tree is published
+func (c *Config) SetSubmitFooterMode(mode string) error {
+	if mode != "body" && mode != "comment" {
+		return fmt.Errorf("invalid submit.footerMode value %q (must be 'body' or 'comment')", mode)
+	}
+	c.data.SubmitFooterMode = &mode
+	return nil
+}
+
+// SubmitSkipHooks returns whether submit should skip the pre-push hook, or false by default
+func (c *Config) SubmitSkipHooks() bool {
+	if v, ok := lookup(c, func(d *RepoConfig) *bool { return d.SubmitSkipHooks }); ok {
+		return v
+	}
+	return false
+}
+
+// SetSubmitSkipHooks sets whether submit should skip the pre-push hook
+func (c *Config) SetSubmitSkipHooks(skip bool) {
+	c.data.SubmitSkipHooks = &skip
+}
+
+// SubmitMaxPRs returns how many new PRs a single submit may open, or 0 (no limit) by default
+func (c *Config) SubmitMaxPRs() int {
+	if v, ok := lookup(c, func(d *RepoConfig) *int { return d.SubmitMaxPRs }); ok {
+		return v
+	}
+	return 0
+}
+
+// SetSubmitMaxPRs sets how many new PRs a single submit may open
+func (c *Config) SetSubmitMaxPRs(maxPRs int) error {
+	if maxPRs < 0 {
+		return fmt.Errorf("invalid submit.maxPrs value %d (must be 0 for no limit or a positive number)", maxPRs)
+	}
+	c.data.SubmitMaxPRs = &maxPRs
+	return nil
+}
+
+// SubmitConcurrency returns how many PRs a submit creates or updates at once, or 1 by default
+func (c *Config) SubmitConcurrency() int {
+	if v, ok := lookup(c, func(d *RepoConfig) *int { return d.SubmitConcurrency }); ok && v > 0 {
+		return v
+	}
+	return 1
+}
+
+// SetSubmitConcurrency sets how many PRs a submit creates or updates at once
+func (c *Config) SetSubmitConcurrency(concurrency int) error {
+	if concurrency < 1 {
+		return fmt.Errorf("invalid submit.concurrency value %d (must be a positive number)", concurrency)
+	}
+	c.data.SubmitConcurrency = &concurrency
+	return nil
+}
+
+// SubmitDraftDefault returns whether new PRs are opened as drafts, or false by default
+func (c *Config) SubmitDraftDefault() bool {
+	if v, ok := lookup(c, func(d *RepoConfig) *bool { return d.SubmitDraftDefault }); ok {
+		return v
+	}
+	return false
+}
+
+// SetSubmitDraftDefault sets whether new PRs are opened as drafts
+func (c *Config) SetSubmitDraftDefault(draft bool) {
+	c.data.SubmitDraftDefault = &draft
+}
+
+// SubmitWIPPattern returns the regular expression that marks a branch as work in progress,
+// or "" (no detection) by default
+func (c *Config) SubmitWIPPattern() string {
+	if v, ok := lookup(c, func(d *RepoConfig) *string { return d.SubmitWIPPattern }); ok {
+		return v
+	}
+	return ""
+}
+
+// SetSubmitWIPPattern sets the regular expression that marks a branch as work in progress.
+// An empty pattern turns detection off.
+func (c *Config) SetSubmitWIPPattern(pattern string) error {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return fmt.Errorf("invalid submit.wipPattern value %q: %w", pattern, err)
+	}
+	c.data.SubmitWIPPattern = &pattern
+	return nil
+}
+
+// SubmitStackLabel returns the label that submit --dependent-labels applies to stacked PRs,
+// or "stacked" by default
+func (c *Config) SubmitStackLabel() string {
+	if v, ok := lookup(c, func(d *RepoConfig) *string { return d.SubmitStackLabel }); ok {
+		return v
+	}
+	return "stacked"
+}
+
+// SetSubmitStackLabel sets the label that submit --dependent-labels applies to stacked PRs
+func (c *Config) SetSubmitStackLabel(label string) error {
+	if strings.TrimSpace(label) == "" {
+		return fmt.Errorf("invalid submit.stackLabel value: must not be empty")
+	}
+	c.data.SubmitStackLabel = &label
+	return nil
+}
+
+// PushSetUpstream returns whether the first push of a branch sets its upstream, or true by default
+func (c *Config) PushSetUpstream() bool {
+	if v, ok := lookup(c, func(d *RepoConfig) *bool { return d.PushSetUpstream }); ok {
+		return v
+	}
+	return true
+}
+
+// SetPushSetUpstream sets whether the first push of a branch sets its upstream
+func (c *Config) SetPushSetUpstream(setUpstream bool) {
+	c.data.PushSetUpstream = &setUpstream
+}
+
+// RestackStrategy returns how branches are restacked onto their parent ("rebase" or "merge"), or "rebase" by default
+func (c *Config) RestackStrategy() string {
+	if v, ok := lookup(c, func(d *RepoConfig) *string { return d.RestackStrategy }); ok && v != "" {
+		return v
+	}
+	return "rebase"
+}
+
+// SetRestackStrategy sets how branches are restacked onto their parent
+func (c *Config) SetRestackStrategy(strategy string) error {
+	if strategy != "rebase" && strategy != "merge" {
+		return fmt.Errorf("invalid restack strategy %q (must be 'rebase' or 'merge')", strategy)
+	}
+	c.data.RestackStrategy = &strategy
+	return nil
+}
+
+// RestackPreserveDates returns whether restacks keep committer dates equal to author dates, or false by default
+func (c *Config) RestackPreserveDates() bool {
+	if v, ok := lookup(c, func(d *RepoConfig) *bool { return d.RestackPreserveDates }); ok {
+		return v
+	}
+	return false
+}
+
+// SetRestackPreserveDates sets whether restacks keep committer dates equal to author dates
+func (c *Config) SetRestackPreserveDates(preserve bool) {
+	c.data.RestackPreserveDates = &preserve
+}
+
+// RestackPruneEmpty returns which empty branches restack deletes ("never", "merged" or "always"), or "never" by default
+func (c *Config) RestackPruneEmpty() string {
+	if v, ok := lookup(c, func(d *RepoConfig) *string { return d.RestackPruneEmpty }); ok && v != "" {
+		return v
+	}
+	return "never"
+}
+
+// SetRestackPruneEmpty sets which empty branches restack deletes
+func (c *Config) SetRestackPruneEmpty(mode string) error {
+	if mode != "never" && mode != "merged" && mode != "always" {
+		return fmt.Errorf("invalid restack.pruneEmpty value %q (must be 'never', 'merged' or 'always')", mode)
+	}
+	c.data.RestackPruneEmpty = &mode
+	return nil
+}
+
+// RestackPostHook returns the shell command run after each branch is restacked, or "" if none is set
+func (c *Config) RestackPostHook() string {
+	if v, ok := lookup(c, func(d *RepoConfig) *string { return d.RestackPostHook }); ok {
+		return v
+	}
+	return ""
+}
+
+// SetRestackPostHook sets the shell command run after each branch is restacked
+func (c *Config) SetRestackPostHook(command string) {
+	c.data.RestackPostHook = &command
+}
+
+// CommitTrailers returns the trailer templates added to commits made by create and amend,
+// such as "Signed-off-by: {name} <{email}>", or none by default
+func (c *Config) CommitTrailers() []string {
+	if v, ok := lookup(c, func(d *RepoConfig) *[]string { return d.CommitTrailers }); ok {
+		return v
+	}
+	return nil
+}
+
+// SetCommitTrailers sets the trailer templates added to commits made by create and amend
+func (c *Config) SetCommitTrailers(trailers []string) error {
+	for _, trailer := range trailers {
+		if key, _, ok := strings.Cut(trailer, ":"); !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid commit.trailers value %q (must be 'Key: value')", trailer)
+		}
+	}
+	c.data.CommitTrailers = &trailers
+	return nil
+}
+
+// CheckoutAutostash returns whether navigation checkouts stash local changes that block them, or false by default
+func (c *Config) CheckoutAutostash() bool {
+	if v, ok := lookup(c, func(d *RepoConfig) *bool { return d.CheckoutAutostash }); ok {
+		return v
+	}
+	return false
+}
+
+// SetCheckoutAutostash sets whether navigation checkouts stash local changes that block them
+func (c *Config) SetCheckoutAutostash(autostash bool) {
+	c.data.CheckoutAutostash = &autostash
+}
+
+// AbsorbNewFileMode returns what absorb does with hunks that match no commit, such as new
+// files: "skip" (the default) leaves them staged, "first" absorbs them into the newest commit
+func (c *Config) AbsorbNewFileMode() string {
+	if v, ok := lookup(c, func(d *RepoConfig) *string { return d.AbsorbNewFileMode }); ok && v != "" {
+		return v
+	}
+	return "skip"
+}
+
+// SetAbsorbNewFileMode sets what absorb does with hunks that match no commit
+func (c *Config) SetAbsorbNewFileMode(mode string) error {
+	if mode != "skip" && mode != "first" {
+		return fmt.Errorf("invalid absorb.newFileMode value %q (must be 'skip' or 'first')", mode)
+	}
+	c.data.AbsorbNewFileMode = &mode
+	return nil
+}
+
+// SyncTrunkStrategy returns how a diverged local trunk is reconciled with the remote
+// ("ff-only", "rebase" or "reset-to-remote"), or "ff-only" by default
+func (c *Config) SyncTrunkStrategy() string {
+	if v, ok := lookup(c, func(d *RepoConfig) *string { return d.SyncTrunkStrategy }); ok && v != "" {
+		return v
+	}
+	return "ff-only"
+}
+
+// SetSyncTrunkStrategy sets how a diverged local trunk is reconciled with the remote
+func (c *Config) SetSyncTrunkStrategy(strategy string) error {
+	if strategy != "ff-only" && strategy != "rebase" && strategy != "reset-to-remote" {
+		return fmt.Errorf("invalid sync.trunkStrategy value %q (must be 'ff-only', 'rebase' or 'reset-to-remote')", strategy)
+	}
+	c.data.SyncTrunkStrategy = &strategy
+	return nil
+}
+
+// MergeStrategy returns the strategy merge uses when --strategy isn't given ("bottom-up",
+// "top-down" or "consolidate"), or "" by default, to ask interactively
+func (c *Config) MergeStrategy() string {
+	if v, ok := lookup(c, func(d *RepoConfig) *string { return d.MergeStrategy }); ok {
+		return v
+	}
+	return ""
+}
+
+// SetMergeStrategy sets the strategy merge uses when --strategy isn't given
+func (c *Config) SetMergeStrategy(strategy string) error {
+	if strategy != "bottom-up" && strategy != "top-down" && strategy != "consolidate" {
+		return fmt.Errorf("invalid merge.strategy value %q (must be 'bottom-up', 'top-down' or 'consolidate')", strategy)
+	}
+	c.data.MergeStrategy = &strategy
+	return nil
+}
+
+// MergeRequireCleanStatus returns whether merge refuses to start with staged, unstaged or
+// untracked changes, or true by default
+func (c *Config) MergeRequireCleanStatus() bool {
+	if v, ok := lookup(c, func(d *RepoConfig) *bool { return d.MergeRequireCleanStatus }); ok {
+		return v
+	}
+	return true
+}
+
+// SetMergeRequireCleanStatus sets whether merge refuses to start with a dirty working tree
+func (c *Config) SetMergeRequireCleanStatus(require bool) {
+	c.data.MergeRequireCleanStatus = &require
+}
+
+// GitTimeoutLocal returns how long git commands that don't touch a remote may run, or
+// git.DefaultCommandTimeout by default
+func (c *Config) GitTimeoutLocal() time.Duration {
+	return c.gitTimeout(func(d *RepoConfig) *string { return d.GitTimeoutLocal })
+}
+
+// SetGitTimeoutLocal sets how long git commands that don't touch a remote may run
+func (c *Config) SetGitTimeoutLocal(timeout string) error {
+	if err := validateGitTimeout("git.timeout.local", timeout); err != nil {
+		return err
+	}
+	c.data.GitTimeoutLocal = &timeout
+	return nil
+}
+
+// GitTimeoutNetwork returns how long git commands that talk to a remote (push, fetch,
+// pull) may run, or git.DefaultCommandTimeout by default
+func (c *Config) GitTimeoutNetwork() time.Duration {
+	return c.gitTimeout(func(d *RepoConfig) *string { return d.GitTimeoutNetwork })
+}
+
+// SetGitTimeoutNetwork sets how long git commands that talk to a remote may run
+func (c *Config) SetGitTimeoutNetwork(timeout string) error {
+	if err := validateGitTimeout("git.timeout.network", timeout); err != nil {
+		return err
+	}
+	c.data.GitTimeoutNetwork = &timeout
+	return nil
+}
+
+func (c *Config) gitTimeout(field func(d *RepoConfig) *string) time.Duration {
+	if v, ok := lookup(c, field); ok {
+		if timeout, err := time.ParseDuration(v); err == nil && timeout > 0 {
+			return timeout
+		}
+	}
+	return git.DefaultCommandTimeout
+}
+
+func validateGitTimeout(key, timeout string) error {
+	if d, err := time.ParseDuration(timeout); err != nil || d <= 0 {
+		return fmt.Errorf("invalid %s value %q (must be a positive duration such as '30s' or '10m')", key, timeout)
+	}
+	return nil
+}
+
 // UndoStackDepth returns the maximum number of undo snapshots to keep, or 10 by default
 func (c *Config) UndoStackDepth() int {
-	if c.data.UndoStackDepth != nil {
-		return *c.data.UndoStackDepth
+	if v, ok := lookup(c, func(d *RepoConfig) *int { return d.UndoStackDepth }); ok {
+		return v
 	}
 	return 10
 }
@@ -152,17 +570,43 @@ func (c *Config) SetUndoStackDepth(depth int) {
 
 // GetBranchPattern returns the branch name pattern as a BranchPattern type
 func (c *Config) GetBranchPattern() BranchPattern {
-	return c.data.GetBranchPattern()
+	for _, data := range []*RepoConfig{c.data, c.shared, c.global} {
+		if data != nil && data.BranchNamePattern != nil && *data.BranchNamePattern != "" {
+			return data.GetBranchPattern()
+		}
+	}
+	return DefaultBranchPattern
 }
 
 // RepoConfig represents the repository configuration
 type RepoConfig struct {
-	Trunk                      *string  `json:"trunk,omitempty"`
-	Trunks                     []string `json:"trunks,omitempty"`
-	IsGithubIntegrationEnabled *bool    `json:"isGithubIntegrationEnabled,omitempty"`
-	BranchNamePattern          *string  `json:"branchNamePattern,omitempty"`
-	SubmitFooter               *bool    `json:"submit.footer,omitempty"`
-	UndoStackDepth             *int     `json:"undo.stackDepth,omitempty"`
+	Trunk                      *string   `json:"trunk,omitempty"`
+	Trunks                     []string  `json:"trunks,omitempty"`
+	IsGithubIntegrationEnabled *bool     `json:"isGithubIntegrationEnabled,omitempty"`
+	BranchNamePattern          *string   `json:"branchNamePattern,omitempty"`
+	BranchOnCollision          *string   `json:"branch.onCollision,omitempty"`
+	SubmitFooter               *bool     `json:"submit.footer,omitempty"`
+	SubmitSkipHooks            *bool     `json:"submit.skipHooks,omitempty"`
+	SubmitFooterMode           *string   `json:"submit.footerMode,omitempty"`
+	SubmitMaxPRs               *int      `json:"submit.maxPrs,omitempty"`
+	SubmitConcurrency          *int      `json:"submit.concurrency,omitempty"`
+	SubmitDraftDefault         *bool     `json:"submit.draftDefault,omitempty"`
+	SubmitWIPPattern           *string   `json:"submit.wipPattern,omitempty"`
+	SubmitStackLabel           *string   `json:"submit.stackLabel,omitempty"`
+	PushSetUpstream            *bool     `json:"push.setUpstream,omitempty"`
+	RestackStrategy            *string   `json:"restack.strategy,omitempty"`
+	RestackPreserveDates       *bool     `json:"restack.preserveDates,omitempty"`
+	RestackPruneEmpty          *string   `json:"restack.pruneEmpty,omitempty"`
+	RestackPostHook            *string   `json:"restack.postHook,omitempty"`
+	CheckoutAutostash          *bool     `json:"checkout.autostash,omitempty"`
+	CommitTrailers             *[]string `json:"commit.trailers,omitempty"`
+	AbsorbNewFileMode          *string   `json:"absorb.newFileMode,omitempty"`
+	UndoStackDepth             *int      `json:"undo.stackDepth,omitempty"`
+	SyncTrunkStrategy          *string   `json:"sync.trunkStrategy,omitempty"`
+	MergeStrategy              *string   `json:"merge.strategy,omitempty"`
+	MergeRequireCleanStatus    *bool     `json:"merge.requireCleanStatus,omitempty"`
+	GitTimeoutLocal            *string   `json:"git.timeout.local,omitempty"`
+	GitTimeoutNetwork          *string   `json:"git.timeout.network,omitempty"`
 }
 
 // GetBranchPattern returns the branch name pattern as a BranchPattern type
@@ -181,8 +625,15 @@ func (c *RepoConfig) GetBranchPattern() BranchPattern {
 
 // GetRepoConfig reads the repository configuration
 func GetRepoConfig(repoRoot string) (*RepoConfig, error) {
-	configPath := filepath.Join(repoRoot, ".git", ".stackit_config")
+	return readConfigFile(repoConfigPath(repoRoot))
+}
+
+func repoConfigPath(repoRoot string) string {
+	return filepath.Join(repoRoot, ".git", ".stackit_config")
+}
 
+// readConfigFile reads a configuration file, returning an empty config if it doesn't exist
+func readConfigFile(configPath string) (*RepoConfig, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		// Config doesn't exist - return default
@@ -191,7 +642,7 @@ func GetRepoConfig(repoRoot string) (*RepoConfig, error) {
 
 	var config RepoConfig
 	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse repo config: %w", err)
+		return nil, fmt.Errorf("failed to parse config %s: %w", configPath, err)
 	}
 
 	return &config, nil
@@ -5,9 +5,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"stackit.dev/stackit/internal/git"
 	"stackit.dev/stackit/testhelpers"
 )
 
@@ -163,6 +165,395 @@ func TestConfigSetSubmitFooter(t *testing.T) {
 	})
 }
 
+func TestConfigRestackStrategy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults to rebase", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, nil)
+
+		cfg, err := LoadConfig(scene.Dir)
+		require.NoError(t, err)
+		require.Equal(t, "rebase", cfg.RestackStrategy())
+	})
+
+	t.Run("sets restack.strategy to merge", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, nil)
+
+		cfg, err := LoadConfig(scene.Dir)
+		require.NoError(t, err)
+		require.NoError(t, cfg.SetRestackStrategy("merge"))
+		require.NoError(t, cfg.Save())
+
+		cfg2, err := LoadConfig(scene.Dir)
+		require.NoError(t, err)
+		require.Equal(t, "merge", cfg2.RestackStrategy())
+	})
+
+	t.Run("rejects unknown strategy", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, nil)
+
+		cfg, err := LoadConfig(scene.Dir)
+		require.NoError(t, err)
+		require.Error(t, cfg.SetRestackStrategy("squash"))
+		require.Equal(t, "rebase", cfg.RestackStrategy())
+	})
+}
+
+func TestConfigRestackPreserveDates(t *testing.T) {
+	t.Parallel()
+	scene := testhelpers.NewSceneParallel(t, nil)
+
+	cfg, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.False(t, cfg.RestackPreserveDates())
+
+	cfg.SetRestackPreserveDates(true)
+	require.NoError(t, cfg.Save())
+
+	cfg2, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.True(t, cfg2.RestackPreserveDates())
+}
+
+func TestConfigRestackPruneEmpty(t *testing.T) {
+	t.Parallel()
+	scene := testhelpers.NewSceneParallel(t, nil)
+
+	cfg, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.Equal(t, "never", cfg.RestackPruneEmpty())
+
+	require.Error(t, cfg.SetRestackPruneEmpty("sometimes"))
+	require.NoError(t, cfg.SetRestackPruneEmpty("merged"))
+	require.NoError(t, cfg.Save())
+
+	cfg2, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.Equal(t, "merged", cfg2.RestackPruneEmpty())
+}
+
+func TestConfigSubmitFooterMode(t *testing.T) {
+	t.Parallel()
+	scene := testhelpers.NewSceneParallel(t, nil)
+
+	cfg, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.Equal(t, "body", cfg.SubmitFooterMode())
+
+	require.Error(t, cfg.SetSubmitFooterMode("description"))
+	require.NoError(t, cfg.SetSubmitFooterMode("comment"))
+	require.NoError(t, cfg.Save())
+
+	cfg2, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.Equal(t, "comment", cfg2.SubmitFooterMode())
+}
+
+func TestConfigSyncTrunkStrategy(t *testing.T) {
+	t.Parallel()
+	scene := testhelpers.NewSceneParallel(t, nil)
+
+	cfg, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.Equal(t, "ff-only", cfg.SyncTrunkStrategy())
+
+	require.Error(t, cfg.SetSyncTrunkStrategy("merge"))
+	require.NoError(t, cfg.SetSyncTrunkStrategy("reset-to-remote"))
+	require.NoError(t, cfg.Save())
+
+	cfg2, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.Equal(t, "reset-to-remote", cfg2.SyncTrunkStrategy())
+}
+
+func TestConfigBranchOnCollision(t *testing.T) {
+	t.Parallel()
+	scene := testhelpers.NewSceneParallel(t, nil)
+
+	cfg, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.Equal(t, "error", cfg.BranchOnCollision())
+
+	require.Error(t, cfg.SetBranchOnCollision("overwrite"))
+	require.NoError(t, cfg.SetBranchOnCollision("suffix"))
+	require.NoError(t, cfg.Save())
+
+	cfg2, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.Equal(t, "suffix", cfg2.BranchOnCollision())
+}
+
+func TestConfigGitTimeouts(t *testing.T) {
+	t.Parallel()
+	scene := testhelpers.NewSceneParallel(t, nil)
+
+	cfg, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.Equal(t, git.DefaultCommandTimeout, cfg.GitTimeoutLocal())
+	require.Equal(t, git.DefaultCommandTimeout, cfg.GitTimeoutNetwork())
+
+	require.Error(t, cfg.SetGitTimeoutLocal("soon"))
+	require.Error(t, cfg.SetGitTimeoutNetwork("0s"))
+	require.NoError(t, cfg.SetGitTimeoutLocal("30s"))
+	require.NoError(t, cfg.SetGitTimeoutNetwork("15m"))
+	require.NoError(t, cfg.Save())
+
+	cfg2, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, cfg2.GitTimeoutLocal())
+	require.Equal(t, 15*time.Minute, cfg2.GitTimeoutNetwork())
+}
+
+func TestConfigSubmitConcurrency(t *testing.T) {
+	t.Parallel()
+	scene := testhelpers.NewSceneParallel(t, nil)
+
+	cfg, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.Equal(t, 1, cfg.SubmitConcurrency())
+
+	require.Error(t, cfg.SetSubmitConcurrency(0))
+	require.NoError(t, cfg.SetSubmitConcurrency(4))
+	require.NoError(t, cfg.Save())
+
+	cfg2, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.Equal(t, 4, cfg2.SubmitConcurrency())
+}
+
+func TestConfigAbsorbNewFileMode(t *testing.T) {
+	t.Parallel()
+	scene := testhelpers.NewSceneParallel(t, nil)
+
+	cfg, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.Equal(t, "skip", cfg.AbsorbNewFileMode())
+
+	require.Error(t, cfg.SetAbsorbNewFileMode("bogus"))
+	require.NoError(t, cfg.SetAbsorbNewFileMode("first"))
+	require.NoError(t, cfg.Save())
+
+	cfg2, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.Equal(t, "first", cfg2.AbsorbNewFileMode())
+}
+
+func TestConfigMergeRequireCleanStatus(t *testing.T) {
+	t.Parallel()
+	scene := testhelpers.NewSceneParallel(t, nil)
+
+	cfg, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.True(t, cfg.MergeRequireCleanStatus())
+
+	cfg.SetMergeRequireCleanStatus(false)
+	require.NoError(t, cfg.Save())
+
+	cfg2, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.False(t, cfg2.MergeRequireCleanStatus())
+}
+
+func TestConfigCommitTrailers(t *testing.T) {
+	t.Parallel()
+	scene := testhelpers.NewSceneParallel(t, nil)
+
+	cfg, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.Empty(t, cfg.CommitTrailers())
+
+	require.Error(t, cfg.SetCommitTrailers([]string{"no separator"}))
+	require.NoError(t, cfg.SetCommitTrailers([]string{"Signed-off-by: {name} <{email}>"}))
+	require.NoError(t, cfg.Save())
+
+	cfg2, err := LoadConfig(scene.Dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Signed-off-by: {name} <{email}>"}, cfg2.CommitTrailers())
+}
+
+func TestConfigGlobalPrecedence(t *testing.T) {
+	t.Parallel()
+	scene := testhelpers.NewSceneParallel(t, nil)
+	globalPath := filepath.Join(t.TempDir(), "stackit", "config.json")
+
+	cfg, err := loadConfig(scene.Dir, globalPath)
+	require.NoError(t, err)
+	require.Equal(t, "rebase", cfg.RestackStrategy())
+	source, err := cfg.Source("restack.strategy")
+	require.NoError(t, err)
+	require.Equal(t, SourceDefault, source)
+
+	// Set the key in both scopes
+	global, err := loadGlobalConfig(globalPath)
+	require.NoError(t, err)
+	require.NoError(t, global.SetRestackStrategy("merge"))
+	global.SetSubmitFooter(false)
+	require.NoError(t, global.Save())
+
+	repo, err := loadConfig(scene.Dir, globalPath)
+	require.NoError(t, err)
+	repo.SetTrunk("develop")
+	require.NoError(t, repo.SetRestackStrategy("rebase"))
+	require.NoError(t, repo.Save())
+
+	// The repo value wins
+	cfg, err = loadConfig(scene.Dir, globalPath)
+	require.NoError(t, err)
+	require.Equal(t, "rebase", cfg.RestackStrategy())
+	source, err = cfg.Source("restack.strategy")
+	require.NoError(t, err)
+	require.Equal(t, SourceRepo, source)
+
+	// Keys only set globally come from the global config
+	require.False(t, cfg.SubmitFooter())
+	source, err = cfg.Source("submit.footer")
+	require.NoError(t, err)
+	require.Equal(t, SourceGlobal, source)
+
+	// Unsetting the repo key falls back to the global value
+	require.NoError(t, cfg.Unset("restack.strategy"))
+	require.NoError(t, cfg.Save())
+
+	cfg, err = loadConfig(scene.Dir, globalPath)
+	require.NoError(t, err)
+	require.Equal(t, "merge", cfg.RestackStrategy())
+	source, err = cfg.Source("restack.strategy")
+	require.NoError(t, err)
+	require.Equal(t, SourceGlobal, source)
+	require.Equal(t, "develop", cfg.Trunk(), "unset should keep unrelated repo settings")
+
+	require.Error(t, cfg.Unset("no.such.key"))
+}
+
+func TestConfigMalformedGlobal(t *testing.T) {
+	t.Parallel()
+	scene := testhelpers.NewSceneParallel(t, nil)
+	globalPath := filepath.Join(t.TempDir(), "stackit", "config.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(globalPath), 0o755))
+	require.NoError(t, os.WriteFile(globalPath, []byte("{not json"), 0o600))
+
+	// The repository still loads, with a warning, and the built-in defaults apply
+	cfg, err := loadConfig(scene.Dir, globalPath)
+	require.NoError(t, err)
+	require.Len(t, cfg.Warnings(), 1)
+	require.Contains(t, cfg.Warnings()[0], "Ignoring the global config")
+	require.Equal(t, "rebase", cfg.RestackStrategy())
+}
+
+func TestConfigSharedPrecedence(t *testing.T) {
+	t.Parallel()
+	scene := testhelpers.NewSceneParallel(t, nil)
+	globalPath := filepath.Join(t.TempDir(), "stackit", "config.json")
+
+	global, err := loadGlobalConfig(globalPath)
+	require.NoError(t, err)
+	require.NoError(t, global.SetRestackStrategy("merge"))
+	require.NoError(t, global.SetSubmitFooterMode("body"))
+	require.NoError(t, global.Save())
+
+	shared := `trunk: develop
+branch.pattern: "{username}/{message}"
+merge.strategy: top-down
+submit:
+  footerMode: comment
+`
+	require.NoError(t, os.WriteFile(filepath.Join(scene.Dir, SharedConfigFile), []byte(shared), 0o600))
+
+	// .stackit.yml overrides the global config and the built-in defaults
+	cfg, err := loadConfig(scene.Dir, globalPath)
+	require.NoError(t, err)
+	require.Empty(t, cfg.Warnings())
+	require.Equal(t, "main", cfg.Trunk(), "the clone's own trunk wins")
+	require.Equal(t, "{username}/{message}", cfg.BranchNamePattern())
+	require.Equal(t, "top-down", cfg.MergeStrategy())
+	require.Equal(t, "comment", cfg.SubmitFooterMode())
+	source, err := cfg.Source("submit.footerMode")
+	require.NoError(t, err)
+	require.Equal(t, SourceShared, source)
+	require.Equal(t, "merge", cfg.RestackStrategy(), "keys missing from .stackit.yml come from the global config")
+
+	// The per-clone repository config overrides .stackit.yml
+	require.NoError(t, cfg.SetSubmitFooterMode("body"))
+	require.NoError(t, cfg.Save())
+
+	cfg, err = loadConfig(scene.Dir, globalPath)
+	require.NoError(t, err)
+	require.Equal(t, "body", cfg.SubmitFooterMode())
+	source, err = cfg.Source("submit.footerMode")
+	require.NoError(t, err)
+	require.Equal(t, SourceRepo, source)
+	require.Equal(t, "top-down", cfg.MergeStrategy())
+}
+
+func TestConfigSharedValidation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reads the trunk of an uninitialized clone", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, SharedConfigFile), []byte("trunk: develop\n"), 0o600))
+
+		cfg, err := LoadConfig(dir)
+		require.NoError(t, err)
+		require.Equal(t, "develop", cfg.Trunk())
+		require.False(t, cfg.IsInitialized(), "each clone is still initialized on its own")
+	})
+
+	t.Run("warns on unknown keys", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, nil)
+		shared := "submit.footer: false\nsubmit:\n  colour: blue\n"
+		require.NoError(t, os.WriteFile(filepath.Join(scene.Dir, SharedConfigFile), []byte(shared), 0o600))
+
+		cfg, err := LoadConfig(scene.Dir)
+		require.NoError(t, err)
+		require.False(t, cfg.SubmitFooter())
+		require.Equal(t, []string{`unknown key "submit.colour" in .stackit.yml`}, cfg.Warnings())
+	})
+
+	t.Run("rejects values of the wrong type", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, nil)
+		require.NoError(t, os.WriteFile(filepath.Join(scene.Dir, SharedConfigFile), []byte("submit.footer: sometimes\n"), 0o600))
+
+		_, err := LoadConfig(scene.Dir)
+		require.ErrorContains(t, err, "invalid .stackit.yml")
+	})
+
+	t.Run("rejects values config set would refuse", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, nil)
+		shared := "merge.strategy: topdown\nsubmit:\n  footerMode: foo\n"
+		require.NoError(t, os.WriteFile(filepath.Join(scene.Dir, SharedConfigFile), []byte(shared), 0o600))
+
+		_, err := LoadConfig(scene.Dir)
+		require.ErrorContains(t, err, "invalid .stackit.yml")
+		require.ErrorContains(t, err, `invalid merge.strategy value "topdown"`)
+		require.ErrorContains(t, err, `invalid submit.footerMode value "foo"`)
+	})
+
+	t.Run("reports a file it can't read", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, nil)
+		require.NoError(t, os.Mkdir(filepath.Join(scene.Dir, SharedConfigFile), 0o755))
+
+		_, err := LoadConfig(scene.Dir)
+		require.ErrorContains(t, err, "failed to read .stackit.yml")
+	})
+
+	t.Run("rejects malformed YAML", func(t *testing.T) {
+		t.Parallel()
+		scene := testhelpers.NewSceneParallel(t, nil)
+		require.NoError(t, os.WriteFile(filepath.Join(scene.Dir, SharedConfigFile), []byte("submit: [\n"), 0o600))
+
+		_, err := LoadConfig(scene.Dir)
+		require.ErrorContains(t, err, "failed to parse .stackit.yml")
+	})
+}
+
 // Helper function to create string pointer
 func stringPtr(s string) *string {
 	return &s
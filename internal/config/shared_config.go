@@ -0,0 +1,122 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SharedConfigFile is the config file committed at the repository root, whose settings
+// are shared by every clone. Keys are the `stackit config` keys, written flat
+// ("submit.footerMode: comment") or nested ("submit: {footerMode: comment}"), plus trunk.
+const SharedConfigFile = ".stackit.yml"
+
+// readSharedConfig reads the committed config file at the repository root, returning an
+// empty config if there is none, along with a warning for each key it doesn't know
+func readSharedConfig(repoRoot string) (*RepoConfig, []string, error) {
+	content, err := os.ReadFile(filepath.Join(repoRoot, SharedConfigFile))
+	if os.IsNotExist(err) {
+		// No shared config - nothing to layer in
+		return &RepoConfig{}, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", SharedConfigFile, err)
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", SharedConfigFile, err)
+	}
+	settings := map[string]any{}
+	flattenSharedConfig("", doc, settings)
+
+	var warnings []string
+	fields := map[string]any{}
+	for _, key := range slices.Sorted(maps.Keys(settings)) {
+		field, ok := sharedConfigField(key)
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("unknown key %q in %s", key, SharedConfigFile))
+			continue
+		}
+		fields[field] = settings[key]
+	}
+
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid %s: %w", SharedConfigFile, err)
+	}
+	var data RepoConfig
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, nil, fmt.Errorf("invalid %s: %w", SharedConfigFile, err)
+	}
+	if err := validateSharedConfig(&data); err != nil {
+		return nil, nil, fmt.Errorf("invalid %s: %w", SharedConfigFile, err)
+	}
+	return &data, warnings, nil
+}
+
+// validateSharedConfig runs each value through the setter `stackit config set` uses, so the
+// file can't hold a value the command would refuse
+func validateSharedConfig(data *RepoConfig) error {
+	scratch := &Config{data: &RepoConfig{}}
+	var errs []error
+	check := func(set bool, validate func() error) {
+		if set {
+			if err := validate(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	check(data.BranchNamePattern != nil, func() error {
+		if err := scratch.SetBranchNamePattern(*data.BranchNamePattern); err != nil {
+			return fmt.Errorf("invalid branch.pattern value %q: %w", *data.BranchNamePattern, err)
+		}
+		return nil
+	})
+	check(data.BranchOnCollision != nil, func() error { return scratch.SetBranchOnCollision(*data.BranchOnCollision) })
+	check(data.SubmitFooterMode != nil, func() error { return scratch.SetSubmitFooterMode(*data.SubmitFooterMode) })
+	check(data.SubmitMaxPRs != nil, func() error { return scratch.SetSubmitMaxPRs(*data.SubmitMaxPRs) })
+	check(data.SubmitConcurrency != nil, func() error { return scratch.SetSubmitConcurrency(*data.SubmitConcurrency) })
+	check(data.SubmitWIPPattern != nil, func() error { return scratch.SetSubmitWIPPattern(*data.SubmitWIPPattern) })
+	check(data.SubmitStackLabel != nil, func() error { return scratch.SetSubmitStackLabel(*data.SubmitStackLabel) })
+	check(data.RestackStrategy != nil, func() error { return scratch.SetRestackStrategy(*data.RestackStrategy) })
+	check(data.RestackPruneEmpty != nil, func() error { return scratch.SetRestackPruneEmpty(*data.RestackPruneEmpty) })
+	check(data.CommitTrailers != nil, func() error { return scratch.SetCommitTrailers(*data.CommitTrailers) })
+	check(data.AbsorbNewFileMode != nil, func() error { return scratch.SetAbsorbNewFileMode(*data.AbsorbNewFileMode) })
+	check(data.SyncTrunkStrategy != nil, func() error { return scratch.SetSyncTrunkStrategy(*data.SyncTrunkStrategy) })
+	check(data.MergeStrategy != nil, func() error { return scratch.SetMergeStrategy(*data.MergeStrategy) })
+	check(data.GitTimeoutLocal != nil, func() error { return scratch.SetGitTimeoutLocal(*data.GitTimeoutLocal) })
+	check(data.GitTimeoutNetwork != nil, func() error { return scratch.SetGitTimeoutNetwork(*data.GitTimeoutNetwork) })
+	return errors.Join(errs...)
+}
+
+// flattenSharedConfig adds the settings of a YAML mapping to settings, joining the keys of
+// nested mappings with dots
+func flattenSharedConfig(prefix string, doc map[string]any, settings map[string]any) {
+	for key, value := range doc {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]any); ok {
+			flattenSharedConfig(key, nested, settings)
+			continue
+		}
+		settings[key] = value
+	}
+}
+
+// sharedConfigField returns the config file field a shared config key sets
+func sharedConfigField(key string) (string, bool) {
+	if key == "trunk" {
+		return "trunk", true
+	}
+	field, ok := settingFields[key]
+	return field, ok
+}
@@ -31,6 +31,10 @@ func (d *demoGitRunner) GetRemote() string {
 	return "origin"
 }
 
+func (d *demoGitRunner) GetPushRemote() string {
+	return "origin"
+}
+
 func (d *demoGitRunner) FetchRemoteShas(_ string) (map[string]string, error) {
 	return make(map[string]string), nil
 }
@@ -135,6 +139,14 @@ func (d *demoGitRunner) GetCommitAuthor(_ string) (string, error) {
 	return "Demo User", nil
 }
 
+func (d *demoGitRunner) BatchGetCommitInfo(branchNames []string) (map[string]git.CommitInfo, error) {
+	infos := make(map[string]git.CommitInfo, len(branchNames))
+	for _, name := range branchNames {
+		infos[name] = git.CommitInfo{Author: "Demo User", Date: time.Now()}
+	}
+	return infos, nil
+}
+
 func (d *demoGitRunner) GetCommitRange(_, _, _ string) ([]string, error) {
 	return []string{"commit message"}, nil
 }
@@ -151,7 +163,11 @@ func (d *demoGitRunner) PushBranch(_ context.Context, _, _ string, _, _ bool) er
 	return nil
 }
 
-func (d *demoGitRunner) Rebase(_ context.Context, _, _, _ string) (git.RebaseResult, error) {
+func (d *demoGitRunner) PushBranchWithOptions(_ context.Context, _ git.PushOptions) error {
+	return nil
+}
+
+func (d *demoGitRunner) Rebase(_ context.Context, _, _, _ string, _ git.RebaseOptions) (git.RebaseResult, error) {
 	return git.RebaseDone, nil
 }
 
@@ -159,6 +175,18 @@ func (d *demoGitRunner) RebaseContinue(_ context.Context) (git.RebaseResult, err
 	return git.RebaseDone, nil
 }
 
+func (d *demoGitRunner) Merge(_ context.Context, _, _ string) (git.RebaseResult, error) {
+	return git.RebaseDone, nil
+}
+
+func (d *demoGitRunner) MergeContinue(_ context.Context) (git.RebaseResult, error) {
+	return git.RebaseDone, nil
+}
+
+func (d *demoGitRunner) IsMergeInProgress(_ context.Context) bool {
+	return false
+}
+
 func (d *demoGitRunner) CherryPick(_ context.Context, commitSHA, _ string) (string, error) {
 	return commitSHA, nil
 }
@@ -171,6 +199,10 @@ func (d *demoGitRunner) StashPop(_ context.Context) error {
 	return nil
 }
 
+func (d *demoGitRunner) GetPatchIDs(_ context.Context, _, _ string) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
 func (d *demoGitRunner) GetCommitRangeSHAs(_, _ string) ([]string, error) {
 	return []string{"sha1", "sha2"}, nil
 }
@@ -227,7 +259,7 @@ func (d *demoGitRunner) GetChangedFiles(_ context.Context, _, _ string) ([]strin
 	return []string{}, nil
 }
 
-func (d *demoGitRunner) ShowDiff(_ context.Context, _, _ string, _ bool) (string, error) {
+func (d *demoGitRunner) ShowDiff(_ context.Context, _, _ string, _ git.DiffOptions) (string, error) {
 	return "diff", nil
 }
 
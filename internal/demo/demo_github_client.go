@@ -89,6 +89,18 @@ func (c *GitHubClient) UpdatePullRequest(_ context.Context, _, _ string, prNumbe
 	return nil
 }
 
+// GetPullRequest returns a simulated PR by number
+func (c *GitHubClient) GetPullRequest(_ context.Context, _, _ string, prNumber int) (*github.PullRequestInfo, error) {
+	simulateDelay(delayShort)
+
+	for _, pr := range c.prs {
+		if pr.Number == prNumber {
+			return pr, nil
+		}
+	}
+	return nil, nil
+}
+
 // GetPullRequestByBranch returns a simulated PR for a branch
 func (c *GitHubClient) GetPullRequestByBranch(_ context.Context, _, _, branchName string) (*github.PullRequestInfo, error) {
 	simulateDelay(delayShort)
@@ -109,6 +121,18 @@ func (c *GitHubClient) MergePullRequest(_ context.Context, branchName string) er
 	return nil
 }
 
+// GetBranchPRStatuses returns the simulated PRs and check statuses of branches
+func (c *GitHubClient) GetBranchPRStatuses(ctx context.Context, branchNames []string) (map[string]*github.BranchPRStatus, error) {
+	statuses := make(map[string]*github.BranchPRStatus)
+	for _, branchName := range branchNames {
+		if pr, ok := c.prs[branchName]; ok {
+			checks, _ := c.GetPRChecksStatus(ctx, branchName)
+			statuses[branchName] = &github.BranchPRStatus{PR: pr, Checks: checks}
+		}
+	}
+	return statuses, nil
+}
+
 // GetPRChecksStatus returns simulated check status
 func (c *GitHubClient) GetPRChecksStatus(_ context.Context, _ string) (*github.CheckStatus, error) {
 	// Simulate a small delay
@@ -125,3 +149,33 @@ func (c *GitHubClient) GetPRChecksStatus(_ context.Context, _ string) (*github.C
 		},
 	}, nil
 }
+
+// AddComment simulates commenting on a pull request
+func (c *GitHubClient) AddComment(_ context.Context, _, _ string, _ int, _ string) error {
+	simulateDelay(delayShort)
+	return nil
+}
+
+// ListComments simulates listing a pull request's comments; demo PRs have none
+func (c *GitHubClient) ListComments(_ context.Context, _, _ string, _ int) ([]github.Comment, error) {
+	simulateDelay(delayShort)
+	return nil, nil
+}
+
+// UpdateComment simulates editing a comment
+func (c *GitHubClient) UpdateComment(_ context.Context, _, _ string, _ int64, _ string) error {
+	simulateDelay(delayShort)
+	return nil
+}
+
+// DeleteComment simulates deleting a comment
+func (c *GitHubClient) DeleteComment(_ context.Context, _, _ string, _ int64) error {
+	simulateDelay(delayShort)
+	return nil
+}
+
+// EnableAutoMerge simulates enabling auto-merge on a PR
+func (c *GitHubClient) EnableAutoMerge(_ context.Context, _, _ string, _ int, _ github.AutoMergeMethod) error {
+	simulateDelay(delayShort)
+	return nil
+}
@@ -22,15 +22,26 @@ type PRManager interface {
 type SyncManager interface {
 	// Remote operations
 	BranchMatchesRemote(branchName string) (bool, error)
+	BranchFastForwardsRemote(branchName string) (bool, error)
 	PopulateRemoteShas() error
 	PushBranch(ctx context.Context, branchName string, remote string, force bool, forceWithLease bool) error
+	PushBranchWithOptions(ctx context.Context, opts git.PushOptions) error
 
 	// Sync operations
-	PullTrunk(ctx context.Context) (PullResult, error)
+	PullTrunk(ctx context.Context) (TrunkPullReport, error)
 	ResetTrunkToRemote(ctx context.Context) error
+	ResetBranchToRemote(ctx context.Context, branchName string) (string, error)
+	RestoreBranchTip(ctx context.Context, branchName, revision, parentRevision string) error
+	// UpdateRewrittenRefs moves branches whose commits were rewritten in a child's history
+	// (e.g. by `git rebase -i` on the top of a stack) onto the rewritten copies, matched by
+	// patch ID, the way git's rebase.updateRefs would have.
+	UpdateRewrittenRefs(ctx context.Context) (UpdateRefsResult, error)
 	RestackBranches(ctx context.Context, branches []Branch) (RestackBatchResult, error)
+	PreviewRestack(ctx context.Context, branch Branch, scope StackRange) ([]ConflictPrediction, error)
 	ContinueRebase(ctx context.Context, branchName string, rebasedBranchBase string) (ContinueRebaseResult, error)
 	Rebase(ctx context.Context, branchName, upstream, oldUpstream string) (RestackResult, error)
+	SetPreserveDates(preserve bool)
+	SetKeepEmpty(keep bool)
 }
 
 // SquashManager provides operations for squashing commits
@@ -51,6 +62,10 @@ type SplitManager interface {
 	// DetachAndResetBranchChanges detaches and resets branch changes
 	DetachAndResetBranchChanges(ctx context.Context, branchName string) error
 
+	// DetachAndStageBranchChanges detaches and resets like DetachAndResetBranchChanges,
+	// leaving the branch's changes staged
+	DetachAndStageBranchChanges(ctx context.Context, branchName string) error
+
 	// ForceCheckoutBranch force checks out a branch
 	ForceCheckoutBranch(ctx context.Context, branch Branch) error
 }
@@ -76,6 +91,25 @@ type Options struct {
 
 	// Git is the git runner to use. If nil, a default real git runner is used.
 	Git git.Runner
+
+	// RestackStrategy controls how branches are brought up to date with their parent.
+	// If empty, defaults to RestackStrategyRebase.
+	RestackStrategy RestackStrategy
+
+	// PreserveDates keeps committer dates equal to author dates when rebasing.
+	PreserveDates bool
+
+	// PruneEmpty controls whether restack deletes branches left empty against their parent.
+	// If empty, defaults to PruneEmptyNever.
+	PruneEmpty PruneEmptyMode
+
+	// PostRestackHook is a shell command run in the working tree after each branch is restacked,
+	// with the branch name in STACKIT_BRANCH. If empty, no hook is run.
+	PostRestackHook string
+
+	// TrunkStrategy controls how PullTrunk handles a local trunk that has diverged from the remote.
+	// If empty, defaults to TrunkStrategyFFOnly.
+	TrunkStrategy TrunkStrategy
 }
 
 // UndoManager provides operations for undo/redo functionality
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"stackit.dev/stackit/internal/git"
@@ -12,6 +13,8 @@ import (
 
 // ApplyHunksToBranch applies multiple hunks to commits in a branch by recreating them.
 func (e *engineImpl) ApplyHunksToBranch(ctx context.Context, branch Branch, hunksByCommit map[string][]git.Hunk) error {
+	defer e.invalidateReadCache()
+
 	if len(hunksByCommit) == 0 {
 		return nil
 	}
@@ -75,29 +78,13 @@ func (e *engineImpl) ApplyHunksToBranch(ctx context.Context, branch Branch, hunk
 			}
 			defer func() { _ = os.RemoveAll(tmpDir) }()
 
-			patchFile := filepath.Join(tmpDir, "hunks.patch")
-			var patchContent strings.Builder
-			hunksByFile := make(map[string][]git.Hunk)
-			for _, hunk := range hunks {
-				hunksByFile[hunk.File] = append(hunksByFile[hunk.File], hunk)
-			}
-			for file, fileHunks := range hunksByFile {
-				patchContent.WriteString(fmt.Sprintf("diff --git a/%s b/%s\n", file, file))
-				patchContent.WriteString(fmt.Sprintf("--- a/%s\n", file))
-				patchContent.WriteString(fmt.Sprintf("+++ b/%s\n", file))
-				for _, hunk := range fileHunks {
-					patchContent.WriteString(hunk.Content)
-					if !strings.HasSuffix(hunk.Content, "\n") {
-						patchContent.WriteString("\n")
-					}
-				}
-			}
-			if err := os.WriteFile(patchFile, []byte(patchContent.String()), 0600); err != nil {
-				return fmt.Errorf("failed to write hunks patch: %w", err)
+			patchFile, err := writeHunksPatch(tmpDir, hunks)
+			if err != nil {
+				return err
 			}
 
 			// Apply hunks to the worktree and index
-			if _, err := e.git.RunGitCommandWithContext(ctx, "apply", patchFile); err != nil {
+			if _, err := e.git.RunGitCommandWithContext(ctx, "apply", "--index", patchFile); err != nil {
 				return fmt.Errorf("failed to apply hunks for commit %s: %w", commitSHA[:8], err)
 			}
 
@@ -123,12 +110,76 @@ func (e *engineImpl) ApplyHunksToBranch(ctx context.Context, branch Branch, hunk
 	return nil
 }
 
-// FindTargetCommitForHunk finds the first commit downstack where the hunk doesn't commute
+// StageHunks applies hunks to the index only, leaving the working tree untouched.
+// Hunks are located by their context, so they still apply after earlier lines of the
+// file have moved.
+func (e *engineImpl) StageHunks(ctx context.Context, hunks []git.Hunk) error {
+	if len(hunks) == 0 {
+		return nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "stackit-stage-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	patchFile, err := writeHunksPatch(tmpDir, hunks)
+	if err != nil {
+		return err
+	}
+	if _, err := e.git.RunGitCommandWithContext(ctx, "apply", "--cached", patchFile); err != nil {
+		return fmt.Errorf("failed to stage hunks: %w", err)
+	}
+	return nil
+}
+
+// writeHunksPatch writes hunks as a patch file in dir, grouped by file, and returns its path
+func writeHunksPatch(dir string, hunks []git.Hunk) (string, error) {
+	patchFile := filepath.Join(dir, "hunks.patch")
+	var patchContent strings.Builder
+	var files []string
+	hunksByFile := make(map[string][]git.Hunk)
+	for _, hunk := range hunks {
+		if _, ok := hunksByFile[hunk.File]; !ok {
+			files = append(files, hunk.File)
+		}
+		hunksByFile[hunk.File] = append(hunksByFile[hunk.File], hunk)
+	}
+	for _, file := range files {
+		patchContent.WriteString(fmt.Sprintf("diff --git a/%s b/%s\n", file, file))
+		if hunksByFile[file][0].NewFile {
+			patchContent.WriteString("new file mode 100644\n")
+			patchContent.WriteString("--- /dev/null\n")
+		} else {
+			patchContent.WriteString(fmt.Sprintf("--- a/%s\n", file))
+		}
+		patchContent.WriteString(fmt.Sprintf("+++ b/%s\n", file))
+		for _, hunk := range hunksByFile[file] {
+			patchContent.WriteString(hunk.Content)
+			if !strings.HasSuffix(hunk.Content, "\n") {
+				patchContent.WriteString("\n")
+			}
+		}
+	}
+	if err := os.WriteFile(patchFile, []byte(patchContent.String()), 0600); err != nil {
+		return "", fmt.Errorf("failed to write hunks patch: %w", err)
+	}
+	return patchFile, nil
+}
+
+// FindTargetCommitForHunk finds the commit downstack a hunk belongs to: the newest commit
+// the hunk doesn't commute with or, if it commutes with all of them, the only commit that
+// changed the hunk's file. It returns an empty SHA when there is no such commit, e.g. for
+// a new file or a file changed by several commits away from the hunk's lines.
 func (e *engineImpl) FindTargetCommitForHunk(hunk git.Hunk, commitSHAs []string) (string, int, error) {
 	if len(commitSHAs) == 0 {
 		return "", -1, nil
 	}
 
+	// The commits that changed the hunk's file without touching its lines
+	fileTarget, fileIndex, fileCommits := "", -1, 0
+
 	// Iterate through commits from newest to oldest
 	for i, commitSHA := range commitSHAs {
 		// Get parent commit SHA
@@ -148,8 +199,59 @@ func (e *engineImpl) FindTargetCommitForHunk(hunk git.Hunk, commitSHAs []string)
 			// Found the target commit - hunk doesn't commute with it
 			return commitSHA, i, nil
 		}
+
+		files, err := e.git.GetChangedFiles(context.Background(), parentSHA, commitSHA)
+		if err == nil && slices.Contains(files, hunk.File) {
+			fileTarget, fileIndex = commitSHA, i
+			fileCommits++
+		}
 	}
 
-	// Hunk commutes with all commits
+	if fileCommits == 1 {
+		return fileTarget, fileIndex, nil
+	}
+
+	// Hunk commutes with all commits and no single commit owns its file
 	return "", -1, nil
 }
+
+// ValidateHunkTarget checks that a hunk can be absorbed into a commit other than the one
+// FindTargetCommitForHunk chose. The hunk must commute with every newer commit, as the
+// automatic target does, so restacking them doesn't conflict, and it must apply cleanly
+// on top of the commit.
+func (e *engineImpl) ValidateHunkTarget(ctx context.Context, hunk git.Hunk, commitSHA string, newerCommitSHAs []string) error {
+	for _, newerSHA := range newerCommitSHAs {
+		parentSHA, err := e.git.GetParentCommitSHA(newerSHA)
+		if err != nil {
+			continue
+		}
+		commutes, err := e.git.CheckCommutation(hunk, newerSHA, parentSHA)
+		if err != nil {
+			return fmt.Errorf("failed to check commutation: %w", err)
+		}
+		if !commutes {
+			return fmt.Errorf("it overlaps newer commit %s", newerSHA[:8])
+		}
+	}
+
+	tmpDir, err := os.MkdirTemp("", "stackit-absorb-check-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	patchFile, err := writeHunksPatch(tmpDir, []git.Hunk{hunk})
+	if err != nil {
+		return err
+	}
+
+	// Check the patch against the commit's tree in a scratch index, leaving the real one alone
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(tmpDir, "index")}
+	if _, err := e.git.RunGitCommandWithEnv(ctx, env, "read-tree", commitSHA); err != nil {
+		return fmt.Errorf("failed to read tree of %s: %w", commitSHA[:8], err)
+	}
+	if _, err := e.git.RunGitCommandWithEnv(ctx, env, "apply", "--cached", "--check", patchFile); err != nil {
+		return fmt.Errorf("it doesn't apply on top of %s", commitSHA[:8])
+	}
+	return nil
+}
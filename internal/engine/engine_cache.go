@@ -0,0 +1,175 @@
+package engine
+
+import (
+	"slices"
+	"sync"
+
+	"stackit.dev/stackit/internal/git"
+)
+
+// readCache memoizes metadata refs, branch revisions and commit ranges for the per-branch
+// read paths (commit lists, commit counts, diff stats, finding a commit's branch) so
+// rendering or searching a large stack doesn't spawn several git processes per branch. It
+// is dropped whenever the engine writes metadata, moves a branch ref, or rebuilds, so
+// entries never outlive a mutation made through the engine.
+//
+// The cache has its own mutex because it is filled from readers that already hold e.mu
+// for reading; taking e.mu for writing there would deadlock.
+type readCache struct {
+	mu        sync.Mutex
+	branches  []string                 // branch names as of the last rebuild, used to batch revision lookups
+	meta      map[string]*Meta         // branch -> metadata
+	revisions map[string]string        // branch -> revision
+	ranges    map[commitRange][]string // base..head -> commit SHAs, newest first
+	warmed    bool                     // whether revisions has been batch-populated since the last invalidation
+
+	commitInfo map[string]git.CommitInfo // branch -> tip author and author date
+}
+
+// commitRange identifies the commits reachable from head but not base
+type commitRange struct {
+	base, head string
+}
+
+func newReadCache() *readCache {
+	return &readCache{
+		meta:      make(map[string]*Meta),
+		revisions: make(map[string]string),
+		ranges:    make(map[commitRange][]string),
+
+		commitInfo: make(map[string]git.CommitInfo),
+	}
+}
+
+// invalidateReadCache drops all cached metadata and revisions. The cached current branch
+// goes with it, since a mutation may have left HEAD somewhere else (e.g. mid-rebase).
+func (e *engineImpl) invalidateReadCache() {
+	e.invalidateCurrentBranch()
+
+	e.cache.mu.Lock()
+	defer e.cache.mu.Unlock()
+
+	e.cache.meta = make(map[string]*Meta)
+	e.cache.revisions = make(map[string]string)
+	e.cache.ranges = make(map[commitRange][]string)
+	e.cache.warmed = false
+	e.cache.commitInfo = make(map[string]git.CommitInfo)
+}
+
+// resetReadCache replaces the cache contents with freshly loaded metadata after a rebuild
+func (e *engineImpl) resetReadCache(branches []string, allMeta map[string]*Meta) {
+	e.cache.mu.Lock()
+	defer e.cache.mu.Unlock()
+
+	e.cache.branches = append([]string(nil), branches...)
+	e.cache.meta = make(map[string]*Meta, len(allMeta))
+	for name, meta := range allMeta {
+		e.cache.meta[name] = meta
+	}
+	e.cache.revisions = make(map[string]string)
+	e.cache.ranges = make(map[commitRange][]string)
+	e.cache.warmed = false
+	e.cache.commitInfo = make(map[string]git.CommitInfo)
+}
+
+// cachedMetadataRef returns metadata for a branch, reading it from Git on a cache miss
+func (e *engineImpl) cachedMetadataRef(branchName string) (*Meta, error) {
+	e.cache.mu.Lock()
+	meta, ok := e.cache.meta[branchName]
+	e.cache.mu.Unlock()
+	if ok {
+		return meta, nil
+	}
+
+	meta, err := e.readMetadataRef(branchName)
+	if err != nil {
+		return nil, err
+	}
+
+	e.cache.mu.Lock()
+	e.cache.meta[branchName] = meta
+	e.cache.mu.Unlock()
+	return meta, nil
+}
+
+// cachedRevision returns the revision of a branch. The first miss after an invalidation
+// resolves every known branch at once via BatchGetRevisions.
+func (e *engineImpl) cachedRevision(branchName string) (string, error) {
+	e.cache.mu.Lock()
+	defer e.cache.mu.Unlock()
+
+	if rev, ok := e.cache.revisions[branchName]; ok {
+		return rev, nil
+	}
+
+	if !e.cache.warmed {
+		e.cache.warmed = true
+		revisions, _ := e.git.BatchGetRevisions(e.cache.branches)
+		for name, rev := range revisions {
+			e.cache.revisions[name] = rev
+		}
+		if rev, ok := e.cache.revisions[branchName]; ok {
+			return rev, nil
+		}
+	}
+
+	rev, err := e.git.GetRevision(branchName)
+	if err != nil {
+		return "", err
+	}
+	e.cache.revisions[branchName] = rev
+	return rev, nil
+}
+
+// cachedCommitRange returns the SHAs in base..head, newest first. Branches in a stack often
+// share ranges (siblings on the same base, repeated searches for a commit's branch), so each
+// pair is only asked of git once per invalidation. Callers get their own copy of the slice.
+func (e *engineImpl) cachedCommitRange(base, head string) ([]string, error) {
+	key := commitRange{base: base, head: head}
+	e.cache.mu.Lock()
+	shas, ok := e.cache.ranges[key]
+	e.cache.mu.Unlock()
+	if ok {
+		return slices.Clone(shas), nil
+	}
+
+	shas, err := e.git.GetCommitRangeSHAs(base, head)
+	if err != nil {
+		return nil, err
+	}
+
+	e.cache.mu.Lock()
+	e.cache.ranges[key] = shas
+	e.cache.mu.Unlock()
+	return slices.Clone(shas), nil
+}
+
+// cachedCommitInfo returns the tip author and author date of each branch, reading every
+// branch not already cached with a single git call. Branches that can't be read are left out.
+func (e *engineImpl) cachedCommitInfo(branchNames []string) map[string]git.CommitInfo {
+	e.cache.mu.Lock()
+	defer e.cache.mu.Unlock()
+
+	infos := make(map[string]git.CommitInfo, len(branchNames))
+	var missing []string
+	for _, name := range branchNames {
+		if info, ok := e.cache.commitInfo[name]; ok {
+			infos[name] = info
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return infos
+	}
+
+	read, err := e.git.BatchGetCommitInfo(missing)
+	if err != nil {
+		return infos
+	}
+	for name, info := range read {
+		e.cache.commitInfo[name] = info
+		infos[name] = info
+	}
+	return infos
+}
@@ -0,0 +1,329 @@
+package engine_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/internal/engine"
+	"stackit.dev/stackit/internal/git"
+	"stackit.dev/stackit/testhelpers"
+	"stackit.dev/stackit/testhelpers/scenario"
+)
+
+// countingRunner counts the git invocations made by the engine's per-branch read paths
+type countingRunner struct {
+	git.Runner
+	calls              atomic.Int64
+	currentBranchCalls atomic.Int64
+	rangeCalls         atomic.Int64
+}
+
+func (r *countingRunner) GetCurrentBranch() (string, error) {
+	r.currentBranchCalls.Add(1)
+	return r.Runner.GetCurrentBranch()
+}
+
+func (r *countingRunner) GetRevision(branchName string) (string, error) {
+	r.calls.Add(1)
+	return r.Runner.GetRevision(branchName)
+}
+
+func (r *countingRunner) BatchGetRevisions(branchNames []string) (map[string]string, []error) {
+	r.calls.Add(int64(len(branchNames)))
+	return r.Runner.BatchGetRevisions(branchNames)
+}
+
+func (r *countingRunner) GetRef(name string) (string, error) {
+	r.calls.Add(1)
+	return r.Runner.GetRef(name)
+}
+
+func (r *countingRunner) ReadBlob(sha string) (string, error) {
+	r.calls.Add(1)
+	return r.Runner.ReadBlob(sha)
+}
+
+func (r *countingRunner) GetCommitRangeSHAs(base, head string) ([]string, error) {
+	r.calls.Add(1)
+	r.rangeCalls.Add(1)
+	return r.Runner.GetCommitRangeSHAs(base, head)
+}
+
+func (r *countingRunner) RunGitCommand(args ...string) (string, error) {
+	r.calls.Add(1)
+	return r.Runner.RunGitCommand(args...)
+}
+
+// buildLinearStack creates and tracks a stack of n branches on top of main, each with one file change
+func buildLinearStack(tb testing.TB, scene *testhelpers.Scene, eng engine.Engine, n int) {
+	tb.Helper()
+	parent := "main"
+	for i := 1; i <= n; i++ {
+		name := fmt.Sprintf("stack-%02d", i)
+		require.NoError(tb, scene.Repo.CreateAndCheckoutBranch(name))
+		require.NoError(tb, scene.Repo.CreateChangeAndCommit(fmt.Sprintf("line %d\nmore %d\n", i, i), name))
+		require.NoError(tb, eng.TrackBranch(context.Background(), name, parent))
+		parent = name
+	}
+	require.NoError(tb, eng.Rebuild("main"))
+}
+
+// uncachedStats computes commit count and diff stats straight from git for comparison
+func uncachedStats(t *testing.T, scene *testhelpers.Scene, parent, branch string) (int, int, int) {
+	t.Helper()
+	countOut, err := scene.Repo.RunGitCommandAndGetOutput("rev-list", "--count", parent+".."+branch)
+	require.NoError(t, err)
+	count, err := strconv.Atoi(strings.TrimSpace(countOut))
+	require.NoError(t, err)
+
+	numstat, err := scene.Repo.RunGitCommandAndGetOutput("diff", "--numstat", parent, branch)
+	require.NoError(t, err)
+	added, deleted := 0, 0
+	for _, line := range strings.Split(strings.TrimSpace(numstat), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 {
+			a, _ := strconv.Atoi(fields[0])
+			d, _ := strconv.Atoi(fields[1])
+			added += a
+			deleted += d
+		}
+	}
+	return count, added, deleted
+}
+
+func TestReadCache(t *testing.T) {
+	t.Run("cached stats match uncached git results", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		buildLinearStack(t, s.Scene, s.Engine, 5)
+
+		// Give the top branch a second commit so counts differ across the stack
+		s.Checkout("stack-05").
+			CommitChange("extra", "extra change")
+
+		for _, branch := range s.Engine.AllBranches() {
+			if branch.IsTrunk() {
+				continue
+			}
+			parent := s.Engine.GetParent(branch)
+			require.NotNil(t, parent)
+
+			expectedCount, expectedAdded, expectedDeleted := uncachedStats(t, s.Scene, parent.GetName(), branch.GetName())
+
+			count, err := branch.GetCommitCount()
+			require.NoError(t, err)
+			require.Equal(t, expectedCount, count, branch.GetName())
+
+			added, deleted, err := branch.GetDiffStats()
+			require.NoError(t, err)
+			require.Equal(t, expectedAdded, added, branch.GetName())
+			require.Equal(t, expectedDeleted, deleted, branch.GetName())
+
+			commits, err := branch.GetAllCommits(engine.CommitFormatSHA)
+			require.NoError(t, err)
+			require.Len(t, commits, expectedCount, branch.GetName())
+		}
+	})
+
+	t.Run("batch commit info matches the single accessors", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		buildLinearStack(t, s.Scene, s.Engine, 3)
+		// Give each branch a different author and date
+		for i, author := range []string{"Alice", "Bob", "Carol"} {
+			s.Checkout(fmt.Sprintf("stack-%02d", i+1)).
+				RunGit("commit", "--amend", "--no-edit", "--author", author+" <"+strings.ToLower(author)+"@example.com>",
+					"--date", fmt.Sprintf("2024-01-0%dT10:00:00+0%d:00", i+1, i))
+		}
+		s.Rebuild()
+
+		names := []string{"main", "stack-01", "stack-02", "stack-03", "missing"}
+		infos := s.Engine.BatchGetCommitInfo(names)
+		require.Len(t, infos, 4)
+		require.NotContains(t, infos, "missing")
+		for _, name := range names[:4] {
+			date, err := git.GetCommitDate(name)
+			require.NoError(t, err)
+			author, err := git.GetCommitAuthor(name)
+			require.NoError(t, err)
+			require.True(t, date.Equal(infos[name].Date), "%s: %s != %s", name, date, infos[name].Date)
+			require.Equal(t, author, infos[name].Author, name)
+
+			// The single accessors read the cached values
+			cachedDate, err := s.Engine.GetBranch(name).GetCommitDate()
+			require.NoError(t, err)
+			require.True(t, date.Equal(cachedDate), name)
+		}
+		require.Equal(t, "Bob", infos["stack-02"].Author)
+	})
+
+	t.Run("commit through the engine invalidates cached revisions", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		buildLinearStack(t, s.Scene, s.Engine, 2)
+		s.Checkout("stack-02")
+
+		branch := s.Engine.GetBranch("stack-02")
+		count, err := branch.GetCommitCount()
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+
+		require.NoError(t, s.Scene.Repo.CreateChange("another", "another", false))
+		require.NoError(t, s.Engine.Commit(context.Background(), "another change", 0))
+
+		count, err = branch.GetCommitCount()
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+	})
+
+	t.Run("rendering stats batches git invocations", func(t *testing.T) {
+		const branches = 30
+		scene := testhelpers.NewScene(t, testhelpers.BasicSceneSetup)
+		runner := &countingRunner{Runner: git.NewRealRunner()}
+		eng, err := engine.NewEngine(engine.Options{RepoRoot: scene.Dir, Trunk: "main", Git: runner})
+		require.NoError(t, err)
+		buildLinearStack(t, scene, eng, branches)
+
+		runner.calls.Store(0)
+		renderStats(t, eng)
+
+		// Uncached, each branch costs ~11 invocations (two metadata reads of two calls each,
+		// three revision lookups, the commit range and the diff). With metadata loaded at
+		// rebuild and revisions batched, it's one batched lookup per branch plus range and diff.
+		require.LessOrEqual(t, runner.calls.Load(), int64(3*(branches+1)))
+	})
+
+	t.Run("commit ranges are shared across lookups", func(t *testing.T) {
+		scene := testhelpers.NewScene(t, testhelpers.BasicSceneSetup)
+		runner := &countingRunner{Runner: git.NewRealRunner()}
+		eng, err := engine.NewEngine(engine.Options{RepoRoot: scene.Dir, Trunk: "main", Git: runner})
+		require.NoError(t, err)
+
+		// Two siblings sharing main as their base
+		commits := map[string]string{}
+		for _, name := range []string{"left", "right"} {
+			require.NoError(t, scene.Repo.CheckoutBranch("main"))
+			require.NoError(t, scene.Repo.CreateAndCheckoutBranch(name))
+			require.NoError(t, scene.Repo.CreateChangeAndCommit(name+" change", name))
+			require.NoError(t, eng.TrackBranch(context.Background(), name, "main"))
+			sha, err := scene.Repo.GetRevision(name)
+			require.NoError(t, err)
+			commits[name] = sha
+		}
+		require.NoError(t, eng.Rebuild("main"))
+
+		runner.rangeCalls.Store(0)
+		for i := 0; i < 3; i++ {
+			for name, sha := range commits {
+				branch, err := eng.FindBranchForCommit(sha)
+				require.NoError(t, err)
+				require.Equal(t, name, branch)
+			}
+		}
+		// Each branch's range is read from git once, however often it is searched
+		require.LessOrEqual(t, runner.rangeCalls.Load(), int64(len(commits)))
+
+		// Callers can't corrupt the cached range
+		shas, err := eng.GetBranch("left").GetAllCommits(engine.CommitFormatSHA)
+		require.NoError(t, err)
+		shas[0] = "mutated"
+		shas, err = eng.GetBranch("left").GetAllCommits(engine.CommitFormatSHA)
+		require.NoError(t, err)
+		require.Equal(t, []string{commits["left"]}, shas)
+
+		// Moving a branch drops the cached ranges
+		require.NoError(t, scene.Repo.CheckoutBranch("left"))
+		require.NoError(t, scene.Repo.CreateChange("more", "left", false))
+		require.NoError(t, eng.Commit(context.Background(), "more left", 0))
+		shas, err = eng.GetBranch("left").GetAllCommits(engine.CommitFormatSHA)
+		require.NoError(t, err)
+		require.Len(t, shas, 2)
+	})
+
+	t.Run("current branch is cached until a checkout", func(t *testing.T) {
+		scene := testhelpers.NewScene(t, testhelpers.BasicSceneSetup)
+		runner := &countingRunner{Runner: git.NewRealRunner()}
+		eng, err := engine.NewEngine(engine.Options{RepoRoot: scene.Dir, Trunk: "main", Git: runner})
+		require.NoError(t, err)
+		buildLinearStack(t, scene, eng, 2)
+
+		runner.currentBranchCalls.Store(0)
+		for i := 0; i < 5; i++ {
+			require.Equal(t, "stack-02", eng.CurrentBranch().GetName())
+		}
+		require.Equal(t, int64(0), runner.currentBranchCalls.Load())
+
+		// A checkout through the engine updates the cached branch without re-reading HEAD
+		require.NoError(t, eng.CheckoutBranch(context.Background(), eng.GetBranch("stack-01")))
+		require.Equal(t, "stack-01", eng.CurrentBranch().GetName())
+		require.Equal(t, int64(0), runner.currentBranchCalls.Load())
+
+		// An out-of-band checkout is picked up after an explicit refresh
+		require.NoError(t, scene.Repo.CheckoutBranch("main"))
+		require.Equal(t, "stack-01", eng.CurrentBranch().GetName())
+		require.Equal(t, "main", eng.RefreshCurrentBranch().GetName())
+		require.Equal(t, "main", eng.CurrentBranch().GetName())
+		require.Equal(t, int64(1), runner.currentBranchCalls.Load())
+
+		// Mutating operations drop the cached branch
+		_, err = eng.RunGitCommand("checkout", "stack-02")
+		require.NoError(t, err)
+		require.Equal(t, "stack-02", eng.CurrentBranch().GetName())
+		require.Equal(t, int64(2), runner.currentBranchCalls.Load())
+	})
+}
+
+// renderStats reads the commit count and diff stats for every branch, as `log --stat` does
+func renderStats(tb testing.TB, eng engine.Engine) {
+	tb.Helper()
+	for _, branch := range eng.AllBranches() {
+		if branch.IsTrunk() {
+			continue
+		}
+		_, err := branch.GetCommitCount()
+		require.NoError(tb, err)
+		_, _, err = branch.GetDiffStats()
+		require.NoError(tb, err)
+	}
+}
+
+func BenchmarkRenderStatsLargeStack(b *testing.B) {
+	scene := testhelpers.NewScene(b, testhelpers.BasicSceneSetup)
+	runner := &countingRunner{Runner: git.NewRealRunner()}
+	eng, err := engine.NewEngine(engine.Options{RepoRoot: scene.Dir, Trunk: "main", Git: runner})
+	require.NoError(b, err)
+	buildLinearStack(b, scene, eng, 30)
+
+	runner.calls.Store(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Rebuild drops the cache, like starting a fresh invocation
+		require.NoError(b, eng.Rebuild("main"))
+		renderStats(b, eng)
+	}
+	b.ReportMetric(float64(runner.calls.Load())/float64(b.N), "git-calls/op")
+}
+
+func BenchmarkFindBranchForCommit(b *testing.B) {
+	scene := testhelpers.NewScene(b, testhelpers.BasicSceneSetup)
+	runner := &countingRunner{Runner: git.NewRealRunner()}
+	eng, err := engine.NewEngine(engine.Options{RepoRoot: scene.Dir, Trunk: "main", Git: runner})
+	require.NoError(b, err)
+	buildLinearStack(b, scene, eng, 30)
+
+	// The bottom branch's commit, so every lookup searches most of the stack
+	sha, err := scene.Repo.GetRevision("stack-01")
+	require.NoError(b, err)
+
+	runner.calls.Store(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		branch, err := eng.FindBranchForCommit(sha)
+		require.NoError(b, err)
+		require.Equal(b, "stack-01", branch)
+	}
+	b.ReportMetric(float64(runner.calls.Load())/float64(b.N), "git-calls/op")
+}
@@ -3,6 +3,7 @@ package engine
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"stackit.dev/stackit/internal/git"
 )
@@ -12,13 +13,22 @@ type engineImpl struct {
 	repoRoot          string
 	trunk             string
 	currentBranch     string
+	currentStale      atomic.Bool // whether currentBranch must be re-read from Git
 	branches          []string
 	parentMap         map[string]string   // branch -> parent
 	childrenMap       map[string][]string // branch -> children
 	scopeMap          map[string]string   // branch -> scope
+	newerMeta         []string            // branches whose metadata was written by a newer stackit
 	remoteShas        map[string]string   // branch -> remote SHA (populated by PopulateRemoteShas)
 	maxUndoStackDepth int
+	restackStrategy   RestackStrategy
+	preserveDates     bool
+	emptyCommits      git.EmptyCommitMode
+	pruneEmpty        PruneEmptyMode
+	postRestackHook   string
+	trunkStrategy     TrunkStrategy
 	git               git.Runner
+	cache             *readCache
 	mu                sync.RWMutex
 }
 
@@ -46,6 +56,21 @@ func NewEngine(opts Options) (Engine, error) {
 		maxDepth = DefaultMaxUndoStackDepth
 	}
 
+	strategy := opts.RestackStrategy
+	if strategy == "" {
+		strategy = RestackStrategyRebase
+	}
+
+	pruneEmpty := opts.PruneEmpty
+	if pruneEmpty == "" {
+		pruneEmpty = PruneEmptyNever
+	}
+
+	trunkStrategy := opts.TrunkStrategy
+	if trunkStrategy == "" {
+		trunkStrategy = TrunkStrategyFFOnly
+	}
+
 	e := &engineImpl{
 		repoRoot:          opts.RepoRoot,
 		trunk:             opts.Trunk,
@@ -54,7 +79,13 @@ func NewEngine(opts Options) (Engine, error) {
 		scopeMap:          make(map[string]string),
 		remoteShas:        make(map[string]string),
 		maxUndoStackDepth: maxDepth,
+		restackStrategy:   strategy,
+		preserveDates:     opts.PreserveDates,
+		pruneEmpty:        pruneEmpty,
+		postRestackHook:   opts.PostRestackHook,
+		trunkStrategy:     trunkStrategy,
 		git:               g,
+		cache:             newReadCache(),
 	}
 
 	currentBranch, err := g.GetCurrentBranch()
@@ -73,6 +104,12 @@ func NewEngine(opts Options) (Engine, error) {
 
 // Reset clears all branch metadata and rebuilds with new trunk
 func (e *engineImpl) Reset(newTrunkName string) error {
+	return e.ResetWithOptions(newTrunkName, ResetOptions{})
+}
+
+// ResetWithOptions untracks all branches and sets a new trunk. With PreservePRInfo, each
+// branch's stored PR info is kept so a re-initialized repository doesn't need re-submitting.
+func (e *engineImpl) ResetWithOptions(newTrunkName string, opts ResetOptions) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
@@ -84,6 +121,15 @@ func (e *engineImpl) Reset(newTrunkName string) error {
 	}
 
 	for branchName := range metadataRefs {
+		if opts.PreservePRInfo {
+			meta, err := e.readMetadataRef(branchName)
+			if err == nil && meta.PrInfo != nil {
+				// Keep only the PR info; without a parent the branch is untracked
+				if err := e.writeMetadataRef(branchName, &Meta{PrInfo: meta.PrInfo}); err == nil {
+					continue
+				}
+			}
+		}
 		if err := e.DeleteMetadataRef(e.GetBranch(branchName)); err != nil {
 			continue
 		}
@@ -104,20 +150,46 @@ func (e *engineImpl) Rebuild(newTrunkName string) error {
 	return e.rebuildInternal(true)
 }
 
-// PopulateRemoteShas populates remote branch information by fetching SHAs from remote
+// RebuildBranches reloads only the named branches into the branch cache, for callers that
+// changed a few branches (creating, reparenting or deleting them) and don't need Rebuild's
+// rescan of every branch's metadata. Rebuild remains the way to recover from changes made
+// outside the engine.
+func (e *engineImpl) RebuildBranches(branchNames ...string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.rebuildBranchesInternal(branchNames)
+}
+
+// PopulateRemoteShas populates remote branch information by fetching SHAs from the fetch
+// remote and, in fork workflows, the push remote. Where both have a branch the push remote's
+// SHA wins, since that is where the branch would be pushed, except for trunk, which comes
+// from the fetch remote; a fork's copy of trunk is often stale.
 func (e *engineImpl) PopulateRemoteShas() error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
 	e.remoteShas = make(map[string]string)
 
-	remote := e.git.GetRemote()
-	remoteShas, err := e.git.FetchRemoteShas(remote)
-	if err != nil {
-		// Don't fail if we can't fetch remote SHAs (e.g., offline)
-		return nil
+	fetchRemote := e.git.GetRemote()
+	pushRemote := e.git.GetPushRemote()
+	remotes := []string{fetchRemote}
+	if pushRemote != fetchRemote {
+		remotes = append(remotes, pushRemote)
 	}
 
-	e.remoteShas = remoteShas
+	for _, remote := range remotes {
+		remoteShas, err := e.git.FetchRemoteShas(remote)
+		if err != nil {
+			// Don't fail if we can't fetch remote SHAs (e.g., offline)
+			continue
+		}
+		for branchName, sha := range remoteShas {
+			if remote != fetchRemote && branchName == e.trunk {
+				continue
+			}
+			e.remoteShas[branchName] = sha
+		}
+	}
 	return nil
 }
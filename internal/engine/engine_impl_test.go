@@ -2,6 +2,10 @@ package engine_test
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -104,6 +108,46 @@ func TestTrackBranch(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "parent branch nonexistent does not exist")
 	})
+
+	t.Run("records the merge base when the parent has moved on", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+
+		s.CreateBranch("branch1").
+			Commit("branch1 change")
+		require.NoError(t, s.Engine.TrackBranch(context.Background(), "branch1", "main"))
+		forkPoint, err := s.Engine.GetBranch("branch1").GetRevision()
+		require.NoError(t, err)
+
+		s.CreateBranch("branch2").
+			Commit("branch2 change").
+			Checkout("branch1").
+			Commit("branch1 follow-up")
+
+		// branch1's tip is no longer in branch2's history, but branch2 was built on it
+		require.NoError(t, s.Engine.TrackBranch(context.Background(), "branch2", "branch1"))
+
+		meta, err := s.Engine.ReadMetadataRef("branch2")
+		require.NoError(t, err)
+		require.Equal(t, forkPoint, *meta.ParentBranchRevision)
+	})
+
+	t.Run("fails when parent is not an ancestor unless forced", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+
+		s.CreateBranch("other").
+			Commit("other change")
+		require.NoError(t, s.Engine.TrackBranch(context.Background(), "other", "main"))
+		s.Checkout("main").
+			CreateBranch("feature").
+			Commit("feature change")
+
+		err := s.Engine.TrackBranch(context.Background(), "feature", "other")
+		require.ErrorContains(t, err, "parent other is not an ancestor of feature")
+		require.False(t, s.Engine.GetBranch("feature").IsTracked())
+
+		require.NoError(t, s.Engine.ForceTrackBranch(context.Background(), "feature", "other"))
+		require.Equal(t, "other", s.Engine.GetParent(s.Engine.GetBranch("feature")).GetName())
+	})
 }
 
 func TestSetParent(t *testing.T) {
@@ -449,11 +493,110 @@ func TestRestackBranches(t *testing.T) {
 		batchResult, err := s.Engine.RestackBranches(context.Background(), []engine.Branch{branch1})
 		require.NoError(t, err)
 		require.Equal(t, engine.RestackDone, batchResult.Results["branch1"].Result)
+		require.False(t, batchResult.Results["branch1"].Reparented)
+		require.Empty(t, batchResult.Results["branch1"].OldParent)
+		require.Equal(t, "main", batchResult.Results["branch1"].NewParent)
 
 		// Verify branch1 is now fixed
 		require.True(t, s.Engine.GetBranch("branch1").IsBranchUpToDate())
 	})
 
+	t.Run("reports reparenting onto trunk when the parent was merged", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "branch1",
+			})
+
+		// branch1 lands in trunk
+		s.Checkout("main").
+			RunGit("merge", "--no-ff", "-m", "merge branch1", "branch1")
+
+		branch2 := s.Engine.GetBranch("branch2")
+		batchResult, err := s.Engine.RestackBranches(context.Background(), []engine.Branch{branch2})
+		require.NoError(t, err)
+		result := batchResult.Results["branch2"]
+		require.Equal(t, engine.RestackDone, result.Result)
+		require.True(t, result.Reparented)
+		require.Equal(t, "branch1", result.OldParent)
+		require.Equal(t, "main", result.NewParent)
+
+		parent := s.Engine.GetParent(s.Engine.GetBranch("branch2"))
+		require.NotNil(t, parent)
+		require.Equal(t, "main", parent.GetName())
+	})
+
+	t.Run("reparents onto trunk when the parent was deleted locally", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "branch1",
+			})
+		s.Checkout("main").
+			RunGit("branch", "-D", "branch1").
+			Rebuild()
+		require.False(t, s.Engine.BranchExistsLocally("branch1"))
+		require.True(t, s.Engine.BranchExistsLocally("branch2"))
+
+		batchResult, err := s.Engine.RestackBranches(context.Background(), []engine.Branch{s.Engine.GetBranch("branch2")})
+		require.NoError(t, err)
+		result := batchResult.Results["branch2"]
+		require.True(t, result.Reparented)
+		require.Equal(t, "branch1", result.OldParent)
+		require.Equal(t, "main", result.NewParent)
+		require.Empty(t, result.RemoteOnlyParent)
+		s.ExpectStackStructure(map[string]string{"branch2": "main"})
+	})
+
+	t.Run("leaves a branch alone when its deleted parent is still on the remote", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "branch1",
+			})
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		s.RunGit("push", "origin", "main", "branch1").
+			Checkout("main").
+			RunGit("branch", "-D", "branch1").
+			Rebuild()
+		branch2Rev, err := s.Engine.GetBranch("branch2").GetRevision()
+		require.NoError(t, err)
+
+		batchResult, err := s.Engine.RestackBranches(context.Background(), []engine.Branch{s.Engine.GetBranch("branch2")})
+		require.NoError(t, err)
+		require.True(t, s.Engine.BranchExistsOnRemote("branch1"))
+		result := batchResult.Results["branch2"]
+		require.Equal(t, engine.RestackUnneeded, result.Result)
+		require.False(t, result.Reparented)
+		require.Equal(t, "branch1", result.RemoteOnlyParent)
+
+		s.ExpectStackStructure(map[string]string{"branch2": "branch1"})
+		rev, err := s.Engine.GetBranch("branch2").GetRevision()
+		require.NoError(t, err)
+		require.Equal(t, branch2Rev, rev)
+	})
+
+	t.Run("checks the remote-tracking branch of a deleted parent without the network", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "branch1",
+			})
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		s.RunGit("push", "origin", "main", "branch1").
+			RunGit("remote", "set-url", "origin", filepath.Join(t.TempDir(), "unreachable")).
+			Checkout("main").
+			RunGit("branch", "-D", "branch1").
+			Rebuild()
+
+		batchResult, err := s.Engine.RestackBranches(context.Background(), []engine.Branch{s.Engine.GetBranch("branch2")})
+		require.NoError(t, err)
+		require.Equal(t, "branch1", batchResult.Results["branch2"].RemoteOnlyParent)
+		s.ExpectStackStructure(map[string]string{"branch2": "branch1"})
+	})
+
 	t.Run("returns unneeded when branch is already fixed", func(t *testing.T) {
 		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
 			WithStack(map[string]string{
@@ -489,6 +632,515 @@ func TestRestackBranches(t *testing.T) {
 	})
 }
 
+func TestRestackBranchesMergeStrategy(t *testing.T) {
+	t.Run("merges parent into branch producing a merge commit", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+			})
+		originalRev, err := s.Scene.Repo.GetRevision("branch1")
+		require.NoError(t, err)
+
+		s.Checkout("main").
+			Commit("main update")
+
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot:        s.Scene.Dir,
+			Trunk:           "main",
+			RestackStrategy: engine.RestackStrategyMerge,
+		})
+		require.NoError(t, err)
+		batchResult, err := eng.RestackBranches(context.Background(), []engine.Branch{eng.GetBranch("branch1")})
+		require.NoError(t, err)
+		require.Equal(t, engine.RestackDone, batchResult.Results["branch1"].Result)
+
+		// The new tip is a merge commit whose first parent is the original branch commit
+		parents, err := s.Scene.Repo.RunGitCommandAndGetOutput("rev-list", "--parents", "-n", "1", "branch1")
+		require.NoError(t, err)
+		fields := strings.Fields(parents)
+		require.Len(t, fields, 3, "expected a merge commit with two parents")
+		require.Equal(t, originalRev, fields[1])
+
+		mainRev, err := s.Scene.Repo.GetRevision("main")
+		require.NoError(t, err)
+		require.Equal(t, mainRev, fields[2])
+
+		require.True(t, eng.GetBranch("branch1").IsBranchUpToDate())
+
+		// Restacking again is a no-op
+		batchResult, err = eng.RestackBranches(context.Background(), []engine.Branch{eng.GetBranch("branch1")})
+		require.NoError(t, err)
+		require.Equal(t, engine.RestackUnneeded, batchResult.Results["branch1"].Result)
+	})
+
+	t.Run("reports conflict when merge conflicts", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			CreateBranch("branch1").
+			CommitChange("shared", "branch1 content").
+			TrackBranch("branch1", "main").
+			Checkout("main").
+			CommitChange("shared", "main content")
+
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot:        s.Scene.Dir,
+			Trunk:           "main",
+			RestackStrategy: engine.RestackStrategyMerge,
+		})
+		require.NoError(t, err)
+		batchResult, err := eng.RestackBranches(context.Background(), []engine.Branch{eng.GetBranch("branch1")})
+		require.NoError(t, err)
+		require.Equal(t, "branch1", batchResult.ConflictBranch)
+		require.Equal(t, engine.RestackConflict, batchResult.Results["branch1"].Result)
+
+		_, err = s.Scene.Repo.RunGitCommandAndGetOutput("rev-parse", "-q", "--verify", "MERGE_HEAD")
+		require.NoError(t, err, "merge should be left in progress")
+	})
+
+	t.Run("merged branch fast-forwards its remote", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+			})
+
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "main"))
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "branch1"))
+
+		s.Checkout("main").
+			Commit("main update")
+
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot:        s.Scene.Dir,
+			Trunk:           "main",
+			RestackStrategy: engine.RestackStrategyMerge,
+		})
+		require.NoError(t, err)
+		batchResult, err := eng.RestackBranches(context.Background(), []engine.Branch{eng.GetBranch("branch1")})
+		require.NoError(t, err)
+		require.Equal(t, engine.RestackDone, batchResult.Results["branch1"].Result)
+
+		require.NoError(t, eng.PopulateRemoteShas())
+
+		matches, err := eng.BranchMatchesRemote("branch1")
+		require.NoError(t, err)
+		require.False(t, matches, "branch has a new merge commit that isn't pushed")
+
+		fastForward, err := eng.BranchFastForwardsRemote("branch1")
+		require.NoError(t, err)
+		require.True(t, fastForward, "merge restack should not require a force push")
+	})
+}
+
+func TestRestackBranchesPreserveDates(t *testing.T) {
+	t.Run("keeps committer dates equal to author dates", func(t *testing.T) {
+		// branch1's commit was authored (and committed) in the past, then main moves on
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+			})
+		s.Checkout("branch1")
+		_, err := s.Scene.Repo.RunGitCommandAndGetOutput("-c", "core.editor=true", "commit", "--amend", "--no-edit",
+			"--date=2020-01-02T03:04:05Z")
+		require.NoError(t, err)
+		s.Checkout("main").
+			Commit("main update")
+
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot:      s.Scene.Dir,
+			Trunk:         "main",
+			PreserveDates: true,
+		})
+		require.NoError(t, err)
+
+		batchResult, err := eng.RestackBranches(context.Background(), []engine.Branch{eng.GetBranch("branch1")})
+		require.NoError(t, err)
+		require.Equal(t, engine.RestackDone, batchResult.Results["branch1"].Result)
+		require.True(t, eng.GetBranch("branch1").IsBranchUpToDate())
+
+		out, err := s.Scene.Repo.RunGitCommandAndGetOutput("log", "-1", "--format=%at %ct", "branch1")
+		require.NoError(t, err)
+		authorDate, committerDate, _ := strings.Cut(strings.TrimSpace(out), " ")
+		require.Equal(t, "1577934245", authorDate)
+		require.Equal(t, authorDate, committerDate)
+	})
+
+	t.Run("resets committer dates by default", func(t *testing.T) {
+		// branch1's commit was authored (and committed) in the past, then main moves on
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+			})
+		s.Checkout("branch1")
+		_, err := s.Scene.Repo.RunGitCommandAndGetOutput("-c", "core.editor=true", "commit", "--amend", "--no-edit",
+			"--date=2020-01-02T03:04:05Z")
+		require.NoError(t, err)
+		s.Checkout("main").
+			Commit("main update")
+
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot: s.Scene.Dir,
+			Trunk:    "main",
+		})
+		require.NoError(t, err)
+
+		_, err = eng.RestackBranches(context.Background(), []engine.Branch{eng.GetBranch("branch1")})
+		require.NoError(t, err)
+
+		out, err := s.Scene.Repo.RunGitCommandAndGetOutput("log", "-1", "--format=%at %ct", "branch1")
+		require.NoError(t, err)
+		authorDate, committerDate, _ := strings.Cut(strings.TrimSpace(out), " ")
+		require.Equal(t, "1577934245", authorDate)
+		require.NotEqual(t, authorDate, committerDate)
+	})
+}
+
+func TestRestackBranchesKeepEmpty(t *testing.T) {
+	t.Run("drops commits already in the parent", func(t *testing.T) {
+		// branch2's change has already landed on branch1
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "branch1",
+			})
+		s.Checkout("branch1").
+			RunGit("cherry-pick", "branch2")
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot: s.Scene.Dir,
+			Trunk:    "main",
+		})
+		require.NoError(t, err)
+		eng.SetKeepEmpty(false)
+
+		batchResult, err := eng.RestackBranches(context.Background(), []engine.Branch{eng.GetBranch("branch2")})
+		require.NoError(t, err)
+		result := batchResult.Results["branch2"]
+		require.Equal(t, engine.RestackDone, result.Result)
+		require.True(t, result.Empty)
+
+		// No empty commit is left on top of branch1
+		parentRev, err := s.Scene.Repo.GetRevision("branch1")
+		require.NoError(t, err)
+		childRev, err := s.Scene.Repo.GetRevision("branch2")
+		require.NoError(t, err)
+		require.Equal(t, parentRev, childRev)
+		require.True(t, eng.GetBranch("branch2").IsTracked())
+	})
+
+	t.Run("keeps them as empty commits when asked", func(t *testing.T) {
+		// branch2's change has already landed on branch1
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "branch1",
+			})
+		s.Checkout("branch1").
+			RunGit("cherry-pick", "branch2")
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot: s.Scene.Dir,
+			Trunk:    "main",
+		})
+		require.NoError(t, err)
+		eng.SetKeepEmpty(true)
+
+		batchResult, err := eng.RestackBranches(context.Background(), []engine.Branch{eng.GetBranch("branch2")})
+		require.NoError(t, err)
+		require.False(t, batchResult.Results["branch2"].Empty)
+
+		count, err := s.Scene.Repo.RunGitCommandAndGetOutput("rev-list", "--count", "branch1..branch2")
+		require.NoError(t, err)
+		require.Equal(t, "1", strings.TrimSpace(count))
+	})
+
+	t.Run("lets merged mode prune the emptied branch", func(t *testing.T) {
+		// branch2's change has already landed on branch1
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "branch1",
+			})
+		s.Checkout("branch1").
+			RunGit("cherry-pick", "branch2")
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot:   s.Scene.Dir,
+			Trunk:      "main",
+			PruneEmpty: engine.PruneEmptyMerged,
+		})
+		require.NoError(t, err)
+		eng.SetKeepEmpty(false)
+
+		batchResult, err := eng.RestackBranches(context.Background(), []engine.Branch{eng.GetBranch("branch2")})
+		require.NoError(t, err)
+		require.Equal(t, engine.RestackPruned, batchResult.Results["branch2"].Result)
+		require.False(t, eng.GetBranch("branch2").IsTracked())
+	})
+}
+
+func TestPullTrunkStrategies(t *testing.T) {
+	t.Run("ff-only reports a conflict and leaves trunk alone", func(t *testing.T) {
+		// Local main has one commit the remote lacks, and the remote one local main lacks
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+			})
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		s.Checkout("main").
+			CommitChange("remote-only", "remote only")
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "main"))
+
+		s.RunGit("reset", "--hard", "HEAD~1").
+			CommitChange("local-only", "local only").
+			Checkout("branch1")
+
+		localTip, err := s.Scene.Repo.GetRevision("main")
+		require.NoError(t, err)
+
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot:      s.Scene.Dir,
+			Trunk:         "main",
+			TrunkStrategy: engine.TrunkStrategyFFOnly,
+		})
+		require.NoError(t, err)
+		report, err := eng.PullTrunk(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, engine.PullConflict, report.Result)
+
+		tip, err := s.Scene.Repo.GetRevision("main")
+		require.NoError(t, err)
+		require.Equal(t, localTip, tip)
+
+		require.Equal(t, 1, report.Ahead)
+		require.Equal(t, 1, report.Behind)
+		require.Equal(t, localTip, report.NewRev)
+	})
+
+	t.Run("reports when trunk is already up to date", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "main"))
+		tip, err := s.Scene.Repo.GetRevision("main")
+		require.NoError(t, err)
+
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot:      s.Scene.Dir,
+			Trunk:         "main",
+			TrunkStrategy: engine.TrunkStrategyFFOnly,
+		})
+		require.NoError(t, err)
+		report, err := eng.PullTrunk(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, engine.PullUnneeded, report.Result)
+		require.Equal(t, tip, report.NewRev)
+	})
+
+	t.Run("reports how many commits trunk was fast-forwarded", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		s.Checkout("main").
+			CommitChange("remote-1", "remote 1").
+			CommitChange("remote-2", "remote 2")
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "main"))
+		remoteTip, err := s.Scene.Repo.GetRevision("main")
+		require.NoError(t, err)
+		s.RunGit("reset", "--hard", "HEAD~2")
+
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot:      s.Scene.Dir,
+			Trunk:         "main",
+			TrunkStrategy: engine.TrunkStrategyFFOnly,
+		})
+		require.NoError(t, err)
+		report, err := eng.PullTrunk(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, engine.PullDone, report.Result)
+		require.Equal(t, 2, report.Behind)
+		require.Zero(t, report.Ahead)
+		require.Equal(t, remoteTip, report.NewRev)
+	})
+
+	t.Run("rebase replays local trunk commits onto the remote", func(t *testing.T) {
+		// Local main has one commit the remote lacks, and the remote one local main lacks
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+			})
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		s.Checkout("main").
+			CommitChange("remote-only", "remote only")
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "main"))
+		remoteTip, err := s.Scene.Repo.GetRevision("main")
+		require.NoError(t, err)
+
+		s.RunGit("reset", "--hard", "HEAD~1").
+			CommitChange("local-only", "local only").
+			Checkout("branch1")
+
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot:      s.Scene.Dir,
+			Trunk:         "main",
+			TrunkStrategy: engine.TrunkStrategyRebase,
+		})
+		require.NoError(t, err)
+		report, err := eng.PullTrunk(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, engine.PullRebased, report.Result)
+
+		parent, err := s.Scene.Repo.GetRevision("main~1")
+		require.NoError(t, err)
+		require.Equal(t, remoteTip, parent)
+		subject, err := s.Scene.Repo.RunGitCommandAndGetOutput("log", "-1", "--format=%s", "main")
+		require.NoError(t, err)
+		require.Equal(t, "local only", strings.TrimSpace(subject))
+
+		// The checkout is restored
+		current, err := s.Scene.Repo.CurrentBranchName()
+		require.NoError(t, err)
+		require.Equal(t, "branch1", current)
+	})
+
+	t.Run("reset-to-remote discards local trunk commits", func(t *testing.T) {
+		// Local main has one commit the remote lacks, and the remote one local main lacks
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+			})
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+
+		s.Checkout("main").
+			CommitChange("remote-only", "remote only")
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "main"))
+		remoteTip, err := s.Scene.Repo.GetRevision("main")
+		require.NoError(t, err)
+
+		s.RunGit("reset", "--hard", "HEAD~1").
+			CommitChange("local-only", "local only").
+			Checkout("branch1")
+
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot:      s.Scene.Dir,
+			Trunk:         "main",
+			TrunkStrategy: engine.TrunkStrategyResetToRemote,
+		})
+		require.NoError(t, err)
+		report, err := eng.PullTrunk(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, engine.PullReset, report.Result)
+
+		tip, err := s.Scene.Repo.GetRevision("main")
+		require.NoError(t, err)
+		require.Equal(t, remoteTip, tip)
+	})
+}
+
+func TestRestackBranchesPruneEmpty(t *testing.T) {
+	t.Run("deletes a merged empty branch and moves its children to the parent", func(t *testing.T) {
+		// branch1's change has already landed on main
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "branch1",
+			})
+		s.Checkout("main").
+			CommitChange("trunk", "unrelated trunk change").
+			RunGit("cherry-pick", "branch1")
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot:   s.Scene.Dir,
+			Trunk:      "main",
+			PruneEmpty: engine.PruneEmptyMerged,
+		})
+		require.NoError(t, err)
+
+		batchResult, err := eng.RestackBranches(context.Background(), []engine.Branch{eng.GetBranch("branch1"), eng.GetBranch("branch2")})
+		require.NoError(t, err)
+		require.Equal(t, engine.RestackPruned, batchResult.Results["branch1"].Result)
+		require.Equal(t, "main", batchResult.Results["branch1"].NewParent)
+		require.Equal(t, engine.RestackDone, batchResult.Results["branch2"].Result)
+
+		require.False(t, eng.GetBranch("branch1").IsTracked())
+		_, err = s.Scene.Repo.GetRevision("branch1")
+		require.Error(t, err, "branch1 should be deleted")
+
+		parent := eng.GetParent(eng.GetBranch("branch2"))
+		require.NotNil(t, parent)
+		require.Equal(t, "main", parent.GetName())
+		require.True(t, eng.GetBranch("branch2").IsBranchUpToDate())
+
+		// Only branch2's own commit is replayed on top of main
+		count, err := s.Scene.Repo.RunGitCommandAndGetOutput("rev-list", "--count", "main..branch2")
+		require.NoError(t, err)
+		require.Equal(t, "1", strings.TrimSpace(count))
+	})
+
+	t.Run("keeps empty branches by default", func(t *testing.T) {
+		// branch1's change has already landed on main
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "branch1",
+			})
+		s.Checkout("main").
+			CommitChange("trunk", "unrelated trunk change").
+			RunGit("cherry-pick", "branch1")
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot: s.Scene.Dir,
+			Trunk:    "main",
+		})
+		require.NoError(t, err)
+
+		batchResult, err := eng.RestackBranches(context.Background(), []engine.Branch{eng.GetBranch("branch1"), eng.GetBranch("branch2")})
+		require.NoError(t, err)
+		require.Equal(t, engine.RestackDone, batchResult.Results["branch1"].Result)
+		require.True(t, eng.GetBranch("branch1").IsTracked())
+	})
+
+	t.Run("keeps intentionally empty branches in merged mode", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithInitialCommit().
+			CreateBranch("placeholder").
+			TrackBranch("placeholder", "main").
+			Checkout("main").
+			Commit("main update")
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot:   s.Scene.Dir,
+			Trunk:      "main",
+			PruneEmpty: engine.PruneEmptyMerged,
+		})
+		require.NoError(t, err)
+
+		batchResult, err := eng.RestackBranches(context.Background(), []engine.Branch{eng.GetBranch("placeholder")})
+		require.NoError(t, err)
+		require.Equal(t, engine.RestackDone, batchResult.Results["placeholder"].Result)
+		require.True(t, eng.GetBranch("placeholder").IsTracked())
+	})
+
+	t.Run("deletes any empty branch in always mode", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithInitialCommit().
+			CreateBranch("placeholder").
+			TrackBranch("placeholder", "main").
+			Checkout("main").
+			Commit("main update")
+		eng, err := engine.NewEngine(engine.Options{
+			RepoRoot:   s.Scene.Dir,
+			Trunk:      "main",
+			PruneEmpty: engine.PruneEmptyAlways,
+		})
+		require.NoError(t, err)
+
+		batchResult, err := eng.RestackBranches(context.Background(), []engine.Branch{eng.GetBranch("placeholder")})
+		require.NoError(t, err)
+		require.Equal(t, engine.RestackPruned, batchResult.Results["placeholder"].Result)
+		require.False(t, eng.GetBranch("placeholder").IsTracked())
+	})
+}
+
 func TestRebuild(t *testing.T) {
 	t.Run("rebuilds cache from Git state", func(t *testing.T) {
 		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
@@ -529,6 +1181,71 @@ func TestRebuild(t *testing.T) {
 	})
 }
 
+func TestRebuildBranches(t *testing.T) {
+	// snapshot describes every branch's parent, children and scope as an engine sees them
+	snapshot := func(eng engine.Engine) map[string]string {
+		result := map[string]string{}
+		for _, branch := range eng.AllBranches() {
+			name := branch.GetName()
+			parent := ""
+			if p := eng.GetParent(branch); p != nil {
+				parent = p.GetName()
+			}
+			children := []string{}
+			for _, child := range eng.GetChildrenInternal(name) {
+				children = append(children, child.GetName())
+			}
+			result[name] = fmt.Sprintf("parent=%s children=%s scope=%s", parent, strings.Join(children, ","),
+				eng.GetExplicitScopeInternal(name))
+		}
+		return result
+	}
+
+	t.Run("matches a full rebuild after localized changes", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"a": "main",
+				"b": "a",
+				"c": "b",
+			})
+
+		// Change a few branches through another engine, leaving s.Engine's maps stale
+		other, err := engine.NewEngine(engine.Options{RepoRoot: s.Scene.Dir, Trunk: "main"})
+		require.NoError(t, err)
+		s.RunGit("branch", "d", "b")
+		require.NoError(t, other.TrackBranch(context.Background(), "d", "b"))
+		require.NoError(t, other.SetScope(other.GetBranch("d"), engine.NewScope("PROJ-1")))
+		require.NoError(t, other.SetParent(context.Background(), other.GetBranch("c"), other.GetBranch("a")))
+		require.NoError(t, other.UntrackBranch("b"))
+
+		require.NoError(t, s.Engine.RebuildBranches("b", "c", "d"))
+		incremental := snapshot(s.Engine)
+
+		full, err := engine.NewEngine(engine.Options{RepoRoot: s.Scene.Dir, Trunk: "main"})
+		require.NoError(t, err)
+		require.Equal(t, snapshot(full), incremental)
+		require.Equal(t, "parent=b children= scope=PROJ-1", incremental["d"])
+		require.Equal(t, "parent=a children= scope=", incremental["c"])
+		require.False(t, s.Engine.GetBranch("b").IsTracked())
+	})
+
+	t.Run("drops deleted branches", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"a": "main",
+				"b": "main",
+			})
+		s.Checkout("main").RunGit("branch", "-D", "b")
+
+		require.NoError(t, s.Engine.RebuildBranches("b"))
+
+		full, err := engine.NewEngine(engine.Options{RepoRoot: s.Scene.Dir, Trunk: "main"})
+		require.NoError(t, err)
+		require.Equal(t, snapshot(full), snapshot(s.Engine))
+		require.NotContains(t, snapshot(s.Engine), "b")
+	})
+}
+
 func TestIsBranchTracked(t *testing.T) {
 	t.Run("returns true for tracked branch", func(t *testing.T) {
 		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
@@ -588,6 +1305,30 @@ func TestGetParentPrecondition(t *testing.T) {
 	})
 }
 
+func TestBatchGetParents(t *testing.T) {
+	t.Run("matches individual lookups for tracked and untracked branches", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "branch1",
+			}).
+			Checkout("main").
+			CreateBranch("untracked").
+			Commit("untracked change").
+			Checkout("main")
+		s.Rebuild()
+
+		names := []string{"branch2", "untracked", "branch1", "main"}
+		parents := s.Engine.BatchGetParents(names)
+		require.Len(t, parents, len(names))
+		for _, name := range names {
+			require.Equal(t, s.Engine.GetBranch(name).GetParentPrecondition(), parents[name], name)
+		}
+		require.Equal(t, "branch1", parents["branch2"])
+		require.Equal(t, "main", parents["untracked"])
+	})
+}
+
 func TestIsMergedIntoTrunk(t *testing.T) {
 	t.Run("returns false for unmerged branch", func(t *testing.T) {
 		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
@@ -595,32 +1336,151 @@ func TestIsMergedIntoTrunk(t *testing.T) {
 			Commit("branch1 change").
 			Checkout("main")
 
-		merged, err := s.Engine.IsMergedIntoTrunk(context.Background(), "branch1")
+		merged, err := s.Engine.IsMergedIntoTrunk(context.Background(), "branch1")
+		require.NoError(t, err)
+		require.False(t, merged)
+	})
+}
+
+func TestIsBranchEmpty(t *testing.T) {
+	t.Run("returns false for branch with changes", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			CreateBranch("branch1").
+			CommitChange("file1", "branch1 change").
+			Checkout("main")
+
+		empty, err := s.Engine.IsBranchEmpty(context.Background(), "branch1")
+		require.NoError(t, err)
+		require.False(t, empty)
+	})
+
+	t.Run("returns true for branch with no changes", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			CreateBranch("branch1").
+			Checkout("main")
+
+		empty, err := s.Engine.IsBranchEmpty(context.Background(), "branch1")
+		require.NoError(t, err)
+		require.True(t, empty)
+	})
+
+	t.Run("returns true for branch with only an empty commit", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			CreateBranch("branch1").
+			Commit("placeholder").
+			Checkout("main")
+
+		empty, err := s.Engine.IsBranchEmpty(context.Background(), "branch1")
+		require.NoError(t, err)
+		require.True(t, empty)
+	})
+}
+
+func TestGetBranchContent(t *testing.T) {
+	s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+		CreateBranch("no-commits").
+		Checkout("main").
+		CreateBranch("empty-commit").
+		Commit("placeholder").
+		Checkout("main").
+		CreateBranch("changes").
+		Commit("placeholder").
+		CommitChange("file1", "real change").
+		Checkout("main")
+
+	for branchName, expected := range map[string]engine.BranchContent{
+		"no-commits":   engine.BranchContentNone,
+		"empty-commit": engine.BranchContentEmptyCommits,
+		"changes":      engine.BranchContentChanges,
+	} {
+		content, err := s.Engine.GetBranchContent(context.Background(), branchName)
+		require.NoError(t, err)
+		require.Equal(t, expected, content, branchName)
+	}
+}
+
+func TestFindTargetCommitForHunk(t *testing.T) {
+	// big.txt has enough lines that edits at its top, middle and bottom are separate hunks
+	lines := make([]string, 30)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	bigTxt := strings.Join(lines, "\n") + "\n"
+
+	t.Run("targets the only commit that changed the file when no lines overlap", func(t *testing.T) {
+		// feature's first commit edits the top of big.txt and its second adds other.txt
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		require.NoError(t, os.WriteFile(filepath.Join(s.Scene.Dir, "big.txt"), []byte(bigTxt), 0600))
+		s.RunGit("add", "big.txt").RunGit("commit", "-m", "add big.txt").
+			CreateBranch("feature")
+		require.NoError(t, os.WriteFile(filepath.Join(s.Scene.Dir, "big.txt"),
+			[]byte(strings.NewReplacer("line 1\n", "top\n").Replace(bigTxt)), 0600))
+		s.RunGit("commit", "-am", "edit top of big.txt")
+		require.NoError(t, os.WriteFile(filepath.Join(s.Scene.Dir, "other.txt"), []byte(bigTxt), 0600))
+		s.RunGit("add", "other.txt").RunGit("commit", "-m", "add other.txt")
+		shas, err := s.Scene.Repo.RunGitCommandAndGetOutput("rev-list", "main..feature")
 		require.NoError(t, err)
-		require.False(t, merged)
+
+		require.NoError(t, os.WriteFile(filepath.Join(s.Scene.Dir, "big.txt"),
+			[]byte(strings.NewReplacer("line 1\n", "top\n", "line 30\n", "bottom\n").Replace(bigTxt)), 0600))
+		s.RunGit("add", "big.txt")
+		hunks, err := s.Engine.ParseStagedHunks(context.Background())
+		require.NoError(t, err)
+		require.Len(t, hunks, 1)
+
+		sha, index, err := s.Engine.FindTargetCommitForHunk(hunks[0], strings.Split(shas, "\n"))
+		require.NoError(t, err)
+		require.Equal(t, strings.Split(shas, "\n")[1], sha)
+		require.Equal(t, 1, index)
 	})
-}
 
-func TestIsBranchEmpty(t *testing.T) {
-	t.Run("returns false for branch with changes", func(t *testing.T) {
-		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
-			CreateBranch("branch1").
-			CommitChange("file1", "branch1 change").
-			Checkout("main")
+	t.Run("finds no target when several commits changed the file", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		require.NoError(t, os.WriteFile(filepath.Join(s.Scene.Dir, "big.txt"), []byte(bigTxt), 0600))
+		s.RunGit("add", "big.txt").RunGit("commit", "-m", "add big.txt").
+			CreateBranch("feature")
+		require.NoError(t, os.WriteFile(filepath.Join(s.Scene.Dir, "big.txt"),
+			[]byte(strings.NewReplacer("line 1\n", "top\n").Replace(bigTxt)), 0600))
+		s.RunGit("commit", "-am", "edit top of big.txt")
+		require.NoError(t, os.WriteFile(filepath.Join(s.Scene.Dir, "big.txt"),
+			[]byte(strings.NewReplacer("line 1\n", "top\n", "line 15\n", "middle\n").Replace(bigTxt)), 0600))
+		s.RunGit("commit", "-am", "edit middle of big.txt")
+		shas, err := s.Scene.Repo.RunGitCommandAndGetOutput("rev-list", "main..feature")
+		require.NoError(t, err)
 
-		empty, err := s.Engine.IsBranchEmpty(context.Background(), "branch1")
+		require.NoError(t, os.WriteFile(filepath.Join(s.Scene.Dir, "big.txt"),
+			[]byte(strings.NewReplacer("line 1\n", "top\n", "line 15\n", "middle\n", "line 30\n", "bottom\n").Replace(bigTxt)), 0600))
+		s.RunGit("add", "big.txt")
+		hunks, err := s.Engine.ParseStagedHunks(context.Background())
 		require.NoError(t, err)
-		require.False(t, empty)
+		require.Len(t, hunks, 1)
+
+		sha, index, err := s.Engine.FindTargetCommitForHunk(hunks[0], strings.Split(shas, "\n"))
+		require.NoError(t, err)
+		require.Empty(t, sha)
+		require.Equal(t, -1, index)
 	})
 
-	t.Run("returns true for branch with no changes", func(t *testing.T) {
-		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
-			CreateBranch("branch1").
-			Checkout("main")
+	t.Run("finds no target for a new file", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		require.NoError(t, os.WriteFile(filepath.Join(s.Scene.Dir, "big.txt"), []byte(bigTxt), 0600))
+		s.RunGit("add", "big.txt").RunGit("commit", "-m", "add big.txt").
+			CreateBranch("feature")
+		require.NoError(t, os.WriteFile(filepath.Join(s.Scene.Dir, "big.txt"),
+			[]byte(strings.NewReplacer("line 1\n", "top\n").Replace(bigTxt)), 0600))
+		s.RunGit("commit", "-am", "edit top of big.txt")
+		shas, err := s.Scene.Repo.RunGitCommandAndGetOutput("rev-list", "main..feature")
+		require.NoError(t, err)
 
-		empty, err := s.Engine.IsBranchEmpty(context.Background(), "branch1")
+		require.NoError(t, os.WriteFile(filepath.Join(s.Scene.Dir, "new.txt"), []byte(bigTxt), 0600))
+		s.RunGit("add", "new.txt")
+		hunks, err := s.Engine.ParseStagedHunks(context.Background())
 		require.NoError(t, err)
-		require.True(t, empty)
+		require.Len(t, hunks, 1)
+
+		sha, _, err := s.Engine.FindTargetCommitForHunk(hunks[0], strings.Split(shas, "\n"))
+		require.NoError(t, err)
+		require.Empty(t, sha)
 	})
 }
 
@@ -679,6 +1539,34 @@ func TestUpsertPrInfo(t *testing.T) {
 		require.Equal(t, "Updated Title", retrieved.Title())
 		require.Equal(t, "Updated body", retrieved.Body())
 	})
+
+	t.Run("reports stale once the branch moves past the submitted head", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+			})
+
+		branch := s.Engine.GetBranch("branch1")
+		submitted, err := branch.GetRevision()
+		require.NoError(t, err)
+		require.NoError(t, s.Engine.UpsertPrInfo(branch, testhelpers.NewTestPrInfoWithTitle(123, "PR").WithHeadSHA(submitted)))
+
+		retrieved, err := s.Engine.GetPrInfo(branch)
+		require.NoError(t, err)
+		require.Equal(t, submitted, retrieved.HeadSHA())
+		require.False(t, retrieved.IsStale())
+
+		s.Checkout("branch1").Commit("unsubmitted work").Rebuild()
+		retrieved, err = s.Engine.GetPrInfo(s.Engine.GetBranch("branch1"))
+		require.NoError(t, err)
+		require.True(t, retrieved.IsStale())
+
+		// Updates that don't come from a submit keep the recorded head
+		require.NoError(t, s.Engine.UpsertPrInfo(branch, retrieved.WithTitle("Retitled")))
+		retrieved, err = s.Engine.GetPrInfo(s.Engine.GetBranch("branch1"))
+		require.NoError(t, err)
+		require.Equal(t, submitted, retrieved.HeadSHA())
+	})
 }
 
 func TestGetRelativeStackUpstack(t *testing.T) {
@@ -703,6 +1591,130 @@ func TestGetRelativeStackUpstack(t *testing.T) {
 	})
 }
 
+func TestSortBranchesTopologically(t *testing.T) {
+	t.Run("orders same-depth siblings by age, then name", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"sib-a": "main",
+				"sib-b": "main",
+				"sib-c": "main",
+			})
+
+		// sib-b is the oldest; sib-a and sib-c tie, so name decides between them
+		for branch, date := range map[string]string{
+			"sib-a": "2024-01-02T00:00:00Z",
+			"sib-b": "2024-01-01T00:00:00Z",
+			"sib-c": "2024-01-02T00:00:00Z",
+		} {
+			s.Checkout(branch).
+				RunGit("commit", "--amend", "--no-edit", "--date="+date)
+		}
+		s.Checkout("sib-c").
+			CreateBranch("child").
+			CommitChange("child", "child change").
+			TrackBranch("child", "sib-c").
+			Rebuild()
+
+		expected := []string{"sib-b", "sib-a", "sib-c", "child"}
+		for _, input := range [][]string{
+			{"child", "sib-c", "sib-b", "sib-a"},
+			{"sib-a", "child", "sib-c", "sib-b"},
+		} {
+			branches := make([]engine.Branch, len(input))
+			for i, name := range input {
+				branches[i] = s.Engine.GetBranch(name)
+			}
+
+			sorted := s.Engine.SortBranchesTopologically(branches)
+			names := make([]string, len(sorted))
+			for i, branch := range sorted {
+				names[i] = branch.GetName()
+			}
+			require.Equal(t, expected, names)
+		}
+	})
+	t.Run("puts siblings without a date after the dated ones, by name", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"sib-a": "main",
+				"sib-b": "main",
+			})
+		for branch, date := range map[string]string{
+			"sib-a": "2024-01-02T00:00:00Z",
+			"sib-b": "2024-01-01T00:00:00Z",
+		} {
+			s.Checkout(branch).
+				RunGit("commit", "--amend", "--no-edit", "--date="+date)
+		}
+		s.Checkout("main").Rebuild()
+
+		// gone-a and gone-z have no ref, so their dates can't be read
+		expected := []string{"sib-b", "sib-a", "gone-a", "gone-z"}
+		for _, input := range [][]string{
+			{"gone-z", "sib-a", "gone-a", "sib-b"},
+			{"sib-a", "gone-a", "sib-b", "gone-z"},
+			{"gone-a", "gone-z", "sib-b", "sib-a"},
+		} {
+			branches := make([]engine.Branch, len(input))
+			for i, name := range input {
+				branches[i] = s.Engine.GetBranch(name)
+			}
+
+			sorted := s.Engine.SortBranchesTopologically(branches)
+			names := make([]string, len(sorted))
+			for i, branch := range sorted {
+				names[i] = branch.GetName()
+			}
+			require.Equal(t, expected, names)
+		}
+	})
+}
+
+func TestGetChildrenOrder(t *testing.T) {
+	childNames := func(s *scenario.Scenario, parent string) []string {
+		children := s.Engine.GetBranch(parent).GetChildren()
+		names := make([]string, len(children))
+		for i, child := range children {
+			names[i] = child.GetName()
+		}
+		return names
+	}
+
+	s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+		WithStack(map[string]string{
+			"zeta":  "main",
+			"alpha": "main",
+			"mid":   "main",
+			"beta":  "main",
+		})
+
+	// zeta is the oldest; alpha and beta tie, so name decides between them
+	for branch, date := range map[string]string{
+		"zeta":  "2024-01-01T00:00:00Z",
+		"alpha": "2024-01-03T00:00:00Z",
+		"mid":   "2024-01-02T00:00:00Z",
+		"beta":  "2024-01-03T00:00:00Z",
+	} {
+		s.Checkout(branch).
+			RunGit("commit", "--amend", "--no-edit", "--date="+date)
+	}
+	s.Checkout("main").Rebuild()
+
+	expected := []string{"zeta", "mid", "alpha", "beta"}
+	require.Equal(t, expected, childNames(s, "main"))
+
+	// Rebuilding again gives the same order
+	s.Rebuild()
+	require.Equal(t, expected, childNames(s, "main"))
+
+	// A branch tracked later is slotted into place without a rebuild
+	s.CreateBranch("newest").
+		CommitChange("newest", "newest change").
+		RunGit("commit", "--amend", "--no-edit", "--date=2024-01-02T12:00:00Z").
+		TrackBranch("newest", "main")
+	require.Equal(t, []string{"zeta", "mid", "newest", "alpha", "beta"}, childNames(s, "main"))
+}
+
 func TestReset(t *testing.T) {
 	t.Run("resets engine with new trunk", func(t *testing.T) {
 		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
@@ -723,6 +1735,39 @@ func TestReset(t *testing.T) {
 		require.Contains(t, branchNames, "branch1")
 		require.False(t, s.Engine.GetBranch("branch1").IsTracked())
 	})
+
+	t.Run("preserves PR info when requested", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "main",
+			})
+
+		branch1 := s.Engine.GetBranch("branch1")
+		err := s.Engine.UpsertPrInfo(branch1, testhelpers.NewTestPrInfoFull(
+			123, "Test PR", "Test body", "OPEN", "main", "https://github.com/owner/repo/pull/123", false,
+		))
+		require.NoError(t, err)
+
+		err = s.Engine.ResetWithOptions("main", engine.ResetOptions{PreservePRInfo: true})
+		require.NoError(t, err)
+
+		// Tracking is cleared for every branch
+		require.False(t, s.Engine.GetBranch("branch1").IsTracked())
+		require.False(t, s.Engine.GetBranch("branch2").IsTracked())
+		require.Nil(t, s.Engine.GetParent(s.Engine.GetBranch("branch1")))
+
+		// PR info survives the reset
+		retrieved, err := s.Engine.GetPrInfo(s.Engine.GetBranch("branch1"))
+		require.NoError(t, err)
+		require.NotNil(t, retrieved)
+		require.Equal(t, 123, *retrieved.Number())
+		require.Equal(t, "Test PR", retrieved.Title())
+
+		retrieved, err = s.Engine.GetPrInfo(s.Engine.GetBranch("branch2"))
+		require.NoError(t, err)
+		require.Nil(t, retrieved)
+	})
 }
 
 func TestConcurrentAccess(t *testing.T) {
@@ -812,6 +1857,56 @@ func TestBranchMatchesRemote(t *testing.T) {
 		require.False(t, matches, "branch should not match remote with local changes")
 	})
 
+	t.Run("prefers the push remote in a fork workflow", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+
+		// Trunk comes from upstream; branches are pushed to a personal fork
+		_, err := s.Scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		_, err = s.Scene.Repo.CreateBareRemote("fork")
+		require.NoError(t, err)
+		require.NoError(t, s.Scene.Repo.RunGitCommand("config", "remote.pushDefault", "fork"))
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "main"))
+
+		// The fork's copy of main is stale
+		s.Commit("upstream change")
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "main"))
+		require.NoError(t, s.Scene.Repo.RunGitCommand("push", "fork", "main~1:refs/heads/main"))
+
+		// Upstream has an older copy of feature than the fork
+		s.CreateBranch("feature").
+			Commit("feature change")
+		require.NoError(t, s.Scene.Repo.PushBranch("origin", "feature"))
+		s.Commit("another feature change")
+		require.NoError(t, s.Scene.Repo.PushBranch("fork", "feature"))
+		s.Checkout("main")
+
+		require.Equal(t, "origin", s.Engine.GetRemote())
+		require.Equal(t, "fork", s.Engine.GetPushRemote())
+		require.NoError(t, s.Engine.PopulateRemoteShas())
+
+		matches, err := s.Engine.BranchMatchesRemote("feature")
+		require.NoError(t, err)
+		require.True(t, matches, "feature should match the push remote's SHA")
+
+		// Trunk is compared with the fetch remote, not the fork's stale copy
+		matches, err = s.Engine.BranchMatchesRemote("main")
+		require.NoError(t, err)
+		require.True(t, matches, "main should match the fetch remote's SHA")
+		ahead, behind, err := s.Engine.GetBranchRemoteDivergence("main")
+		require.NoError(t, err)
+		require.Zero(t, ahead)
+		require.Zero(t, behind)
+
+		diff, err := s.Engine.GetBranchRemoteDifference("feature", "")
+		require.NoError(t, err)
+		require.Empty(t, diff)
+
+		diff, err = s.Engine.GetBranchRemoteDifference("feature", "origin")
+		require.NoError(t, err)
+		require.Contains(t, diff, "local is ahead of remote")
+	})
+
 	t.Run("returns false when branch does not exist on remote", func(t *testing.T) {
 		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
 
@@ -1120,6 +2215,35 @@ func TestDetachAndResetBranchChanges(t *testing.T) {
 		hasUntracked, _ := s.Scene.Repo.HasUntrackedFiles()
 		require.True(t, hasUntracked, "new files should appear as untracked")
 	})
+
+	t.Run("stages the changes when requested", func(t *testing.T) {
+		s := scenario.NewScenario(t, nil)
+		err := s.Scene.Repo.CreateChangeAndCommit("initial", "shared")
+		require.NoError(t, err)
+
+		// One modified file and one new file
+		s.CreateBranch("feature").
+			CommitChange("shared", "modify shared").
+			CommitChange("newfile", "add newfile").
+			TrackBranch("feature", "main")
+		mainCommit, _ := s.Scene.Repo.GetRevision("main")
+
+		err = s.Engine.DetachAndStageBranchChanges(context.Background(), "feature")
+		require.NoError(t, err)
+
+		require.Nil(t, s.Engine.CurrentBranch(), "should be in detached HEAD state")
+		headCommit, _ := s.Scene.Repo.GetRevision("HEAD")
+		require.Equal(t, mainCommit, headCommit)
+
+		hasUnstaged, _ := s.Scene.Repo.HasUnstagedChanges()
+		require.False(t, hasUnstaged, "changes should not be left unstaged")
+		hasUntracked, _ := s.Scene.Repo.HasUntrackedFiles()
+		require.False(t, hasUntracked, "new files should be staged rather than untracked")
+		staged, err := s.Scene.Repo.RunGitCommandAndGetOutput("diff", "--cached", "--name-status")
+		require.NoError(t, err)
+		require.Contains(t, staged, "M\tshared_test.txt")
+		require.Contains(t, staged, "A\tnewfile_test.txt")
+	})
 }
 
 func TestSetParentScenarios(t *testing.T) {
@@ -1225,4 +2349,343 @@ func TestSetParentScenarios(t *testing.T) {
 		require.Equal(t, mainNewSHA, *meta.ParentBranchRevision)
 		require.NotEqual(t, *originalMeta.ParentBranchRevision, *meta.ParentBranchRevision)
 	})
+
+	t.Run("metadata-only reparent onto a parent the branch already descends from", func(t *testing.T) {
+		// Scenario: main -> branch1 and main -> branch2, then branch2 is manually
+		// rebased onto branch1
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "main",
+			})
+		s.Checkout("branch2")
+		s.RunGit("rebase", "branch1")
+		branch2SHA, err := s.Scene.Repo.GetRevision("branch2")
+		require.NoError(t, err)
+		branch1SHA, err := s.Scene.Repo.GetRevision("branch1")
+		require.NoError(t, err)
+
+		err = s.Engine.SetParentMetadataOnly(s.Engine.GetBranch("branch2"), s.Engine.GetBranch("branch1"))
+		require.NoError(t, err)
+
+		// No commits moved
+		tip, err := s.Scene.Repo.GetRevision("branch2")
+		require.NoError(t, err)
+		require.Equal(t, branch2SHA, tip)
+
+		meta, err := s.Engine.ReadMetadataRef("branch2")
+		require.NoError(t, err)
+		require.Equal(t, "branch1", *meta.ParentBranchName)
+		require.Equal(t, branch1SHA, *meta.ParentBranchRevision)
+		require.Equal(t, "branch1", s.Engine.GetParent(s.Engine.GetBranch("branch2")).GetName())
+		require.Contains(t, s.Engine.GetBranch("branch1").GetChildren(), s.Engine.GetBranch("branch2"))
+		require.Empty(t, s.Engine.GetBranch("branch2").GetChildren())
+		require.True(t, s.Engine.GetBranch("branch2").IsBranchUpToDate())
+	})
+
+	t.Run("metadata-only reparent refuses a parent the branch isn't based on", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"branch1": "main",
+				"branch2": "main",
+			})
+
+		err := s.Engine.SetParentMetadataOnly(s.Engine.GetBranch("branch2"), s.Engine.GetBranch("branch1"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "branch2 is not based on the tip of branch1")
+		require.Equal(t, "main", s.Engine.GetParent(s.Engine.GetBranch("branch2")).GetName())
+	})
+}
+
+func TestRepairParentRevision(t *testing.T) {
+	t.Run("recomputes a revision that isn't in the branch's history", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		mergeBase, err := s.Engine.Trunk().GetRevision()
+		require.NoError(t, err)
+		s.CreateBranch("branch1").
+			CommitChange("file1.txt", "feat: branch1").
+			TrackBranch("branch1", "main").
+			Checkout("main").
+			CommitChange("main.txt", "feat: main")
+
+		// Point the stored parent revision at main's new tip, which branch1 doesn't contain
+		mainTip, err := s.Engine.Trunk().GetRevision()
+		require.NoError(t, err)
+		require.NoError(t, s.Engine.UpdateParentRevision("branch1", mainTip))
+
+		repaired, err := s.Engine.RepairParentRevision(s.Engine.GetBranch("branch1"))
+		require.NoError(t, err)
+		require.True(t, repaired)
+
+		meta, err := s.Engine.ReadMetadataRef("branch1")
+		require.NoError(t, err)
+		require.Equal(t, mergeBase, *meta.ParentBranchRevision)
+	})
+
+	t.Run("recomputes a revision that no longer exists", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		mergeBase, err := s.Engine.Trunk().GetRevision()
+		require.NoError(t, err)
+		s.CreateBranch("branch1").
+			CommitChange("file1.txt", "feat: branch1").
+			TrackBranch("branch1", "main").
+			Checkout("main").
+			CommitChange("main.txt", "feat: main")
+		require.NoError(t, s.Engine.UpdateParentRevision("branch1", "0123456789abcdef0123456789abcdef01234567"))
+
+		repaired, err := s.Engine.RepairParentRevision(s.Engine.GetBranch("branch1"))
+		require.NoError(t, err)
+		require.True(t, repaired)
+
+		meta, err := s.Engine.ReadMetadataRef("branch1")
+		require.NoError(t, err)
+		require.Equal(t, mergeBase, *meta.ParentBranchRevision)
+	})
+
+	t.Run("leaves a revision in the branch's history alone", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		mergeBase, err := s.Engine.Trunk().GetRevision()
+		require.NoError(t, err)
+		s.CreateBranch("branch1").
+			CommitChange("file1.txt", "feat: branch1").
+			TrackBranch("branch1", "main").
+			Checkout("main").
+			CommitChange("main.txt", "feat: main")
+
+		repaired, err := s.Engine.RepairParentRevision(s.Engine.GetBranch("branch1"))
+		require.NoError(t, err)
+		require.False(t, repaired)
+
+		meta, err := s.Engine.ReadMetadataRef("branch1")
+		require.NoError(t, err)
+		require.Equal(t, mergeBase, *meta.ParentBranchRevision)
+	})
+}
+
+func TestSetScopeForStack(t *testing.T) {
+	t.Run("leaves explicit child overrides intact", func(t *testing.T) {
+		// main -> a (PROJ-1) -> b -> c
+		//                    -> d (OTHER-1) -> e
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			WithStack(map[string]string{
+				"a": "main",
+				"b": "a",
+				"c": "b",
+				"d": "a",
+				"e": "d",
+			})
+		require.NoError(t, s.Engine.SetScope(s.Engine.GetBranch("a"), engine.NewScope("PROJ-1")))
+		require.NoError(t, s.Engine.SetScope(s.Engine.GetBranch("d"), engine.NewScope("OTHER-1")))
+
+		require.NoError(t, s.Engine.SetScopeForStack("a", "PROJ-9"))
+
+		for _, name := range []string{"a", "b", "c"} {
+			require.Equal(t, "PROJ-9", s.Engine.GetExplicitScopeInternal(name).String(), name)
+			require.Equal(t, "PROJ-9", s.Engine.GetScopeInternal(name).String(), name)
+		}
+		require.Equal(t, "OTHER-1", s.Engine.GetExplicitScopeInternal("d").String())
+		require.True(t, s.Engine.GetExplicitScopeInternal("e").IsEmpty())
+		require.Equal(t, "OTHER-1", s.Engine.GetScopeInternal("e").String())
+
+		// The scopes are persisted to metadata and survive a rebuild
+		meta, err := s.Engine.ReadMetadataRef("c")
+		require.NoError(t, err)
+		require.NotNil(t, meta.Scope)
+		require.Equal(t, "PROJ-9", *meta.Scope)
+
+		require.NoError(t, s.Engine.Rebuild("main"))
+		require.Equal(t, "PROJ-9", s.Engine.GetScopeInternal("c").String())
+		require.Equal(t, "OTHER-1", s.Engine.GetScopeInternal("e").String())
+	})
+}
+
+func TestSafeCheckout(t *testing.T) {
+	t.Run("fails without autostash", func(t *testing.T) {
+		// feature has its own version of a file, and a local change to it on main blocks checking feature out
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		s.CommitChange("shared", "main version")
+		s.CreateBranch("feature").
+			CommitChange("shared", "feature version").
+			TrackBranch("feature", "main")
+		s.Checkout("main")
+		require.NoError(t, s.Scene.Repo.CreateChange("local version", "shared", true))
+
+		result, err := s.Engine.SafeCheckout(context.Background(), s.Engine.GetBranch("feature"), false)
+		require.Error(t, err)
+		require.False(t, result.Stashed)
+		s.ExpectBranch("main")
+	})
+
+	t.Run("leaves conflicting changes in the stash", func(t *testing.T) {
+		// feature has its own version of a file, and a local change to it on main blocks checking feature out
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+		s.CommitChange("shared", "main version")
+		s.CreateBranch("feature").
+			CommitChange("shared", "feature version").
+			TrackBranch("feature", "main")
+		s.Checkout("main")
+		require.NoError(t, s.Scene.Repo.CreateChange("local version", "shared", true))
+
+		result, err := s.Engine.SafeCheckout(context.Background(), s.Engine.GetBranch("feature"), true)
+		require.NoError(t, err)
+		require.True(t, result.Stashed)
+		require.True(t, result.StashConflict)
+		s.ExpectBranch("feature")
+
+		stashes, err := s.Scene.Repo.RunGitCommandAndGetOutput("stash", "list")
+		require.NoError(t, err)
+		require.Contains(t, stashes, "stackit-autostash")
+	})
+}
+
+func TestPreviewRestack(t *testing.T) {
+	// main -> a -> b -> c, where b and a later commit on main both change the same file
+	s := scenario.NewScenario(t, testhelpers.BasicSceneSetup)
+	s.CreateBranch("a").
+		CommitChange("a", "a change").
+		TrackBranch("a", "main")
+	s.CreateBranch("b").
+		CommitChange("shared", "b version").
+		TrackBranch("b", "a")
+	s.CreateBranch("c").
+		CommitChange("c", "c change").
+		TrackBranch("c", "b")
+	s.Checkout("main").
+		CommitChange("shared", "main version")
+	s.Checkout("a")
+
+	revisions := map[string]string{}
+	for _, name := range []string{"main", "a", "b", "c"} {
+		rev, err := s.Engine.GetBranch(name).GetRevision()
+		require.NoError(t, err)
+		revisions[name] = rev
+	}
+
+	predictions, err := s.Engine.PreviewRestack(context.Background(), s.Engine.GetBranch("a"), engine.StackRange{
+		IncludeCurrent:    true,
+		RecursiveChildren: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []engine.ConflictPrediction{
+		{BranchName: "a", NeedsRestack: true},
+		{BranchName: "b", NeedsRestack: true, Conflict: true, Files: []string{"shared_test.txt"}},
+		{BranchName: "c", NeedsRestack: true, BlockedBy: "b"},
+	}, predictions)
+
+	// Nothing was restacked and the working tree wasn't touched
+	for name, rev := range revisions {
+		actual, err := s.Engine.GetBranch(name).GetRevision()
+		require.NoError(t, err)
+		require.Equal(t, rev, actual, "branch %s moved", name)
+	}
+	s.ExpectBranch("a")
+	status, err := s.Scene.Repo.RunGitCommandAndGetOutput("status", "--porcelain")
+	require.NoError(t, err)
+	require.Empty(t, status)
+	worktrees, err := s.Scene.Repo.RunGitCommandAndGetOutput("worktree", "list")
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(worktrees), "\n"), 1)
+
+	t.Run("up to date stack", func(t *testing.T) {
+		predictions, err := s.Engine.PreviewRestack(context.Background(), s.Engine.GetBranch("c"), engine.StackRange{
+			IncludeCurrent: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []engine.ConflictPrediction{{BranchName: "c"}}, predictions)
+	})
+}
+
+func TestListStacks(t *testing.T) {
+	s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+		WithStack(map[string]string{
+			"auth":          "main",
+			"auth-api":      "auth",
+			"auth-ui":       "auth",
+			"auth-ui-tests": "auth-ui",
+			"billing":       "main",
+		})
+
+	stacks := s.Engine.ListStacks()
+	require.Len(t, stacks, 2)
+
+	byRoot := map[string]engine.Stack{}
+	for _, stack := range stacks {
+		byRoot[stack.Root.GetName()] = stack
+	}
+	require.Contains(t, byRoot, "auth")
+	require.Contains(t, byRoot, "billing")
+
+	auth := byRoot["auth"]
+	require.Equal(t, 3, auth.DescendantCount)
+	tips := []string{}
+	for _, tip := range auth.Tips {
+		tips = append(tips, tip.GetName())
+	}
+	require.ElementsMatch(t, []string{"auth-api", "auth-ui-tests"}, tips)
+
+	billing := byRoot["billing"]
+	require.Equal(t, 0, billing.DescendantCount)
+	require.Len(t, billing.Tips, 1)
+	require.Equal(t, "billing", billing.Tips[0].GetName())
+}
+
+func TestMetadataVersioning(t *testing.T) {
+	t.Run("upgrades version 1 metadata and writes it back with the next change", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			CreateBranch("branch1").
+			CommitChange("file1.txt", "feat: branch1").
+			Checkout("main")
+		mainRev, err := s.Engine.Trunk().GetRevision()
+		require.NoError(t, err)
+		// Metadata as written before the version field existed
+		blobPath := filepath.Join(t.TempDir(), "meta.json")
+		require.NoError(t, os.WriteFile(blobPath, []byte(fmt.Sprintf(
+			`{"parentBranchName":"main","parentBranchRevision":%q,"prInfo":{"number":7,"reviewers":["alice"]}}`, mainRev)), 0o600))
+		sha, err := s.Scene.Repo.RunGitCommandAndGetOutput("hash-object", "-w", blobPath)
+		require.NoError(t, err)
+		s.RunGit("update-ref", engine.MetadataRefPrefix+"branch1", sha)
+
+		meta, err := s.Engine.ReadMetadataRef("branch1")
+		require.NoError(t, err)
+		require.Equal(t, engine.MetadataVersion, meta.Version)
+		require.Equal(t, "main", *meta.ParentBranchName)
+		require.Equal(t, 7, *meta.PrInfo.Number)
+		require.Equal(t, []string{"alice"}, meta.PrInfo.Reviewers)
+
+		s.Rebuild()
+		s.ExpectStackStructure(map[string]string{"branch1": "main"})
+		stored, err := s.Scene.Repo.RunGitCommandAndGetOutput("cat-file", "-p", engine.MetadataRefPrefix+"branch1")
+		require.NoError(t, err)
+		require.NotContains(t, stored, `"version"`, "reading shouldn't rewrite the ref")
+		require.Empty(t, s.Engine.NewerMetadataBranches())
+
+		require.NoError(t, s.Engine.UpsertPrInfo(s.Engine.GetBranch("branch1"), testhelpers.NewTestPrInfoWithTitle(7, "branch1")))
+		stored, err = s.Scene.Repo.RunGitCommandAndGetOutput("cat-file", "-p", engine.MetadataRefPrefix+"branch1")
+		require.NoError(t, err)
+		require.Contains(t, stored, fmt.Sprintf(`"version":%d`, engine.MetadataVersion))
+	})
+
+	t.Run("leaves metadata from a newer version alone and reports it", func(t *testing.T) {
+		s := scenario.NewScenario(t, testhelpers.BasicSceneSetup).
+			CreateBranch("branch1").
+			CommitChange("file1.txt", "feat: branch1").
+			Checkout("main")
+		// Metadata written by a newer stackit, with a field this one doesn't know
+		blob := fmt.Sprintf(`{"version":%d,"parentBranchName":"main","futureField":true}`, engine.MetadataVersion+1)
+		blobPath := filepath.Join(t.TempDir(), "meta.json")
+		require.NoError(t, os.WriteFile(blobPath, []byte(blob), 0o600))
+		sha, err := s.Scene.Repo.RunGitCommandAndGetOutput("hash-object", "-w", blobPath)
+		require.NoError(t, err)
+		s.RunGit("update-ref", engine.MetadataRefPrefix+"branch1", sha)
+
+		s.Rebuild()
+		s.ExpectStackStructure(map[string]string{"branch1": "main"})
+		require.Equal(t, []string{"branch1"}, s.Engine.NewerMetadataBranches())
+
+		err = s.Engine.UpsertPrInfo(s.Engine.GetBranch("branch1"), testhelpers.NewTestPrInfoWithTitle(7, "branch1"))
+		require.ErrorIs(t, err, engine.ErrNewerMetadata)
+		stored, err := s.Scene.Repo.RunGitCommandAndGetOutput("cat-file", "-p", engine.MetadataRefPrefix+"branch1")
+		require.NoError(t, err)
+		require.Equal(t, blob, stored, "the newer metadata should be left as it was")
+	})
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strings"
+	"time"
 )
 
 // rebuildInternal is the internal rebuild logic without locking
@@ -25,6 +27,7 @@ func (e *engineImpl) rebuildInternal(refreshCurrentBranch bool) error {
 		} else {
 			e.currentBranch = currentBranch
 		}
+		e.currentStale.Store(false)
 	}
 
 	// Reset maps
@@ -34,10 +37,22 @@ func (e *engineImpl) rebuildInternal(refreshCurrentBranch bool) error {
 
 	// Load metadata for each branch in parallel
 	allMeta, _ := e.batchReadMetadataRefs(branches)
+	// Older metadata was upgraded in memory as it was read and is written back in the
+	// current schema the next time the branch changes
+	e.newerMeta = nil
+	for name, meta := range allMeta {
+		if isNewerMeta(meta) {
+			e.newerMeta = append(e.newerMeta, name)
+		}
+	}
+	slices.Sort(e.newerMeta)
+	e.resetReadCache(branches, allMeta)
 
 	// Collect results and populate maps sequentially to avoid lock contention/races
 	for name, meta := range allMeta {
-		if meta.ParentBranchName != nil {
+		// The trunk has no parent, even when it is tracked on top of another trunk
+		// (e.g. a release branch used as trunk with --use-trunk)
+		if meta.ParentBranchName != nil && name != e.trunk {
 			parent := *meta.ParentBranchName
 			e.parentMap[name] = parent
 			e.childrenMap[parent] = append(e.childrenMap[parent], name)
@@ -47,9 +62,69 @@ func (e *engineImpl) rebuildInternal(refreshCurrentBranch bool) error {
 		}
 	}
 
-	// Sort children by name for deterministic traversal
+	// Order children by commit date, then name, reading every sibling's date in one call
+	var siblings []string
 	for _, children := range e.childrenMap {
-		slices.Sort(children)
+		if len(children) > 1 {
+			siblings = append(siblings, children...)
+		}
+	}
+	dates := e.commitDates(siblings)
+	for _, children := range e.childrenMap {
+		sortChildren(dates, children)
+	}
+
+	return nil
+}
+
+// rebuildBranchesInternal reloads the metadata of branchNames into the in-memory maps,
+// leaving every other branch's entries alone. The branch list and current branch are
+// refreshed as in a full rebuild, so created and deleted branches are picked up. The
+// caller must hold e.mu for writing.
+func (e *engineImpl) rebuildBranchesInternal(branchNames []string) error {
+	branches, err := e.git.GetAllBranchNames()
+	if err != nil {
+		return fmt.Errorf("failed to get branches: %w", err)
+	}
+	e.branches = branches
+
+	if currentBranch, err := e.git.GetCurrentBranch(); err == nil {
+		e.currentBranch = currentBranch
+	} else {
+		e.currentBranch = ""
+	}
+	e.currentStale.Store(false)
+	e.resetReadCache(branches, nil)
+
+	for _, name := range branchNames {
+		// Detach the branch from its old parent; its own children are keyed by their
+		// metadata, so they stay unless they are in branchNames too
+		if oldParent, ok := e.parentMap[name]; ok {
+			if children := e.childrenMap[oldParent]; slices.Contains(children, name) {
+				e.childrenMap[oldParent] = slices.DeleteFunc(children, func(child string) bool { return child == name })
+				if len(e.childrenMap[oldParent]) == 0 {
+					delete(e.childrenMap, oldParent)
+				}
+			}
+			delete(e.parentMap, name)
+		}
+		delete(e.scopeMap, name)
+
+		// Deleted branches and untracked branches have nothing to load
+		if !slices.Contains(branches, name) {
+			continue
+		}
+		meta, err := e.readMetadataRef(name)
+		if err != nil {
+			return err
+		}
+		if meta.ParentBranchName != nil && name != e.trunk {
+			e.parentMap[name] = *meta.ParentBranchName
+			e.addChild(*meta.ParentBranchName, name)
+		}
+		if meta.Scope != nil {
+			e.scopeMap[name] = *meta.Scope
+		}
 	}
 
 	return nil
@@ -115,10 +190,56 @@ func (e *engineImpl) updateBranchInCache(branchName string) {
 
 	// Add to new parent's children if it has a parent
 	if newParent != "" {
-		e.childrenMap[newParent] = append(e.childrenMap[newParent], branchName)
-		// Sort for deterministic traversal
-		slices.Sort(e.childrenMap[newParent])
+		e.addChild(newParent, branchName)
+	}
+}
+
+// addChild records child under parent in childrenMap, keeping the children ordered by
+// commit date, then name. Callers must hold e.mu for writing.
+func (e *engineImpl) addChild(parent, child string) {
+	if slices.Contains(e.childrenMap[parent], child) {
+		return
+	}
+	children := append(e.childrenMap[parent], child)
+	if len(children) > 1 {
+		sortChildren(e.commitDates(children), children)
+	}
+	e.childrenMap[parent] = children
+}
+
+// sortChildren orders sibling branches by tip author date, oldest first, then by name
+func sortChildren(dates map[string]time.Time, children []string) {
+	slices.SortFunc(children, func(a, b string) int {
+		return compareCreationOrder(dates, a, b)
+	})
+}
+
+// commitDates reads the tip author date of each branch, skipping any that can't be read
+func (e *engineImpl) commitDates(branchNames []string) map[string]time.Time {
+	dates := make(map[string]time.Time, len(branchNames))
+	for name, info := range e.cachedCommitInfo(branchNames) {
+		dates[name] = info.Date
 	}
+	return dates
+}
+
+// compareCreationOrder compares two branches by tip author date, oldest first, then by
+// name. Branches without a date come after all dated ones, in name order, so the
+// comparison stays consistent for any mix of dated and undated branches.
+func compareCreationOrder(dates map[string]time.Time, a, b string) int {
+	aDate, aOK := dates[a]
+	bDate, bOK := dates[b]
+	switch {
+	case aOK && bOK:
+		if c := aDate.Compare(bDate); c != 0 {
+			return c
+		}
+	case aOK:
+		return -1
+	case bOK:
+		return 1
+	}
+	return strings.Compare(a, b)
 }
 
 // rebuild loads all branches and their metadata from Git
@@ -141,14 +262,7 @@ func (e *engineImpl) shouldReparentBranch(ctx context.Context, parentBranchName
 	}
 
 	// Check if parent branch still exists locally
-	parentExists := false
-	for _, name := range e.branches {
-		if name == parentBranchName {
-			parentExists = true
-			break
-		}
-	}
-	if !parentExists {
+	if !slices.Contains(e.branches, parentBranchName) {
 		return true
 	}
 
@@ -158,9 +272,14 @@ func (e *engineImpl) shouldReparentBranch(ctx context.Context, parentBranchName
 		return true
 	}
 
-	// Check if parent has "MERGED" PR state in metadata
+	return e.isPrMerged(parentBranchName, metaMap)
+}
+
+// isPrMerged reports whether a branch's PR is recorded as merged, preferring metaMap
+// over the engine's cache
+func (e *engineImpl) isPrMerged(branchName string, metaMap map[string]*Meta) bool {
 	if metaMap != nil {
-		if meta, ok := metaMap[parentBranchName]; ok && meta != nil && meta.PrInfo != nil {
+		if meta, ok := metaMap[branchName]; ok && meta != nil && meta.PrInfo != nil {
 			if meta.PrInfo.State != nil && *meta.PrInfo.State == "MERGED" {
 				return true
 			}
@@ -170,8 +289,8 @@ func (e *engineImpl) shouldReparentBranch(ctx context.Context, parentBranchName
 	}
 
 	// Fall back to engine cache/disk if not in metaMap or state unknown
-	parentBranch := e.GetBranch(parentBranchName)
-	prInfo, err := e.GetPrInfo(parentBranch)
+	branch := e.GetBranch(branchName)
+	prInfo, err := e.GetPrInfo(branch)
 	if err == nil && prInfo != nil && prInfo.State() == "MERGED" {
 		return true
 	}
@@ -179,6 +298,16 @@ func (e *engineImpl) shouldReparentBranch(ctx context.Context, parentBranchName
 	return false
 }
 
+// parentOnlyOnRemote reports whether a parent branch is gone locally but still exists on the
+// remote without its PR having merged, in which case it was likely deleted by mistake and
+// its children shouldn't be moved off it
+func (e *engineImpl) parentOnlyOnRemote(parentBranchName string, metaMap map[string]*Meta) bool {
+	if parentBranchName == e.trunk || e.BranchExistsLocally(parentBranchName) || !e.BranchExistsOnRemote(parentBranchName) {
+		return false
+	}
+	return !e.isPrMerged(parentBranchName, metaMap)
+}
+
 // findNearestValidAncestor finds the nearest ancestor that hasn't been merged/deleted
 // Returns trunk if all ancestors have been merged
 func (e *engineImpl) findNearestValidAncestor(ctx context.Context, branchName string, metaMap map[string]*Meta) string {
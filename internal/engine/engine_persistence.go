@@ -3,6 +3,7 @@ package engine
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 )
@@ -40,18 +41,40 @@ func (e *engineImpl) readMetadataRef(branchName string) (*Meta, error) {
 	if err := json.Unmarshal([]byte(content), &meta); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal metadata for %s: %w", branchName, err)
 	}
+	migrateMeta(&meta)
 
 	return &meta, nil
 }
 
+// NewerMetadataBranches returns the branches whose metadata was written by a newer version
+// of stackit, as of the last rebuild
+func (e *engineImpl) NewerMetadataBranches() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return slices.Clone(e.newerMeta)
+}
+
 // WriteMetadataRef writes metadata for a branch to Git refs
 func (e *engineImpl) WriteMetadataRef(branch Branch, meta *Meta) error {
 	return e.writeMetadataRef(branch.GetName(), meta)
 }
 
-// writeMetadataRef writes metadata for a branch to Git refs
+// writeMetadataRef writes metadata for a branch to Git refs, in this build's schema. It
+// refuses to overwrite metadata from a newer version of stackit with ErrNewerMetadata.
 func (e *engineImpl) writeMetadataRef(branchName string, meta *Meta) error {
-	jsonData, err := json.Marshal(meta)
+	if isNewerMeta(meta) {
+		return fmt.Errorf("%s: %w; upgrade stackit to change it", branchName, ErrNewerMetadata)
+	}
+	if existing, err := e.cachedMetadataRef(branchName); err == nil && isNewerMeta(existing) {
+		return fmt.Errorf("%s: %w; upgrade stackit to change it", branchName, ErrNewerMetadata)
+	}
+
+	defer e.invalidateReadCache()
+
+	// Older metadata was migrated when it was read, so it is upgraded here too
+	stamped := *meta
+	stamped.Version = MetadataVersion
+	jsonData, err := json.Marshal(&stamped)
 	if err != nil {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
@@ -71,12 +94,16 @@ func (e *engineImpl) writeMetadataRef(branchName string, meta *Meta) error {
 
 // DeleteMetadataRef deletes a metadata ref for a branch
 func (e *engineImpl) DeleteMetadataRef(branch Branch) error {
+	defer e.invalidateReadCache()
+
 	refName := fmt.Sprintf("%s%s", MetadataRefPrefix, branch.GetName())
 	return e.git.DeleteRef(refName)
 }
 
 // RenameMetadataRef renames a metadata ref from one branch name to another
 func (e *engineImpl) RenameMetadataRef(oldBranch, newBranch Branch) error {
+	defer e.invalidateReadCache()
+
 	oldRefName := fmt.Sprintf("%s%s", MetadataRefPrefix, oldBranch.GetName())
 	newRefName := fmt.Sprintf("%s%s", MetadataRefPrefix, newBranch.GetName())
 
@@ -25,7 +25,15 @@ func (e *engineImpl) GetPrInfo(branch Branch) (*PrInfo, error) {
 		getStringValue(meta.PrInfo.Base),
 		getStringValue(meta.PrInfo.URL),
 		getBoolValue(meta.PrInfo.IsDraft),
-	)
+	).WithReviewersAndLabels(meta.PrInfo.Reviewers, meta.PrInfo.TeamReviewers, meta.PrInfo.Labels).
+		WithTargetTrunk(getStringValue(meta.PrInfo.TargetTrunk))
+
+	if headSHA := getStringValue(meta.PrInfo.HeadSHA); headSHA != "" {
+		prInfo.headSHA = headSHA
+		if rev, err := branch.GetRevision(); err == nil {
+			prInfo.stale = rev != headSHA
+		}
+	}
 
 	return prInfo, nil
 }
@@ -75,6 +83,23 @@ func (e *engineImpl) UpsertPrInfo(branch Branch, prInfo *PrInfo) error {
 		url := prInfo.URL()
 		meta.PrInfo.URL = &url
 	}
+	if prInfo.Reviewers() != nil {
+		meta.PrInfo.Reviewers = prInfo.Reviewers()
+	}
+	if prInfo.TeamReviewers() != nil {
+		meta.PrInfo.TeamReviewers = prInfo.TeamReviewers()
+	}
+	if prInfo.Labels() != nil {
+		meta.PrInfo.Labels = prInfo.Labels()
+	}
+	if prInfo.HeadSHA() != "" {
+		headSHA := prInfo.HeadSHA()
+		meta.PrInfo.HeadSHA = &headSHA
+	}
+	if prInfo.TargetTrunk() != "" {
+		targetTrunk := prInfo.TargetTrunk()
+		meta.PrInfo.TargetTrunk = &targetTrunk
+	}
 
 	return e.writeMetadataRef(branch.GetName(), meta)
 }
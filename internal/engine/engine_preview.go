@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PreviewRestack predicts which branches in scope would conflict if restacked, without
+// touching the working tree or any refs. Each branch that needs restacking is rebased (or
+// merged, with the merge restack strategy) in a throwaway worktree onto its parent's
+// predicted new tip. Branches above a predicted conflict are reported as blocked by it.
+func (e *engineImpl) PreviewRestack(ctx context.Context, branch Branch, scope StackRange) ([]ConflictPrediction, error) {
+	branches := branch.GetRelativeStack(scope)
+	predictions := make([]ConflictPrediction, 0, len(branches))
+	if len(branches) == 0 {
+		return predictions, nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "stackit-preview-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	worktree := filepath.Join(tmpDir, "worktree")
+	if err := e.git.AddWorktree(ctx, worktree, "", true); err != nil {
+		return nil, err
+	}
+	defer func() { _ = e.git.RemoveWorktree(ctx, worktree) }()
+
+	// runInWorktree runs a git command in the throwaway worktree
+	runInWorktree := func(args ...string) (string, error) {
+		return e.git.RunGitCommandWithContext(ctx, append([]string{"-C", worktree}, args...)...)
+	}
+
+	predictedTips := make(map[string]string) // branch -> tip after the simulated restack
+	blockers := make(map[string]string)      // branch -> the conflicting branch at or below it
+
+	for _, b := range branches {
+		branchName := b.GetName()
+		if b.IsTrunk() {
+			continue
+		}
+		e.mu.RLock()
+		parent, ok := e.parentMap[branchName]
+		e.mu.RUnlock()
+		if !ok {
+			continue
+		}
+
+		prediction := ConflictPrediction{BranchName: branchName}
+		if blocker, ok := blockers[parent]; ok {
+			prediction.NeedsRestack = true
+			prediction.BlockedBy = blocker
+			blockers[branchName] = blocker
+			predictions = append(predictions, prediction)
+			continue
+		}
+
+		parentRev, ok := predictedTips[parent]
+		if !ok {
+			parentRev, err = e.git.GetRevision(parent)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get revision for %s: %w", parent, err)
+			}
+		}
+		meta, err := e.readMetadataRef(branchName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read metadata for %s: %w", branchName, err)
+		}
+		oldParentRev := e.restackBase(branchName, parent, parentRev, meta)
+		if oldParentRev == parentRev {
+			predictions = append(predictions, prediction)
+			continue
+		}
+		prediction.NeedsRestack = true
+
+		branchRev, err := e.git.GetRevision(branchName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get revision for %s: %w", branchName, err)
+		}
+		if _, err := runInWorktree("checkout", "--detach", branchRev); err != nil {
+			return nil, fmt.Errorf("failed to check out %s in the preview worktree: %w", branchName, err)
+		}
+
+		var simulateErr error
+		if e.restackStrategy == RestackStrategyMerge {
+			_, simulateErr = runInWorktree("merge", "--no-ff", "--no-edit", parentRev)
+		} else {
+			_, simulateErr = runInWorktree("rebase", "--onto", parentRev, oldParentRev)
+		}
+
+		if simulateErr != nil {
+			conflicted, _ := runInWorktree("diff", "--name-only", "--diff-filter=U")
+			prediction.Conflict = true
+			prediction.Files = strings.Fields(conflicted)
+			blockers[branchName] = branchName
+			if e.restackStrategy == RestackStrategyMerge {
+				_, _ = runInWorktree("merge", "--abort")
+			} else {
+				_, _ = runInWorktree("rebase", "--abort")
+			}
+		} else {
+			newRev, err := runInWorktree("rev-parse", "HEAD")
+			if err != nil {
+				return nil, fmt.Errorf("failed to get simulated revision for %s: %w", branchName, err)
+			}
+			predictedTips[branchName] = newRev
+		}
+		predictions = append(predictions, prediction)
+	}
+
+	return predictions, nil
+}
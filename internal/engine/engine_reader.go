@@ -1,9 +1,11 @@
 package engine
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"iter"
+	"slices"
 	"strings"
 	"time"
 
@@ -21,16 +23,22 @@ func (e *engineImpl) AllBranches() []Branch {
 	return branches
 }
 
-// CurrentBranch returns the current branch (nil if not on a branch)
+// CurrentBranch returns the current branch (nil if not on a branch). The branch is cached
+// and only re-read from Git after a checkout or mutation made through the engine, or an
+// explicit RefreshCurrentBranch.
 func (e *engineImpl) CurrentBranch() *Branch {
-	e.mu.Lock()
-	if current, err := e.git.GetCurrentBranch(); err == nil {
-		e.currentBranch = current
-	} else {
-		// Not on a branch (e.g., detached HEAD)
-		e.currentBranch = ""
+	if e.currentStale.Load() {
+		e.mu.Lock()
+		// Clear the flag before reading so an invalidation racing with the read isn't lost
+		e.currentStale.Store(false)
+		if current, err := e.git.GetCurrentBranch(); err == nil {
+			e.currentBranch = current
+		} else {
+			// Not on a branch (e.g., detached HEAD)
+			e.currentBranch = ""
+		}
+		e.mu.Unlock()
 	}
-	e.mu.Unlock()
 
 	e.mu.RLock()
 	defer e.mu.RUnlock()
@@ -41,6 +49,19 @@ func (e *engineImpl) CurrentBranch() *Branch {
 	return &branch
 }
 
+// RefreshCurrentBranch drops the cached current branch and re-reads it from Git, for callers
+// that know HEAD moved outside the engine
+func (e *engineImpl) RefreshCurrentBranch() *Branch {
+	e.invalidateCurrentBranch()
+	return e.CurrentBranch()
+}
+
+// invalidateCurrentBranch makes the next CurrentBranch call re-read HEAD. It doesn't take
+// e.mu, so it is safe to call from mutating operations that already hold it.
+func (e *engineImpl) invalidateCurrentBranch() {
+	e.currentStale.Store(true)
+}
+
 // Trunk returns the trunk branch
 func (e *engineImpl) Trunk() Branch {
 	e.mu.RLock()
@@ -65,7 +86,25 @@ func (e *engineImpl) GetParent(branch Branch) *Branch {
 	return nil
 }
 
-// GetChildrenInternal returns the children branches (internal method for Branch type)
+// BatchGetParents returns the parent of each branch, or trunk for branches without one
+// (as GetParentPrecondition does), taking the lock once rather than once per branch
+func (e *engineImpl) BatchGetParents(branchNames []string) map[string]string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	parents := make(map[string]string, len(branchNames))
+	for _, branchName := range branchNames {
+		if parent, ok := e.parentMap[branchName]; ok {
+			parents[branchName] = parent
+		} else {
+			parents[branchName] = e.trunk
+		}
+	}
+	return parents
+}
+
+// GetChildrenInternal returns the children branches (internal method for Branch type).
+// Children are ordered by their tip's author date, oldest first, then by name.
 func (e *engineImpl) GetChildrenInternal(branchName string) []Branch {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
@@ -245,16 +284,37 @@ func (e *engineImpl) IsBranchUpToDateInternal(branchName string) bool {
 	return *meta.ParentBranchRevision == parentRev
 }
 
-// GetCommitDateInternal returns the commit date for a branch
+// GetCommitDateInternal returns the commit date for a branch, from the cache filled by
+// BatchGetCommitInfo when it's there
 func (e *engineImpl) GetCommitDateInternal(branchName string) (time.Time, error) {
+	e.cache.mu.Lock()
+	info, ok := e.cache.commitInfo[branchName]
+	e.cache.mu.Unlock()
+	if ok {
+		return info.Date, nil
+	}
 	return e.git.GetCommitDate(branchName)
 }
 
-// GetCommitAuthorInternal returns the commit author for a branch
+// GetCommitAuthorInternal returns the commit author for a branch, from the cache filled by
+// BatchGetCommitInfo when it's there
 func (e *engineImpl) GetCommitAuthorInternal(branchName string) (string, error) {
+	e.cache.mu.Lock()
+	info, ok := e.cache.commitInfo[branchName]
+	e.cache.mu.Unlock()
+	if ok {
+		return info.Author, nil
+	}
 	return e.git.GetCommitAuthor(branchName)
 }
 
+// BatchGetCommitInfo returns the tip author and author date of each branch, so rendering
+// many branches costs one git call rather than two per branch. Results are cached until
+// the engine next changes a branch. Branches whose commit can't be read are left out.
+func (e *engineImpl) BatchGetCommitInfo(branchNames []string) map[string]git.CommitInfo {
+	return e.cachedCommitInfo(branchNames)
+}
+
 // GetRevisionInternal returns the SHA of a branch
 func (e *engineImpl) GetRevisionInternal(branchName string) (string, error) {
 	return e.git.GetRevision(branchName)
@@ -272,20 +332,20 @@ func (e *engineImpl) GetCommitCountInternal(branchName string) (int, error) {
 	}
 
 	// Get base revision (stored parent revision)
-	meta, err := e.readMetadataRef(branchName)
+	meta, err := e.cachedMetadataRef(branchName)
 	var base string
 	if err == nil && meta.ParentBranchRevision != nil {
 		base = *meta.ParentBranchRevision
 	} else {
 		// Fallback to current parent branch tip if metadata is missing
-		baseRev, err := e.git.GetRevision(parent)
+		baseRev, err := e.cachedRevision(parent)
 		if err != nil {
 			return 0, err
 		}
 		base = baseRev
 	}
 
-	branchRev, err := e.git.GetRevision(branchName)
+	branchRev, err := e.cachedRevision(branchName)
 	if err != nil {
 		return 0, err
 	}
@@ -304,8 +364,9 @@ func (e *engineImpl) GetCommitCountInternal(branchName string) (int, error) {
 	return len(commits), nil
 }
 
-// GetDiffStatsInternal returns diff stats for a branch
-func (e *engineImpl) GetDiffStatsInternal(branchName string) (int, int, error) {
+// GetDiffBaseInternal returns the revision a branch's own changes are measured from: its stored
+// parent revision, or the parent's tip (trunk for untracked branches) when metadata is missing
+func (e *engineImpl) GetDiffBaseInternal(branchName string) (string, error) {
 	e.mu.RLock()
 	trunk := e.trunk
 	parent, ok := e.parentMap[branchName]
@@ -315,20 +376,21 @@ func (e *engineImpl) GetDiffStatsInternal(branchName string) (int, int, error) {
 		parent = trunk
 	}
 
-	// Get base revision (stored parent revision)
-	meta, err := e.readMetadataRef(branchName)
-	var base string
+	meta, err := e.cachedMetadataRef(branchName)
 	if err == nil && meta.ParentBranchRevision != nil {
-		base = *meta.ParentBranchRevision
-	} else {
-		baseRev, err := e.git.GetRevision(parent)
-		if err != nil {
-			return 0, 0, err
-		}
-		base = baseRev
+		return *meta.ParentBranchRevision, nil
 	}
+	return e.cachedRevision(parent)
+}
 
-	branchRev, err := e.git.GetRevision(branchName)
+// GetDiffStatsInternal returns diff stats for a branch
+func (e *engineImpl) GetDiffStatsInternal(branchName string) (int, int, error) {
+	base, err := e.GetDiffBaseInternal(branchName)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	branchRev, err := e.cachedRevision(branchName)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -382,7 +444,7 @@ func (e *engineImpl) BranchMatchesRemote(branchName string) (bool, error) {
 
 	// Fall back to checking local remote tracking branch (like getBranchRemoteDifference does)
 	// This handles cases where remote fetching failed but we have local remote tracking
-	remoteTrackingSha, err := e.git.GetRemoteRevision(branchName)
+	remoteTrackingSha, err := e.git.GetRemoteSha(e.branchRemote(branchName), branchName)
 	if err != nil {
 		// No remote tracking branch exists
 		return false, nil
@@ -391,6 +453,25 @@ func (e *engineImpl) BranchMatchesRemote(branchName string) (bool, error) {
 	return localSha == remoteTrackingSha, nil
 }
 
+// BranchFastForwardsRemote checks if the remote branch is an ancestor of the local branch,
+// meaning the branch can be pushed without rewriting remote history
+func (e *engineImpl) BranchFastForwardsRemote(branchName string) (bool, error) {
+	e.mu.RLock()
+	remoteSha, exists := e.remoteShas[branchName]
+	e.mu.RUnlock()
+
+	if !exists {
+		var err error
+		remoteSha, err = e.git.GetRemoteSha(e.branchRemote(branchName), branchName)
+		if err != nil {
+			// No remote tracking branch exists
+			return false, nil
+		}
+	}
+
+	return e.git.IsAncestor(remoteSha, branchName)
+}
+
 // IsMergedIntoTrunk checks if a branch is merged into trunk
 func (e *engineImpl) IsMergedIntoTrunk(ctx context.Context, branchName string) (bool, error) {
 	e.mu.RLock()
@@ -399,8 +480,19 @@ func (e *engineImpl) IsMergedIntoTrunk(ctx context.Context, branchName string) (
 	return e.git.IsMerged(ctx, branchName, trunk)
 }
 
-// IsBranchEmpty checks if a branch has no changes compared to its parent
+// IsBranchEmpty checks if a branch has no changes compared to its parent, either because
+// it has no commits of its own or because its commits are all empty
 func (e *engineImpl) IsBranchEmpty(ctx context.Context, branchName string) (bool, error) {
+	content, err := e.GetBranchContent(ctx, branchName)
+	if err != nil {
+		return false, err
+	}
+	return content != BranchContentChanges, nil
+}
+
+// GetBranchContent reports whether a branch has commits above its parent and whether they
+// change anything
+func (e *engineImpl) GetBranchContent(ctx context.Context, branchName string) (BranchContent, error) {
 	e.mu.RLock()
 	parent, ok := e.parentMap[branchName]
 	trunk := e.trunk
@@ -414,10 +506,25 @@ func (e *engineImpl) IsBranchEmpty(ctx context.Context, branchName string) (bool
 	// Get parent revision
 	parentRev, err := e.GetRevisionInternal(parent)
 	if err != nil {
-		return false, err
+		return BranchContentNone, err
 	}
 
-	return e.git.IsDiffEmpty(ctx, branchName, parentRev)
+	empty, err := e.git.IsDiffEmpty(ctx, branchName, parentRev)
+	if err != nil {
+		return BranchContentNone, err
+	}
+	if !empty {
+		return BranchContentChanges, nil
+	}
+
+	commits, err := e.git.GetCommitRangeSHAs(parentRev, branchName)
+	if err != nil {
+		return BranchContentNone, fmt.Errorf("failed to list commits of %s: %w", branchName, err)
+	}
+	if len(commits) == 0 {
+		return BranchContentNone, nil
+	}
+	return BranchContentEmptyCommits, nil
 }
 
 // FindMostRecentTrackedAncestors finds the most recent tracked ancestors of a branch
@@ -517,13 +624,13 @@ func (e *engineImpl) GetAllCommitsInternal(branchName string, format CommitForma
 	}
 
 	// Get metadata to find parent revision
-	meta, err := e.readMetadataRef(branchName)
+	meta, err := e.cachedMetadataRef(branchName)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get branch revision
-	branchRevision, err := e.GetRevisionInternal(branchName)
+	branchRevision, err := e.cachedRevision(branchName)
 	if err != nil {
 		return nil, err
 	}
@@ -535,7 +642,7 @@ func (e *engineImpl) GetAllCommitsInternal(branchName string, format CommitForma
 	}
 
 	// Get SHAs first
-	shas, err := e.git.GetCommitRangeSHAs(baseRevision, branchRevision)
+	shas, err := e.cachedCommitRange(baseRevision, branchRevision)
 	if err != nil {
 		return nil, err
 	}
@@ -592,8 +699,29 @@ func (e *engineImpl) GetFullStack(branch Branch) []Branch {
 	return e.GetRelativeStackInternal(branch.GetName(), rng)
 }
 
+// ListStacks returns the independent stacks in the repository, one per child of trunk
+func (e *engineImpl) ListStacks() []Stack {
+	trunk := e.Trunk()
+	var stacks []Stack
+	for _, root := range trunk.GetChildren() {
+		stack := Stack{Root: root}
+		for branch, depth := range e.BranchesDepthFirst(root) {
+			if depth > 0 {
+				stack.DescendantCount++
+			}
+			if len(branch.GetChildren()) == 0 {
+				stack.Tips = append(stack.Tips, branch)
+			}
+		}
+		stacks = append(stacks, stack)
+	}
+	return stacks
+}
+
 // SortBranchesTopologically sorts branches so parents come before children.
-// This ensures correct restack order (bottom of stack first).
+// This ensures correct restack order (bottom of stack first). Branches at the same
+// depth are ordered by their tip's author date, oldest first, then by name, so the
+// order is the same on every run.
 func (e *engineImpl) SortBranchesTopologically(branches []Branch) []Branch {
 	if len(branches) == 0 {
 		return branches
@@ -629,16 +757,24 @@ func (e *engineImpl) SortBranchesTopologically(branches []Branch) []Branch {
 		getDepth(branch.GetName())
 	}
 
-	// Sort by depth (parents first, then children)
+	// Break depth ties by creation order, approximated by each branch's tip author date,
+	// which survives restacks. Branches whose date can't be read go last, in name order.
+	names := make([]string, len(branches))
+	for i, branch := range branches {
+		names[i] = branch.GetName()
+	}
+	dates := e.commitDates(names)
+
+	// Sort by depth (parents first, then children), then oldest first, then by name
 	result := make([]Branch, len(branches))
 	copy(result, branches)
-	for i := 0; i < len(result)-1; i++ {
-		for j := i + 1; j < len(result); j++ {
-			if depths[result[i].GetName()] > depths[result[j].GetName()] {
-				result[i], result[j] = result[j], result[i]
-			}
+	slices.SortStableFunc(result, func(a, b Branch) int {
+		aName, bName := a.GetName(), b.GetName()
+		if c := cmp.Compare(depths[aName], depths[bName]); c != 0 {
+			return c
 		}
-	}
+		return compareCreationOrder(dates, aName, bName)
+	})
 
 	return result
 }
@@ -661,14 +797,14 @@ func (e *engineImpl) GetDeletionStatus(ctx context.Context, branchName string) (
 			if base == "" {
 				base = e.Trunk().GetName()
 			}
-			return DeletionStatus{SafeToDelete: true, Reason: fmt.Sprintf("%s is merged into %s", branchName, base)}, nil
+			return DeletionStatus{SafeToDelete: true, Merged: true, Reason: fmt.Sprintf("%s is merged into %s", branchName, base)}, nil
 		}
 	}
 
 	// Check if merged into trunk
 	merged, err := e.IsMergedIntoTrunk(ctx, branchName)
 	if err == nil && merged {
-		return DeletionStatus{SafeToDelete: true, Reason: fmt.Sprintf("%s is merged into %s", branchName, e.Trunk().GetName())}, nil
+		return DeletionStatus{SafeToDelete: true, Merged: true, Reason: fmt.Sprintf("%s is merged into %s", branchName, e.Trunk().GetName())}, nil
 	}
 
 	// Check if empty
@@ -718,16 +854,35 @@ func (e *engineImpl) GetRemote() string {
 	return e.git.GetRemote()
 }
 
-// GetBranchRemoteDifference returns a string describing the difference between local and remote branch
-func (e *engineImpl) GetBranchRemoteDifference(branchName string) (string, error) {
+// GetPushRemote returns the remote branches are pushed to
+func (e *engineImpl) GetPushRemote() string {
+	return e.git.GetPushRemote()
+}
+
+// branchRemote returns the remote a branch's counterpart is compared with: the fetch remote
+// for trunk, which comes from upstream, and the push remote for everything else
+func (e *engineImpl) branchRemote(branchName string) string {
+	if branchName == e.trunk {
+		return e.git.GetRemote()
+	}
+	return e.git.GetPushRemote()
+}
+
+// GetBranchRemoteDifference returns a string describing the difference between a local branch
+// and its counterpart on remote. An empty remote means the push remote, or the fetch remote
+// for trunk.
+func (e *engineImpl) GetBranchRemoteDifference(branchName, remote string) (string, error) {
+	if remote == "" {
+		remote = e.branchRemote(branchName)
+	}
+
 	localSha, err := e.git.GetRevision(branchName)
 	if err != nil {
 		return "", fmt.Errorf("failed to get local SHA for %s: %w", branchName, err)
 	}
 
-	remoteSha, err := e.git.GetRemoteRevision(branchName)
+	remoteSha, err := e.git.GetRemoteSha(remote, branchName)
 	if err != nil {
-		remote := e.git.GetRemote()
 		remoteShas, err := e.git.FetchRemoteShas(remote)
 		if err != nil {
 			localShort := localSha
@@ -760,7 +915,6 @@ func (e *engineImpl) GetBranchRemoteDifference(branchName string) (string, error
 		remoteShort = remoteSha[:7]
 	}
 
-	remote := e.git.GetRemote()
 	remoteBranchRef := "refs/remotes/" + remote + "/" + branchName
 	commonAncestor, err := e.git.GetMergeBaseByRef(branchName, remoteBranchRef)
 	if err != nil {
@@ -777,6 +931,52 @@ func (e *engineImpl) GetBranchRemoteDifference(branchName string) (string, error
 	}
 }
 
+// GetBranchRemoteDivergence returns how many commits a branch has that its remote
+// counterpart doesn't (ahead) and vice versa (behind). The remote SHA comes from the
+// cache populated by PopulateRemoteShas, falling back to the remote tracking branch.
+func (e *engineImpl) GetBranchRemoteDivergence(branchName string) (int, int, error) {
+	localSha, err := e.git.GetRevision(branchName)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get local SHA for %s: %w", branchName, err)
+	}
+
+	e.mu.RLock()
+	remoteSha, exists := e.remoteShas[branchName]
+	e.mu.RUnlock()
+	if !exists {
+		remoteSha, err = e.git.GetRemoteSha(e.branchRemote(branchName), branchName)
+		if err != nil {
+			return 0, 0, fmt.Errorf("branch %s not found on remote: %w", branchName, err)
+		}
+	}
+
+	return e.GetDivergence(localSha, remoteSha)
+}
+
+// GetDivergence returns how many commits ref has that otherRef doesn't (ahead) and
+// vice versa (behind), counted from their merge base
+func (e *engineImpl) GetDivergence(ref, otherRef string) (int, int, error) {
+	if ref == otherRef {
+		return 0, 0, nil
+	}
+
+	mergeBase, err := e.git.GetMergeBaseByRef(ref, otherRef)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to find merge base of %s and %s: %w", ref, otherRef, err)
+	}
+
+	aheadShas, err := e.git.GetCommitRangeSHAs(mergeBase, ref)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count commits: %w", err)
+	}
+	behindShas, err := e.git.GetCommitRangeSHAs(mergeBase, otherRef)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count commits: %w", err)
+	}
+
+	return len(aheadShas), len(behindShas), nil
+}
+
 // HasStagedChanges checks if there are staged changes in the repository
 func (e *engineImpl) HasStagedChanges(ctx context.Context) (bool, error) {
 	return e.git.HasStagedChanges(ctx)
@@ -809,11 +1009,14 @@ func (e *engineImpl) GetWorkingDir() string {
 
 // RunGitCommandWithContext runs a git command with context
 func (e *engineImpl) RunGitCommandWithContext(ctx context.Context, args ...string) (string, error) {
+	// Arbitrary commands may move HEAD
+	defer e.invalidateCurrentBranch()
 	return e.git.RunGitCommandWithContext(ctx, args...)
 }
 
 // RunGitCommandRawWithContext runs a git command raw with context
 func (e *engineImpl) RunGitCommandRawWithContext(ctx context.Context, args ...string) (string, error) {
+	defer e.invalidateCurrentBranch()
 	return e.git.RunGitCommandRawWithContext(ctx, args...)
 }
 
@@ -822,9 +1025,9 @@ func (e *engineImpl) ParseStagedHunks(ctx context.Context) ([]git.Hunk, error) {
 	return e.git.ParseStagedHunks(ctx)
 }
 
-// ShowDiff returns the diff between two refs with optional stat mode
-func (e *engineImpl) ShowDiff(ctx context.Context, left, right string, stat bool) (string, error) {
-	return e.git.ShowDiff(ctx, left, right, stat)
+// ShowDiff returns the diff between two refs, optionally as a diffstat or a list of file names
+func (e *engineImpl) ShowDiff(ctx context.Context, left, right string, opts git.DiffOptions) (string, error) {
+	return e.git.ShowDiff(ctx, left, right, opts)
 }
 
 // ShowCommits returns commit log with optional patches/stat
@@ -851,3 +1054,25 @@ func (e *engineImpl) GetCommitSHA(branchName string, offset int) (string, error)
 func (e *engineImpl) IsAncestor(ancestor, descendant string) (bool, error) {
 	return e.git.IsAncestor(ancestor, descendant)
 }
+
+// BranchExistsLocally reports whether a local branch with the name exists, as of the last
+// rebuild
+func (e *engineImpl) BranchExistsLocally(branchName string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return slices.Contains(e.branches, branchName)
+}
+
+// BranchExistsOnRemote reports whether the remote has a branch with the name, as of the
+// last PopulateRemoteShas or, if that hasn't run, the last fetch. It never goes to the
+// network.
+func (e *engineImpl) BranchExistsOnRemote(branchName string) bool {
+	e.mu.RLock()
+	_, ok := e.remoteShas[branchName]
+	e.mu.RUnlock()
+	if ok {
+		return true
+	}
+	_, err := e.git.GetRemoteSha(e.branchRemote(branchName), branchName)
+	return err == nil
+}
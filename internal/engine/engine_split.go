@@ -69,13 +69,15 @@ func (e *engineImpl) ApplySplitToCommits(ctx context.Context, opts ApplySplitOpt
 		// Preserve PR info if applicable
 		if prInfo != nil {
 			newMeta.PrInfo = &PrInfoPersistence{
-				Number:  prInfo.Number(),
-				Title:   stringPtr(prInfo.Title()),
-				Body:    stringPtr(prInfo.Body()),
-				IsDraft: boolPtr(prInfo.IsDraft()),
-				State:   stringPtr(prInfo.State()),
-				Base:    stringPtr(prInfo.Base()),
-				URL:     stringPtr(prInfo.URL()),
+				Number:      prInfo.Number(),
+				Title:       stringPtr(prInfo.Title()),
+				Body:        stringPtr(prInfo.Body()),
+				IsDraft:     boolPtr(prInfo.IsDraft()),
+				State:       stringPtr(prInfo.State()),
+				Base:        stringPtr(prInfo.Base()),
+				URL:         stringPtr(prInfo.URL()),
+				HeadSHA:     stringPtr(prInfo.HeadSHA()),
+				TargetTrunk: stringPtr(prInfo.TargetTrunk()),
 			}
 		}
 
@@ -85,7 +87,7 @@ func (e *engineImpl) ApplySplitToCommits(ctx context.Context, opts ApplySplitOpt
 
 		// Update in-memory cache
 		e.parentMap[branchName] = lastBranchName
-		e.childrenMap[lastBranchName] = append(e.childrenMap[lastBranchName], branchName)
+		e.addChild(lastBranchName, branchName)
 
 		// Update last branch info
 		lastBranchName = branchName
@@ -134,10 +136,22 @@ func (e *engineImpl) Detach(ctx context.Context, revision string) error {
 	return nil
 }
 
-// DetachAndResetBranchChanges detaches HEAD and soft resets to the parent's merge base,
+// DetachAndResetBranchChanges detaches HEAD and resets to the parent's merge base,
 // leaving the branch's changes as unstaged modifications. This is used by split --by-hunk
 // to allow the user to interactively re-stage changes into new branches.
 func (e *engineImpl) DetachAndResetBranchChanges(ctx context.Context, branchName string) error {
+	return e.detachAndResetBranchChanges(ctx, branchName, false)
+}
+
+// DetachAndStageBranchChanges is like DetachAndResetBranchChanges, but leaves the branch's
+// changes staged, new files included, ready to be committed onto another base
+func (e *engineImpl) DetachAndStageBranchChanges(ctx context.Context, branchName string) error {
+	return e.detachAndResetBranchChanges(ctx, branchName, true)
+}
+
+func (e *engineImpl) detachAndResetBranchChanges(ctx context.Context, branchName string, stage bool) error {
+	defer e.invalidateReadCache()
+
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
@@ -166,11 +180,16 @@ func (e *engineImpl) DetachAndResetBranchChanges(ctx context.Context, branchName
 		return fmt.Errorf("failed to detach HEAD: %w", err)
 	}
 
-	// Soft reset to the merge base - this keeps all the branch's changes
-	// but unstages them, allowing the user to re-stage them interactively
-	_, err = e.git.RunGitCommandWithContext(ctx, "reset", mergeBase)
+	// Reset to the merge base, keeping all the branch's changes. A mixed reset unstages
+	// them so the user can re-stage them interactively; a soft reset keeps the index at the
+	// branch's tree, so exactly the branch's changes stay staged.
+	mode := "--mixed"
+	if stage {
+		mode = "--soft"
+	}
+	_, err = e.git.RunGitCommandWithContext(ctx, "reset", mode, mergeBase)
 	if err != nil {
-		return fmt.Errorf("failed to soft reset: %w", err)
+		return fmt.Errorf("failed to reset to merge base: %w", err)
 	}
 
 	e.currentBranch = ""
@@ -4,18 +4,21 @@ import (
 	"context"
 	"fmt"
 
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/git"
 )
 
 // SquashCurrentBranch squashes all commits in the current branch into a single commit
 func (e *engineImpl) SquashCurrentBranch(ctx context.Context, opts SquashOptions) error {
+	defer e.invalidateReadCache()
+
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
 	// Get current branch
 	branchName := e.currentBranch
 	if branchName == "" {
-		return fmt.Errorf("not on a branch")
+		return stackiterrors.ErrNotOnBranch
 	}
 
 	// Check if branch is trunk (check directly since we hold the lock)
@@ -48,8 +51,6 @@ func (e *engineImpl) SquashCurrentBranch(ctx context.Context, opts SquashOptions
 		return fmt.Errorf("failed to get commit range: %w", err)
 	}
 
-	fmt.Printf("DEBUG: Squash branch=%s parentRev=%s headRev=%s range=%v\n", branchName, parentBranchRevision, branchRevision, commitSHAs)
-
 	// Check if there are commits to squash
 	if len(commitSHAs) == 0 {
 		return fmt.Errorf("no commits to squash")
@@ -72,7 +73,7 @@ func (e *engineImpl) SquashCurrentBranch(ctx context.Context, opts SquashOptions
 		Amend:   true,
 		Message: opts.Message,
 		NoEdit:  opts.NoEdit,
-		// Don't set Edit - git will open editor by default if no message and no noEdit
+		Edit:    opts.Edit && !opts.NoEdit,
 	}
 
 	if err := e.git.CommitWithOptions(commitOpts); err != nil {
@@ -3,44 +3,303 @@ package engine
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
 
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/git"
 )
 
-// PullTrunk pulls the trunk branch from remote
-func (e *engineImpl) PullTrunk(ctx context.Context) (PullResult, error) {
+// PullTrunk pulls the trunk branch from remote. Trunk is fast-forwarded when possible;
+// when it has diverged, the engine's TrunkStrategy decides whether to rebase it, reset it,
+// or report PullConflict. The report says how far trunk was behind or ahead of the remote.
+func (e *engineImpl) PullTrunk(ctx context.Context) (TrunkPullReport, error) {
+	defer e.invalidateReadCache()
+
 	remote := e.git.GetRemote()
 	e.mu.RLock()
 	trunk := e.trunk
 	e.mu.RUnlock()
+
+	report := TrunkPullReport{Result: PullConflict}
+	report.OldRev, _ = e.git.GetRevision(trunk)
+
 	gitResult, err := e.git.PullBranch(ctx, remote, trunk)
 	if err != nil {
-		return PullConflict, err
+		return report, err
 	}
 
 	// Convert git.PullResult to engine.PullResult
-	var result PullResult
 	switch gitResult {
 	case git.PullDone:
-		result = PullDone
+		report.Result = PullDone
 	case git.PullUnneeded:
-		result = PullUnneeded
-	case git.PullConflict:
-		result = PullConflict
+		report.Result = PullUnneeded
 	default:
-		result = PullConflict
+		report.Result = PullConflict
+	}
+
+	// The pull fetched the remote trunk, so count how the two sides differ before
+	// reconciling them
+	if remoteRev, err := e.git.RunGitCommandWithContext(ctx, "rev-parse", fmt.Sprintf("%s/%s", remote, trunk)); err == nil {
+		report.RemoteRev = remoteRev
+		if report.OldRev != "" && report.Result != PullUnneeded {
+			if ahead, behind, err := e.GetDivergence(report.OldRev, remoteRev); err == nil {
+				report.Ahead, report.Behind = ahead, behind
+			}
+		}
 	}
 
+	if report.Result == PullConflict && e.trunkStrategy != TrunkStrategyFFOnly {
+		report.Result, err = e.reconcileDivergedTrunk(ctx, remote, trunk)
+		if err != nil {
+			report.Result = PullConflict
+			return report, err
+		}
+	}
+
+	report.NewRev, _ = e.git.GetRevision(trunk)
+
 	// Rebuild to refresh branch cache
 	if err := e.rebuild(); err != nil {
-		return result, fmt.Errorf("failed to rebuild after pull: %w", err)
+		return report, fmt.Errorf("failed to rebuild after pull: %w", err)
+	}
+
+	return report, nil
+}
+
+// ResetBranchToRemote fetches a branch and points it at the remote tip, returning the local
+// revision it replaced. Branch metadata is left alone, so children still record the replaced
+// revision as their parent base and a restack replays just their own commits onto the remote tip.
+func (e *engineImpl) ResetBranchToRemote(ctx context.Context, branchName string) (string, error) {
+	defer e.invalidateReadCache()
+
+	remote := e.git.GetRemote()
+	oldRev, err := e.git.GetRevision(branchName)
+	if err != nil {
+		return "", fmt.Errorf("failed to get revision for %s: %w", branchName, err)
+	}
+
+	if _, err := e.git.RunGitCommandWithContext(ctx, "fetch", remote, branchName); err != nil {
+		return "", fmt.Errorf("failed to fetch %s from %s: %w", branchName, remote, err)
+	}
+	remoteRev, err := e.git.RunGitCommandWithContext(ctx, "rev-parse", fmt.Sprintf("%s/%s", remote, branchName))
+	if err != nil {
+		return "", fmt.Errorf("failed to get remote revision for %s: %w", branchName, err)
 	}
 
+	currentBranch, err := e.git.GetCurrentBranch()
+	if err != nil {
+		currentBranch = ""
+	}
+	if currentBranch == branchName {
+		if err := e.git.HardReset(ctx, remoteRev); err != nil {
+			return "", fmt.Errorf("failed to reset %s: %w", branchName, err)
+		}
+	} else if err := e.git.UpdateBranchRef(branchName, remoteRev); err != nil {
+		return "", fmt.Errorf("failed to reset %s: %w", branchName, err)
+	}
+
+	// Rebuild to refresh branch cache
+	if err := e.rebuild(); err != nil {
+		return "", fmt.Errorf("failed to rebuild after reset: %w", err)
+	}
+
+	return oldRev, nil
+}
+
+// RestoreBranchTip moves a branch back to a revision it had before a restack, along with the
+// parent revision recorded in its metadata. A checked-out branch is hard reset. An empty
+// parentRevision leaves the metadata alone.
+func (e *engineImpl) RestoreBranchTip(ctx context.Context, branchName, revision, parentRevision string) error {
+	defer e.invalidateReadCache()
+
+	currentBranch, err := e.git.GetCurrentBranch()
+	if err != nil {
+		currentBranch = ""
+	}
+	if currentBranch == branchName {
+		if err := e.git.HardReset(ctx, revision); err != nil {
+			return fmt.Errorf("failed to reset %s: %w", branchName, err)
+		}
+	} else if err := e.git.UpdateBranchRef(branchName, revision); err != nil {
+		return fmt.Errorf("failed to reset %s: %w", branchName, err)
+	}
+
+	if parentRevision == "" {
+		return nil
+	}
+	return e.UpdateParentRevision(branchName, parentRevision)
+}
+
+// UpdateRewrittenRefs moves each branch whose child was rewritten on top of it (the child no
+// longer contains the branch's tip, though the branch hasn't changed since the child was
+// stacked) to the commit in the child's history with the same patch ID as its tip. Children
+// are visited before their parents, so a whole stack rewritten from its top branch is
+// followed all the way down to trunk.
+func (e *engineImpl) UpdateRewrittenRefs(ctx context.Context) (UpdateRefsResult, error) {
+	defer e.invalidateReadCache()
+
+	var result UpdateRefsResult
+	trunk := e.Trunk()
+	branches := e.SortBranchesTopologically(trunk.GetRelativeStack(StackRange{RecursiveChildren: true}))
+	for _, child := range slices.Backward(branches) {
+		parent := e.GetParent(child)
+		if parent == nil || parent.IsTrunk() {
+			continue
+		}
+		followed, err := e.followRewrittenParent(ctx, child.GetName(), parent.GetName(), trunk.GetName())
+		if err != nil {
+			return result, err
+		}
+		switch {
+		case followed == nil:
+		case followed.NewRevision == "":
+			result.Unmatched = append(result.Unmatched, *followed)
+		default:
+			result.Moved = append(result.Moved, *followed)
+		}
+	}
 	return result, nil
 }
 
+// followRewrittenParent moves parent to the rewritten copy of its tip in child's history.
+// It returns nil when child wasn't rewritten on top of parent.
+func (e *engineImpl) followRewrittenParent(ctx context.Context, child, parent, trunk string) (*FollowedBranch, error) {
+	parentTip, err := e.git.GetRevision(parent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision for %s: %w", parent, err)
+	}
+	childTip, err := e.git.GetRevision(child)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision for %s: %w", child, err)
+	}
+	meta, err := e.readMetadataRef(child)
+	if err != nil || meta.ParentBranchRevision == nil || *meta.ParentBranchRevision != parentTip {
+		// The parent moved since the child was stacked: an ordinary restack
+		return nil, nil
+	}
+	if contained, err := e.git.IsAncestor(parentTip, childTip); err != nil || contained {
+		return nil, err
+	}
+
+	followed := &FollowedBranch{Name: parent, Child: child, OldRevision: parentTip}
+	tipIDs, err := e.git.GetPatchIDs(ctx, parentTip+"~1", parentTip)
+	if err != nil || tipIDs[parentTip] == "" {
+		// A merge or empty commit has no patch ID to match
+		return followed, nil
+	}
+	base, err := e.git.GetMergeBase(trunk, child)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge base of %s and %s: %w", trunk, child, err)
+	}
+	childIDs, err := e.git.GetPatchIDs(ctx, base, childTip)
+	if err != nil {
+		return nil, err
+	}
+	for sha, id := range childIDs {
+		if id != tipIDs[parentTip] {
+			continue
+		}
+		if followed.NewRevision != "" {
+			// More than one copy; don't guess
+			followed.NewRevision = ""
+			return followed, nil
+		}
+		followed.NewRevision = sha
+	}
+	if followed.NewRevision == "" {
+		return followed, nil
+	}
+
+	if err := e.RestoreBranchTip(ctx, parent, followed.NewRevision, ""); err != nil {
+		return nil, err
+	}
+	if err := e.UpdateParentRevision(child, followed.NewRevision); err != nil {
+		return nil, err
+	}
+
+	// A branch on trunk may have been rewritten onto a newer trunk as well; its own
+	// changes now start where its new tip meets trunk
+	if grandparent := e.GetParent(NewBranch(parent, e)); grandparent != nil && grandparent.IsTrunk() {
+		newBase, err := e.git.GetMergeBase(trunk, parent)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get merge base of %s and %s: %w", trunk, parent, err)
+		}
+		if err := e.UpdateParentRevision(parent, newBase); err != nil {
+			return nil, err
+		}
+	}
+	return followed, nil
+}
+
+// reconcileDivergedTrunk brings a trunk that can't be fast-forwarded in line with the
+// remote trunk (already fetched by the pull) according to the trunk strategy
+func (e *engineImpl) reconcileDivergedTrunk(ctx context.Context, remote, trunk string) (PullResult, error) {
+	remoteRev, err := e.git.RunGitCommandWithContext(ctx, "rev-parse", fmt.Sprintf("%s/%s", remote, trunk))
+	if err != nil {
+		return PullConflict, fmt.Errorf("failed to get remote revision for %s: %w", trunk, err)
+	}
+
+	currentBranch, err := e.git.GetCurrentBranch()
+	if err != nil {
+		currentBranch = ""
+	}
+
+	switch e.trunkStrategy {
+	case TrunkStrategyResetToRemote:
+		if currentBranch == trunk {
+			if err := e.git.HardReset(ctx, remoteRev); err != nil {
+				return PullConflict, fmt.Errorf("failed to reset %s: %w", trunk, err)
+			}
+		} else if err := e.git.UpdateBranchRef(trunk, remoteRev); err != nil {
+			return PullConflict, fmt.Errorf("failed to reset %s: %w", trunk, err)
+		}
+		return PullReset, nil
+
+	case TrunkStrategyRebase:
+		mergeBase, err := e.git.GetMergeBaseByRef(trunk, remoteRev)
+		if err != nil {
+			return PullConflict, fmt.Errorf("failed to find merge base of %s and %s: %w", trunk, remoteRev, err)
+		}
+
+		// The rebase checks out trunk, so remember where to come back to
+		originalRev := ""
+		if currentBranch == "" {
+			originalRev, _ = e.git.RunGitCommandWithContext(ctx, "rev-parse", "HEAD")
+		}
+
+		rebaseResult, err := e.git.Rebase(ctx, trunk, remoteRev, mergeBase, git.RebaseOptions{})
+		if err != nil || rebaseResult == git.RebaseConflict {
+			_, _ = e.git.RunGitCommandWithContext(ctx, "rebase", "--abort")
+		}
+
+		switch {
+		case currentBranch != "" && currentBranch != trunk:
+			_ = e.git.CheckoutBranch(ctx, currentBranch)
+		case originalRev != "":
+			_ = e.git.CheckoutDetached(ctx, originalRev)
+		}
+
+		if err != nil {
+			return PullConflict, fmt.Errorf("failed to rebase %s onto %s: %w", trunk, remoteRev, err)
+		}
+		if rebaseResult == git.RebaseConflict {
+			return PullConflict, nil
+		}
+		return PullRebased, nil
+
+	default:
+		return PullConflict, nil
+	}
+}
+
 // ResetTrunkToRemote resets trunk to match remote
 func (e *engineImpl) ResetTrunkToRemote(ctx context.Context) error {
+	defer e.invalidateReadCache()
+
 	remote := e.git.GetRemote()
 
 	e.mu.RLock()
@@ -95,6 +354,8 @@ func (e *engineImpl) restackBranch(
 	revMap map[string]string,
 	rebuildAfterRestack bool,
 ) (RestackBranchResult, error) {
+	defer e.invalidateReadCache()
+
 	branchName := branch.GetName()
 	e.mu.RLock()
 	parent, ok := e.parentMap[branchName]
@@ -125,6 +386,12 @@ func (e *engineImpl) restackBranch(
 	needsReparent := e.shouldReparentBranch(ctx, parent, metaMap)
 	e.mu.RUnlock()
 
+	// A parent that was deleted locally but is still on the remote may have been deleted by
+	// mistake, so leave its children where they are rather than silently moving them
+	if needsReparent && e.parentOnlyOnRemote(parent, metaMap) {
+		return RestackBranchResult{Result: RestackUnneeded, RemoteOnlyParent: parent}, nil
+	}
+
 	if needsReparent {
 		oldParent = parent
 
@@ -186,12 +453,9 @@ func (e *engineImpl) restackBranch(
 		}, nil
 	}
 
-	oldParentRev := parentRev
-	if meta.ParentBranchRevision != nil {
-		oldParentRev = *meta.ParentBranchRevision
-	}
+	oldParentRev := e.restackBase(branchName, parent, parentRev, meta)
 
-	// If parent hasn't changed, no need to restack (early exit before expensive operations)
+	// The parent might still be unchanged after resolving where the branch starts
 	if parentRev == oldParentRev {
 		return RestackBranchResult{
 			Result:            RestackUnneeded,
@@ -202,35 +466,19 @@ func (e *engineImpl) restackBranch(
 		}, nil
 	}
 
-	// RESILIENCY: If oldParentRev is no longer an ancestor of branchName,
-	// or if it's empty, find the actual merge base. This handles cases where
-	// the parent was amended or rebased outside of stackit.
-	if oldParentRev != "" {
-		if isAncestor, _ := e.git.IsAncestor(oldParentRev, branchName); !isAncestor {
-			if mergeBase, err := e.git.GetMergeBase(branchName, parent); err == nil {
-				oldParentRev = mergeBase
-			}
-		}
-	} else {
-		// No old parent revision in metadata, try to find merge base
-		if mergeBase, err := e.git.GetMergeBase(branchName, parent); err == nil {
-			oldParentRev = mergeBase
-		}
+	// Remember where the branch was so callers can report how it moved
+	oldRev := revMap[branchName]
+	if oldRev == "" {
+		oldRev, _ = e.git.GetRevision(branchName)
 	}
 
-	// Check again after resiliency logic - parent might still be unchanged
-	if parentRev == oldParentRev {
-		return RestackBranchResult{
-			Result:            RestackUnneeded,
-			RebasedBranchBase: parentRev,
-			Reparented:        reparented,
-			OldParent:         oldParent,
-			NewParent:         parent,
-		}, nil
+	// Perform rebase, or merge the parent in when using the merge strategy
+	var gitResult git.RebaseResult
+	if e.restackStrategy == RestackStrategyMerge {
+		gitResult, err = e.git.Merge(ctx, branchName, parent)
+	} else {
+		gitResult, err = e.git.Rebase(ctx, branchName, parent, oldParentRev, e.rebaseOptions())
 	}
-
-	// Perform rebase
-	gitResult, err := e.git.Rebase(ctx, branchName, parent, oldParentRev)
 	if err != nil {
 		return RestackBranchResult{
 			Result:            RestackConflict,
@@ -305,9 +553,45 @@ func (e *engineImpl) restackBranch(
 		Reparented:        reparented,
 		OldParent:         oldParent,
 		NewParent:         parent,
+		OldRevision:       oldRev,
+		NewRevision:       newRev,
+		// Every commit of the branch was dropped, e.g. because the parent already has its changes
+		Empty: newRev == parentRev && oldRev != oldParentRev,
 	}, nil
 }
 
+// restackBase returns the revision a branch's own commits start from, which restack replays
+// onto parentRev. This is the parent revision recorded in the branch's metadata, or the merge
+// base with the parent if that revision is no longer in the branch's history.
+func (e *engineImpl) restackBase(branchName, parent, parentRev string, meta *Meta) string {
+	oldParentRev := parentRev
+	if meta.ParentBranchRevision != nil {
+		oldParentRev = *meta.ParentBranchRevision
+	}
+
+	// If parent hasn't changed, no need to restack (early exit before expensive operations)
+	if parentRev == oldParentRev {
+		return oldParentRev
+	}
+
+	// RESILIENCY: If oldParentRev is no longer an ancestor of branchName,
+	// or if it's empty, find the actual merge base. This handles cases where
+	// the parent was amended or rebased outside of stackit.
+	if oldParentRev != "" {
+		if isAncestor, _ := e.git.IsAncestor(oldParentRev, branchName); !isAncestor {
+			if mergeBase, err := e.git.GetMergeBase(branchName, parent); err == nil {
+				oldParentRev = mergeBase
+			}
+		}
+	} else {
+		// No old parent revision in metadata, try to find merge base
+		if mergeBase, err := e.git.GetMergeBase(branchName, parent); err == nil {
+			oldParentRev = mergeBase
+		}
+	}
+	return oldParentRev
+}
+
 // RestackBranches implements a hybrid batch approach for performance:
 // 1. Collect all data required for the restack (in bulk)
 // 2. Process branches using individual restackBranch calls with deferred rebuilds
@@ -370,8 +654,49 @@ func (e *engineImpl) RestackBranches(ctx context.Context, branches []Branch) (Re
 
 	for i, branch := range branches {
 		branchName := branch.GetName()
+		// Decide before rebasing, while the branch still has its own commits to compare against trunk
+		prunable := e.canPruneEmpty(ctx, branchName, allMeta, allRevisions)
+
 		result, err := e.restackBranch(ctx, branch, allMeta, allRevisions, false) // Don't rebuild after each branch
 		results[branchName] = result
+		// A branch whose commits were all dropped by the rebase had changes that landed
+		// upstream, so it isn't intentionally empty
+		if result.Empty && !prunable {
+			prunable = e.canPruneDropped()
+		}
+
+		if err == nil && prunable && (result.Result == RestackDone || result.Result == RestackUnneeded) {
+			parent, pruned, pruneErr := e.pruneIfEmpty(ctx, branchName, result.RebasedBranchBase, allMeta)
+			if pruneErr != nil {
+				return RestackBatchResult{Results: results}, pruneErr
+			}
+			if pruned {
+				result.Result = RestackPruned
+				result.NewParent = parent
+				results[branchName] = result
+				needsRebuild = true
+				// Land on the parent rather than trunk if we pruned the branch we started on
+				if originalBranch != nil && originalBranch.GetName() == branchName {
+					parentBranch := e.GetBranch(parent)
+					originalBranch = &parentBranch
+				}
+				continue
+			}
+		}
+
+		if err == nil && result.Result == RestackDone {
+			if hookErr := e.runPostRestackHook(ctx, branchName); hookErr != nil {
+				remainingBranchNames := make([]string, len(branches[i+1:]))
+				for j, b := range branches[i+1:] {
+					remainingBranchNames[j] = b.GetName()
+				}
+				return RestackBatchResult{
+					HookFailedBranch:  branchName,
+					RemainingBranches: remainingBranchNames,
+					Results:           results,
+				}, hookErr
+			}
+		}
 
 		if err == nil && (result.Result == RestackDone || result.Result == RestackUnneeded) {
 			// Update the revision map with the current SHA of the branch.
@@ -437,8 +762,16 @@ func (e *engineImpl) RestackBranches(ctx context.Context, branches []Branch) (Re
 
 // ContinueRebase continues an in-progress rebase
 func (e *engineImpl) ContinueRebase(ctx context.Context, branchName string, rebasedBranchBase string) (ContinueRebaseResult, error) {
-	// Call git rebase --continue
-	result, err := e.git.RebaseContinue(ctx)
+	defer e.invalidateReadCache()
+
+	// Call git rebase --continue, or conclude the merge if restacking with the merge strategy
+	var result git.RebaseResult
+	var err error
+	if e.git.IsMergeInProgress(ctx) {
+		result, err = e.git.MergeContinue(ctx)
+	} else {
+		result, err = e.git.RebaseContinue(ctx)
+	}
 	if err != nil {
 		return ContinueRebaseResult{Result: int(git.RebaseConflict), BranchName: branchName}, err
 	}
@@ -471,6 +804,10 @@ func (e *engineImpl) ContinueRebase(ctx context.Context, branchName string, reba
 		return ContinueRebaseResult{}, fmt.Errorf("failed to rebuild after continue: %w", err)
 	}
 
+	if err := e.runPostRestackHook(ctx, branchName); err != nil {
+		return ContinueRebaseResult{Result: int(git.RebaseDone), BranchName: branchName}, err
+	}
+
 	return ContinueRebaseResult{
 		Result:     int(git.RebaseDone),
 		BranchName: branchName,
@@ -479,7 +816,9 @@ func (e *engineImpl) ContinueRebase(ctx context.Context, branchName string, reba
 
 // Rebase rebases a branch onto another branch
 func (e *engineImpl) Rebase(ctx context.Context, branchName, upstream, oldUpstream string) (RestackResult, error) {
-	gitResult, err := e.git.Rebase(ctx, branchName, upstream, oldUpstream)
+	defer e.invalidateReadCache()
+
+	gitResult, err := e.git.Rebase(ctx, branchName, upstream, oldUpstream, e.rebaseOptions())
 	if err != nil {
 		return RestackConflict, err
 	}
@@ -490,3 +829,156 @@ func (e *engineImpl) Rebase(ctx context.Context, branchName, upstream, oldUpstre
 
 	return RestackDone, nil
 }
+
+// runPostRestackHook runs the configured post-restack hook in the working tree, which has the
+// just-restacked branch checked out. The hook may commit to the branch, e.g. to regenerate lockfiles.
+func (e *engineImpl) runPostRestackHook(ctx context.Context, branchName string) error {
+	if e.postRestackHook == "" {
+		return nil
+	}
+
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", e.postRestackHook)
+	cmd.Dir = e.repoRoot
+	cmd.Env = append(os.Environ(), "STACKIT_BRANCH="+branchName)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return &stackiterrors.PostRestackHookError{
+			BranchName: branchName,
+			Output:     strings.TrimSpace(string(output)),
+			Err:        err,
+		}
+	}
+	return nil
+}
+
+// SetPreserveDates sets whether rebases keep committer dates equal to author dates
+func (e *engineImpl) SetPreserveDates(preserve bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.preserveDates = preserve
+}
+
+// SetKeepEmpty sets whether rebases keep commits that are or become empty, overriding
+// git's defaults
+func (e *engineImpl) SetKeepEmpty(keep bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.emptyCommits = git.EmptyCommitsDrop
+	if keep {
+		e.emptyCommits = git.EmptyCommitsKeep
+	}
+}
+
+// rebaseOptions returns the git rebase options for the engine's configuration
+func (e *engineImpl) rebaseOptions() git.RebaseOptions {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return git.RebaseOptions{PreserveDates: e.preserveDates, EmptyCommits: e.emptyCommits}
+}
+
+// canPruneEmpty reports whether restack may delete the branch if it ends up empty, per the
+// engine's PruneEmptyMode. In PruneEmptyMerged mode the branch must have a merged PR, or
+// have commits of its own that are all already in trunk, so intentionally empty branches are kept.
+func (e *engineImpl) canPruneEmpty(ctx context.Context, branchName string, metaMap map[string]*Meta, revMap map[string]string) bool {
+	e.mu.RLock()
+	mode := e.pruneEmpty
+	trunk := e.trunk
+	_, tracked := e.parentMap[branchName]
+	e.mu.RUnlock()
+
+	if !tracked || branchName == trunk {
+		return false
+	}
+
+	if mode == PruneEmptyAlways {
+		return true
+	}
+	if mode != PruneEmptyMerged {
+		return false
+	}
+
+	meta := metaMap[branchName]
+	if meta == nil {
+		var err error
+		if meta, err = e.readMetadataRef(branchName); err != nil {
+			return false
+		}
+	}
+	if meta.PrInfo != nil && meta.PrInfo.State != nil && *meta.PrInfo.State == "MERGED" {
+		return true
+	}
+
+	rev := revMap[branchName]
+	if rev == "" {
+		var err error
+		if rev, err = e.git.GetRevision(branchName); err != nil {
+			return false
+		}
+	}
+	// A branch without commits of its own is trivially "merged"; keep it
+	if meta.ParentBranchRevision == nil || *meta.ParentBranchRevision == rev {
+		return false
+	}
+
+	merged, err := e.git.IsMerged(ctx, branchName, trunk)
+	return err == nil && merged
+}
+
+// canPruneDropped reports whether restack may delete a branch whose commits were all dropped
+// while rebasing it, which any PruneEmptyMode but PruneEmptyNever allows
+func (e *engineImpl) canPruneDropped() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.pruneEmpty != PruneEmptyNever
+}
+
+// pruneIfEmpty deletes a branch whose tree matches its parent at parentRev, moving its
+// children onto the parent. Children keep their recorded parent revision so that restacking
+// them replays only their own commits. It returns the parent and whether the branch was deleted.
+func (e *engineImpl) pruneIfEmpty(ctx context.Context, branchName, parentRev string, metaMap map[string]*Meta) (string, bool, error) {
+	e.mu.RLock()
+	parent := e.parentMap[branchName]
+	children := slices.Clone(e.childrenMap[branchName])
+	e.mu.RUnlock()
+
+	if parent == "" || parentRev == "" {
+		return "", false, nil
+	}
+
+	// Failing to diff just means the branch is kept
+	empty, err := e.git.IsDiffEmpty(ctx, branchName, parentRev)
+	if err != nil || !empty {
+		return parent, false, nil //nolint:nilerr
+	}
+
+	for _, child := range children {
+		meta, err := e.readMetadataRef(child)
+		if err != nil {
+			return parent, false, fmt.Errorf("failed to read metadata for %s: %w", child, err)
+		}
+		meta.ParentBranchName = &parent
+		if err := e.writeMetadataRef(child, meta); err != nil {
+			return parent, false, fmt.Errorf("failed to move %s onto %s: %w", child, parent, err)
+		}
+		if metaMap != nil {
+			metaMap[child] = meta
+		}
+	}
+
+	e.mu.Lock()
+	for _, child := range children {
+		e.parentMap[child] = parent
+		e.addChild(parent, child)
+	}
+	delete(e.childrenMap, branchName)
+	e.mu.Unlock()
+
+	// The rebase leaves the branch checked out; step off it so it can be deleted
+	if _, err := e.git.RunGitCommandWithContext(ctx, "checkout", "--detach", parentRev); err != nil {
+		return parent, false, fmt.Errorf("failed to detach from %s: %w", branchName, err)
+	}
+	if err := e.DeleteBranch(ctx, e.GetBranch(branchName)); err != nil {
+		return parent, false, fmt.Errorf("failed to delete empty branch %s: %w", branchName, err)
+	}
+	return parent, true, nil
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+
+	"stackit.dev/stackit/internal/git"
 )
 
 // PushBranch pushes a branch to the remote
@@ -12,8 +14,23 @@ func (e *engineImpl) PushBranch(ctx context.Context, branchName string, remote s
 	return e.git.PushBranch(ctx, branchName, remote, force, forceWithLease)
 }
 
-// TrackBranch tracks a branch with a parent branch
+// PushBranchWithOptions pushes a branch to the remote with the given options
+func (e *engineImpl) PushBranchWithOptions(ctx context.Context, opts git.PushOptions) error {
+	return e.git.PushBranchWithOptions(ctx, opts)
+}
+
+// TrackBranch tracks a branch with a parent branch, which must be one the branch was built on
 func (e *engineImpl) TrackBranch(ctx context.Context, branchName string, parentBranchName string) error {
+	return e.trackBranch(ctx, branchName, parentBranchName, false)
+}
+
+// ForceTrackBranch tracks a branch with a parent branch without checking that the branch was
+// built on it, for callers that restack the branch onto its new parent afterwards
+func (e *engineImpl) ForceTrackBranch(ctx context.Context, branchName string, parentBranchName string) error {
+	return e.trackBranch(ctx, branchName, parentBranchName, true)
+}
+
+func (e *engineImpl) trackBranch(ctx context.Context, branchName string, parentBranchName string, force bool) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
@@ -78,9 +95,42 @@ func (e *engineImpl) TrackBranch(ctx context.Context, branchName string, parentB
 		}
 	}
 
+	if !force {
+		if err := e.validateTrackingParent(branchName, parentBranchName); err != nil {
+			return err
+		}
+	}
+
 	return e.setParentInternal(ctx, branchName, parentBranchName)
 }
 
+// validateTrackingParent checks that branchName was built on parentBranchName. The parent's
+// tip must be an ancestor of the branch or, if the parent has moved on since the branch was
+// created, their merge base must be one of the parent's own commits rather than a commit
+// from below the parent. Any branch may be tracked onto trunk.
+func (e *engineImpl) validateTrackingParent(branchName string, parentBranchName string) error {
+	if parentBranchName == e.trunk {
+		return nil
+	}
+	if isAncestor, err := e.git.IsAncestor(parentBranchName, branchName); err == nil && isAncestor {
+		return nil
+	}
+
+	mergeBase, err := e.git.GetMergeBase(branchName, parentBranchName)
+	if err == nil {
+		if meta, err := e.readMetadataRef(parentBranchName); err == nil && meta.ParentBranchRevision != nil {
+			parentBase := *meta.ParentBranchRevision
+			if mergeBase != parentBase {
+				if isAncestor, _ := e.git.IsAncestor(parentBase, mergeBase); isAncestor {
+					return nil
+				}
+			}
+		}
+	}
+
+	return fmt.Errorf("parent %s is not an ancestor of %s", parentBranchName, branchName)
+}
+
 // UntrackBranch stops tracking a branch by deleting its metadata
 func (e *engineImpl) UntrackBranch(branchName string) error {
 	if e.IsTrunkInternal(branchName) {
@@ -95,12 +145,14 @@ func (e *engineImpl) UntrackBranch(branchName string) error {
 		return fmt.Errorf("failed to delete metadata ref: %w", err)
 	}
 
-	// Rebuild cache (already holding lock, so call rebuildInternal)
-	return e.rebuildInternal(true)
+	// Only this branch's entries change (already holding lock)
+	return e.rebuildBranchesInternal([]string{branchName})
 }
 
 // DeleteBranch deletes a branch and its metadata
 func (e *engineImpl) DeleteBranch(ctx context.Context, branch Branch) error {
+	defer e.invalidateReadCache()
+
 	branchName := branch.GetName()
 	if e.IsTrunkInternal(branchName) {
 		return fmt.Errorf("cannot delete trunk branch")
@@ -212,15 +264,45 @@ func (e *engineImpl) CheckoutBranch(ctx context.Context, branch Branch) error {
 				return err
 			}
 			e.currentBranch = "" // Detached HEAD
+			e.currentStale.Store(false)
 			return nil
 		}
 		return err
 	}
 
 	e.currentBranch = branchName
+	e.currentStale.Store(false)
 	return nil
 }
 
+// SafeCheckout checks out a branch like CheckoutBranch. If local changes block the checkout
+// and autostash is set, they are stashed, the branch is checked out, and the changes are
+// popped onto it. Changes that conflict with the new branch are left in the stash.
+func (e *engineImpl) SafeCheckout(ctx context.Context, branch Branch, autostash bool) (SafeCheckoutResult, error) {
+	err := e.CheckoutBranch(ctx, branch)
+	if err == nil || !autostash {
+		return SafeCheckoutResult{}, err
+	}
+	status, statusErr := e.git.RunGitCommandWithContext(ctx, "status", "--porcelain")
+	if statusErr != nil || status == "" {
+		// Not blocked by local changes, so stashing won't help
+		return SafeCheckoutResult{}, err
+	}
+
+	if _, err := e.StashPush(ctx, "stackit-autostash"); err != nil {
+		return SafeCheckoutResult{}, err
+	}
+	if err := e.CheckoutBranch(ctx, branch); err != nil {
+		// Put the changes back where they came from
+		_ = e.StashPop(ctx)
+		return SafeCheckoutResult{}, err
+	}
+	if err := e.StashPop(ctx); err != nil {
+		return SafeCheckoutResult{Stashed: true, StashConflict: true}, nil
+	}
+	return SafeCheckoutResult{Stashed: true}, nil
+}
+
 // CreateAndCheckoutBranch creates and checks out a new branch
 func (e *engineImpl) CreateAndCheckoutBranch(ctx context.Context, branch Branch) error {
 	e.mu.Lock()
@@ -232,6 +314,7 @@ func (e *engineImpl) CreateAndCheckoutBranch(ctx context.Context, branch Branch)
 	}
 
 	e.currentBranch = branchName
+	e.currentStale.Store(false)
 	// Add to branches list if not already there
 	found := false
 	for _, b := range e.branches {
@@ -254,6 +337,47 @@ func (e *engineImpl) SetParent(ctx context.Context, branch Branch, parentBranch
 	return e.setParentInternal(ctx, branch.GetName(), parentBranch.GetName())
 }
 
+// SetParentMetadataOnly reparents a branch that already sits on top of its new parent,
+// e.g. after a manual rebase. Unlike SetParent it doesn't reconcile the old divergence
+// point: ParentBranchRevision becomes the new parent's current tip and no commits move.
+func (e *engineImpl) SetParentMetadataOnly(branch Branch, parentBranch Branch) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	branchName := branch.GetName()
+	parentBranchName := parentBranch.GetName()
+	if branchName == parentBranchName {
+		return fmt.Errorf("cannot set %s as its own parent", branchName)
+	}
+
+	parentRev, err := e.git.GetRevision(parentBranchName)
+	if err != nil {
+		return fmt.Errorf("failed to get revision for %s: %w", parentBranchName, err)
+	}
+	if isAncestor, err := e.git.IsAncestor(parentRev, branchName); err != nil || !isAncestor {
+		return fmt.Errorf("%s is not based on the tip of %s; restack it onto %s instead", branchName, parentBranchName, parentBranchName)
+	}
+
+	meta, err := e.readMetadataRef(branchName)
+	if err != nil {
+		return fmt.Errorf("failed to read metadata: %w", err)
+	}
+	oldParent := ""
+	if meta.ParentBranchName != nil {
+		oldParent = *meta.ParentBranchName
+	}
+
+	meta.ParentBranchName = &parentBranchName
+	meta.ParentBranchRevision = &parentRev
+	if err := e.writeMetadataRef(branchName, meta); err != nil {
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+
+	e.moveChild(branchName, oldParent, parentBranchName)
+
+	return nil
+}
+
 // UpdateParentRevision updates the parent revision in metadata
 func (e *engineImpl) UpdateParentRevision(branchName string, parentRev string) error {
 	e.mu.Lock()
@@ -272,13 +396,80 @@ func (e *engineImpl) UpdateParentRevision(branchName string, parentRev string) e
 	return nil
 }
 
+// RepairParentRevision recomputes a branch's ParentBranchRevision as the merge-base of the
+// branch and its parent when the stored revision isn't in the branch's history, e.g. after
+// it was rewritten outside stackit. Restacking from a stale revision would replay commits
+// the branch no longer has. It reports whether the metadata was changed.
+func (e *engineImpl) RepairParentRevision(branch Branch) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	branchName := branch.GetName()
+	meta, err := e.readMetadataRef(branchName)
+	if err != nil {
+		return false, fmt.Errorf("failed to read metadata: %w", err)
+	}
+	if meta.ParentBranchName == nil {
+		return false, nil
+	}
+	if meta.ParentBranchRevision != nil && *meta.ParentBranchRevision != "" {
+		// A revision that no longer exists can't be an ancestor, so errors count as divergent
+		if isAncestor, err := e.git.IsAncestor(*meta.ParentBranchRevision, branchName); err == nil && isAncestor {
+			return false, nil
+		}
+	}
+
+	mergeBase, err := e.git.GetMergeBase(branchName, *meta.ParentBranchName)
+	if err != nil {
+		return false, fmt.Errorf("failed to get merge base of %s and %s: %w", branchName, *meta.ParentBranchName, err)
+	}
+	meta.ParentBranchRevision = &mergeBase
+	if err := e.writeMetadataRef(branchName, meta); err != nil {
+		return false, fmt.Errorf("failed to write metadata: %w", err)
+	}
+
+	return true, nil
+}
+
 // SetScope updates a branch's scope
 func (e *engineImpl) SetScope(branch Branch, scope Scope) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	branchName := branch.GetName()
+	return e.setScopeLocked(branch.GetName(), scope)
+}
+
+// SetScopeForStack sets scope on a branch and every descendant that doesn't have an
+// explicit scope of its own. Descendants with an explicit override, and the branches
+// beneath them, are left untouched so they keep resolving to that override.
+func (e *engineImpl) SetScopeForStack(branchName, scope string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 
+	newScope := NewScope(scope)
+	if err := e.setScopeLocked(branchName, newScope); err != nil {
+		return err
+	}
+
+	queue := append([]string(nil), e.childrenMap[branchName]...)
+	for len(queue) > 0 {
+		child := queue[0]
+		queue = queue[1:]
+		if e.scopeMap[child] != "" {
+			continue
+		}
+		if err := e.setScopeLocked(child, newScope); err != nil {
+			return err
+		}
+		queue = append(queue, e.childrenMap[child]...)
+	}
+
+	return nil
+}
+
+// setScopeLocked writes a branch's scope to its metadata and the in-memory map.
+// The caller must hold e.mu for writing.
+func (e *engineImpl) setScopeLocked(branchName string, scope Scope) error {
 	// Read existing metadata
 	meta, err := e.readMetadataRef(branchName)
 	if err != nil {
@@ -310,6 +501,8 @@ func (e *engineImpl) SetScope(branch Branch, scope Scope) error {
 
 // RenameBranch renames a branch and its metadata
 func (e *engineImpl) RenameBranch(ctx context.Context, oldBranch, newBranch Branch) error {
+	defer e.invalidateReadCache()
+
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
@@ -343,15 +536,24 @@ func (e *engineImpl) RenameBranch(ctx context.Context, oldBranch, newBranch Bran
 		}
 	}
 
-	// Rebuild in-memory state to be safe
-	return e.rebuildInternal(true)
+	// Reload the renamed branch and the children now pointing at it (already holding lock)
+	return e.rebuildBranchesInternal(append([]string{oldName, newName}, children...))
 }
 
 // Commit creates a new commit
 func (e *engineImpl) Commit(_ context.Context, message string, verbose int) error {
+	defer e.invalidateReadCache()
+
 	return e.git.Commit(message, verbose)
 }
 
+// CommitWithOptions creates a new commit with the given options
+func (e *engineImpl) CommitWithOptions(_ context.Context, opts git.CommitOptions) error {
+	defer e.invalidateReadCache()
+
+	return e.git.CommitWithOptions(opts)
+}
+
 // StageAll stages all changes
 func (e *engineImpl) StageAll(ctx context.Context) error {
 	return e.git.StageAll(ctx)
@@ -384,11 +586,13 @@ func (e *engineImpl) SetWorkingDir(dir string) {
 
 // RunGitCommand runs a git command
 func (e *engineImpl) RunGitCommand(args ...string) (string, error) {
+	defer e.invalidateCurrentBranch()
 	return e.git.RunGitCommand(args...)
 }
 
 // RunGitCommandWithEnv runs a git command with environment variables
 func (e *engineImpl) RunGitCommandWithEnv(ctx context.Context, env []string, args ...string) (string, error) {
+	defer e.invalidateCurrentBranch()
 	return e.git.RunGitCommandWithEnv(ctx, env, args...)
 }
 
@@ -438,31 +642,20 @@ func (e *engineImpl) setParentInternal(ctx context.Context, branchName string, p
 	}
 
 	// Update in-memory maps
+	e.moveChild(branchName, oldParent, parentBranchName)
+
+	return nil
+}
+
+// moveChild updates the in-memory parent and children maps for a branch moving from
+// oldParent (empty if it had none) to newParent. The caller must hold e.mu for writing.
+func (e *engineImpl) moveChild(branchName, oldParent, newParent string) {
 	if oldParent != "" {
-		// Remove from old parent's children
 		oldChildren := e.childrenMap[oldParent]
 		if i := slices.Index(oldChildren, branchName); i >= 0 {
 			e.childrenMap[oldParent] = slices.Delete(oldChildren, i, i+1)
 		}
 	}
-
-	// Add to new parent's children
-	e.parentMap[branchName] = parentBranchName
-	if e.childrenMap[parentBranchName] == nil {
-		e.childrenMap[parentBranchName] = []string{}
-	}
-
-	// Check if already in children list
-	found := false
-	for _, c := range e.childrenMap[parentBranchName] {
-		if c == branchName {
-			found = true
-			break
-		}
-	}
-	if !found {
-		e.childrenMap[parentBranchName] = append(e.childrenMap[parentBranchName], branchName)
-	}
-
-	return nil
+	e.parentMap[branchName] = newParent
+	e.addChild(newParent, branchName)
 }
@@ -14,18 +14,23 @@ type BranchReader interface {
 	// State queries
 	AllBranches() []Branch              // Returns all branches
 	CurrentBranch() *Branch             // Returns current branch (nil if not on a branch)
+	RefreshCurrentBranch() *Branch      // Re-reads the current branch from Git, dropping the cached value
 	Trunk() Branch                      // Returns the trunk branch
 	GetBranch(branchName string) Branch // Returns a Branch wrapper
 	GetParent(branch Branch) *Branch    // Returns nil if no parent
 	GetRelativeStack(branch Branch, rng StackRange) []Branch
+	BatchGetParents(branchNames []string) map[string]string            // Parent of each branch, or trunk if it has none
+	BatchGetCommitInfo(branchNames []string) map[string]git.CommitInfo // Tip author and author date of each branch
 
 	// Stack queries
 	GetRelativeStackUpstack(branch Branch) []Branch
 	GetRelativeStackDownstack(branch Branch) []Branch
 	GetFullStack(branch Branch) []Branch
+	ListStacks() []Stack
 	SortBranchesTopologically(branches []Branch) []Branch
 	IsMergedIntoTrunk(ctx context.Context, branchName string) (bool, error)
 	IsBranchEmpty(ctx context.Context, branchName string) (bool, error)
+	GetBranchContent(ctx context.Context, branchName string) (BranchContent, error)
 
 	// Internal methods used by Branch type (exported so implementations outside this package can provide them)
 	IsTrunkInternal(branchName string) bool
@@ -39,6 +44,7 @@ type BranchReader interface {
 	GetRevisionInternal(branchName string) (string, error)                          // Internal method for Branch type
 	GetCommitCountInternal(branchName string) (int, error)                          // Internal method for Branch type
 	GetDiffStatsInternal(branchName string) (added int, deleted int, err error)     // Internal method for Branch type
+	GetDiffBaseInternal(branchName string) (string, error)                          // Internal method for Branch type
 	GetAllCommitsInternal(branchName string, format CommitFormat) ([]string, error) // Internal method for Branch type
 	GetRelativeStackInternal(branchName string, rng StackRange) []Branch            // Internal method for Branch type
 
@@ -54,8 +60,16 @@ type BranchReader interface {
 	ListMetadataRefs() (map[string]string, error)
 	BatchReadMetadataRefs(branchNames []string) (map[string]*Meta, map[string]error)
 	ReadMetadataRef(branchName string) (*Meta, error)
+	// NewerMetadataBranches returns the branches whose metadata was written by a newer
+	// version of stackit, which this version may not read fully
+	NewerMetadataBranches() []string
 	GetRemote() string
-	GetBranchRemoteDifference(branchName string) (string, error)
+	GetPushRemote() string
+	BranchExistsLocally(branchName string) bool
+	BranchExistsOnRemote(branchName string) bool
+	GetBranchRemoteDifference(branchName, remote string) (string, error)
+	GetBranchRemoteDivergence(branchName string) (ahead int, behind int, err error)
+	GetDivergence(ref, otherRef string) (ahead int, behind int, err error)
 
 	// Low-level Git state queries
 	HasStagedChanges(ctx context.Context) (bool, error)
@@ -63,7 +77,7 @@ type BranchReader interface {
 	GetMergeBase(rev1, rev2 string) (string, error)
 	GetChangedFiles(ctx context.Context, base, head string) ([]string, error)
 	ParseStagedHunks(ctx context.Context) ([]git.Hunk, error)
-	ShowDiff(ctx context.Context, left, right string, stat bool) (string, error)
+	ShowDiff(ctx context.Context, left, right string, opts git.DiffOptions) (string, error)
 	ShowCommits(ctx context.Context, base, head string, patch, stat bool) (string, error)
 	GetUnmergedFiles(ctx context.Context) ([]string, error)
 	GetParentCommitSHA(commitSHA string) (string, error)
@@ -83,10 +97,14 @@ type BranchReader interface {
 type BranchWriter interface {
 	// Branch tracking
 	TrackBranch(ctx context.Context, branchName string, parentBranchName string) error
+	ForceTrackBranch(ctx context.Context, branchName string, parentBranchName string) error
 	UntrackBranch(branchName string) error
 	SetParent(ctx context.Context, branch Branch, parentBranch Branch) error
+	SetParentMetadataOnly(branch Branch, parentBranch Branch) error
 	UpdateParentRevision(branchName string, parentRev string) error
+	RepairParentRevision(branch Branch) (bool, error)
 	SetScope(branch Branch, scope Scope) error
+	SetScopeForStack(branchName, scope string) error
 	RenameBranch(ctx context.Context, oldBranch, newBranch Branch) error
 	DeleteBranch(ctx context.Context, branch Branch) error
 	DeleteBranches(ctx context.Context, branches []Branch) ([]string, error)
@@ -96,10 +114,12 @@ type BranchWriter interface {
 
 	// Checkout operations
 	CheckoutBranch(ctx context.Context, branch Branch) error
+	SafeCheckout(ctx context.Context, branch Branch, autostash bool) (SafeCheckoutResult, error)
 	CreateAndCheckoutBranch(ctx context.Context, branch Branch) error
 
 	// Git write operations
 	Commit(ctx context.Context, message string, verbose int) error
+	CommitWithOptions(ctx context.Context, opts git.CommitOptions) error
 	StageAll(ctx context.Context) error
 	StashPush(ctx context.Context, message string) (string, error)
 	StashPop(ctx context.Context) error
@@ -115,11 +135,15 @@ type BranchWriter interface {
 
 	// Initialization operations
 	Reset(newTrunkName string) error
+	ResetWithOptions(newTrunkName string, opts ResetOptions) error
 	Rebuild(newTrunkName string) error
+	RebuildBranches(branchNames ...string) error
 }
 
 // AbsorbManager defines the interface for the absorb operation.
 type AbsorbManager interface {
 	ApplyHunksToBranch(ctx context.Context, branch Branch, hunksByCommit map[string][]git.Hunk) error
 	FindTargetCommitForHunk(hunk git.Hunk, commitSHAs []string) (string, int, error)
+	ValidateHunkTarget(ctx context.Context, hunk git.Hunk, commitSHA string, newerCommitSHAs []string) error
+	StageHunks(ctx context.Context, hunks []git.Hunk) error
 }
@@ -0,0 +1,32 @@
+package engine
+
+import "errors"
+
+// ErrNewerMetadata is returned when writing metadata that a newer version of stackit wrote,
+// since this build would drop whatever the newer version added
+var ErrNewerMetadata = errors.New("metadata was written by a newer version of stackit")
+
+// metadataMigrations upgrade metadata one version at a time: the migration at index i
+// upgrades version i+1 to version i+2
+var metadataMigrations = []func(meta *Meta){
+	// Version 2 introduced the version field; the fields themselves are unchanged
+	func(*Meta) {},
+}
+
+// migrateMeta upgrades metadata read from a ref to MetadataVersion in memory; it reaches the
+// ref with the next write. Metadata from a newer version is left alone, since this build
+// doesn't know what changed.
+func migrateMeta(meta *Meta) {
+	if meta.Version == 0 {
+		meta.Version = 1
+	}
+	for meta.Version < MetadataVersion {
+		metadataMigrations[meta.Version-1](meta)
+		meta.Version++
+	}
+}
+
+// isNewerMeta reports whether metadata was written by a newer version of stackit
+func isNewerMeta(meta *Meta) bool {
+	return meta.Version > MetadataVersion
+}
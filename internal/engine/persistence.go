@@ -1,7 +1,13 @@
 package engine
 
+// MetadataVersion is the schema version of the branch metadata this build writes.
+// Metadata written before versioning has no version field and is version 1.
+const MetadataVersion = 2
+
 // Meta represents branch metadata stored in Git refs
 type Meta struct {
+	// Version is the schema version the metadata was written with; see MetadataVersion
+	Version              int                `json:"version,omitempty"`
 	ParentBranchName     *string            `json:"parentBranchName,omitempty"`
 	ParentBranchRevision *string            `json:"parentBranchRevision,omitempty"`
 	PrInfo               *PrInfoPersistence `json:"prInfo,omitempty"`
@@ -17,4 +23,12 @@ type PrInfoPersistence struct {
 	Body    *string `json:"body,omitempty"`
 	State   *string `json:"state,omitempty"`
 	IsDraft *bool   `json:"isDraft,omitempty"`
+	// Review defaults reused by later submits
+	Reviewers     []string `json:"reviewers,omitempty"`
+	TeamReviewers []string `json:"teamReviewers,omitempty"`
+	Labels        []string `json:"labels,omitempty"`
+	// HeadSHA is the branch revision pushed by the last submit
+	HeadSHA *string `json:"headSha,omitempty"`
+	// TargetTrunk is the remote branch given with --target-trunk, kept by later submits
+	TargetTrunk *string `json:"targetTrunk,omitempty"`
 }
@@ -96,6 +96,7 @@ func (s *Scope) UnmarshalJSON(data []byte) error {
 // DeletionStatus represents the deletion status of a branch
 type DeletionStatus struct {
 	SafeToDelete bool   // True if the branch is merged, closed, or empty (with PR)
+	Merged       bool   // True if SafeToDelete because the branch (or its PR) was merged
 	Reason       string // Reason why it's safe (or not) to delete
 }
 
@@ -139,7 +140,8 @@ func (b Branch) GetScope() Scope {
 	return b.Reader.GetScopeInternal(b.name)
 }
 
-// GetChildren returns the children branches
+// GetChildren returns the children branches, ordered by their tip's author date
+// (oldest first), then by name
 func (b Branch) GetChildren() []Branch {
 	return b.Reader.GetChildrenInternal(b.name)
 }
@@ -190,6 +192,11 @@ func (b Branch) GetDiffStats() (added int, deleted int, err error) {
 	return b.Reader.GetDiffStatsInternal(b.name)
 }
 
+// GetDiffBase returns the revision this branch's changes are diffed against
+func (b Branch) GetDiffBase() (string, error) {
+	return b.Reader.GetDiffBaseInternal(b.name)
+}
+
 // GetAllCommits returns commits for this branch in various formats
 func (b Branch) GetAllCommits(format CommitFormat) ([]string, error) {
 	return b.Reader.GetAllCommitsInternal(b.name, format)
@@ -205,6 +212,17 @@ type PrInfo struct {
 	state   string // MERGED, CLOSED, OPEN
 	base    string // Base branch name
 	url     string // PR URL
+	// Review defaults remembered from earlier submits
+	reviewers     []string
+	teamReviewers []string
+	labels        []string
+	// headSHA is the branch revision last submitted to the PR, and stale whether the
+	// branch has moved past it since
+	headSHA string
+	stale   bool
+	// targetTrunk is the remote branch given with --target-trunk that the PR targets
+	// instead of trunk while the branch sits on trunk
+	targetTrunk string
 }
 
 // NewPrInfo creates a new PrInfo instance
@@ -280,39 +298,57 @@ func (p *PrInfo) MarshalJSON() ([]byte, error) {
 // WithNumber returns a new PrInfo with the number field updated
 func (p *PrInfo) WithNumber(number *int) *PrInfo {
 	return &PrInfo{
-		number:  number,
-		title:   p.title,
-		body:    p.body,
-		isDraft: p.isDraft,
-		state:   p.state,
-		base:    p.base,
-		url:     p.url,
+		number:        number,
+		title:         p.title,
+		body:          p.body,
+		isDraft:       p.isDraft,
+		state:         p.state,
+		base:          p.base,
+		url:           p.url,
+		reviewers:     p.reviewers,
+		teamReviewers: p.teamReviewers,
+		labels:        p.labels,
+		headSHA:       p.headSHA,
+		stale:         p.stale,
+		targetTrunk:   p.targetTrunk,
 	}
 }
 
 // WithTitle returns a new PrInfo with the title field updated
 func (p *PrInfo) WithTitle(title string) *PrInfo {
 	return &PrInfo{
-		number:  p.number,
-		title:   title,
-		body:    p.body,
-		isDraft: p.isDraft,
-		state:   p.state,
-		base:    p.base,
-		url:     p.url,
+		number:        p.number,
+		title:         title,
+		body:          p.body,
+		isDraft:       p.isDraft,
+		state:         p.state,
+		base:          p.base,
+		url:           p.url,
+		reviewers:     p.reviewers,
+		teamReviewers: p.teamReviewers,
+		labels:        p.labels,
+		headSHA:       p.headSHA,
+		stale:         p.stale,
+		targetTrunk:   p.targetTrunk,
 	}
 }
 
 // WithBody returns a new PrInfo with the body field updated
 func (p *PrInfo) WithBody(body string) *PrInfo {
 	return &PrInfo{
-		number:  p.number,
-		title:   p.title,
-		body:    body,
-		isDraft: p.isDraft,
-		state:   p.state,
-		base:    p.base,
-		url:     p.url,
+		number:        p.number,
+		title:         p.title,
+		body:          body,
+		isDraft:       p.isDraft,
+		state:         p.state,
+		base:          p.base,
+		url:           p.url,
+		reviewers:     p.reviewers,
+		teamReviewers: p.teamReviewers,
+		labels:        p.labels,
+		headSHA:       p.headSHA,
+		stale:         p.stale,
+		targetTrunk:   p.targetTrunk,
 	}
 }
 
@@ -320,65 +356,184 @@ func (p *PrInfo) WithBody(body string) *PrInfo {
 // This is more efficient than chaining WithTitle().WithBody() as it only creates one copy
 func (p *PrInfo) WithTitleAndBody(title, body string) *PrInfo {
 	return &PrInfo{
-		number:  p.number,
-		title:   title,
-		body:    body,
-		isDraft: p.isDraft,
-		state:   p.state,
-		base:    p.base,
-		url:     p.url,
+		number:        p.number,
+		title:         title,
+		body:          body,
+		isDraft:       p.isDraft,
+		state:         p.state,
+		base:          p.base,
+		url:           p.url,
+		reviewers:     p.reviewers,
+		teamReviewers: p.teamReviewers,
+		labels:        p.labels,
+		headSHA:       p.headSHA,
+		stale:         p.stale,
+		targetTrunk:   p.targetTrunk,
 	}
 }
 
 // WithIsDraft returns a new PrInfo with the isDraft field updated
 func (p *PrInfo) WithIsDraft(isDraft bool) *PrInfo {
 	return &PrInfo{
-		number:  p.number,
-		title:   p.title,
-		body:    p.body,
-		isDraft: isDraft,
-		state:   p.state,
-		base:    p.base,
-		url:     p.url,
+		number:        p.number,
+		title:         p.title,
+		body:          p.body,
+		isDraft:       isDraft,
+		state:         p.state,
+		base:          p.base,
+		url:           p.url,
+		reviewers:     p.reviewers,
+		teamReviewers: p.teamReviewers,
+		labels:        p.labels,
+		headSHA:       p.headSHA,
+		stale:         p.stale,
+		targetTrunk:   p.targetTrunk,
 	}
 }
 
 // WithState returns a new PrInfo with the state field updated
 func (p *PrInfo) WithState(state string) *PrInfo {
 	return &PrInfo{
-		number:  p.number,
-		title:   p.title,
-		body:    p.body,
-		isDraft: p.isDraft,
-		state:   state,
-		base:    p.base,
-		url:     p.url,
+		number:        p.number,
+		title:         p.title,
+		body:          p.body,
+		isDraft:       p.isDraft,
+		state:         state,
+		base:          p.base,
+		url:           p.url,
+		reviewers:     p.reviewers,
+		teamReviewers: p.teamReviewers,
+		labels:        p.labels,
+		headSHA:       p.headSHA,
+		stale:         p.stale,
+		targetTrunk:   p.targetTrunk,
 	}
 }
 
 // WithBase returns a new PrInfo with the base field updated
 func (p *PrInfo) WithBase(base string) *PrInfo {
 	return &PrInfo{
-		number:  p.number,
-		title:   p.title,
-		body:    p.body,
-		isDraft: p.isDraft,
-		state:   p.state,
-		base:    base,
-		url:     p.url,
+		number:        p.number,
+		title:         p.title,
+		body:          p.body,
+		isDraft:       p.isDraft,
+		state:         p.state,
+		base:          base,
+		url:           p.url,
+		reviewers:     p.reviewers,
+		teamReviewers: p.teamReviewers,
+		labels:        p.labels,
+		headSHA:       p.headSHA,
+		stale:         p.stale,
+		targetTrunk:   p.targetTrunk,
 	}
 }
 
 // WithURL returns a new PrInfo with the url field updated
 func (p *PrInfo) WithURL(url string) *PrInfo {
 	return &PrInfo{
-		number:  p.number,
-		title:   p.title,
-		body:    p.body,
-		isDraft: p.isDraft,
-		state:   p.state,
-		base:    p.base,
-		url:     url,
+		number:        p.number,
+		title:         p.title,
+		body:          p.body,
+		isDraft:       p.isDraft,
+		state:         p.state,
+		base:          p.base,
+		url:           url,
+		reviewers:     p.reviewers,
+		teamReviewers: p.teamReviewers,
+		labels:        p.labels,
+		headSHA:       p.headSHA,
+		stale:         p.stale,
+		targetTrunk:   p.targetTrunk,
+	}
+}
+
+// HeadSHA returns the branch revision pushed when the PR was last submitted, or "" if unknown
+func (p *PrInfo) HeadSHA() string {
+	return p.headSHA
+}
+
+// IsStale returns whether the branch has moved past the revision last submitted to the PR,
+// so the PR needs re-submitting. It is false when the submitted revision is unknown.
+func (p *PrInfo) IsStale() bool {
+	return p.stale
+}
+
+// WithHeadSHA returns a new PrInfo with the submitted head revision updated
+func (p *PrInfo) WithHeadSHA(headSHA string) *PrInfo {
+	return &PrInfo{
+		number:        p.number,
+		title:         p.title,
+		body:          p.body,
+		isDraft:       p.isDraft,
+		state:         p.state,
+		base:          p.base,
+		url:           p.url,
+		reviewers:     p.reviewers,
+		teamReviewers: p.teamReviewers,
+		labels:        p.labels,
+		headSHA:       headSHA,
+		stale:         p.stale,
+		targetTrunk:   p.targetTrunk,
+	}
+}
+
+// Reviewers returns the user reviewers requested when the PR was last submitted
+func (p *PrInfo) Reviewers() []string {
+	return p.reviewers
+}
+
+// TeamReviewers returns the team reviewers requested when the PR was last submitted
+func (p *PrInfo) TeamReviewers() []string {
+	return p.teamReviewers
+}
+
+// Labels returns the labels applied when the PR was last submitted
+func (p *PrInfo) Labels() []string {
+	return p.labels
+}
+
+// WithReviewersAndLabels returns a new PrInfo with the reviewers and labels updated
+func (p *PrInfo) WithReviewersAndLabels(reviewers, teamReviewers, labels []string) *PrInfo {
+	return &PrInfo{
+		number:        p.number,
+		title:         p.title,
+		body:          p.body,
+		isDraft:       p.isDraft,
+		state:         p.state,
+		base:          p.base,
+		url:           p.url,
+		reviewers:     reviewers,
+		teamReviewers: teamReviewers,
+		labels:        labels,
+		headSHA:       p.headSHA,
+		stale:         p.stale,
+		targetTrunk:   p.targetTrunk,
+	}
+}
+
+// TargetTrunk returns the remote branch the PR targets instead of trunk, as given with
+// --target-trunk, or "" when it targets trunk
+func (p *PrInfo) TargetTrunk() string {
+	return p.targetTrunk
+}
+
+// WithTargetTrunk returns a new PrInfo with the target trunk updated
+func (p *PrInfo) WithTargetTrunk(targetTrunk string) *PrInfo {
+	return &PrInfo{
+		number:        p.number,
+		title:         p.title,
+		body:          p.body,
+		isDraft:       p.isDraft,
+		state:         p.state,
+		base:          p.base,
+		url:           p.url,
+		reviewers:     p.reviewers,
+		teamReviewers: p.teamReviewers,
+		labels:        p.labels,
+		headSHA:       p.headSHA,
+		stale:         p.stale,
+		targetTrunk:   targetTrunk,
 	}
 }
 
@@ -398,6 +553,19 @@ const (
 	ValidationResultTrunk
 )
 
+// BranchContent describes what a branch adds on top of its parent
+type BranchContent int
+
+const (
+	// BranchContentNone indicates the branch has no commits above its parent
+	BranchContentNone BranchContent = iota
+	// BranchContentEmptyCommits indicates the branch only has commits that change nothing,
+	// e.g. ones made with --allow-empty
+	BranchContentEmptyCommits
+	// BranchContentChanges indicates the branch changes files relative to its parent
+	BranchContentChanges
+)
+
 // PullResult represents the result of pulling trunk
 type PullResult int
 
@@ -408,6 +576,37 @@ const (
 	PullUnneeded
 	// PullConflict indicates a conflict occurred during pull
 	PullConflict
+	// PullRebased indicates local trunk had diverged and its commits were rebased onto the remote
+	PullRebased
+	// PullReset indicates local trunk had diverged and was reset to the remote, dropping its local commits
+	PullReset
+)
+
+// TrunkPullReport describes what PullTrunk did to trunk, so callers can tell the user
+// whether it moved, by how much, and where it ended up
+type TrunkPullReport struct {
+	Result PullResult
+	// OldRev and NewRev are the local trunk tip before and after the pull
+	OldRev string
+	NewRev string
+	// RemoteRev is the remote trunk tip, empty when there is no remote trunk
+	RemoteRev string
+	// Behind is the number of remote commits local trunk was missing
+	Behind int
+	// Ahead is the number of local trunk commits the remote doesn't have
+	Ahead int
+}
+
+// TrunkStrategy determines how PullTrunk reconciles a local trunk that has diverged from the remote
+type TrunkStrategy string
+
+const (
+	// TrunkStrategyFFOnly only fast-forwards trunk, reporting PullConflict when it has diverged
+	TrunkStrategyFFOnly TrunkStrategy = "ff-only"
+	// TrunkStrategyRebase rebases local trunk commits onto the remote trunk
+	TrunkStrategyRebase TrunkStrategy = "rebase"
+	// TrunkStrategyResetToRemote hard-resets local trunk to the remote trunk
+	TrunkStrategyResetToRemote TrunkStrategy = "reset-to-remote"
 )
 
 // RestackResult represents the result of restacking a branch
@@ -420,25 +619,111 @@ const (
 	RestackUnneeded
 	// RestackConflict indicates a conflict occurred during restack
 	RestackConflict
+	// RestackPruned indicates the branch was empty after restacking and was deleted,
+	// with its children moved onto its parent
+	RestackPruned
+)
+
+// String returns the display name of the restack result
+func (r RestackResult) String() string {
+	switch r {
+	case RestackDone:
+		return "Done"
+	case RestackUnneeded:
+		return "Unneeded"
+	case RestackConflict:
+		return "Conflict"
+	case RestackPruned:
+		return "Pruned"
+	default:
+		return "Unknown"
+	}
+}
+
+// RestackStrategy determines how a branch is restacked onto its parent
+type RestackStrategy string
+
+const (
+	// RestackStrategyRebase rebases the branch onto its parent, rewriting its commits
+	RestackStrategyRebase RestackStrategy = "rebase"
+	// RestackStrategyMerge merges the parent into the branch, preserving existing commits
+	RestackStrategyMerge RestackStrategy = "merge"
+)
+
+// PruneEmptyMode determines which branches restack deletes when they end up with no changes
+type PruneEmptyMode string
+
+const (
+	// PruneEmptyNever keeps empty branches
+	PruneEmptyNever PruneEmptyMode = "never"
+	// PruneEmptyMerged deletes empty branches whose changes landed in trunk, or whose PR was merged
+	PruneEmptyMerged PruneEmptyMode = "merged"
+	// PruneEmptyAlways deletes any branch that is empty after restacking
+	PruneEmptyAlways PruneEmptyMode = "always"
 )
 
 // RestackBranchResult represents the result of restacking a branch, including the rebased branch base
 type RestackBranchResult struct {
 	Result            RestackResult
 	RebasedBranchBase string // The new parent revision after successful rebase (only set if Result is RestackDone or RestackConflict)
-	Reparented        bool   // True if the branch was reparented due to merged/deleted parent
-	OldParent         string // The old parent branch name (only set if Reparented is true)
-	NewParent         string // The new parent branch name (only set if Reparented is true)
+	Reparented        bool   // True if the branch was moved off a merged/deleted parent before restacking
+	OldParent         string // The merged/deleted parent branch name (only set if Reparented is true)
+	NewParent         string // The parent branch the branch was restacked onto, which its children moved to if Result is RestackPruned
+	OldRevision       string // The branch's revision before the restack (only set if Result is RestackDone)
+	NewRevision       string // The branch's revision after the restack (only set if Result is RestackDone)
+	// RemoteOnlyParent is the parent the branch was left on, unrestacked, because it is gone
+	// locally but still on the remote (only set if Result is RestackUnneeded)
+	RemoteOnlyParent string
+	// Empty is true if the rebase dropped every commit of the branch, leaving it at its
+	// parent's revision (only set if Result is RestackDone)
+	Empty bool
 }
 
 // RestackBatchResult represents the result of restacking multiple branches
 type RestackBatchResult struct {
 	ConflictBranch    string                         // The branch that hit a conflict
+	HookFailedBranch  string                         // The branch whose post-restack hook failed
 	RebasedBranchBase string                         // The parent revision for the conflict
 	RemainingBranches []string                       // Branches that weren't reached
 	Results           map[string]RestackBranchResult // Results for each branch attempted
 }
 
+// FollowedBranch is a branch that UpdateRewrittenRefs found in the rewritten history of its child
+type FollowedBranch struct {
+	Name        string
+	Child       string // The rewritten branch whose history was searched
+	OldRevision string
+	NewRevision string // Empty when the branch's tip couldn't be found
+}
+
+// UpdateRefsResult reports the branches moved by UpdateRewrittenRefs
+type UpdateRefsResult struct {
+	Moved     []FollowedBranch
+	Unmatched []FollowedBranch // Branches whose tip has no rewritten copy in their child, left in place
+}
+
+// Stack is an independent stack: one of trunk's children and the branches built on it
+type Stack struct {
+	Root            Branch
+	DescendantCount int      // Number of branches above the root
+	Tips            []Branch // Branches with no children, in depth-first order
+}
+
+// ConflictPrediction is the predicted result of restacking a single branch
+type ConflictPrediction struct {
+	BranchName   string
+	NeedsRestack bool     // The branch's parent has moved, so restacking would rewrite it
+	Conflict     bool     // Restacking the branch is predicted to stop on a conflict
+	Files        []string // The files predicted to conflict
+	BlockedBy    string   // An ancestor predicted to conflict first; this branch wasn't simulated
+}
+
+// SafeCheckoutResult represents the result of a checkout that may have stashed local changes
+type SafeCheckoutResult struct {
+	Stashed       bool // Local changes blocked the checkout and were stashed
+	StashConflict bool // The stashed changes conflicted with the new branch and were left in the stash
+}
+
 // ContinueRebaseResult represents the result of continuing a rebase
 type ContinueRebaseResult struct {
 	Result     int    // git.RebaseResult value (0 = RebaseDone, 1 = RebaseConflict)
@@ -454,8 +739,16 @@ type PRSubmissionStatus struct {
 	PRInfo      *PrInfo
 }
 
+// ResetOptions contains options for resetting the engine
+type ResetOptions struct {
+	// PreservePRInfo keeps each branch's stored PR info while clearing its tracking
+	PreservePRInfo bool
+}
+
 // SquashOptions contains options for squashing commits
 type SquashOptions struct {
 	Message string
 	NoEdit  bool
+	// Edit opens the editor on Message before committing
+	Edit bool
 }
@@ -325,6 +325,8 @@ func (e *engineImpl) LoadSnapshot(snapshotID string) (*Snapshot, error) {
 
 // RestoreSnapshot restores the repository to the state captured in a snapshot
 func (e *engineImpl) RestoreSnapshot(ctx context.Context, snapshotID string) error {
+	defer e.invalidateReadCache()
+
 	// Load the snapshot
 	snapshot, err := e.LoadSnapshot(snapshotID)
 	if err != nil {
@@ -5,6 +5,7 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 // Sentinel errors for common conditions
@@ -23,8 +24,60 @@ var (
 
 	// ErrTrunkOperation indicates an invalid operation on the trunk branch
 	ErrTrunkOperation = errors.New("invalid operation on trunk branch")
+
+	// ErrOperationInProgress indicates that another stackit operation holds the repository lock
+	ErrOperationInProgress = errors.New("another stackit operation is in progress")
+
+	// ErrGitOperationInProgress indicates that git is in the middle of a rebase, merge or
+	// cherry-pick, which mutating commands refuse to run on top of
+	ErrGitOperationInProgress = errors.New("a git operation is in progress; run stackit continue or git rebase --abort")
+
+	// ErrNotGitRepository indicates that the working directory is not inside a git work tree
+	ErrNotGitRepository = errors.New("not a git repository (or any of the parent directories)")
+
+	// ErrNotInitialized indicates that stackit has not been initialized in the repository
+	ErrNotInitialized = errors.New("stackit not initialized. Run 'stackit init' first")
+
+	// ErrRemoteAuth indicates that the remote (GitHub) could not be authenticated against
+	ErrRemoteAuth = errors.New("remote authentication failed")
+
+	// ErrValidation indicates invalid user input, such as conflicting or malformed flags
+	ErrValidation = errors.New("invalid input")
 )
 
+// CategorizedError tags an error with one of the sentinel errors above without changing
+// its message, so it can be classified with errors.Is (and mapped to an exit code)
+type CategorizedError struct {
+	Category error
+	Err      error
+}
+
+func (e *CategorizedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CategorizedError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if the target error is the error's category
+func (e *CategorizedError) Is(target error) bool {
+	return target == e.Category
+}
+
+// WithCategory tags err with category, keeping err's message
+func WithCategory(category error, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &CategorizedError{Category: category, Err: err}
+}
+
+// NewValidationError creates an error for invalid user input
+func NewValidationError(format string, args ...any) error {
+	return WithCategory(ErrValidation, fmt.Errorf(format, args...))
+}
+
 // BranchNotFoundError represents an error when a branch is not found
 type BranchNotFoundError struct {
 	BranchName string
@@ -70,6 +123,43 @@ func NewRebaseConflictError(branchName string, message string) *RebaseConflictEr
 	}
 }
 
+// PostRestackHookError represents a failure of the restack.postHook command on a restacked branch
+type PostRestackHookError struct {
+	BranchName string
+	Output     string
+	Err        error
+}
+
+func (e *PostRestackHookError) Error() string {
+	msg := fmt.Sprintf("post-restack hook failed on %s: %v", e.BranchName, e.Err)
+	if e.Output != "" {
+		msg += "\n" + e.Output
+	}
+	return msg
+}
+
+func (e *PostRestackHookError) Unwrap() error {
+	return e.Err
+}
+
+// OperationInProgressError represents an error when another stackit operation holds the repository lock
+type OperationInProgressError struct {
+	Command   string
+	PID       int
+	StartedAt time.Time
+}
+
+func (e *OperationInProgressError) Error() string {
+	return fmt.Sprintf("another stackit operation is in progress (%s, pid %d, started %s); "+
+		"wait for it to finish or rerun with --force-unlock if it is no longer running",
+		e.Command, e.PID, e.StartedAt.Format(time.RFC3339))
+}
+
+// Is returns true if the target error is ErrOperationInProgress
+func (e *OperationInProgressError) Is(target error) bool {
+	return target == ErrOperationInProgress
+}
+
 // GitCommandError represents an error from a git command execution
 type GitCommandError struct {
 	Command string
@@ -0,0 +1,39 @@
+package errors
+
+import "errors"
+
+// Process exit codes, so scripts can tell failure categories apart
+const (
+	ExitCodeError               = 1 // Any error not covered by a more specific code
+	ExitCodeValidation          = 2
+	ExitCodeNotInitialized      = 3
+	ExitCodeNotOnBranch         = 4
+	ExitCodeConflict            = 5
+	ExitCodeRemoteAuth          = 6
+	ExitCodeOperationInProgress = 7
+	ExitCodeNotGitRepository    = 8
+)
+
+// ExitCode returns the process exit code for an error returned from a command
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, ErrValidation):
+		return ExitCodeValidation
+	case errors.Is(err, ErrNotGitRepository):
+		return ExitCodeNotGitRepository
+	case errors.Is(err, ErrNotInitialized):
+		return ExitCodeNotInitialized
+	case errors.Is(err, ErrNotOnBranch):
+		return ExitCodeNotOnBranch
+	case errors.Is(err, ErrRebaseConflict):
+		return ExitCodeConflict
+	case errors.Is(err, ErrRemoteAuth):
+		return ExitCodeRemoteAuth
+	case errors.Is(err, ErrOperationInProgress), errors.Is(err, ErrGitOperationInProgress):
+		return ExitCodeOperationInProgress
+	default:
+		return ExitCodeError
+	}
+}
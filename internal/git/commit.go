@@ -2,10 +2,23 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 )
 
+// ErrCommitMessageRequired is returned for a new commit without a message while the editor
+// is disabled, since git would otherwise open one to ask for it
+var ErrCommitMessageRequired = errors.New("a commit message is required when the editor is disabled; pass one with -m")
+
+// editorDisabled keeps git from opening an editor for commit messages (--no-editor)
+var editorDisabled bool
+
+// SetEditorDisabled stops commits from opening git's editor for their message
+func SetEditorDisabled(disabled bool) {
+	editorDisabled = disabled
+}
+
 // CommitOptions contains options for creating a commit
 type CommitOptions struct {
 	Message     string
@@ -14,6 +27,9 @@ type CommitOptions struct {
 	Edit        bool
 	Verbose     int
 	ResetAuthor bool
+	// Trailers are "Key: value" lines appended to the message's trailer block. A trailer
+	// whose key is already in the message is skipped, so amending keeps e.g. a Change-Id.
+	Trailers []string
 }
 
 // Commit creates a commit with the given message
@@ -25,9 +41,20 @@ func Commit(message string, verbose int) error {
 	})
 }
 
-// CommitWithOptions creates a commit with the given options
+// CommitWithOptions creates a commit with the given options. While the editor is disabled,
+// amends keep their message and a new commit must be given one.
 func CommitWithOptions(opts CommitOptions) error {
+	if editorDisabled {
+		if opts.Message == "" && !opts.Amend {
+			return ErrCommitMessageRequired
+		}
+		opts.NoEdit, opts.Edit = true, false
+	}
+
 	args := []string{"commit"}
+	if len(opts.Trailers) > 0 {
+		args = []string{"-c", "trailer.ifexists=doNothing", "commit"}
+	}
 
 	if opts.Amend {
 		args = append(args, "--amend")
@@ -45,6 +72,10 @@ func CommitWithOptions(opts CommitOptions) error {
 		args = append(args, "-m", opts.Message)
 	}
 
+	for _, trailer := range opts.Trailers {
+		args = append(args, "--trailer", trailer)
+	}
+
 	if opts.NoEdit {
 		args = append(args, "--no-edit")
 	} else if opts.Edit {
@@ -2,6 +2,7 @@ package git
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -57,6 +58,46 @@ func GetCommitAuthor(branchName string) (string, error) {
 	return commit.Author.Name, nil
 }
 
+// CommitInfo is the author of a branch's tip commit and when it was authored
+type CommitInfo struct {
+	Author string
+	Date   time.Time
+}
+
+// BatchGetCommitInfo returns the author and author date of the tip commit of each of the
+// given local branches with a single git call. Branches that don't exist are left out.
+func BatchGetCommitInfo(branchNames []string) (map[string]CommitInfo, error) {
+	results := make(map[string]CommitInfo, len(branchNames))
+	if len(branchNames) == 0 {
+		return results, nil
+	}
+
+	wanted := make(map[string]bool, len(branchNames))
+	args := []string{"for-each-ref", "--format=%(refname:lstrip=2)%09%(authorname)%09%(authordate:iso-strict)"}
+	for _, name := range branchNames {
+		wanted[name] = true
+		args = append(args, "refs/heads/"+name)
+	}
+	output, err := RunGitCommand(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit info: %w", err)
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		// A pattern also matches branches nested under it, e.g. feature/x for feature
+		if len(parts) != 3 || !wanted[parts[0]] {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the author date of %s: %w", parts[0], err)
+		}
+		results[parts[0]] = CommitInfo{Author: parts[1], Date: date}
+	}
+	return results, nil
+}
+
 // GetRevision returns the SHA of a branch
 func GetRevision(branchName string) (string, error) {
 	repo, err := GetDefaultRepo()
@@ -0,0 +1,45 @@
+package git_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/internal/git"
+	"stackit.dev/stackit/testhelpers"
+)
+
+func TestCommitWithOptionsEditorDisabled(t *testing.T) {
+	scene := testhelpers.NewScene(t, func(s *testhelpers.Scene) error {
+		return s.Repo.CreateChangeAndCommit("initial", "init")
+	})
+	git.SetWorkingDir(scene.Dir)
+	t.Cleanup(func() { git.SetWorkingDir("") })
+
+	marker := filepath.Join(t.TempDir(), "launched")
+	t.Setenv("GIT_EDITOR", "touch "+marker+" &&")
+	git.SetEditorDisabled(true)
+	t.Cleanup(func() { git.SetEditorDisabled(false) })
+
+	t.Run("amends keep their message instead of opening the editor", func(t *testing.T) {
+		require.NoError(t, scene.Repo.CreateChange("amended", "test", false))
+		require.NoError(t, git.CommitWithOptions(git.CommitOptions{Amend: true, Edit: true}))
+		require.NoFileExists(t, marker)
+
+		subject, err := scene.Repo.RunGitCommandAndGetOutput("log", "-1", "--format=%s")
+		require.NoError(t, err)
+		require.Equal(t, "initial", strings.TrimSpace(subject))
+	})
+
+	t.Run("new commits without a message fail", func(t *testing.T) {
+		require.NoError(t, scene.Repo.CreateChange("new", "other", false))
+		err := git.CommitWithOptions(git.CommitOptions{})
+		require.ErrorIs(t, err, git.ErrCommitMessageRequired)
+		require.NoFileExists(t, marker)
+
+		require.NoError(t, git.CommitWithOptions(git.CommitOptions{Message: "with a message", Edit: true}))
+		require.NoFileExists(t, marker)
+	})
+}
@@ -17,6 +17,24 @@ func GetUserName(ctx context.Context) (string, error) {
 	return username, nil
 }
 
+// GetUserEmail returns the Git user's email from git config
+func GetUserEmail(ctx context.Context) (string, error) {
+	email, err := RunGitCommandWithContext(ctx, "config", "user.email")
+	if err != nil {
+		return "", fmt.Errorf("failed to get git user email: %w", err)
+	}
+	return email, nil
+}
+
+// GetMergeTool returns the merge.tool configured in git, or "" if there isn't one
+func GetMergeTool(ctx context.Context) string {
+	tool, err := RunGitCommandWithContext(ctx, "config", "--get", "merge.tool")
+	if err != nil {
+		return ""
+	}
+	return tool
+}
+
 // GetCurrentDate returns the current date and time in yyyyMMddHHmmss format in UTC
 func GetCurrentDate() string {
 	now := time.Now().UTC()
@@ -33,10 +33,19 @@ func GetUnmergedFiles(ctx context.Context) ([]string, error) {
 	return strings.Split(strings.TrimSpace(output), "\n"), nil
 }
 
-// ShowDiff returns the diff between two refs with optional stat mode
-func ShowDiff(ctx context.Context, left, right string, stat bool) (string, error) {
+// DiffOptions controls how ShowDiff formats its output
+type DiffOptions struct {
+	Stat     bool // Show a diffstat instead of a full diff
+	NameOnly bool // Show only the names of changed files; takes precedence over Stat
+}
+
+// ShowDiff returns the diff between two refs, optionally as a diffstat or a list of file names
+func ShowDiff(ctx context.Context, left, right string, opts DiffOptions) (string, error) {
 	args := []string{"-c", "color.ui=always", "--no-pager", "diff", "--no-ext-diff"}
-	if stat {
+	switch {
+	case opts.NameOnly:
+		args = append(args, "--name-only")
+	case opts.Stat:
 		args = append(args, "--stat")
 	}
 	args = append(args, left, right, "--")
@@ -15,6 +15,7 @@ type Hunk struct {
 	NewStart int    // Line number in new file (1-indexed)
 	NewCount int    // Number of lines in new file
 	Content  string // The actual diff content (including header)
+	NewFile  bool   // Whether the diff creates the file
 }
 
 // ParseStagedHunks parses the output of `git diff --cached` into structured hunks
@@ -37,6 +38,7 @@ func ParseStagedHunks(ctx context.Context) ([]Hunk, error) {
 
 	var currentHunk *Hunk
 	var currentFile string
+	var currentNewFile bool
 	var hunkLines []string
 
 	for _, line := range lines {
@@ -60,6 +62,13 @@ func ParseStagedHunks(ctx context.Context) ([]Hunk, error) {
 					currentFile = strings.TrimPrefix(bPath, "b/")
 				}
 			}
+			currentNewFile = false
+			continue
+		}
+
+		// A file created by the diff has no old side
+		if currentHunk == nil && line == "--- /dev/null" {
+			currentNewFile = true
 			continue
 		}
 
@@ -89,6 +98,7 @@ func ParseStagedHunks(ctx context.Context) ([]Hunk, error) {
 				OldCount: oldCount,
 				NewStart: newStart,
 				NewCount: newCount,
+				NewFile:  currentNewFile,
 			}
 			hunkLines = []string{line}
 			continue
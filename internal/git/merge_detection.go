@@ -3,20 +3,13 @@ package git
 import (
 	"context"
 	"fmt"
-	"os"
 )
 
-// IsMergeInProgress checks if a merge is currently in progress
+// IsMergeInProgress checks if a merge is currently in progress. It asks git for MERGE_HEAD
+// rather than stat-ing the git dir, so it doesn't depend on the process working directory.
 func IsMergeInProgress(ctx context.Context) bool {
-	output, err := RunGitCommandWithContext(ctx, "rev-parse", "--git-dir")
-	if err != nil {
-		return false
-	}
-	gitDir := output
-	if _, err := os.Stat(gitDir + "/MERGE_HEAD"); err == nil {
-		return true
-	}
-	return false
+	_, err := RunGitCommandWithContext(ctx, "rev-parse", "-q", "--verify", "MERGE_HEAD")
+	return err == nil
 }
 
 // IsMerged checks if a branch is merged into trunk
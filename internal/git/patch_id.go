@@ -0,0 +1,34 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// GetPatchIDs returns the stable patch ID of each commit in (base..head], keyed by commit SHA.
+// A commit keeps its patch ID when it is rebased or reworded, so patch IDs identify the
+// rewritten copy of a commit. Merges and empty commits have no patch ID and are left out.
+func GetPatchIDs(ctx context.Context, base, head string) (map[string]string, error) {
+	log, err := RunGitCommandRawWithContext(ctx, "log", "-p", "--no-color", "--no-ext-diff", "--no-merges", base+".."+head)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commits in %s..%s: %w", base, head, err)
+	}
+
+	ids := make(map[string]string)
+	if strings.TrimSpace(log) == "" {
+		return ids, nil
+	}
+	output, err := RunGitCommandWithInputAndContext(ctx, log, "patch-id", "--stable")
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute patch IDs: %w", err)
+	}
+	for line := range strings.SplitSeq(output, "\n") {
+		// Each line is "<patch-id> <commit-sha>"
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			ids[fields[1]] = fields[0]
+		}
+	}
+	return ids, nil
+}
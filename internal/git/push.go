@@ -6,28 +6,119 @@ import (
 	"strings"
 )
 
+// PushOptions contains options for pushing a branch
+type PushOptions struct {
+	BranchName     string
+	Remote         string
+	Force          bool
+	ForceWithLease bool
+	NoVerify       bool // Skip the pre-push hook
+	SetUpstream    bool // Track the remote branch if the branch has no upstream yet
+}
+
 // PushBranch pushes a branch to remote with optional force
 // If forceWithLease is true, uses --force-with-lease (safer)
 // If force is true, uses --force (overwrites remote)
 // If both are false, does a normal push
 func PushBranch(ctx context.Context, branchName string, remote string, force bool, forceWithLease bool) error {
-	args := []string{"push", "-u", remote}
+	return PushBranchWithOptions(ctx, PushOptions{
+		BranchName:     branchName,
+		Remote:         remote,
+		Force:          force,
+		ForceWithLease: forceWithLease,
+		SetUpstream:    true,
+	})
+}
+
+// PushBranchWithOptions pushes a branch to remote with the given options.
+// The push goes through git itself, so any pre-push hook (including one found
+// via core.hooksPath) runs unless NoVerify is set.
+func PushBranchWithOptions(ctx context.Context, opts PushOptions) error {
+	args := []string{"push"}
+	// Only the first push sets the upstream, so one configured by hand is left alone
+	if opts.SetUpstream && !HasUpstream(ctx, opts.BranchName) {
+		args = append(args, "--set-upstream")
+	}
+	args = append(args, opts.Remote)
 
-	if force {
+	if opts.Force {
 		args = append(args, "--force")
-	} else if forceWithLease {
+	} else if opts.ForceWithLease {
 		args = append(args, "--force-with-lease")
 	}
 
-	args = append(args, branchName)
+	if opts.NoVerify {
+		args = append(args, "--no-verify")
+	}
+
+	args = append(args, opts.BranchName)
 
 	_, err := RunGitCommandWithContext(ctx, args...)
 	if err != nil {
 		if strings.Contains(err.Error(), "stale info") || strings.Contains(err.Error(), "forced update") {
-			return fmt.Errorf("%w: force-with-lease push of %s failed due to external changes to the remote branch", ErrStaleRemoteInfo, branchName)
+			return fmt.Errorf("%w: force-with-lease push of %s failed due to external changes to the remote branch", ErrStaleRemoteInfo, opts.BranchName)
 		}
-		return fmt.Errorf("failed to push branch %s: %w", branchName, err)
+		if rejection, ok := findPushRejection(err.Error()); ok {
+			return fmt.Errorf("failed to push branch %s: %w because %s. %s\n%w",
+				opts.BranchName, ErrPushRejected, rejection.reason, fmt.Sprintf(rejection.guidance, opts.BranchName), err)
+		}
+		return fmt.Errorf("failed to push branch %s: %w", opts.BranchName, err)
 	}
 
 	return nil
 }
+
+// pushRejection is a kind of push the remote refuses, recognised by its message
+type pushRejection struct {
+	markers  []string // lowercase substrings of the remote's output
+	reason   string
+	guidance string // formatted with the branch name
+}
+
+// pushRejections are checked in order; more specific protection rules come before the
+// generic "protected branch" message that GitHub prints alongside them
+var pushRejections = []pushRejection{
+	{
+		markers:  []string{"required status check"},
+		reason:   "its required status checks have not passed",
+		guidance: "Push %s to a different branch and open a PR, or wait for the required checks to pass.",
+	},
+	{
+		markers:  []string{"approving review", "changes must be made through a pull request"},
+		reason:   "changes must go through a reviewed pull request",
+		guidance: "Push your changes to a new branch and open a PR targeting %s instead.",
+	},
+	{
+		markers: []string{
+			"protected branch",
+			"not allowed to push code to protected branches",
+			"not allowed to force push",
+		},
+		reason:   "the branch is protected",
+		guidance: "Push to a different branch and open a PR, or ask a repository admin to allow pushes to %s.",
+	},
+	{
+		markers:  []string{"hook declined"},
+		reason:   "a server-side hook declined it",
+		guidance: "Check the remote's message below for why, fix %s, and push again.",
+	},
+}
+
+// findPushRejection matches a failed push's output against the known rejections
+func findPushRejection(output string) (pushRejection, bool) {
+	output = strings.ToLower(output)
+	for _, rejection := range pushRejections {
+		for _, marker := range rejection.markers {
+			if strings.Contains(output, marker) {
+				return rejection, true
+			}
+		}
+	}
+	return pushRejection{}, false
+}
+
+// HasUpstream reports whether a branch has an upstream branch configured
+func HasUpstream(ctx context.Context, branchName string) bool {
+	_, err := RunGitCommandWithContext(ctx, "rev-parse", "--abbrev-ref", "--symbolic-full-name", branchName+"@{upstream}")
+	return err == nil
+}
@@ -0,0 +1,116 @@
+package git_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/internal/git"
+	"stackit.dev/stackit/testhelpers"
+)
+
+func TestPushBranchWithOptions(t *testing.T) {
+	t.Run("sets the upstream on the first push", func(t *testing.T) {
+		scene := testhelpers.NewScene(t, func(s *testhelpers.Scene) error {
+			return s.Repo.CreateChangeAndCommit("initial", "init")
+		})
+		_, err := scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		require.NoError(t, scene.Repo.PushBranch("origin", "main"))
+		require.NoError(t, scene.Repo.CreateAndCheckoutBranch("feature"))
+		require.NoError(t, scene.Repo.CreateChangeAndCommit("feature change", "feat"))
+		git.SetWorkingDir(scene.Dir)
+		t.Cleanup(func() { git.SetWorkingDir("") })
+		require.NoError(t, git.InitDefaultRepo())
+		ctx := context.Background()
+		require.False(t, git.HasUpstream(ctx, "feature"))
+
+		require.NoError(t, git.PushBranchWithOptions(ctx, git.PushOptions{
+			BranchName: "feature", Remote: "origin", ForceWithLease: true, SetUpstream: true,
+		}))
+		require.True(t, git.HasUpstream(ctx, "feature"))
+		upstream, err := scene.Repo.RunGitCommandAndGetOutput("rev-parse", "--abbrev-ref", "feature@{upstream}")
+		require.NoError(t, err)
+		require.Equal(t, "origin/feature", upstream)
+	})
+
+	t.Run("leaves an existing upstream alone and still force pushes", func(t *testing.T) {
+		scene := testhelpers.NewScene(t, func(s *testhelpers.Scene) error {
+			return s.Repo.CreateChangeAndCommit("initial", "init")
+		})
+		_, err := scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		require.NoError(t, scene.Repo.PushBranch("origin", "main"))
+		require.NoError(t, scene.Repo.CreateAndCheckoutBranch("feature"))
+		require.NoError(t, scene.Repo.CreateChangeAndCommit("feature change", "feat"))
+		git.SetWorkingDir(scene.Dir)
+		t.Cleanup(func() { git.SetWorkingDir("") })
+		require.NoError(t, git.InitDefaultRepo())
+		ctx := context.Background()
+		require.NoError(t, scene.Repo.RunGitCommand("push", "origin", "feature"))
+		require.NoError(t, scene.Repo.RunGitCommand("branch", "--set-upstream-to=origin/main", "feature"))
+
+		// Rewrite the branch so only a forced push succeeds
+		require.NoError(t, scene.Repo.RunGitCommand("commit", "--amend", "-m", "rewritten"))
+		require.NoError(t, git.PushBranchWithOptions(ctx, git.PushOptions{
+			BranchName: "feature", Remote: "origin", Force: true, SetUpstream: true,
+		}))
+		upstream, err := scene.Repo.RunGitCommandAndGetOutput("rev-parse", "--abbrev-ref", "feature@{upstream}")
+		require.NoError(t, err)
+		require.Equal(t, "origin/main", upstream)
+
+		local, err := scene.Repo.RunGitCommandAndGetOutput("rev-parse", "feature")
+		require.NoError(t, err)
+		remote, err := scene.Repo.RunGitCommandAndGetOutput("ls-remote", "origin", "refs/heads/feature")
+		require.NoError(t, err)
+		require.Contains(t, remote, local)
+	})
+
+	t.Run("doesn't set the upstream when disabled", func(t *testing.T) {
+		scene := testhelpers.NewScene(t, func(s *testhelpers.Scene) error {
+			return s.Repo.CreateChangeAndCommit("initial", "init")
+		})
+		_, err := scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		require.NoError(t, scene.Repo.PushBranch("origin", "main"))
+		require.NoError(t, scene.Repo.CreateAndCheckoutBranch("feature"))
+		require.NoError(t, scene.Repo.CreateChangeAndCommit("feature change", "feat"))
+		git.SetWorkingDir(scene.Dir)
+		t.Cleanup(func() { git.SetWorkingDir("") })
+		require.NoError(t, git.InitDefaultRepo())
+		require.NoError(t, git.PushBranchWithOptions(context.Background(), git.PushOptions{
+			BranchName: "feature", Remote: "origin",
+		}))
+		_, err = scene.Repo.RunGitCommandAndGetOutput("rev-parse", "--abbrev-ref", "feature@{upstream}")
+		require.Error(t, err, "feature shouldn't have an upstream")
+	})
+
+	t.Run("explains protected branch rejections", func(t *testing.T) {
+		scene := testhelpers.NewScene(t, func(s *testhelpers.Scene) error {
+			return s.Repo.CreateChangeAndCommit("initial", "init")
+		})
+		_, err := scene.Repo.CreateBareRemote("origin")
+		require.NoError(t, err)
+		require.NoError(t, scene.Repo.PushBranch("origin", "main"))
+		require.NoError(t, scene.Repo.CreateAndCheckoutBranch("feature"))
+		require.NoError(t, scene.Repo.CreateChangeAndCommit("feature change", "feat"))
+		git.SetWorkingDir(scene.Dir)
+		t.Cleanup(func() { git.SetWorkingDir("") })
+		require.NoError(t, git.InitDefaultRepo())
+		hook := "#!/bin/sh\necho 'GH006: Protected branch update failed for refs/heads/feature.' >&2\nexit 1\n"
+		hookPath := filepath.Join(scene.Dir+"-origin.git", "hooks", "pre-receive")
+		require.NoError(t, os.WriteFile(hookPath, []byte(hook), 0o755))
+
+		err = git.PushBranchWithOptions(context.Background(), git.PushOptions{
+			BranchName: "feature", Remote: "origin",
+		})
+		require.ErrorIs(t, err, git.ErrPushRejected)
+		require.NotErrorIs(t, err, git.ErrStaleRemoteInfo)
+		require.Contains(t, err.Error(), "failed to push branch feature: push rejected by the remote because the branch is protected")
+		require.Contains(t, err.Error(), "ask a repository admin to allow pushes to feature")
+		require.Contains(t, err.Error(), "GH006: Protected branch update failed")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -17,13 +18,53 @@ const (
 	RebaseConflict
 )
 
+// RebaseOptions contains optional behavior for a rebase
+type RebaseOptions struct {
+	// PreserveDates keeps each rewritten commit's committer date equal to its
+	// author date, so restacked branches keep their original timeline.
+	PreserveDates bool
+	// EmptyCommits controls what happens to commits that are, or become, empty when
+	// rebased. If empty, git's own defaults apply.
+	EmptyCommits EmptyCommitMode
+}
+
+// EmptyCommitMode is how a rebase handles commits without changes of their own
+type EmptyCommitMode string
+
+const (
+	// EmptyCommitsDrop drops empty commits, e.g. a commit whose change is already upstream
+	EmptyCommitsDrop EmptyCommitMode = "drop"
+	// EmptyCommitsKeep keeps empty commits in the rebased branch
+	EmptyCommitsKeep EmptyCommitMode = "keep"
+)
+
 // Rebase rebases a branch onto another branch.
 // onto is the branch name to rebase onto (parent branch).
 // from is the base revision (old parent branch revision).
 func Rebase(ctx context.Context, branchName, onto, from string) (RebaseResult, error) {
+	return RebaseWithOptions(ctx, branchName, onto, from, RebaseOptions{})
+}
+
+// RebaseWithOptions rebases a branch onto another branch with the given options.
+// Commit signing configured through commit.gpgSign still applies to the rewritten
+// commits; preserving dates only changes the committer timestamp being signed.
+func RebaseWithOptions(ctx context.Context, branchName, onto, from string, opts RebaseOptions) (RebaseResult, error) {
 	// Use detached HEAD to avoid "already used by worktree" errors
 	// git rebase --onto <onto> <from> <branchName>
-	_, err := RunGitCommandWithContext(ctx, "rebase", "--onto", onto, from, branchName)
+	args := []string{"rebase"}
+	if opts.PreserveDates {
+		// Recorded in the rebase state, so it also applies after rebase --continue
+		args = append(args, "--committer-date-is-author-date")
+	}
+	switch opts.EmptyCommits {
+	case EmptyCommitsDrop:
+		// --empty covers commits that become empty, --no-keep-empty those that started empty
+		args = append(args, "--empty=drop", "--no-keep-empty")
+	case EmptyCommitsKeep:
+		args = append(args, "--empty=keep", "--keep-empty")
+	}
+	args = append(args, "--onto", onto, from, branchName)
+	_, err := RunGitCommandWithContext(ctx, args...)
 	if err != nil {
 		if IsRebaseInProgress(ctx) {
 			return RebaseConflict, nil
@@ -69,6 +110,43 @@ func RebaseContinue(ctx context.Context) (RebaseResult, error) {
 	return RebaseDone, nil
 }
 
+// RunMergetool runs the configured merge.tool on each conflicted file, with the terminal
+// attached so graphical and terminal tools both work
+func RunMergetool() error {
+	return RunGitCommandInteractive("mergetool", "--no-prompt")
+}
+
+// Merge merges upstream into branchName, recording a merge commit instead of
+// rewriting the branch's history. Conflicts leave the merge in progress.
+func Merge(ctx context.Context, branchName, upstream string) (RebaseResult, error) {
+	if _, err := RunGitCommandWithContext(ctx, "checkout", branchName); err != nil {
+		return RebaseConflict, fmt.Errorf("failed to checkout %s: %w", branchName, err)
+	}
+
+	_, err := RunGitCommandWithContext(ctx, "merge", "--no-ff", "--no-edit", upstream)
+	if err != nil {
+		if IsMergeInProgress(ctx) {
+			return RebaseConflict, nil
+		}
+		return RebaseConflict, fmt.Errorf("failed to merge %s into %s: %w", upstream, branchName, err)
+	}
+
+	return RebaseDone, nil
+}
+
+// MergeContinue concludes an in-progress merge once conflicts are resolved
+func MergeContinue(ctx context.Context) (RebaseResult, error) {
+	_, err := RunGitCommandWithEnv(ctx, []string{"GIT_EDITOR=true"}, "commit", "--no-edit")
+	if err != nil {
+		if IsMergeInProgress(ctx) {
+			return RebaseConflict, nil
+		}
+		return RebaseConflict, fmt.Errorf("merge continue failed: %w", err)
+	}
+
+	return RebaseDone, nil
+}
+
 // RebaseAbort aborts an in-progress rebase
 func RebaseAbort(ctx context.Context) error {
 	_, err := RunGitCommandWithContext(ctx, "rebase", "--abort")
@@ -95,6 +173,22 @@ func IsRebaseInProgress(ctx context.Context) bool {
 	return false
 }
 
+// IsGitOperationInProgress reports whether git is in the middle of a rebase, merge or
+// cherry-pick that is waiting to be continued or aborted
+func IsGitOperationInProgress(ctx context.Context) bool {
+	gitDir, err := RunGitCommandWithContext(ctx, "rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return false
+	}
+
+	for _, marker := range []string{"rebase-merge", "rebase-apply", "MERGE_HEAD", "CHERRY_PICK_HEAD"} {
+		if _, err := os.Stat(filepath.Join(strings.TrimSpace(gitDir), marker)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
 // GetRebaseHead returns the commit being rebased (REBASE_HEAD)
 func GetRebaseHead() (string, error) {
 	// Try standard rebase head refs in order:
@@ -131,6 +131,33 @@ func TestIsRebaseInProgress(t *testing.T) {
 	})
 }
 
+func TestIsGitOperationInProgress(t *testing.T) {
+	t.Run("returns false when no operation is in progress", func(t *testing.T) {
+		_ = testhelpers.NewScene(t, func(s *testhelpers.Scene) error {
+			return s.Repo.CreateChangeAndCommit("initial", "init")
+		})
+
+		require.False(t, git.IsGitOperationInProgress(context.Background()))
+	})
+
+	t.Run("returns true when a cherry-pick stopped on a conflict", func(t *testing.T) {
+		scene := testhelpers.NewScene(t, func(s *testhelpers.Scene) error {
+			return s.Repo.CreateChangeAndCommit("initial content", "conflict")
+		})
+
+		require.NoError(t, scene.Repo.CreateAndCheckoutBranch("branch1"))
+		require.NoError(t, scene.Repo.CreateChangeAndCommit("branch1 change", "conflict"))
+		require.NoError(t, scene.Repo.CheckoutBranch("main"))
+		require.NoError(t, scene.Repo.CreateChangeAndCommit("main conflicting", "conflict"))
+
+		_, err := scene.Repo.RunGitCommandAndGetOutput("cherry-pick", "branch1")
+		require.Error(t, err)
+
+		require.True(t, git.IsGitOperationInProgress(context.Background()))
+		require.False(t, git.IsRebaseInProgress(context.Background()))
+	})
+}
+
 func TestRebaseContinue(t *testing.T) {
 	t.Run("continues rebase after resolving conflict", func(t *testing.T) {
 		scene := testhelpers.NewScene(t, func(s *testhelpers.Scene) error {
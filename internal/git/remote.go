@@ -1,8 +1,10 @@
 package git
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing/transport"
@@ -35,6 +37,38 @@ func PruneRemote(remote string) error {
 	return nil
 }
 
+// ListRemotes returns the names of the configured remotes
+func ListRemotes(ctx context.Context) ([]string, error) {
+	return RunGitCommandLinesWithContext(ctx, "remote")
+}
+
+// StaleRemoteBranches returns the remote-tracking refs (e.g. origin/feature) whose branch
+// has been deleted on the remote, without removing them
+func StaleRemoteBranches(ctx context.Context, remote string) ([]string, error) {
+	output, err := RunGitCommandWithContext(ctx, "remote", "prune", "--dry-run", remote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %s for stale branches: %w", remote, err)
+	}
+
+	var stale []string
+	for _, line := range strings.Split(output, "\n") {
+		// Lines look like " * [would prune] origin/feature"
+		if _, ref, ok := strings.Cut(line, "[would prune] "); ok {
+			stale = append(stale, strings.TrimSpace(ref))
+		}
+	}
+	return stale, nil
+}
+
+// PruneStaleRemoteBranches deletes the remote-tracking refs whose branch has been deleted
+// on the remote
+func PruneStaleRemoteBranches(ctx context.Context, remote string) error {
+	if _, err := RunGitCommandWithContext(ctx, "remote", "prune", remote); err != nil {
+		return fmt.Errorf("failed to prune %s: %w", remote, err)
+	}
+	return nil
+}
+
 // GetRemote returns the default remote name (usually "origin")
 func GetRemote() string {
 	repo, err := GetDefaultRepo()
@@ -63,6 +97,26 @@ func GetRemote() string {
 	return "origin"
 }
 
+// GetRemoteDefaultBranch returns the branch a remote's HEAD points to, e.g. "main" for
+// refs/remotes/origin/HEAD -> origin/main. It returns "" if the remote HEAD isn't known,
+// which is the case until it is cloned or set with `git remote set-head`.
+func GetRemoteDefaultBranch(ctx context.Context, remote string) string {
+	remoteHead, err := RunGitCommandWithContext(ctx, "symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(remoteHead, remote+"/")
+}
+
+// GetPushRemote returns the remote branches are pushed to. This is remote.pushDefault when
+// set, e.g. a personal fork in a fork workflow, and otherwise the default remote.
+func GetPushRemote() string {
+	if remote, err := RunGitCommand("config", "--get", "remote.pushDefault"); err == nil && remote != "" {
+		return remote
+	}
+	return GetRemote()
+}
+
 // FetchRemoteShas fetches the SHAs of all branches on the remote.
 // Returns a map of branch name -> SHA.
 func FetchRemoteShas(remote string) (map[string]string, error) {
@@ -79,10 +133,12 @@ func FetchRemoteShas(remote string) (map[string]string, error) {
 		return nil, fmt.Errorf("failed to get remote %s: %w", remote, err)
 	}
 
-	// List remote references
+	// List remote references, giving up after the network timeout
+	ctx, cancel := context.WithTimeout(context.Background(), NetworkCommandTimeout())
+	defer cancel()
 	// Synchronize go-git operations to prevent concurrent packfile access
 	goGitMu.Lock()
-	refs, err := r.List(&gogit.ListOptions{})
+	refs, err := r.ListContext(ctx, &gogit.ListOptions{})
 	goGitMu.Unlock()
 	if err != nil {
 		if errors.Is(err, transport.ErrEmptyRemoteRepository) || errors.Is(err, gogit.NoErrAlreadyUpToDate) {
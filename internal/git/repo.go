@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	gogit "github.com/go-git/go-git/v5"
+
+	stackiterrors "stackit.dev/stackit/internal/errors"
 )
 
 // GetRepoRoot returns the root directory of the Git repository
@@ -30,6 +32,25 @@ func GetRepoRoot() (string, error) {
 	return worktree.Filesystem.Root(), nil
 }
 
+// IsInsideWorkTree reports whether the working directory is inside a git work tree
+func IsInsideWorkTree() bool {
+	output, err := RunGitCommand("rev-parse", "--is-inside-work-tree")
+	return err == nil && output == "true"
+}
+
+// EnsureRepository opens the default repository, returning ErrNotGitRepository when the
+// working directory isn't inside a git work tree so callers can tell that apart from a
+// repository where stackit hasn't been initialized
+func EnsureRepository() error {
+	if !IsInsideWorkTree() {
+		return stackiterrors.ErrNotGitRepository
+	}
+	if err := InitDefaultRepo(); err != nil {
+		return fmt.Errorf("failed to open git repository: %w", err)
+	}
+	return nil
+}
+
 // GetRef returns the SHA of a ref
 func GetRef(name string) (string, error) {
 	return RunGitCommand("rev-parse", "--verify", name)
@@ -13,12 +13,16 @@ import (
 	stackiterrors "stackit.dev/stackit/internal/errors"
 )
 
-// DefaultCommandTimeout is the default timeout for git commands
+// DefaultCommandTimeout is the timeout for git commands when none is configured for their
+// category (see SetCommandTimeouts)
 const DefaultCommandTimeout = 5 * time.Minute
 
 // ErrStaleRemoteInfo indicates that a push failed because the remote has changed
 var ErrStaleRemoteInfo = errors.New("stale info")
 
+// ErrPushRejected indicates that the remote refused a push, e.g. because of branch protection
+var ErrPushRejected = errors.New("push rejected by the remote")
+
 // CommandRunner handles execution of git commands
 type CommandRunner struct {
 	workingDir string
@@ -67,7 +71,7 @@ func (r *CommandRunner) runWithEnv(ctx context.Context, env []string, args ...st
 
 	if _, ok := ctx.Deadline(); !ok {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, DefaultCommandTimeout)
+		ctx, cancel = context.WithTimeout(ctx, commandTimeout(args))
 		defer cancel()
 	}
 
@@ -101,10 +105,10 @@ func (r *CommandRunner) runInternal(ctx context.Context, input string, trim bool
 		ctx = context.Background()
 	}
 
-	// If no timeout/deadline is set in the context, add the default one
+	// If no timeout/deadline is set in the context, add the one for the command's category
 	if _, ok := ctx.Deadline(); !ok {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, DefaultCommandTimeout)
+		ctx, cancel = context.WithTimeout(ctx, commandTimeout(args))
 		defer cancel()
 	}
 
@@ -232,6 +236,7 @@ type Runner interface {
 	// Repository and Config
 	InitDefaultRepo() error
 	GetRemote() string
+	GetPushRemote() string
 	FetchRemoteShas(remote string) (map[string]string, error)
 	GetRemoteSha(remote, branchName string) (string, error)
 
@@ -254,16 +259,22 @@ type Runner interface {
 	IsAncestor(ancestor, descendant string) (bool, error)
 	GetCommitDate(branchName string) (time.Time, error)
 	GetCommitAuthor(branchName string) (string, error)
+	BatchGetCommitInfo(branchNames []string) (map[string]CommitInfo, error)
 	GetCommitRange(base, head, format string) ([]string, error)
 	GetCommitRangeSHAs(base, head string) ([]string, error)
 	GetCommitHistorySHAs(branchName string) ([]string, error)
 	GetCommitSHA(branchName string, offset int) (string, error)
+	GetPatchIDs(ctx context.Context, base, head string) (map[string]string, error)
 
 	// Git Operations
 	PullBranch(ctx context.Context, remote, branchName string) (PullResult, error)
 	PushBranch(ctx context.Context, branchName, remote string, force, forceWithLease bool) error
-	Rebase(ctx context.Context, branchName, upstream, oldUpstream string) (RebaseResult, error)
+	PushBranchWithOptions(ctx context.Context, opts PushOptions) error
+	Rebase(ctx context.Context, branchName, upstream, oldUpstream string, opts RebaseOptions) (RebaseResult, error)
 	RebaseContinue(ctx context.Context) (RebaseResult, error)
+	Merge(ctx context.Context, branchName, upstream string) (RebaseResult, error)
+	MergeContinue(ctx context.Context) (RebaseResult, error)
+	IsMergeInProgress(ctx context.Context) bool
 	CherryPick(ctx context.Context, commitSHA, onto string) (string, error)
 	StashPush(ctx context.Context, message string) (string, error)
 	StashPop(ctx context.Context) error
@@ -279,7 +290,7 @@ type Runner interface {
 	GetChangedFiles(ctx context.Context, base, head string) ([]string, error)
 	GetRebaseHead() (string, error)
 	ParseStagedHunks(ctx context.Context) ([]Hunk, error)
-	ShowDiff(ctx context.Context, left, right string, stat bool) (string, error)
+	ShowDiff(ctx context.Context, left, right string, opts DiffOptions) (string, error)
 	ShowCommits(ctx context.Context, base, head string, patch, stat bool) (string, error)
 	GetUnmergedFiles(ctx context.Context) ([]string, error)
 
@@ -364,6 +375,10 @@ func (r *realRunner) GetRemote() string {
 	return GetRemote()
 }
 
+func (r *realRunner) GetPushRemote() string {
+	return GetPushRemote()
+}
+
 func (r *realRunner) FetchRemoteShas(remote string) (map[string]string, error) {
 	return FetchRemoteShas(remote)
 }
@@ -436,6 +451,10 @@ func (r *realRunner) GetCommitAuthor(branchName string) (string, error) {
 	return GetCommitAuthor(branchName)
 }
 
+func (r *realRunner) BatchGetCommitInfo(branchNames []string) (map[string]CommitInfo, error) {
+	return BatchGetCommitInfo(branchNames)
+}
+
 func (r *realRunner) GetCommitRange(base, head, format string) ([]string, error) {
 	return GetCommitRange(base, head, format)
 }
@@ -444,6 +463,10 @@ func (r *realRunner) GetCommitRangeSHAs(base, head string) ([]string, error) {
 	return GetCommitRangeSHAs(base, head)
 }
 
+func (r *realRunner) GetPatchIDs(ctx context.Context, base, head string) (map[string]string, error) {
+	return GetPatchIDs(ctx, base, head)
+}
+
 func (r *realRunner) GetCommitHistorySHAs(branchName string) ([]string, error) {
 	return GetCommitHistorySHAs(branchName)
 }
@@ -460,14 +483,30 @@ func (r *realRunner) PushBranch(ctx context.Context, branchName, remote string,
 	return PushBranch(ctx, branchName, remote, force, forceWithLease)
 }
 
-func (r *realRunner) Rebase(ctx context.Context, branchName, upstream, oldUpstream string) (RebaseResult, error) {
-	return Rebase(ctx, branchName, upstream, oldUpstream)
+func (r *realRunner) PushBranchWithOptions(ctx context.Context, opts PushOptions) error {
+	return PushBranchWithOptions(ctx, opts)
+}
+
+func (r *realRunner) Rebase(ctx context.Context, branchName, upstream, oldUpstream string, opts RebaseOptions) (RebaseResult, error) {
+	return RebaseWithOptions(ctx, branchName, upstream, oldUpstream, opts)
 }
 
 func (r *realRunner) RebaseContinue(ctx context.Context) (RebaseResult, error) {
 	return RebaseContinue(ctx)
 }
 
+func (r *realRunner) Merge(ctx context.Context, branchName, upstream string) (RebaseResult, error) {
+	return Merge(ctx, branchName, upstream)
+}
+
+func (r *realRunner) MergeContinue(ctx context.Context) (RebaseResult, error) {
+	return MergeContinue(ctx)
+}
+
+func (r *realRunner) IsMergeInProgress(ctx context.Context) bool {
+	return IsMergeInProgress(ctx)
+}
+
 func (r *realRunner) CherryPick(ctx context.Context, commitSHA, onto string) (string, error) {
 	return CherryPick(ctx, commitSHA, onto)
 }
@@ -524,8 +563,8 @@ func (r *realRunner) ParseStagedHunks(ctx context.Context) ([]Hunk, error) {
 	return ParseStagedHunks(ctx)
 }
 
-func (r *realRunner) ShowDiff(ctx context.Context, left, right string, stat bool) (string, error) {
-	return ShowDiff(ctx, left, right, stat)
+func (r *realRunner) ShowDiff(ctx context.Context, left, right string, opts DiffOptions) (string, error) {
+	return ShowDiff(ctx, left, right, opts)
 }
 
 func (r *realRunner) ShowCommits(ctx context.Context, base, head string, patch, stat bool) (string, error) {
@@ -0,0 +1,70 @@
+package git
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// networkCommands are the git subcommands that talk to a remote, which can legitimately
+// take much longer than commands that only read or write the local repository
+var networkCommands = map[string]bool{
+	"clone":     true,
+	"fetch":     true,
+	"ls-remote": true,
+	"pull":      true,
+	"push":      true,
+}
+
+var (
+	localTimeout   atomic.Int64
+	networkTimeout atomic.Int64
+)
+
+// SetCommandTimeouts sets how long local and network git commands may run when their
+// context has no deadline of its own. A zero duration keeps DefaultCommandTimeout.
+func SetCommandTimeouts(local, network time.Duration) {
+	localTimeout.Store(int64(local))
+	networkTimeout.Store(int64(network))
+}
+
+// LocalCommandTimeout returns the timeout for git commands that don't touch a remote
+func LocalCommandTimeout() time.Duration {
+	if timeout := time.Duration(localTimeout.Load()); timeout > 0 {
+		return timeout
+	}
+	return DefaultCommandTimeout
+}
+
+// NetworkCommandTimeout returns the timeout for git commands that talk to a remote
+func NetworkCommandTimeout() time.Duration {
+	if timeout := time.Duration(networkTimeout.Load()); timeout > 0 {
+		return timeout
+	}
+	return DefaultCommandTimeout
+}
+
+// commandTimeout returns the timeout for the git command with the given arguments,
+// depending on whether its subcommand talks to a remote
+func commandTimeout(args []string) time.Duration {
+	if networkCommands[subcommand(args)] {
+		return NetworkCommandTimeout()
+	}
+	return LocalCommandTimeout()
+}
+
+// subcommand returns the git subcommand in args, skipping global options such as
+// `-c key=value` and `--no-pager`
+func subcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "-c" || arg == "-C":
+			i++ // skip the option's value
+		case strings.HasPrefix(arg, "-"):
+			continue
+		default:
+			return arg
+		}
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package git_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"stackit.dev/stackit/internal/git"
+	"stackit.dev/stackit/testhelpers"
+)
+
+func TestCommandTimeouts(t *testing.T) {
+	scene := testhelpers.NewScene(t, func(s *testhelpers.Scene) error {
+		return s.Repo.CreateChangeAndCommit("initial", "init")
+	})
+	_, err := scene.Repo.CreateBareRemote("origin")
+	require.NoError(t, err)
+	git.SetWorkingDir(scene.Dir)
+	t.Cleanup(func() { git.SetWorkingDir("") })
+	require.NoError(t, git.InitDefaultRepo())
+
+	t.Cleanup(func() { git.SetCommandTimeouts(0, 0) })
+
+	t.Run("defaults both categories", func(t *testing.T) {
+		git.SetCommandTimeouts(0, 0)
+		require.Equal(t, git.DefaultCommandTimeout, git.LocalCommandTimeout())
+		require.Equal(t, git.DefaultCommandTimeout, git.NetworkCommandTimeout())
+	})
+
+	t.Run("local commands get the local timeout", func(t *testing.T) {
+		git.SetCommandTimeouts(time.Nanosecond, time.Hour)
+
+		_, err := git.RunGitCommand("rev-parse", "HEAD")
+		require.True(t, errors.Is(err, context.DeadlineExceeded), "expected a timeout, got %v", err)
+		_, err = git.RunGitCommand("-c", "color.ui=never", "log", "-1")
+		require.True(t, errors.Is(err, context.DeadlineExceeded), "expected a timeout, got %v", err)
+	})
+
+	t.Run("network commands get the network timeout", func(t *testing.T) {
+		git.SetCommandTimeouts(time.Nanosecond, time.Hour)
+
+		_, err := git.RunGitCommand("push", "origin", "main")
+		require.NoError(t, err)
+		_, err = git.RunGitCommand("fetch", "origin")
+		require.NoError(t, err)
+
+		git.SetCommandTimeouts(time.Hour, time.Nanosecond)
+		_, err = git.RunGitCommand("fetch", "origin")
+		require.True(t, errors.Is(err, context.DeadlineExceeded), "expected a timeout, got %v", err)
+	})
+
+	t.Run("an existing deadline is kept", func(t *testing.T) {
+		git.SetCommandTimeouts(time.Nanosecond, time.Nanosecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		_, err := git.RunGitCommandWithContext(ctx, "rev-parse", "HEAD")
+		require.NoError(t, err)
+	})
+}
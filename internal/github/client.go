@@ -3,6 +3,8 @@ package github
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -21,8 +23,56 @@ type PullRequestInfo struct {
 	Draft   bool
 	Base    string
 	Head    string
+
+	// HeadRepo is the full name (owner/name) of the repository the head branch lives in,
+	// and HeadCloneURL the URL to fetch it from
+	HeadRepo     string
+	HeadCloneURL string
+	// CrossRepository is set when the head branch lives in a fork of the base repository
+	CrossRepository bool
+
+	AutoMergeEnabled bool
+
+	// Reviewers and TeamReviewers are the users and team slugs whose review is still
+	// requested; reviewers who have already reviewed aren't included
+	Reviewers     []string
+	TeamReviewers []string
+	Labels        []string
+}
+
+// AutoMergeMethod is the merge method GitHub uses when it auto-merges a pull request
+type AutoMergeMethod string
+
+const (
+	// AutoMergeMerge creates a merge commit
+	AutoMergeMerge AutoMergeMethod = "MERGE"
+	// AutoMergeSquash squashes the pull request into a single commit
+	AutoMergeSquash AutoMergeMethod = "SQUASH"
+	// AutoMergeRebase rebases the pull request's commits onto the base branch
+	AutoMergeRebase AutoMergeMethod = "REBASE"
+)
+
+// ParseAutoMergeMethod parses a merge method name ("merge", "squash" or "rebase")
+func ParseAutoMergeMethod(name string) (AutoMergeMethod, error) {
+	switch method := AutoMergeMethod(strings.ToUpper(name)); method {
+	case AutoMergeMerge, AutoMergeSquash, AutoMergeRebase:
+		return method, nil
+	default:
+		return "", fmt.Errorf("invalid auto-merge method %q (must be 'merge', 'squash' or 'rebase')", name)
+	}
 }
 
+var (
+	// ErrAutoMergeNotAllowed is returned when the repository doesn't allow auto-merge
+	ErrAutoMergeNotAllowed = errors.New("auto-merge is not allowed on this repository; enable \"Allow auto-merge\" in the repository settings")
+	// ErrAutoMergeNotNeeded is returned when a pull request has no pending branch protection
+	// requirements, so GitHub won't queue it for auto-merge
+	ErrAutoMergeNotNeeded = errors.New("pull request has no pending requirements and can be merged now")
+	// ErrPRSetupIncomplete is returned together with a newly created pull request when its
+	// labels or milestone couldn't be applied. The PR exists, so callers should record it.
+	ErrPRSetupIncomplete = errors.New("pull request was created but not fully set up")
+)
+
 // CheckDetail represents the status of an individual CI check
 type CheckDetail struct {
 	Name       string
@@ -39,6 +89,12 @@ type CheckStatus struct {
 	Checks  []CheckDetail
 }
 
+// Comment is a comment on a pull request's conversation
+type Comment struct {
+	ID   int64
+	Body string
+}
+
 // Client is an interface for GitHub API interactions
 type Client interface {
 	// CreatePullRequest creates a new pull request
@@ -47,6 +103,9 @@ type Client interface {
 	// UpdatePullRequest updates an existing pull request
 	UpdatePullRequest(ctx context.Context, owner, repo string, prNumber int, opts UpdatePROptions) error
 
+	// GetPullRequest gets a pull request by number, returning nil if it doesn't exist
+	GetPullRequest(ctx context.Context, owner, repo string, prNumber int) (*PullRequestInfo, error)
+
 	// GetPullRequestByBranch gets a pull request for a branch
 	GetPullRequestByBranch(ctx context.Context, owner, repo, branchName string) (*PullRequestInfo, error)
 
@@ -56,6 +115,28 @@ type Client interface {
 	// GetPRChecksStatus returns the check status for a PR
 	GetPRChecksStatus(ctx context.Context, branchName string) (*CheckStatus, error)
 
+	// GetBranchPRStatuses fetches the PR and check status of each branch in one batch request,
+	// keyed by branch name; branches without a PR are left out. It fails when the batch API
+	// is unavailable, and callers then fall back to per-branch requests.
+	GetBranchPRStatuses(ctx context.Context, branchNames []string) (map[string]*BranchPRStatus, error)
+
+	// AddComment posts a comment on a pull request's conversation
+	AddComment(ctx context.Context, owner, repo string, prNumber int, body string) error
+
+	// ListComments returns the comments on a pull request's conversation, oldest first
+	ListComments(ctx context.Context, owner, repo string, prNumber int) ([]Comment, error)
+
+	// UpdateComment replaces the body of an existing comment
+	UpdateComment(ctx context.Context, owner, repo string, commentID int64, body string) error
+
+	// DeleteComment removes a comment from a pull request's conversation
+	DeleteComment(ctx context.Context, owner, repo string, commentID int64) error
+
+	// EnableAutoMerge turns on auto-merge for a pull request, so GitHub merges it with
+	// method once its branch protection requirements pass. It is a no-op if auto-merge
+	// is already enabled.
+	EnableAutoMerge(ctx context.Context, owner, repo string, prNumber int, method AutoMergeMethod) error
+
 	// GetOwnerRepo returns the repository owner and name
 	GetOwnerRepo() (owner, repo string)
 }
@@ -86,15 +167,39 @@ func ToPullRequestInfo(pr *github.PullRequest) *PullRequestInfo {
 	if pr.State != nil {
 		info.State = strings.ToUpper(*pr.State)
 	}
+	// The REST API reports merged PRs as closed
+	if pr.GetMerged() || pr.MergedAt != nil {
+		info.State = "MERGED"
+	}
 	if pr.Draft != nil {
 		info.Draft = *pr.Draft
 	}
+	if pr.AutoMerge != nil {
+		info.AutoMergeEnabled = true
+	}
 	if pr.Base != nil && pr.Base.Ref != nil {
 		info.Base = *pr.Base.Ref
 	}
 	if pr.Head != nil && pr.Head.Ref != nil {
 		info.Head = *pr.Head.Ref
 	}
+	if pr.Head != nil && pr.Head.Repo != nil {
+		info.HeadRepo = pr.Head.Repo.GetFullName()
+		info.HeadCloneURL = pr.Head.Repo.GetCloneURL()
+		if pr.Base != nil && pr.Base.Repo != nil {
+			info.CrossRepository = info.HeadRepo != pr.Base.Repo.GetFullName()
+		}
+	}
+
+	for _, user := range pr.RequestedReviewers {
+		info.Reviewers = append(info.Reviewers, user.GetLogin())
+	}
+	for _, team := range pr.RequestedTeams {
+		info.TeamReviewers = append(info.TeamReviewers, team.GetSlug())
+	}
+	for _, label := range pr.Labels {
+		info.Labels = append(info.Labels, label.GetName())
+	}
 
 	return info
 }
@@ -4,6 +4,7 @@ package github
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/google/go-github/v62/github"
 )
@@ -19,6 +20,10 @@ type RealGitHubClient struct {
 func NewRealGitHubClient(ctx context.Context) (*RealGitHubClient, error) {
 	token, err := getGitHubToken()
 	if err != nil {
+		// Prefer the setup guidance when the CLI is missing or logged out
+		if checkErr := CheckCLIAuth(ctx); checkErr != nil {
+			return nil, checkErr
+		}
 		return nil, fmt.Errorf("failed to get GitHub token: %w", err)
 	}
 
@@ -70,6 +75,14 @@ func (c *RealGitHubClient) CreatePullRequest(ctx context.Context, owner, repo st
 		})
 	}
 
+	var milestone *string
+	if opts.Milestone != "" {
+		milestone = &opts.Milestone
+	}
+	if err := applyLabelsAndMilestone(ctx, c.client, owner, repo, *createdPR.Number, opts.Labels, false, milestone); err != nil {
+		return ToPullRequestInfo(createdPR), fmt.Errorf("%w: %w", ErrPRSetupIncomplete, err)
+	}
+
 	return ToPullRequestInfo(createdPR), nil
 }
 
@@ -147,7 +160,19 @@ func (c *RealGitHubClient) UpdatePullRequest(ctx context.Context, owner, repo st
 		}
 	}
 
-	return nil
+	return applyLabelsAndMilestone(ctx, c.client, owner, repo, prNumber, opts.Labels, opts.ReplaceLabels, opts.Milestone)
+}
+
+// GetPullRequest gets a pull request by number
+func (c *RealGitHubClient) GetPullRequest(ctx context.Context, owner, repo string, prNumber int) (*PullRequestInfo, error) {
+	pr, resp, err := c.client.PullRequests.Get(ctx, owner, repo, prNumber)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get PR %d: %w", prNumber, err)
+	}
+	return ToPullRequestInfo(pr), nil
 }
 
 // GetPullRequestByBranch gets a pull request for a branch
@@ -179,3 +204,72 @@ func (c *RealGitHubClient) MergePullRequest(ctx context.Context, branchName stri
 func (c *RealGitHubClient) GetPRChecksStatus(ctx context.Context, branchName string) (*CheckStatus, error) {
 	return GetPRChecksStatus(ctx, c.client, c.owner, c.repo, branchName)
 }
+
+// GetBranchPRStatuses fetches the PRs and check statuses of branches with one GraphQL query
+func (c *RealGitHubClient) GetBranchPRStatuses(ctx context.Context, branchNames []string) (map[string]*BranchPRStatus, error) {
+	return GetBranchPRStatuses(ctx, c.owner, c.repo, branchNames)
+}
+
+// AddComment posts a comment on a pull request's conversation
+func (c *RealGitHubClient) AddComment(ctx context.Context, owner, repo string, prNumber int, body string) error {
+	_, _, err := c.client.Issues.CreateComment(ctx, owner, repo, prNumber, &github.IssueComment{Body: &body})
+	if err != nil {
+		return fmt.Errorf("failed to comment on PR %d: %w", prNumber, err)
+	}
+	return nil
+}
+
+// ListComments returns the comments on a pull request's conversation, oldest first
+func (c *RealGitHubClient) ListComments(ctx context.Context, owner, repo string, prNumber int) ([]Comment, error) {
+	opts := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	var comments []Comment
+	for {
+		page, resp, err := c.client.Issues.ListComments(ctx, owner, repo, prNumber, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list comments on PR %d: %w", prNumber, err)
+		}
+		for _, comment := range page {
+			comments = append(comments, Comment{ID: comment.GetID(), Body: comment.GetBody()})
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return comments, nil
+}
+
+// EnableAutoMerge turns on auto-merge for a pull request via GitHub's GraphQL API
+func (c *RealGitHubClient) EnableAutoMerge(ctx context.Context, owner, repo string, prNumber int, method AutoMergeMethod) error {
+	pr, _, err := c.client.PullRequests.Get(ctx, owner, repo, prNumber)
+	if err != nil {
+		return fmt.Errorf("failed to get PR %d: %w", prNumber, err)
+	}
+	if pr.AutoMerge != nil {
+		return nil
+	}
+	if pr.NodeID == nil {
+		return fmt.Errorf("PR %d does not have a Node ID", prNumber)
+	}
+	return enablePRAutoMerge(ctx, *pr.NodeID, method)
+}
+
+// UpdateComment replaces the body of an existing comment
+func (c *RealGitHubClient) UpdateComment(ctx context.Context, owner, repo string, commentID int64, body string) error {
+	_, _, err := c.client.Issues.EditComment(ctx, owner, repo, commentID, &github.IssueComment{Body: &body})
+	if err != nil {
+		return fmt.Errorf("failed to update comment %d: %w", commentID, err)
+	}
+	return nil
+}
+
+// DeleteComment removes a comment from a pull request's conversation
+func (c *RealGitHubClient) DeleteComment(ctx context.Context, owner, repo string, commentID int64) error {
+	_, err := c.client.Issues.DeleteComment(ctx, owner, repo, commentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment %d: %w", commentID, err)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxBatchBranches bounds how many branches are looked up in one GraphQL query, keeping
+// each query well under GitHub's node limits
+const maxBatchBranches = 50
+
+// BranchPRStatus is a branch's pull request along with the status of its CI checks
+type BranchPRStatus struct {
+	PR     *PullRequestInfo
+	Checks *CheckStatus
+}
+
+// graphQLPullRequest is a pull request as returned by the batch PR status query
+type graphQLPullRequest struct {
+	Number            int    `json:"number"`
+	ID                string `json:"id"`
+	URL               string `json:"url"`
+	Title             string `json:"title"`
+	Body              string `json:"body"`
+	State             string `json:"state"`
+	IsDraft           bool   `json:"isDraft"`
+	BaseRefName       string `json:"baseRefName"`
+	HeadRefName       string `json:"headRefName"`
+	IsCrossRepository bool   `json:"isCrossRepository"`
+	AutoMergeRequest  *struct {
+		EnabledAt string `json:"enabledAt"`
+	} `json:"autoMergeRequest"`
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					Contexts struct {
+						Nodes []graphQLCheckContext `json:"nodes"`
+					} `json:"contexts"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+}
+
+// graphQLPullRequestConnection is the PRs found for one branch by the batch PR status query
+type graphQLPullRequestConnection struct {
+	Nodes []graphQLPullRequest `json:"nodes"`
+}
+
+// graphQLCheckContext is a check run or commit status in a PR's status check rollup
+type graphQLCheckContext struct {
+	Typename string `json:"__typename"`
+	// CheckRun fields
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Conclusion  string     `json:"conclusion"`
+	StartedAt   *time.Time `json:"startedAt"`
+	CompletedAt *time.Time `json:"completedAt"`
+	// StatusContext fields
+	Context string `json:"context"`
+	State   string `json:"state"`
+}
+
+// branchPRStatusQuery builds a query looking up the newest PR opened from each branch of
+// the repository, with the check rollup of its head commit. Each branch gets an alias
+// (b0, b1, ...) and a variable of the same name, so branch names never need escaping.
+func branchPRStatusQuery(owner, repo string, branchNames []string) (string, map[string]interface{}) {
+	variables := map[string]interface{}{"owner": owner, "repo": repo}
+	params := []string{"$owner: String!", "$repo: String!"}
+	var fields strings.Builder
+	for i, branchName := range branchNames {
+		alias := fmt.Sprintf("b%d", i)
+		variables[alias] = branchName
+		params = append(params, fmt.Sprintf("$%s: String!", alias))
+		fmt.Fprintf(&fields, `
+		%s: pullRequests(headRefName: $%s, first: 5, orderBy: {field: CREATED_AT, direction: DESC}) {
+			nodes { ...branchPR }
+		}`, alias, alias)
+	}
+
+	query := fmt.Sprintf(`query BranchPRStatuses(%s) {
+	repository(owner: $owner, name: $repo) {%s
+	}
+}
+
+fragment branchPR on PullRequest {
+	number id url title body state isDraft baseRefName headRefName isCrossRepository
+	autoMergeRequest { enabledAt }
+	commits(last: 1) {
+		nodes {
+			commit {
+				statusCheckRollup {
+					contexts(first: 100) {
+						nodes {
+							__typename
+							... on CheckRun { name status conclusion startedAt completedAt }
+							... on StatusContext { context state }
+						}
+					}
+				}
+			}
+		}
+	}
+}`, strings.Join(params, ", "), fields.String())
+	return query, variables
+}
+
+// parseBranchPRStatuses maps the response of branchPRStatusQuery back to branch names.
+// PRs from forks are skipped, as they aren't the branch's own PR.
+func parseBranchPRStatuses(branchNames []string, repository map[string]graphQLPullRequestConnection) map[string]*BranchPRStatus {
+	statuses := make(map[string]*BranchPRStatus)
+	for i, branchName := range branchNames {
+		for _, pr := range repository[fmt.Sprintf("b%d", i)].Nodes {
+			if pr.IsCrossRepository {
+				continue
+			}
+			statuses[branchName] = &BranchPRStatus{
+				PR: &PullRequestInfo{
+					Number:           pr.Number,
+					NodeID:           pr.ID,
+					HTMLURL:          pr.URL,
+					Title:            pr.Title,
+					Body:             pr.Body,
+					State:            pr.State,
+					Draft:            pr.IsDraft,
+					Base:             pr.BaseRefName,
+					Head:             pr.HeadRefName,
+					AutoMergeEnabled: pr.AutoMergeRequest != nil,
+				},
+				Checks: rollupCheckStatus(pr),
+			}
+			break
+		}
+	}
+	return statuses
+}
+
+// rollupCheckStatus summarizes the check runs and commit statuses of a PR's head commit the
+// same way GetPRChecksStatus does. A PR without checks is passing.
+func rollupCheckStatus(pr graphQLPullRequest) *CheckStatus {
+	status := &CheckStatus{Passing: true}
+	if len(pr.Commits.Nodes) == 0 || pr.Commits.Nodes[0].Commit.StatusCheckRollup == nil {
+		return status
+	}
+
+	for _, check := range pr.Commits.Nodes[0].Commit.StatusCheckRollup.Contexts.Nodes {
+		var detail CheckDetail
+		if check.Typename == "StatusContext" {
+			detail = CheckDetail{Name: check.Context, Status: "COMPLETED"}
+			switch check.State {
+			case checkStatePending, "EXPECTED":
+				detail.Status = "IN_PROGRESS"
+			case checkStateFailure, checkStateError:
+				detail.Conclusion = checkConclusionFailure
+			case "SUCCESS":
+				detail.Conclusion = "SUCCESS"
+			}
+		} else {
+			detail = CheckDetail{Name: check.Name, Status: check.Status, Conclusion: check.Conclusion}
+			if check.StartedAt != nil {
+				detail.StartedAt = *check.StartedAt
+			}
+			if check.CompletedAt != nil {
+				detail.FinishedAt = *check.CompletedAt
+			}
+		}
+
+		if detail.Status != "COMPLETED" {
+			status.Pending = true
+		}
+		switch detail.Conclusion {
+		case checkConclusionFailure, checkConclusionCanceled, "CANCELLED", checkConclusionTimedOut, checkConclusionActionRequired, "STARTUP_FAILURE":
+			status.Passing = false
+		}
+		status.Checks = append(status.Checks, detail)
+	}
+	return status
+}
+
+// GetBranchPRStatuses looks up the PRs and check statuses of many branches with one GraphQL
+// query per maxBatchBranches branches. Branches without a PR are left out of the result.
+func GetBranchPRStatuses(ctx context.Context, owner, repo string, branchNames []string) (map[string]*BranchPRStatus, error) {
+	statuses := make(map[string]*BranchPRStatus, len(branchNames))
+	for start := 0; start < len(branchNames); start += maxBatchBranches {
+		batch := branchNames[start:min(start+maxBatchBranches, len(branchNames))]
+		query, variables := branchPRStatusQuery(owner, repo, batch)
+
+		var data struct {
+			Repository map[string]graphQLPullRequestConnection `json:"repository"`
+		}
+		if err := runGraphQL(ctx, "BranchPRStatuses query", query, variables, &data); err != nil {
+			return nil, err
+		}
+		for branchName, status := range parseBranchPRStatuses(batch, data.Repository) {
+			statuses[branchName] = status
+		}
+	}
+	return statuses, nil
+}
+
+// ChecksStatusFetcher returns a function that looks up the check status of a branch's PR.
+// The statuses of all of branchNames are fetched up front with one GetBranchPRStatuses call;
+// when that fails, e.g. because GraphQL is unavailable, each lookup calls GetPRChecksStatus.
+func ChecksStatusFetcher(ctx context.Context, client Client, branchNames []string) func(branchName string) (*CheckStatus, error) {
+	statuses, err := client.GetBranchPRStatuses(ctx, branchNames)
+	if err != nil {
+		return func(branchName string) (*CheckStatus, error) {
+			return client.GetPRChecksStatus(ctx, branchName)
+		}
+	}
+	return func(branchName string) (*CheckStatus, error) {
+		if status := statuses[branchName]; status != nil && status.Checks != nil {
+			return status.Checks, nil
+		}
+		// Like GetPRChecksStatus, a branch without a PR has nothing failing
+		return &CheckStatus{Passing: true}, nil
+	}
+}
@@ -0,0 +1,76 @@
+package github
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBranchPRStatusQuery(t *testing.T) {
+	query, variables := branchPRStatusQuery("owner", "repo", []string{"a", `b"quoted`})
+
+	// Branch names are passed as variables, so they need no escaping in the query
+	require.Equal(t, map[string]interface{}{"owner": "owner", "repo": "repo", "b0": "a", "b1": `b"quoted`}, variables)
+	require.Contains(t, query, "query BranchPRStatuses($owner: String!, $repo: String!, $b0: String!, $b1: String!)")
+	require.Contains(t, query, "b0: pullRequests(headRefName: $b0")
+	require.Contains(t, query, "b1: pullRequests(headRefName: $b1")
+	require.NotContains(t, query, "quoted")
+}
+
+func TestParseBranchPRStatuses(t *testing.T) {
+	// A batch response for four branches: two with PRs, one whose only PR is from a fork,
+	// and one without a PR
+	response := `{
+		"repository": {
+			"b0": {"nodes": [{
+				"number": 101, "id": "PR_a", "url": "https://github.com/owner/repo/pull/101",
+				"title": "a", "body": "body a", "state": "MERGED", "isDraft": false,
+				"baseRefName": "main", "headRefName": "a", "isCrossRepository": false,
+				"autoMergeRequest": null,
+				"commits": {"nodes": [{"commit": {"statusCheckRollup": {"contexts": {"nodes": [
+					{"__typename": "CheckRun", "name": "build", "status": "COMPLETED", "conclusion": "SUCCESS",
+						"startedAt": "2026-01-01T10:00:00Z", "completedAt": "2026-01-01T10:05:00Z"},
+					{"__typename": "StatusContext", "context": "ci/legacy", "state": "SUCCESS"}
+				]}}}}]}
+			}]},
+			"b1": {"nodes": [{
+				"number": 102, "id": "PR_b", "url": "https://github.com/owner/repo/pull/102",
+				"title": "b", "body": "", "state": "OPEN", "isDraft": true,
+				"baseRefName": "a", "headRefName": "b", "isCrossRepository": false,
+				"autoMergeRequest": {"enabledAt": "2026-01-01T11:00:00Z"},
+				"commits": {"nodes": [{"commit": {"statusCheckRollup": {"contexts": {"nodes": [
+					{"__typename": "CheckRun", "name": "build", "status": "IN_PROGRESS", "conclusion": ""},
+					{"__typename": "CheckRun", "name": "lint", "status": "COMPLETED", "conclusion": "FAILURE"}
+				]}}}}]}
+			}]},
+			"b2": {"nodes": [{"number": 103, "headRefName": "c", "state": "OPEN", "isCrossRepository": true}]},
+			"b3": {"nodes": []}
+		}
+	}`
+	var data struct {
+		Repository map[string]graphQLPullRequestConnection `json:"repository"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(response), &data))
+
+	statuses := parseBranchPRStatuses([]string{"a", "b", "c", "d"}, data.Repository)
+	require.Len(t, statuses, 2)
+
+	a := statuses["a"]
+	require.Equal(t, &PullRequestInfo{
+		Number: 101, NodeID: "PR_a", HTMLURL: "https://github.com/owner/repo/pull/101",
+		Title: "a", Body: "body a", State: "MERGED", Base: "main", Head: "a",
+	}, a.PR)
+	require.True(t, a.Checks.Passing)
+	require.False(t, a.Checks.Pending)
+	require.Len(t, a.Checks.Checks, 2)
+	require.Equal(t, 5*time.Minute, a.Checks.Checks[0].FinishedAt.Sub(a.Checks.Checks[0].StartedAt))
+
+	b := statuses["b"]
+	require.Equal(t, 102, b.PR.Number)
+	require.True(t, b.PR.Draft)
+	require.True(t, b.PR.AutoMergeEnabled)
+	require.False(t, b.Checks.Passing)
+	require.True(t, b.Checks.Pending)
+}
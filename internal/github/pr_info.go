@@ -12,6 +12,7 @@ import (
 	"github.com/google/go-github/v62/github"
 	"golang.org/x/oauth2"
 
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/git"
 )
 
@@ -133,12 +134,12 @@ func getGitHubToken() (string, error) {
 	// Try gh CLI
 	output, err := git.RunGHCommandWithContext(context.Background(), "auth", "token")
 	if err != nil {
-		return "", fmt.Errorf("failed to get GitHub token: %w", err)
+		return "", stackiterrors.WithCategory(stackiterrors.ErrRemoteAuth, fmt.Errorf("failed to get GitHub token: %w", err))
 	}
 
 	token := strings.TrimSpace(output)
 	if token == "" {
-		return "", fmt.Errorf("empty GitHub token")
+		return "", stackiterrors.WithCategory(stackiterrors.ErrRemoteAuth, fmt.Errorf("empty GitHub token"))
 	}
 
 	return token, nil
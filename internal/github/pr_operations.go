@@ -34,6 +34,8 @@ type CreatePROptions struct {
 	Draft         bool
 	Reviewers     []string
 	TeamReviewers []string
+	Labels        []string
+	Milestone     string
 }
 
 // UpdatePROptions contains options for updating a pull request
@@ -46,6 +48,9 @@ type UpdatePROptions struct {
 	TeamReviewers   []string
 	MergeWhenReady  *bool
 	RerequestReview bool
+	Labels          []string
+	ReplaceLabels   bool // Replace the PR's labels with Labels instead of adding missing ones
+	Milestone       *string
 }
 
 // CreatePullRequest creates a new pull request
@@ -74,6 +79,14 @@ func CreatePullRequest(ctx context.Context, client *github.Client, owner, repo s
 		})
 	}
 
+	var milestone *string
+	if opts.Milestone != "" {
+		milestone = &opts.Milestone
+	}
+	if err := applyLabelsAndMilestone(ctx, client, owner, repo, *createdPR.Number, opts.Labels, false, milestone); err != nil {
+		return createdPR, fmt.Errorf("%w: %w", ErrPRSetupIncomplete, err)
+	}
+
 	return createdPR, nil
 }
 
@@ -159,12 +172,69 @@ func UpdatePullRequest(ctx context.Context, client *github.Client, owner, repo s
 		}
 	}
 
+	if err := applyLabelsAndMilestone(ctx, client, owner, repo, prNumber, opts.Labels, opts.ReplaceLabels, opts.Milestone); err != nil {
+		return err
+	}
+
 	// Merge when ready (this is typically handled via GitHub's auto-merge feature)
 	// For now, we'll skip this as it requires additional API calls and permissions
 
 	return nil
 }
 
+// applyLabelsAndMilestone adds labels to a PR (or replaces its labels when replaceLabels
+// is set) and assigns it to the milestone with the given title, if any.
+// PRs are issues as far as the GitHub API is concerned, so this uses the issues endpoints.
+func applyLabelsAndMilestone(ctx context.Context, client *github.Client, owner, repo string, prNumber int, labels []string, replaceLabels bool, milestone *string) error {
+	if replaceLabels {
+		if _, _, err := client.Issues.ReplaceLabelsForIssue(ctx, owner, repo, prNumber, labels); err != nil {
+			return fmt.Errorf("failed to replace labels on PR %d: %w", prNumber, err)
+		}
+	} else if len(labels) > 0 {
+		// Adding is idempotent, so labels already on the PR are left untouched
+		if _, _, err := client.Issues.AddLabelsToIssue(ctx, owner, repo, prNumber, labels); err != nil {
+			return fmt.Errorf("failed to add labels to PR %d: %w", prNumber, err)
+		}
+	}
+
+	if milestone == nil || *milestone == "" {
+		return nil
+	}
+
+	milestoneNumber, err := findMilestoneNumber(ctx, client, owner, repo, *milestone)
+	if err != nil {
+		return err
+	}
+	if _, _, err := client.Issues.Edit(ctx, owner, repo, prNumber, &github.IssueRequest{Milestone: &milestoneNumber}); err != nil {
+		return fmt.Errorf("failed to set milestone on PR %d: %w", prNumber, err)
+	}
+	return nil
+}
+
+// findMilestoneNumber looks up an open milestone by title (case-insensitive)
+func findMilestoneNumber(ctx context.Context, client *github.Client, owner, repo, title string) (int, error) {
+	opts := &github.MilestoneListOptions{
+		State:       "open",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		milestones, resp, err := client.Issues.ListMilestones(ctx, owner, repo, opts)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list milestones: %w", err)
+		}
+		for _, m := range milestones {
+			if m.Title != nil && m.Number != nil && strings.EqualFold(*m.Title, title) {
+				return *m.Number, nil
+			}
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return 0, fmt.Errorf("milestone %q not found", title)
+}
+
 // GetPullRequestByBranch gets a pull request for a branch
 func GetPullRequestByBranch(ctx context.Context, client *github.Client, owner, repo, branchName string) (*github.PullRequest, error) {
 	// List PRs for this branch
@@ -365,6 +435,71 @@ func GetPRChecksStatus(ctx context.Context, client *github.Client, owner, repo,
 
 // updatePRDraftStatus updates the draft status of a PR using GitHub's GraphQL API
 func updatePRDraftStatus(ctx context.Context, pullRequestID string, isDraft bool) error {
+	// Determine which mutation to use
+	var mutation string
+	var mutationName string
+	if isDraft {
+		mutationName = "convertPullRequestToDraft"
+		mutation = `mutation ConvertPullRequestToDraft($pullRequestId: ID!) {
+			convertPullRequestToDraft(input: {pullRequestId: $pullRequestId}) {
+				pullRequest {
+					id
+					isDraft
+				}
+			}
+		}`
+	} else {
+		mutationName = "markPullRequestReadyForReview"
+		mutation = `mutation MarkPullRequestReadyForReview($pullRequestId: ID!) {
+			markPullRequestReadyForReview(input: {pullRequestId: $pullRequestId}) {
+				pullRequest {
+					id
+					isDraft
+				}
+			}
+		}`
+	}
+
+	return runGraphQLMutation(ctx, mutationName, mutation, map[string]interface{}{
+		"pullRequestId": pullRequestID,
+	})
+}
+
+// enablePRAutoMerge enables auto-merge on a PR using GitHub's GraphQL API
+func enablePRAutoMerge(ctx context.Context, pullRequestID string, method AutoMergeMethod) error {
+	mutation := `mutation EnablePullRequestAutoMerge($pullRequestId: ID!, $mergeMethod: PullRequestMergeMethod!) {
+		enablePullRequestAutoMerge(input: {pullRequestId: $pullRequestId, mergeMethod: $mergeMethod}) {
+			pullRequest {
+				id
+			}
+		}
+	}`
+
+	err := runGraphQLMutation(ctx, "enablePullRequestAutoMerge", mutation, map[string]interface{}{
+		"pullRequestId": pullRequestID,
+		"mergeMethod":   string(method),
+	})
+	if err != nil {
+		message := strings.ToLower(err.Error())
+		switch {
+		case strings.Contains(message, "auto merge is not allowed"):
+			return ErrAutoMergeNotAllowed
+		case strings.Contains(message, "clean status"):
+			return ErrAutoMergeNotNeeded
+		}
+	}
+	return err
+}
+
+// runGraphQLMutation runs a mutation against the repository's GitHub GraphQL API
+func runGraphQLMutation(ctx context.Context, mutationName, mutation string, variables map[string]interface{}) error {
+	return runGraphQL(ctx, mutationName+" mutation", mutation, variables, nil)
+}
+
+// runGraphQL runs a query or mutation against the repository's GitHub GraphQL API,
+// decoding the response's data into out unless it is nil. requestName names the request
+// in errors.
+func runGraphQL(ctx context.Context, requestName, query string, variables map[string]interface{}, out interface{}) error {
 	// Get GitHub token
 	token, err := getGitHubToken()
 	if err != nil {
@@ -392,37 +527,10 @@ func updatePRDraftStatus(ctx context.Context, pullRequestID string, isDraft bool
 	)
 	httpClient := oauth2.NewClient(ctx, ts)
 
-	// Determine which mutation to use
-	var mutation string
-	var mutationName string
-	if isDraft {
-		mutationName = "convertPullRequestToDraft"
-		mutation = `mutation ConvertPullRequestToDraft($pullRequestId: ID!) {
-			convertPullRequestToDraft(input: {pullRequestId: $pullRequestId}) {
-				pullRequest {
-					id
-					isDraft
-				}
-			}
-		}`
-	} else {
-		mutationName = "markPullRequestReadyForReview"
-		mutation = `mutation MarkPullRequestReadyForReview($pullRequestId: ID!) {
-			markPullRequestReadyForReview(input: {pullRequestId: $pullRequestId}) {
-				pullRequest {
-					id
-					isDraft
-				}
-			}
-		}`
-	}
-
 	// Prepare GraphQL request
 	requestBody := map[string]interface{}{
-		"query": mutation,
-		"variables": map[string]interface{}{
-			"pullRequestId": pullRequestID,
-		},
+		"query":     query,
+		"variables": variables,
 	}
 
 	jsonData, err := json.Marshal(requestBody)
@@ -458,7 +566,7 @@ func updatePRDraftStatus(ctx context.Context, pullRequestID string, isDraft bool
 
 	// Parse response to check for GraphQL errors
 	var graphqlResponse struct {
-		Data   interface{} `json:"data"`
+		Data   json.RawMessage `json:"data"`
 		Errors []struct {
 			Message string `json:"message"`
 		} `json:"errors"`
@@ -473,8 +581,13 @@ func updatePRDraftStatus(ctx context.Context, pullRequestID string, isDraft bool
 		for i, err := range graphqlResponse.Errors {
 			errorMessages[i] = err.Message
 		}
-		return fmt.Errorf("GraphQL %s mutation failed: %s", mutationName, strings.Join(errorMessages, "; "))
+		return fmt.Errorf("GraphQL %s failed: %s", requestName, strings.Join(errorMessages, "; "))
 	}
 
+	if out != nil {
+		if err := json.Unmarshal(graphqlResponse.Data, out); err != nil {
+			return fmt.Errorf("failed to parse GraphQL %s data: %w", requestName, err)
+		}
+	}
 	return nil
 }
@@ -0,0 +1,52 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	stackiterrors "stackit.dev/stackit/internal/errors"
+	"stackit.dev/stackit/internal/git"
+)
+
+// The CLI check result is cached for the life of the process
+var (
+	cliCheckMu  sync.Mutex
+	cliChecked  bool
+	cliCheckErr error
+)
+
+// CheckCLIAuth verifies that stackit can authenticate with GitHub, either through GITHUB_TOKEN
+// or a logged-in GitHub CLI. The error says what to install or run, so commands can fail with
+// it before pushing anything rather than deep inside a GitHub call.
+func CheckCLIAuth(ctx context.Context) error {
+	cliCheckMu.Lock()
+	defer cliCheckMu.Unlock()
+
+	if !cliChecked {
+		cliCheckErr = checkCLIAuth(ctx)
+		cliChecked = true
+	}
+	return cliCheckErr
+}
+
+func checkCLIAuth(ctx context.Context) error {
+	if os.Getenv("GITHUB_TOKEN") != "" {
+		return nil
+	}
+
+	if _, err := exec.LookPath("gh"); err != nil {
+		return stackiterrors.WithCategory(stackiterrors.ErrRemoteAuth,
+			fmt.Errorf("GitHub CLI (gh) not found: install it from https://cli.github.com and run `gh auth login`, or set GITHUB_TOKEN"))
+	}
+
+	output, err := git.RunGHCommandWithContext(ctx, "auth", "token")
+	if err != nil || strings.TrimSpace(output) == "" {
+		return stackiterrors.WithCategory(stackiterrors.ErrRemoteAuth,
+			fmt.Errorf("GitHub CLI (gh) is not logged in: run `gh auth login`, or set GITHUB_TOKEN"))
+	}
+	return nil
+}
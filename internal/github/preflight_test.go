@@ -0,0 +1,76 @@
+package github
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	stackiterrors "stackit.dev/stackit/internal/errors"
+)
+
+// useFakeGH puts a gh stub running the given shell script first on PATH (or an empty PATH when
+// script is empty) and clears the cached check
+func useFakeGH(t *testing.T, script string) {
+	t.Helper()
+	dir := t.TempDir()
+	if script != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "gh"), []byte("#!/bin/sh\n"+script+"\n"), 0o755))
+	}
+	t.Setenv("PATH", dir)
+	t.Setenv("GITHUB_TOKEN", "")
+
+	resetCLICheck()
+	t.Cleanup(resetCLICheck)
+}
+
+func resetCLICheck() {
+	cliCheckMu.Lock()
+	defer cliCheckMu.Unlock()
+	cliChecked = false
+	cliCheckErr = nil
+}
+
+func TestCheckCLIAuth(t *testing.T) {
+	t.Run("reports a missing gh binary", func(t *testing.T) {
+		useFakeGH(t, "")
+
+		err := CheckCLIAuth(context.Background())
+		require.ErrorIs(t, err, stackiterrors.ErrRemoteAuth)
+		require.ErrorContains(t, err, "GitHub CLI (gh) not found")
+		require.ErrorContains(t, err, "gh auth login")
+	})
+
+	t.Run("reports a logged-out gh", func(t *testing.T) {
+		useFakeGH(t, `echo "You are not logged into any GitHub hosts." >&2; exit 1`)
+
+		err := CheckCLIAuth(context.Background())
+		require.ErrorIs(t, err, stackiterrors.ErrRemoteAuth)
+		require.ErrorContains(t, err, "not logged in: run `gh auth login`")
+	})
+
+	t.Run("passes with GITHUB_TOKEN and no gh", func(t *testing.T) {
+		useFakeGH(t, "")
+		t.Setenv("GITHUB_TOKEN", "token")
+
+		require.NoError(t, CheckCLIAuth(context.Background()))
+	})
+
+	t.Run("caches the result for the process", func(t *testing.T) {
+		useFakeGH(t, "echo gho_token")
+		require.NoError(t, CheckCLIAuth(context.Background()))
+
+		// Logging out afterwards isn't noticed
+		require.NoError(t, os.Remove(filepath.Join(os.Getenv("PATH"), "gh")))
+		require.NoError(t, CheckCLIAuth(context.Background()))
+	})
+
+	t.Run("NewRealGitHubClient returns the guidance", func(t *testing.T) {
+		useFakeGH(t, "exit 1")
+
+		_, err := NewRealGitHubClient(context.Background())
+		require.ErrorContains(t, err, "run `gh auth login`")
+	})
+}
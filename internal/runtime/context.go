@@ -6,9 +6,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"stackit.dev/stackit/internal/config"
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/git"
 	"stackit.dev/stackit/internal/github"
 	"stackit.dev/stackit/internal/tui"
@@ -24,22 +26,25 @@ type Context struct {
 	GitHubClient github.Client
 }
 
+// newSplog creates the splog for a command, teeing output to the log file unless
+// STACKIT_NO_LOGGING is set (e.g., during tests or CI). A log file requested explicitly
+// with --log-file or STACKIT_LOG_FILE is always written.
+func newSplog() *tui.Splog {
+	if os.Getenv("STACKIT_NO_LOGGING") != "" && !tui.HasExplicitLogFile() {
+		return tui.NewSplog() // Console-only logging
+	}
+
+	splog, err := tui.NewSplogWithConfig(tui.GetLogFilePath(), "")
+	if err != nil {
+		// If file logging fails, fall back to console-only
+		splog, _ = tui.NewSplogWithConfig("", "")
+	}
+	return splog
+}
+
 // NewContext creates a new context with the given engine
 func NewContext(eng engine.Engine) *Context {
-	var splog *tui.Splog
-	var err error
-
-	// Skip file logging when STACKIT_NO_LOGGING is set (e.g., during tests or CI)
-	if os.Getenv("STACKIT_NO_LOGGING") != "" {
-		splog = tui.NewSplog() // Console-only logging
-	} else {
-		logPath := tui.GetLogFilePath()
-		splog, err = tui.NewSplogWithConfig(logPath, "")
-		if err != nil {
-			// If file logging fails, fall back to console-only
-			splog, _ = tui.NewSplogWithConfig("", "")
-		}
-	}
+	splog := newSplog()
 
 	return &Context{
 		Context: context.Background(),
@@ -50,20 +55,7 @@ func NewContext(eng engine.Engine) *Context {
 
 // NewContextWithRepoRoot creates a new context with the given engine and repo root
 func NewContextWithRepoRoot(eng engine.Engine, repoRoot string) *Context {
-	var splog *tui.Splog
-	var err error
-
-	// Skip file logging when STACKIT_NO_LOGGING is set (e.g., during tests or CI)
-	if os.Getenv("STACKIT_NO_LOGGING") != "" {
-		splog = tui.NewSplog() // Console-only logging
-	} else {
-		logPath := tui.GetLogFilePath()
-		splog, err = tui.NewSplogWithConfig(logPath, "")
-		if err != nil {
-			// If file logging fails, fall back to console-only
-			splog, _ = tui.NewSplogWithConfig("", "")
-		}
-	}
+	splog := newSplog()
 
 	return &Context{
 		Context:  context.Background(),
@@ -73,6 +65,15 @@ func NewContextWithRepoRoot(eng engine.Engine, repoRoot string) *Context {
 	}
 }
 
+// trunkOverride is the branch given with --use-trunk, used as trunk for this invocation only
+var trunkOverride string
+
+// SetTrunkOverride makes contexts created by this process use branchName as trunk instead
+// of the configured one, e.g. to stack onto a release branch. The persisted trunk is unchanged.
+func SetTrunkOverride(branchName string) {
+	trunkOverride = branchName
+}
+
 // DemoEngineFactory is a function that creates a demo engine.
 // This is set by the demo package to avoid circular imports.
 var DemoEngineFactory func() engine.Engine
@@ -101,13 +102,26 @@ func NewContextAuto(ctx context.Context, repoRoot string) (*Context, error) {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 	trunk := cfg.Trunk()
+	if trunkOverride != "" {
+		if _, err := git.GetRef("refs/heads/" + trunkOverride); err != nil {
+			return nil, stackiterrors.NewValidationError("trunk override %s is not a local branch", trunkOverride)
+		}
+		trunk = trunkOverride
+	}
+	git.SetCommandTimeouts(cfg.GitTimeoutLocal(), cfg.GitTimeoutNetwork())
 	maxUndoDepth := cfg.UndoStackDepth()
+	restackStrategy := engine.RestackStrategy(cfg.RestackStrategy())
 
 	// Create real engine
 	eng, err := engine.NewEngine(engine.Options{
 		RepoRoot:          repoRoot,
 		Trunk:             trunk,
 		MaxUndoStackDepth: maxUndoDepth,
+		RestackStrategy:   restackStrategy,
+		PreserveDates:     cfg.RestackPreserveDates(),
+		PruneEmpty:        engine.PruneEmptyMode(cfg.RestackPruneEmpty()),
+		PostRestackHook:   cfg.RestackPostHook(),
+		TrunkStrategy:     engine.TrunkStrategy(cfg.SyncTrunkStrategy()),
 	})
 	if err != nil {
 		return nil, err
@@ -115,6 +129,13 @@ func NewContextAuto(ctx context.Context, repoRoot string) (*Context, error) {
 
 	runtimeCtx := NewContextWithRepoRoot(eng, repoRoot)
 	runtimeCtx.Context = ctx
+	for _, warning := range cfg.Warnings() {
+		runtimeCtx.Splog.Warn("%s", warning)
+	}
+	if newer := eng.NewerMetadataBranches(); len(newer) > 0 {
+		runtimeCtx.Splog.Warn("The metadata of %s was written by a newer version of stackit. Upgrade stackit to change these branches; this version won't write their metadata.",
+			strings.Join(newer, ", "))
+	}
 
 	// Try to create real GitHub client (may fail if no token)
 	ghClient, err := github.NewRealGitHubClient(ctx)
@@ -134,8 +155,8 @@ func GetContext(ctx context.Context) (*Context, error) {
 	}
 
 	// Initialize git repository
-	if err := git.InitDefaultRepo(); err != nil {
-		return nil, fmt.Errorf("not a git repository: %w", err)
+	if err := git.EnsureRepository(); err != nil {
+		return nil, err
 	}
 
 	// Get repo root
@@ -150,8 +171,14 @@ func GetContext(ctx context.Context) (*Context, error) {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 	if !cfg.IsInitialized() {
-		return nil, fmt.Errorf("stackit not initialized. Run 'stackit init' first")
+		return nil, stackiterrors.ErrNotInitialized
 	}
 
-	return NewContextAuto(ctx, repoRoot)
+	runtimeCtx, err := NewContextAuto(ctx, repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	// Record the invocation so the log file shows which command produced what follows
+	runtimeCtx.Splog.Debug("Running stackit %s in %s", strings.Join(os.Args[1:], " "), repoRoot)
+	return runtimeCtx, nil
 }
@@ -38,6 +38,9 @@ type BranchAnnotation struct {
 	LinesAdded   int
 	LinesDeleted int
 	PRState      string // "OPEN", "MERGED", "CLOSED"
+
+	Commits     []string // One-line commits listed under the branch, newest first
+	MoreCommits int      // Commits left out of Commits by a per-branch cap
 }
 
 // RenderOptions configures rendering behavior
@@ -48,6 +51,7 @@ type RenderOptions struct {
 	OmitCurrentBranch bool
 	NoStyleBranchName bool
 	HideStats         bool
+	HideMerged        bool // Omit merged branches, attaching their children to the nearest visible ancestor
 }
 
 // StackTreeRenderer renders branch trees with annotations
@@ -58,6 +62,7 @@ type StackTreeRenderer struct {
 	getParent     func(branchName string) string
 	isTrunk       func(branchName string) bool
 	isBranchFixed func(branchName string) bool
+	isMerged      func(branchName string) bool
 	Annotations   map[string]BranchAnnotation
 }
 
@@ -91,6 +96,12 @@ func (r *StackTreeRenderer) SetAnnotations(annotations map[string]BranchAnnotati
 	r.Annotations = annotations
 }
 
+// SetMergedPredicate sets the function that reports whether a branch has been merged.
+// Merged branches are dimmed, or omitted entirely when RenderOptions.HideMerged is set.
+func (r *StackTreeRenderer) SetMergedPredicate(isMerged func(branchName string) bool) {
+	r.isMerged = isMerged
+}
+
 // RenderStack renders the full stack tree starting from a branch
 func (r *StackTreeRenderer) RenderStack(branchName string, opts RenderOptions) []string {
 	overallIndent := 0
@@ -104,6 +115,7 @@ func (r *StackTreeRenderer) RenderStack(branchName string, opts RenderOptions) [
 		omitCurrentBranch: opts.OmitCurrentBranch,
 		noStyleBranchName: opts.NoStyleBranchName,
 		hideStats:         opts.HideStats,
+		hideMerged:        opts.HideMerged,
 		overallIndent:     &overallIndent,
 	}
 
@@ -144,6 +156,7 @@ type treeRenderArgs struct {
 	omitCurrentBranch bool
 	noStyleBranchName bool
 	hideStats         bool
+	hideMerged        bool
 	skipBranchingLine bool
 	overallIndent     *int
 }
@@ -153,7 +166,7 @@ func (r *StackTreeRenderer) getUpstackExclusiveLines(args treeRenderArgs) []stri
 		return []string{}
 	}
 
-	children := r.getChildren(args.branchName)
+	children := r.getVisibleChildren(args.branchName, args.hideMerged)
 
 	// Filter out current branch if needed
 	filteredChildren := []string{}
@@ -199,6 +212,7 @@ func (r *StackTreeRenderer) getUpstackExclusiveLines(args treeRenderArgs) []stri
 			omitCurrentBranch: args.omitCurrentBranch,
 			noStyleBranchName: args.noStyleBranchName,
 			hideStats:         args.hideStats,
+			hideMerged:        args.hideMerged,
 			overallIndent:     args.overallIndent,
 		})
 
@@ -237,7 +251,7 @@ func (r *StackTreeRenderer) getDownstackExclusiveLines(args treeRenderArgs) []st
 	var fullStack []string
 	current := args.branchName
 	for {
-		parent := r.getParent(current)
+		parent := r.getVisibleParent(current, args.hideMerged)
 		if parent == "" || r.isTrunk(parent) {
 			break
 		}
@@ -265,6 +279,7 @@ func (r *StackTreeRenderer) getDownstackExclusiveLines(args treeRenderArgs) []st
 			branchName:        branchName,
 			indentLevel:       args.indentLevel,
 			parentScopes:      args.parentScopes,
+			hideMerged:        args.hideMerged,
 			skipBranchingLine: true,
 			overallIndent:     args.overallIndent,
 		})
@@ -282,7 +297,7 @@ func (r *StackTreeRenderer) getDownstackExclusiveLines(args treeRenderArgs) []st
 }
 
 func (r *StackTreeRenderer) getBranchLines(args treeRenderArgs) []string {
-	children := r.getChildren(args.branchName)
+	children := r.getVisibleChildren(args.branchName, args.hideMerged)
 	numChildren := len(children)
 
 	if args.overallIndent != nil {
@@ -414,7 +429,7 @@ func (r *StackTreeRenderer) getInfoLines(args treeRenderArgs) []string {
 	isTrunk := r.isTrunk(args.branchName)
 	isMerged := annotation.PRState == PRStateMerged
 	isClosed := annotation.PRState == PRStateClosed
-	isDim := isMerged || isClosed
+	isDim := isMerged || isClosed || r.isMergedBranch(args.branchName)
 
 	// Get branch info with colors
 	branchName := args.branchName
@@ -466,7 +481,7 @@ func (r *StackTreeRenderer) getInfoLines(args treeRenderArgs) []string {
 	// Style for the vertical line below the symbol (connecting to parent)
 	// It should use the parent's scope color, not the branch's own scope.
 	parentScope := ""
-	if parent := r.getParent(args.branchName); parent != "" {
+	if parent := r.getVisibleParent(args.branchName, args.hideMerged); parent != "" {
 		parentScope = r.Annotations[parent].Scope
 	}
 	parentStyle := lipgloss.NewStyle()
@@ -481,12 +496,58 @@ func (r *StackTreeRenderer) getInfoLines(args treeRenderArgs) []string {
 
 	result = append(result, prefix+styleObj.Render(symbol)+" "+coloredBranchName)
 
+	// Add the branch's commits, indented under it
+	for _, commit := range annotation.Commits {
+		result = append(result, prefix+parentStyle.Render("│")+"   "+style.ColorDim(commit))
+	}
+	if annotation.MoreCommits > 0 {
+		result = append(result, prefix+parentStyle.Render("│")+"   "+style.ColorDim(fmt.Sprintf("… %d more", annotation.MoreCommits)))
+	}
+
 	// Add trailing line
 	result = append(result, prefix+parentStyle.Render("│"))
 
 	return result
 }
 
+// isMergedBranch reports whether the merged predicate considers a branch merged. Trunk is never merged.
+func (r *StackTreeRenderer) isMergedBranch(branchName string) bool {
+	return r.isMerged != nil && !r.isTrunk(branchName) && r.isMerged(branchName)
+}
+
+// isHidden reports whether a branch is omitted from the tree. The current branch is always shown.
+func (r *StackTreeRenderer) isHidden(branchName string, hideMerged bool) bool {
+	return hideMerged && branchName != r.currentBranch && r.isMergedBranch(branchName)
+}
+
+// getVisibleChildren returns the children drawn under a branch. The children of a hidden
+// branch take its place, so they connect to the nearest visible ancestor.
+func (r *StackTreeRenderer) getVisibleChildren(branchName string, hideMerged bool) []string {
+	children := r.getChildren(branchName)
+	if !hideMerged {
+		return children
+	}
+
+	visible := []string{}
+	for _, child := range children {
+		if r.isHidden(child, hideMerged) {
+			visible = append(visible, r.getVisibleChildren(child, hideMerged)...)
+		} else {
+			visible = append(visible, child)
+		}
+	}
+	return visible
+}
+
+// getVisibleParent returns the nearest ancestor of a branch that is drawn in the tree
+func (r *StackTreeRenderer) getVisibleParent(branchName string, hideMerged bool) string {
+	parent := r.getParent(branchName)
+	for parent != "" && r.isHidden(parent, hideMerged) {
+		parent = r.getParent(parent)
+	}
+	return parent
+}
+
 func (r *StackTreeRenderer) formatAnnotation(annotation BranchAnnotation, _ bool) string {
 	var parts []string
 
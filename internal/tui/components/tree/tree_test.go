@@ -574,3 +574,92 @@ func TestStackTreeRenderer_ScopeColoringBoundaries(t *testing.T) {
 		t.Error("scoped-branch symbol should be colored")
 	}
 }
+
+func TestStackTreeRenderer_HideMerged(t *testing.T) {
+	// main -> a -> b (merged) -> {c, d}
+	mock := &MockTreeData{
+		CurrentBranch: "c",
+		Trunk:         "main",
+		Children: map[string][]string{
+			"main": {"a"},
+			"a":    {"b"},
+			"b":    {"c", "d"},
+		},
+		Parents: map[string]string{
+			"a": "main",
+			"b": "a",
+			"c": "b",
+			"d": "b",
+		},
+		Fixed: map[string]bool{
+			"main": true,
+			"a":    true,
+			"b":    true,
+			"c":    true,
+			"d":    true,
+		},
+	}
+
+	renderer := NewStackTreeRenderer(
+		mock.CurrentBranch,
+		mock.Trunk,
+		mock.GetChildren,
+		mock.GetParent,
+		mock.IsTrunk,
+		mock.IsBranchFixed,
+	)
+	renderer.SetMergedPredicate(func(branchName string) bool { return branchName == "b" })
+
+	// findBranchLine returns the index of the line drawing a branch, or -1
+	findBranchLine := func(lines []string, branchName string) int {
+		name := style.ColorBranchName(branchName, branchName == mock.CurrentBranch)
+		for i, line := range lines {
+			if strings.Contains(line, name) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	t.Run("dims merged branches by default", func(t *testing.T) {
+		lines := renderer.RenderStack("main", RenderOptions{HideStats: true})
+		idx := findBranchLine(lines, "b")
+		if idx == -1 {
+			t.Fatalf("expected merged branch b to be shown, got:\n%s", strings.Join(lines, "\n"))
+		}
+		dimSymbol := lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render(BranchSymbol)
+		if !strings.HasPrefix(lines[idx], dimSymbol) {
+			t.Errorf("expected merged branch b to be dimmed, got: %q", lines[idx])
+		}
+	})
+
+	t.Run("omits merged branches and reattaches their children", func(t *testing.T) {
+		lines := renderer.RenderStack("main", RenderOptions{HideStats: true, HideMerged: true})
+		output := strings.Join(lines, "\n")
+
+		if findBranchLine(lines, "b") != -1 {
+			t.Errorf("expected merged branch b to be hidden, got:\n%s", output)
+		}
+		for _, branch := range []string{"main", "a", "c", "d"} {
+			if findBranchLine(lines, branch) == -1 {
+				t.Errorf("expected branch %s to be shown, got:\n%s", branch, output)
+			}
+		}
+
+		// c and d now branch directly from a, the nearest visible ancestor
+		aIdx := findBranchLine(lines, "a")
+		if aIdx < 1 || !strings.Contains(lines[aIdx-1], "├──┘") {
+			t.Errorf("expected c and d to branch from a, got:\n%s", output)
+		}
+	})
+
+	t.Run("always shows the current branch", func(t *testing.T) {
+		renderer.SetMergedPredicate(func(branchName string) bool { return branchName == "c" })
+		defer renderer.SetMergedPredicate(func(branchName string) bool { return branchName == "b" })
+
+		lines := renderer.RenderStack("main", RenderOptions{HideStats: true, HideMerged: true})
+		if findBranchLine(lines, "c") == -1 {
+			t.Errorf("expected current branch c to be shown, got:\n%s", strings.Join(lines, "\n"))
+		}
+	})
+}
@@ -1,15 +1,57 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
+
+	"stackit.dev/stackit/internal/git"
 )
 
+// ErrEditorDisabled is returned by OpenEditor when --no-editor is set
+var ErrEditorDisabled = errors.New("the editor is disabled by --no-editor")
+
+// editorDisabled is set by --no-editor so commands use their non-interactive defaults
+var editorDisabled bool
+
+// SetEditorDisabled turns the editor off for this process, as with --no-editor, including
+// the one git opens for commit messages
+func SetEditorDisabled(disabled bool) {
+	editorDisabled = disabled
+	git.SetEditorDisabled(disabled)
+}
+
+// EditorDisabled reports whether --no-editor was given
+func EditorDisabled() bool {
+	return editorDisabled
+}
+
+// ResolveEditor returns the editor command to run, taken from $GIT_EDITOR, $EDITOR,
+// and git's core.editor in that order, falling back to vi
+func ResolveEditor() string {
+	if editor := os.Getenv("GIT_EDITOR"); editor != "" {
+		return editor
+	}
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		return editor
+	}
+	if output, err := exec.Command("git", "config", "--get", "core.editor").Output(); err == nil {
+		if editor := strings.TrimSpace(string(output)); editor != "" {
+			return editor
+		}
+	}
+	return "vi"
+}
+
 // OpenEditor opens the user's preferred editor with the given initial content.
-// It returns the edited content or an error.
+// It returns the edited content or an error, or ErrEditorDisabled with --no-editor.
 func OpenEditor(initialContent, filenamePattern string) (string, error) {
+	if editorDisabled {
+		return "", ErrEditorDisabled
+	}
+
 	// Create temporary file
 	tmpFile, err := os.CreateTemp("", filenamePattern)
 	if err != nil {
@@ -25,24 +67,8 @@ func OpenEditor(initialContent, filenamePattern string) (string, error) {
 		return "", fmt.Errorf("failed to close temp file: %w", err)
 	}
 
-	// Get editor from environment
-	editor := os.Getenv("GIT_EDITOR")
-	if editor == "" {
-		editor = os.Getenv("EDITOR")
-	}
-	if editor == "" {
-		// Try to get from git config
-		output, err := exec.Command("git", "config", "--get", "core.editor").Output()
-		if err == nil {
-			editor = strings.TrimSpace(string(output))
-		}
-	}
-	if editor == "" {
-		editor = "vi" // Default to vi
-	}
-
 	// Open editor
-	cmd := exec.Command("sh", "-c", fmt.Sprintf("%s %s", editor, tmpFile.Name()))
+	cmd := exec.Command("sh", "-c", fmt.Sprintf("%s %s", ResolveEditor(), tmpFile.Name()))
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveEditor(t *testing.T) {
+	// A global git config with core.editor set, and no system config to interfere
+	gitConfig := filepath.Join(t.TempDir(), "gitconfig")
+	require.NoError(t, os.WriteFile(gitConfig, []byte("[core]\n\teditor = from-core-editor\n"), 0o600))
+	t.Setenv("GIT_CONFIG_GLOBAL", gitConfig)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Chdir(t.TempDir())
+
+	t.Setenv("GIT_EDITOR", "from-git-editor")
+	t.Setenv("EDITOR", "from-editor")
+	require.Equal(t, "from-git-editor", ResolveEditor())
+
+	t.Setenv("GIT_EDITOR", "")
+	require.Equal(t, "from-editor", ResolveEditor())
+
+	t.Setenv("EDITOR", "")
+	require.Equal(t, "from-core-editor", ResolveEditor())
+
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "missing"))
+	require.Equal(t, "vi", ResolveEditor())
+}
+
+func TestOpenEditorDisabled(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "launched")
+	t.Setenv("GIT_EDITOR", "touch "+marker+" &&")
+
+	SetEditorDisabled(true)
+	t.Cleanup(func() { SetEditorDisabled(false) })
+
+	_, err := OpenEditor("content", "stackit-test-*")
+	require.ErrorIs(t, err, ErrEditorDisabled)
+	require.NoFileExists(t, marker)
+}
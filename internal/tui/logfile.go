@@ -6,10 +6,26 @@ import (
 	"path/filepath"
 )
 
+// logFileOverride is the path given with --log-file, which takes precedence over STACKIT_LOG_FILE
+var logFileOverride string
+
+// SetLogFilePath sets the log file path for this process, as given with --log-file
+func SetLogFilePath(path string) {
+	logFileOverride = path
+}
+
+// HasExplicitLogFile reports whether a log file was requested with --log-file or STACKIT_LOG_FILE
+func HasExplicitLogFile() bool {
+	return logFileOverride != "" || os.Getenv("STACKIT_LOG_FILE") != ""
+}
+
 // GetLogFilePath returns the path to the log file.
-// If STACKIT_LOG_FILE is set, uses that path.
+// If --log-file or STACKIT_LOG_FILE is set, uses that path.
 // Otherwise, uses ~/.stackit/logs/stackit.log
 func GetLogFilePath() string {
+	if logFileOverride != "" {
+		return logFileOverride
+	}
 	if customPath := os.Getenv("STACKIT_LOG_FILE"); customPath != "" {
 		return customPath
 	}
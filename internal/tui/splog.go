@@ -8,7 +8,10 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
+	"sync"
+	"sync/atomic"
 
 	"gopkg.in/natefinch/lumberjack.v2"
 )
@@ -17,7 +20,7 @@ import (
 type simpleHandler struct {
 	writer    io.Writer
 	debugMode bool
-	quiet     *bool // Pointer to quiet flag so it can be changed dynamically
+	quiet     *atomic.Bool // Pointer to quiet flag so it can be changed dynamically
 }
 
 func (h *simpleHandler) Enabled(_ context.Context, level slog.Level) bool {
@@ -30,7 +33,7 @@ func (h *simpleHandler) Enabled(_ context.Context, level slog.Level) bool {
 }
 
 func (h *simpleHandler) Handle(_ context.Context, record slog.Record) error {
-	if *h.quiet {
+	if h.quiet.Load() {
 		return nil // Suppress output when in quiet mode
 	}
 	_, err := fmt.Fprintln(h.writer, record.Message)
@@ -45,6 +48,41 @@ func (h *simpleHandler) WithGroup(_ string) slog.Handler {
 	return h
 }
 
+// ansiEscape matches terminal escape sequences (colors, styles, and OSC hyperlinks)
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// fileHandler writes every level to the log file as plain, timestamped lines with terminal
+// styling stripped. The mutex keeps lines whole when the TUI and the merge executor log
+// from different goroutines.
+type fileHandler struct {
+	mu     *sync.Mutex
+	writer io.Writer
+}
+
+func (h *fileHandler) Enabled(_ context.Context, _ slog.Level) bool {
+	return true // Always log everything to file
+}
+
+func (h *fileHandler) Handle(_ context.Context, record slog.Record) error {
+	line := fmt.Sprintf("%s %-5s %s\n",
+		record.Time.Format("2006-01-02 15:04:05.000"),
+		record.Level.String(),
+		ansiEscape.ReplaceAllString(record.Message, ""))
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.writer, line)
+	return err
+}
+
+func (h *fileHandler) WithAttrs(_ []slog.Attr) slog.Handler {
+	return h
+}
+
+func (h *fileHandler) WithGroup(_ string) slog.Handler {
+	return h
+}
+
 // createLumberjackLogger creates a lumberjack logger with configuration from environment variables
 func createLumberjackLogger(logFilePath string) *lumberjack.Logger {
 	config := &lumberjack.Logger{
@@ -124,9 +162,9 @@ func (h *multiHandler) WithGroup(name string) slog.Handler {
 type Splog struct {
 	logger     *slog.Logger
 	fileLogger *slog.Logger // Separate logger for file output
-	writer     *os.File
+	writer     io.Writer
 	logWriter  io.WriteCloser // Lumberjack logger for file logging
-	quiet      bool           // When true, suppresses all output (used during TUI mode)
+	quiet      atomic.Bool    // When true, suppresses all console output (used during TUI mode)
 }
 
 // NewSplog creates a new splog instance with console-only logging
@@ -136,13 +174,22 @@ func NewSplog() *Splog {
 	return splog
 }
 
+// NewSplogWithWriter creates a new splog instance that writes console output to w instead
+// of stdout, without file logging
+func NewSplogWithWriter(w io.Writer) *Splog {
+	splog, _ := newSplog(w, "")
+	return splog
+}
+
 // NewSplogWithConfig creates a new splog instance with optional file logging
 func NewSplogWithConfig(logFilePath string, _ string) (*Splog, error) {
-	writer := os.Stdout
+	return newSplog(os.Stdout, logFilePath)
+}
+
+func newSplog(writer io.Writer, logFilePath string) (*Splog, error) {
 	debugMode := os.Getenv("DEBUG") != ""
 	splog := &Splog{
 		writer: writer,
-		quiet:  false,
 	}
 
 	// Create console handler (existing behavior)
@@ -167,18 +214,7 @@ func NewSplogWithConfig(logFilePath string, _ string) (*Splog, error) {
 		lumberjackLogger := createLumberjackLogger(logFilePath)
 		splog.logWriter = lumberjackLogger
 
-		// Create file handler with timestamps
-		fileHandler := slog.NewTextHandler(lumberjackLogger, &slog.HandlerOptions{
-			Level: slog.LevelDebug, // Always log everything to file
-			ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
-				// Add timestamps to file logs
-				if a.Key == slog.TimeKey {
-					return slog.Attr{Key: a.Key, Value: slog.StringValue(a.Value.Time().Format("2006-01-02 15:04:05.000"))}
-				}
-				return a
-			},
-		})
-
+		fileHandler := &fileHandler{mu: &sync.Mutex{}, writer: lumberjackLogger}
 		handlers = append(handlers, fileHandler)
 		splog.fileLogger = slog.New(fileHandler)
 	}
@@ -193,12 +229,12 @@ func NewSplogWithConfig(logFilePath string, _ string) (*Splog, error) {
 // SetQuiet sets the quiet mode for the logger.
 // When quiet is true, all output is suppressed (used during TUI mode).
 func (s *Splog) SetQuiet(quiet bool) {
-	s.quiet = quiet
+	s.quiet.Store(quiet)
 }
 
 // IsQuiet returns whether the logger is in quiet mode.
 func (s *Splog) IsQuiet() bool {
-	return s.quiet
+	return s.quiet.Load()
 }
 
 // logMessage is a helper to log a message using slog without format string validation
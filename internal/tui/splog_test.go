@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplogLogFile(t *testing.T) {
+	t.Run("writes plain timestamped lines for every level", func(t *testing.T) {
+		logPath := filepath.Join(t.TempDir(), "stackit.log")
+		splog, err := NewSplogWithConfig(logPath, "")
+		require.NoError(t, err)
+		splog.SetQuiet(true) // Console output is suppressed; the file still gets everything
+
+		splog.Debug("debug %d", 1)
+		splog.Info("\x1b[32mgreen\x1b[0m branch")
+		splog.Warn("careful")
+		require.NoError(t, splog.Close())
+
+		data, err := os.ReadFile(logPath)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, lines, 3)
+		require.Regexp(t, `^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d{3} DEBUG debug 1$`, lines[0])
+		require.Regexp(t, `INFO  green branch$`, lines[1])
+		require.Regexp(t, `WARN  ⚠️  careful$`, lines[2])
+	})
+
+	t.Run("keeps lines whole when logging concurrently", func(t *testing.T) {
+		logPath := filepath.Join(t.TempDir(), "stackit.log")
+		splog, err := NewSplogWithConfig(logPath, "")
+		require.NoError(t, err)
+		splog.SetQuiet(true)
+
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 50; i++ {
+					splog.Debug("goroutine %d line %d", g, i)
+					splog.SetQuiet(i%2 == 0)
+				}
+			}()
+		}
+		wg.Wait()
+		require.NoError(t, splog.Close())
+
+		data, err := os.ReadFile(logPath)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, lines, 8*50)
+		line := regexp.MustCompile(`^\S+ \S+ DEBUG goroutine \d line \d+$`)
+		for _, l := range lines {
+			require.Regexp(t, line, l, "malformed line %q", l)
+		}
+	})
+}
+
+func TestSplogWithWriter(t *testing.T) {
+	var buf bytes.Buffer
+	splog := NewSplogWithWriter(&buf)
+
+	splog.Info("hello %s", "world")
+	splog.Warn("careful")
+	splog.Page("paged\n")
+	splog.SetQuiet(true)
+	splog.Info("suppressed")
+
+	require.Equal(t, "hello world\n⚠️  careful\npaged\n", buf.String())
+}
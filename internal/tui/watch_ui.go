@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// WatchRefreshFunc refreshes the watched state, returning the view to show and a line
+// for each change since the previous refresh
+type WatchRefreshFunc func() (view string, changes []string, err error)
+
+// watchRefreshedMsg carries the result of a refresh
+type watchRefreshedMsg struct {
+	view    string
+	changes []string
+	err     error
+	at      time.Time
+}
+
+// watchTickMsg starts the next refresh
+type watchTickMsg struct{}
+
+// watchModel is the bubbletea model that re-renders a view on an interval
+type watchModel struct {
+	title       string
+	interval    time.Duration
+	refresh     WatchRefreshFunc
+	view        string
+	changes     []string
+	err         error
+	refreshing  bool
+	lastUpdated time.Time
+	styles      watchStyles
+}
+
+type watchStyles struct {
+	title   lipgloss.Style
+	changed lipgloss.Style
+	err     lipgloss.Style
+	dim     lipgloss.Style
+}
+
+func newWatchModel(title string, interval time.Duration, refresh WatchRefreshFunc) watchModel {
+	return watchModel{
+		title:      title,
+		interval:   interval,
+		refresh:    refresh,
+		refreshing: true,
+		styles: watchStyles{
+			title:   lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39")),
+			changed: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214")),
+			err:     lipgloss.NewStyle().Foreground(lipgloss.Color("196")),
+			dim:     lipgloss.NewStyle().Foreground(lipgloss.Color("240")),
+		},
+	}
+}
+
+// runRefresh refreshes in the background so the UI stays responsive to quitting
+func (m watchModel) runRefresh() tea.Cmd {
+	return func() tea.Msg {
+		view, changes, err := m.refresh()
+		return watchRefreshedMsg{view: view, changes: changes, err: err, at: time.Now()}
+	}
+}
+
+func (m watchModel) Init() tea.Cmd {
+	return m.runRefresh()
+}
+
+func (m watchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case KeyCtrlC, KeyQuit, KeyEsc:
+			return m, tea.Quit
+		}
+
+	case watchRefreshedMsg:
+		m.refreshing = false
+		m.lastUpdated = msg.at
+		m.err = msg.err
+		if msg.err == nil {
+			m.view = msg.view
+			// Keep the last changes on screen until something else changes
+			if len(msg.changes) > 0 {
+				m.changes = msg.changes
+			}
+		}
+		return m, tea.Tick(m.interval, func(time.Time) tea.Msg { return watchTickMsg{} })
+
+	case watchTickMsg:
+		m.refreshing = true
+		return m, m.runRefresh()
+	}
+
+	return m, nil
+}
+
+func (m watchModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(m.styles.title.Render(m.title))
+	b.WriteString("\n\n")
+
+	if m.view != "" {
+		b.WriteString(m.view)
+		b.WriteString("\n")
+	}
+
+	if len(m.changes) > 0 {
+		b.WriteString("\n")
+		for _, change := range m.changes {
+			b.WriteString(m.styles.changed.Render("● " + change))
+			b.WriteString("\n")
+		}
+	}
+
+	if m.err != nil {
+		b.WriteString("\n")
+		b.WriteString(m.styles.err.Render(fmt.Sprintf("Refresh failed: %v", m.err)))
+		b.WriteString("\n")
+	}
+
+	status := "Refreshing..."
+	if !m.refreshing {
+		status = fmt.Sprintf("Updated %s", m.lastUpdated.Format("15:04:05"))
+	}
+	b.WriteString("\n")
+	b.WriteString(m.styles.dim.Render(fmt.Sprintf("%s • every %s • q/Esc: quit", status, m.interval)))
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// RunWatchTUI shows the view returned by refresh, calling it again every interval and
+// highlighting the changes it reports, until the user quits
+func RunWatchTUI(title string, interval time.Duration, refresh WatchRefreshFunc) error {
+	m := newWatchModel(title, interval, refresh)
+	p := tea.NewProgram(m, tea.WithInput(os.Stdin), tea.WithOutput(os.Stdout))
+	_, err := p.Run()
+	return err
+}
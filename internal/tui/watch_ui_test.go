@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchModel(t *testing.T) {
+	refreshes := 0
+	m := newWatchModel("watching", time.Minute, func() (string, []string, error) {
+		refreshes++
+		return "tree", nil, nil
+	})
+
+	// The first refresh runs straight away
+	msg := m.Init()()
+	require.Equal(t, 1, refreshes)
+
+	updated, cmd := m.Update(msg)
+	m = updated.(watchModel)
+	require.NotNil(t, cmd, "the next refresh should be scheduled")
+	require.Contains(t, m.View(), "tree")
+	require.Contains(t, m.View(), "every 1m0s")
+
+	// Changes stay highlighted until newer ones replace them
+	updated, _ = m.Update(watchRefreshedMsg{view: "tree", changes: []string{"feature: PR #1 is now MERGED (was OPEN)"}})
+	m = updated.(watchModel)
+	updated, _ = m.Update(watchRefreshedMsg{view: "tree"})
+	m = updated.(watchModel)
+	require.Contains(t, m.View(), "feature: PR #1 is now MERGED (was OPEN)")
+
+	// A failed refresh keeps the last view
+	updated, _ = m.Update(watchRefreshedMsg{err: errors.New("rate limited")})
+	m = updated.(watchModel)
+	require.Contains(t, m.View(), "tree")
+	require.Contains(t, m.View(), "Refresh failed: rate limited")
+
+	updated, cmd = m.Update(watchTickMsg{})
+	m = updated.(watchModel)
+	require.True(t, m.refreshing)
+	cmd()
+	require.Equal(t, 2, refreshes)
+
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	require.Equal(t, tea.Quit(), cmd())
+}
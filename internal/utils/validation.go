@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"stackit.dev/stackit/internal/engine"
+	stackiterrors "stackit.dev/stackit/internal/errors"
 	"stackit.dev/stackit/internal/git"
 )
 
@@ -26,7 +27,7 @@ func IsInteractive() bool {
 func ValidateOnBranch(engine engine.Engine) (string, error) {
 	currentBranch := engine.CurrentBranch()
 	if currentBranch == nil {
-		return "", fmt.Errorf("not on a branch")
+		return "", stackiterrors.ErrNotOnBranch
 	}
 	return currentBranch.GetName(), nil
 }
@@ -14,6 +14,7 @@ type SamplePRData struct {
 	HTMLURL       string
 	Draft         bool
 	State         string
+	Merged        bool
 	Reviewers     []string
 	TeamReviewers []string
 }
@@ -29,6 +30,7 @@ func NewSamplePullRequest(data SamplePRData) *github.PullRequest {
 		HTMLURL: github.String(data.HTMLURL),
 		Draft:   github.Bool(data.Draft),
 		State:   github.String(data.State),
+		Merged:  github.Bool(data.Merged),
 	}
 
 	if len(data.Reviewers) > 0 {
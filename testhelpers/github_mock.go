@@ -12,6 +12,8 @@ import (
 	"testing"
 
 	"github.com/google/go-github/v62/github"
+
+	githubpkg "stackit.dev/stackit/internal/github"
 )
 
 // MockGitHubServerConfig configures the behavior of a mock GitHub server
@@ -22,24 +24,70 @@ type MockGitHubServerConfig struct {
 	CreatedPRs []*github.PullRequest
 	// UpdatedPRs stores PRs that were updated (for testing)
 	UpdatedPRs map[int]*github.PullRequest
+	// Labels stores the labels applied to each PR number (for testing)
+	Labels map[int][]string
+	// Reviewers stores the user reviewers requested on each PR number (for testing)
+	Reviewers map[int][]string
+	// TeamReviewers stores the team reviewers requested on each PR number (for testing)
+	TeamReviewers map[int][]string
+	// Milestones stores the milestone title assigned to each PR number (for testing)
+	Milestones map[int]string
+	// Comments stores the comments posted on each PR number (for testing)
+	Comments map[int][]string
+	// AutoMergeMethods stores the method auto-merge was enabled with for each PR number (for testing)
+	AutoMergeMethods map[int]githubpkg.AutoMergeMethod
+	// AutoMergeNotAllowed makes EnableAutoMerge fail as it does on repos that don't allow auto-merge
+	AutoMergeNotAllowed bool
+	// LabelsUnavailable makes applying labels to a newly created PR fail, as it does when the
+	// token can't edit issues
+	LabelsUnavailable bool
+	// ChecksStatus maps branch names to the CI status returned by GetPRChecksStatus (passing if unset)
+	ChecksStatus map[string]*githubpkg.CheckStatus
+	// ChecksUnavailable makes GetPRChecksStatus fail for these branches, as it does when the
+	// status can't be fetched
+	ChecksUnavailable map[string]bool
+	// BatchPRStatusCalls counts GetBranchPRStatuses calls, and PRByBranchCalls counts
+	// GetPullRequestByBranch calls (for testing)
+	BatchPRStatusCalls int
+	PRByBranchCalls    int
+	// MaxPRByBranchInFlight is the most GetPullRequestByBranch calls that ran at once (for testing)
+	MaxPRByBranchInFlight int
+	// BatchPRStatusUnavailable makes GetBranchPRStatuses fail as it does when the GraphQL API
+	// can't be reached
+	BatchPRStatusUnavailable bool
 	// ErrorResponses maps endpoint+method to error responses
 	ErrorResponses map[string]error
 	// Owner and Repo for the mock server
 	Owner string
 	Repo  string
 
+	// prByBranchInFlight counts the GetPullRequestByBranch calls running now
+	prByBranchInFlight int
+
+	// commentIDs holds the ID of each comment in Comments, index for index
+	commentIDs    map[int][]int64
+	nextCommentID int64
+
 	mu sync.Mutex
 }
 
 // NewMockGitHubServerConfig creates a new mock server config with defaults
 func NewMockGitHubServerConfig() *MockGitHubServerConfig {
 	return &MockGitHubServerConfig{
-		PRs:            make(map[string]*github.PullRequest),
-		CreatedPRs:     make([]*github.PullRequest, 0),
-		UpdatedPRs:     make(map[int]*github.PullRequest),
-		ErrorResponses: make(map[string]error),
-		Owner:          "owner",
-		Repo:           "repo",
+		PRs:               make(map[string]*github.PullRequest),
+		CreatedPRs:        make([]*github.PullRequest, 0),
+		UpdatedPRs:        make(map[int]*github.PullRequest),
+		Labels:            make(map[int][]string),
+		Reviewers:         make(map[int][]string),
+		TeamReviewers:     make(map[int][]string),
+		Milestones:        make(map[int]string),
+		Comments:          make(map[int][]string),
+		AutoMergeMethods:  make(map[int]githubpkg.AutoMergeMethod),
+		ChecksStatus:      make(map[string]*githubpkg.CheckStatus),
+		ChecksUnavailable: make(map[string]bool),
+		ErrorResponses:    make(map[string]error),
+		Owner:             "owner",
+		Repo:              "repo",
 	}
 }
 
@@ -226,6 +274,16 @@ func NewMockGitHubServer(t *testing.T, config *MockGitHubServerConfig) *httptest
 					}
 				}
 
+				// Fall back to PRs seeded by branch name
+				if pr == nil {
+					for _, seeded := range config.PRs {
+						if seeded.GetNumber() == prNumber {
+							pr = seeded
+							break
+						}
+					}
+				}
+
 				if pr == nil {
 					http.Error(w, "PR not found", http.StatusNotFound)
 					return
@@ -2,6 +2,10 @@ package testhelpers
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/google/go-github/v62/github"
 
@@ -50,7 +54,19 @@ func (c *MockGitHubClient) CreatePullRequest(ctx context.Context, owner, repo st
 		return nil, err
 	}
 
-	return toPullRequestInfo(createdPR), nil
+	c.recordReviewers(createdPR.GetNumber(), opts.Reviewers, opts.TeamReviewers)
+	if len(opts.Labels) > 0 && c.config != nil && c.config.LabelsUnavailable {
+		return githubpkg.ToPullRequestInfo(createdPR), fmt.Errorf("%w: failed to add labels to PR %d: 403 Forbidden",
+			githubpkg.ErrPRSetupIncomplete, createdPR.GetNumber())
+	}
+
+	var milestone *string
+	if opts.Milestone != "" {
+		milestone = &opts.Milestone
+	}
+	c.recordLabelsAndMilestone(createdPR.GetNumber(), opts.Labels, false, milestone)
+
+	return githubpkg.ToPullRequestInfo(createdPR), nil
 }
 
 // UpdatePullRequest updates an existing pull request
@@ -70,11 +86,88 @@ func (c *MockGitHubClient) UpdatePullRequest(ctx context.Context, owner, repo st
 	}
 
 	_, _, err := c.client.PullRequests.Edit(ctx, owner, repo, prNumber, update)
-	return err
+	if err != nil {
+		return err
+	}
+
+	c.recordLabelsAndMilestone(prNumber, opts.Labels, opts.ReplaceLabels, opts.Milestone)
+	c.recordReviewers(prNumber, opts.Reviewers, opts.TeamReviewers)
+	return nil
+}
+
+// recordReviewers records the reviewers requested on a PR in the mock server config
+func (c *MockGitHubClient) recordReviewers(prNumber int, reviewers, teamReviewers []string) {
+	if c.config == nil {
+		return
+	}
+
+	c.config.mu.Lock()
+	defer c.config.mu.Unlock()
+
+	for _, reviewer := range reviewers {
+		if !slices.Contains(c.config.Reviewers[prNumber], reviewer) {
+			c.config.Reviewers[prNumber] = append(c.config.Reviewers[prNumber], reviewer)
+		}
+	}
+	for _, team := range teamReviewers {
+		if !slices.Contains(c.config.TeamReviewers[prNumber], team) {
+			c.config.TeamReviewers[prNumber] = append(c.config.TeamReviewers[prNumber], team)
+		}
+	}
+}
+
+// recordLabelsAndMilestone mirrors the label/milestone reconciliation of the real client
+// by recording the resulting state in the mock server config
+func (c *MockGitHubClient) recordLabelsAndMilestone(prNumber int, labels []string, replaceLabels bool, milestone *string) {
+	if c.config == nil {
+		return
+	}
+
+	c.config.mu.Lock()
+	defer c.config.mu.Unlock()
+
+	if replaceLabels {
+		c.config.Labels[prNumber] = append([]string{}, labels...)
+	} else {
+		for _, label := range labels {
+			if !slices.Contains(c.config.Labels[prNumber], label) {
+				c.config.Labels[prNumber] = append(c.config.Labels[prNumber], label)
+			}
+		}
+	}
+
+	if milestone != nil && *milestone != "" {
+		c.config.Milestones[prNumber] = *milestone
+	}
+}
+
+// GetPullRequest gets a pull request by number
+func (c *MockGitHubClient) GetPullRequest(ctx context.Context, owner, repo string, prNumber int) (*githubpkg.PullRequestInfo, error) {
+	pr, resp, err := c.client.PullRequests.Get(ctx, owner, repo, prNumber)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return githubpkg.ToPullRequestInfo(pr), nil
 }
 
 // GetPullRequestByBranch gets a pull request for a branch
 func (c *MockGitHubClient) GetPullRequestByBranch(ctx context.Context, owner, repo, branchName string) (*githubpkg.PullRequestInfo, error) {
+	if c.config != nil {
+		c.config.mu.Lock()
+		c.config.PRByBranchCalls++
+		c.config.prByBranchInFlight++
+		c.config.MaxPRByBranchInFlight = max(c.config.MaxPRByBranchInFlight, c.config.prByBranchInFlight)
+		c.config.mu.Unlock()
+		defer func() {
+			c.config.mu.Lock()
+			c.config.prByBranchInFlight--
+			c.config.mu.Unlock()
+		}()
+	}
+
 	prs, _, err := c.client.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{
 		Head:  owner + ":" + branchName,
 		State: "all",
@@ -90,7 +183,7 @@ func (c *MockGitHubClient) GetPullRequestByBranch(ctx context.Context, owner, re
 		return nil, nil
 	}
 
-	return toPullRequestInfo(prs[0]), nil
+	return githubpkg.ToPullRequestInfo(prs[0]), nil
 }
 
 // MergePullRequest merges a pull request
@@ -100,8 +193,21 @@ func (c *MockGitHubClient) MergePullRequest(_ context.Context, _ string) error {
 }
 
 // GetPRChecksStatus returns the check status for a PR
-func (c *MockGitHubClient) GetPRChecksStatus(_ context.Context, _ string) (*githubpkg.CheckStatus, error) {
-	// In tests, always return passing
+func (c *MockGitHubClient) GetPRChecksStatus(_ context.Context, branchName string) (*githubpkg.CheckStatus, error) {
+	if c.config != nil {
+		c.config.mu.Lock()
+		status, ok := c.config.ChecksStatus[branchName]
+		unavailable := c.config.ChecksUnavailable[branchName]
+		c.config.mu.Unlock()
+		if unavailable {
+			return nil, fmt.Errorf("failed to get check runs for %s: 502 Bad Gateway", branchName)
+		}
+		if ok {
+			return status, nil
+		}
+	}
+
+	// Default to passing
 	return &githubpkg.CheckStatus{
 		Passing: true,
 		Pending: false,
@@ -111,41 +217,129 @@ func (c *MockGitHubClient) GetPRChecksStatus(_ context.Context, _ string) (*gith
 	}, nil
 }
 
-// toPullRequestInfo converts a github.PullRequest to githubpkg.PullRequestInfo
-func toPullRequestInfo(pr *github.PullRequest) *githubpkg.PullRequestInfo {
-	if pr == nil {
-		return nil
+// GetBranchPRStatuses returns the PRs in the mock server config for the branches, with
+// their check statuses from ChecksStatus, in a single call
+func (c *MockGitHubClient) GetBranchPRStatuses(ctx context.Context, branchNames []string) (map[string]*githubpkg.BranchPRStatus, error) {
+	if c.config == nil {
+		return map[string]*githubpkg.BranchPRStatus{}, nil
 	}
 
-	info := &githubpkg.PullRequestInfo{}
+	c.config.mu.Lock()
+	c.config.BatchPRStatusCalls++
+	unavailable := c.config.BatchPRStatusUnavailable
+	prs := make(map[string]*github.PullRequest, len(branchNames))
+	for _, branchName := range branchNames {
+		if pr, ok := c.config.PRs[branchName]; ok {
+			prs[branchName] = pr
+		}
+	}
+	c.config.mu.Unlock()
+	if unavailable {
+		return nil, fmt.Errorf("GraphQL API unavailable")
+	}
 
-	if pr.Number != nil {
-		info.Number = *pr.Number
+	statuses := make(map[string]*githubpkg.BranchPRStatus, len(prs))
+	for branchName, pr := range prs {
+		checks, err := c.GetPRChecksStatus(ctx, branchName)
+		if err != nil {
+			return nil, err
+		}
+		statuses[branchName] = &githubpkg.BranchPRStatus{PR: githubpkg.ToPullRequestInfo(pr), Checks: checks}
 	}
-	if pr.NodeID != nil {
-		info.NodeID = *pr.NodeID
+	return statuses, nil
+}
+
+// AddComment records the comment in the mock server config
+func (c *MockGitHubClient) AddComment(_ context.Context, _, _ string, prNumber int, body string) error {
+	if c.config == nil {
+		return nil
 	}
-	if pr.HTMLURL != nil {
-		info.HTMLURL = *pr.HTMLURL
+
+	c.config.mu.Lock()
+	defer c.config.mu.Unlock()
+	if c.config.commentIDs == nil {
+		c.config.commentIDs = make(map[int][]int64)
 	}
-	if pr.Title != nil {
-		info.Title = *pr.Title
+	c.config.nextCommentID++
+	c.config.Comments[prNumber] = append(c.config.Comments[prNumber], body)
+	c.config.commentIDs[prNumber] = append(c.config.commentIDs[prNumber], c.config.nextCommentID)
+	return nil
+}
+
+// ListComments returns the comments recorded in the mock server config
+func (c *MockGitHubClient) ListComments(_ context.Context, _, _ string, prNumber int) ([]githubpkg.Comment, error) {
+	if c.config == nil {
+		return nil, nil
 	}
-	if pr.Body != nil {
-		info.Body = *pr.Body
+
+	c.config.mu.Lock()
+	defer c.config.mu.Unlock()
+	comments := make([]githubpkg.Comment, 0, len(c.config.Comments[prNumber]))
+	ids := c.config.commentIDs[prNumber]
+	for i, body := range c.config.Comments[prNumber] {
+		// Comments seeded directly into the config have no ID
+		var id int64
+		if i < len(ids) {
+			id = ids[i]
+		}
+		comments = append(comments, githubpkg.Comment{ID: id, Body: body})
 	}
-	if pr.State != nil {
-		info.State = *pr.State
+	return comments, nil
+}
+
+// UpdateComment replaces a comment's body in the mock server config
+func (c *MockGitHubClient) UpdateComment(_ context.Context, _, _ string, commentID int64, body string) error {
+	if c.config == nil {
+		return nil
 	}
-	if pr.Draft != nil {
-		info.Draft = *pr.Draft
+
+	c.config.mu.Lock()
+	defer c.config.mu.Unlock()
+	for prNumber, ids := range c.config.commentIDs {
+		if i := slices.Index(ids, commentID); i >= 0 {
+			c.config.Comments[prNumber][i] = body
+			return nil
+		}
 	}
-	if pr.Base != nil && pr.Base.Ref != nil {
-		info.Base = *pr.Base.Ref
+	return fmt.Errorf("comment %d not found", commentID)
+}
+
+// DeleteComment removes a comment from the mock server config
+func (c *MockGitHubClient) DeleteComment(_ context.Context, _, _ string, commentID int64) error {
+	if c.config == nil {
+		return nil
 	}
-	if pr.Head != nil && pr.Head.Ref != nil {
-		info.Head = *pr.Head.Ref
+
+	c.config.mu.Lock()
+	defer c.config.mu.Unlock()
+	for prNumber, ids := range c.config.commentIDs {
+		if i := slices.Index(ids, commentID); i >= 0 {
+			c.config.Comments[prNumber] = slices.Delete(c.config.Comments[prNumber], i, i+1)
+			c.config.commentIDs[prNumber] = slices.Delete(ids, i, i+1)
+			return nil
+		}
+	}
+	return fmt.Errorf("comment %d not found", commentID)
+}
+
+// EnableAutoMerge records every call's method in the mock server config and marks the
+// PR as auto-merging, so later lookups see it enabled
+func (c *MockGitHubClient) EnableAutoMerge(_ context.Context, _, _ string, prNumber int, method githubpkg.AutoMergeMethod) error {
+	if c.config == nil {
+		return nil
 	}
 
-	return info
+	c.config.mu.Lock()
+	defer c.config.mu.Unlock()
+	if c.config.AutoMergeNotAllowed {
+		return githubpkg.ErrAutoMergeNotAllowed
+	}
+	for _, pr := range c.config.PRs {
+		if pr.GetNumber() != prNumber {
+			continue
+		}
+		pr.AutoMerge = &github.PullRequestAutoMerge{MergeMethod: github.String(strings.ToLower(string(method)))}
+	}
+	c.config.AutoMergeMethods[prNumber] = method
+	return nil
 }
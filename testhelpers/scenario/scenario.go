@@ -157,10 +157,11 @@ func (s *Scenario) CommitChange(name, message string) *Scenario {
 	return s
 }
 
-// TrackBranch tracks a branch with a parent in the engine.
+// TrackBranch tracks a branch with a parent in the engine. The parent isn't required to be
+// an ancestor of the branch, so tests can set up stacks that need restacking.
 func (s *Scenario) TrackBranch(branch, parent string) *Scenario {
 	s.T.Helper()
-	err := s.Engine.TrackBranch(context.Background(), branch, parent)
+	err := s.Engine.ForceTrackBranch(context.Background(), branch, parent)
 	require.NoError(s.T, err)
 	return s
 }
@@ -20,7 +20,7 @@ var (
 	basicTemplateOnce sync.Once
 )
 
-func getMinimalTemplate(t *testing.T) string {
+func getMinimalTemplate(t testing.TB) string {
 	minimalTemplateOnce.Do(func() {
 		dir, err := os.MkdirTemp("", "stackit-test-minimal-template-*")
 		if err != nil {
@@ -44,7 +44,7 @@ func getMinimalTemplate(t *testing.T) string {
 	return minimalTemplateDir
 }
 
-func getBasicTemplate(t *testing.T) string {
+func getBasicTemplate(t testing.TB) string {
 	basicTemplateOnce.Do(func() {
 		minimalDir := getMinimalTemplate(t)
 
@@ -91,7 +91,7 @@ type SceneSetup func(*Scene) error
 // It automatically handles cleanup using t.Cleanup().
 // NOTE: This function uses os.Chdir() and is NOT safe for parallel tests.
 // Use NewSceneParallel for tests that can run in parallel.
-func NewScene(t *testing.T, setup SceneSetup) *Scene {
+func NewScene(t testing.TB, setup SceneSetup) *Scene {
 	// Reset the default git repository to ensure this test gets a fresh one.
 	git.ResetDefaultRepo()
 
@@ -253,6 +253,8 @@ func (s *Scene) writeDefaultConfigs() error {
 	// Set environment variable for user config path
 	_ = os.Setenv("STACKIT_USER_CONFIG_PATH", userConfigPath)
 	_ = os.Setenv("STACKIT_PROFILE", "")
+	// Keep the developer's global stackit config out of tests
+	_ = os.Setenv("XDG_CONFIG_HOME", filepath.Join(s.Dir, ".git", "xdg-config"))
 
 	return nil
 }